@@ -0,0 +1,79 @@
+package fauna
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AbortPayload is the conventional shape of the data passed to `abort()` by
+// services signalling business errors, such as:
+//
+//	abort({ code: "insufficient_funds", message: "Balance too low", data: { balance: 10 } })
+type AbortPayload struct {
+	// Code identifies the kind of error, for callers to match on.
+	Code string `fauna:"code"`
+
+	// Message describes the error.
+	Message string `fauna:"message"`
+
+	// Data is any further details of the error.
+	Data any `fauna:"data"`
+}
+
+// AbortDetails returns the [fauna.AbortPayload] of the [fauna.ErrAbort] in
+// err's chain, reporting false if there isn't one, or its data isn't an object
+// with a string `code`.
+func AbortDetails(err error) (*AbortPayload, bool) {
+	var abortErr *ErrAbort
+	if !errors.As(err, &abortErr) || abortErr.ErrFauna == nil {
+		return nil, false
+	}
+
+	if _, isObject := abortErr.Abort.(map[string]any); !isObject {
+		return nil, false
+	}
+
+	var payload AbortPayload
+	if decodeErr := abortErr.Unmarshal(&payload); decodeErr != nil || payload.Code == "" {
+		return nil, false
+	}
+
+	return &payload, true
+}
+
+// AbortCode returns the code of the [fauna.AbortPayload] of the
+// [fauna.ErrAbort] in err's chain, or an empty string if there isn't one.
+//
+//	switch fauna.AbortCode(err) {
+//	case "insufficient_funds":
+//		// ...
+//	}
+func AbortCode(err error) string {
+	if payload, ok := AbortDetails(err); ok {
+		return payload.Code
+	}
+
+	return ""
+}
+
+// AbortData decodes the data of the [fauna.AbortPayload] of the
+// [fauna.ErrAbort] in err's chain into a T, returning an error if there isn't
+// one or its data can't be decoded.
+func AbortData[T any](err error) (T, error) {
+	var data T
+
+	payload, ok := AbortDetails(err)
+	if !ok {
+		return data, fmt.Errorf("error is not a structured abort: %w", err)
+	}
+
+	if payload.Data == nil {
+		return data, nil
+	}
+
+	if decodeErr := decodeInto(payload.Data, &data); decodeErr != nil {
+		return data, fmt.Errorf("failed to decode abort data: %w", decodeErr)
+	}
+
+	return data, nil
+}
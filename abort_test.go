@@ -0,0 +1,52 @@
+package fauna
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAbortPayload(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		switch req.Body["query"].(map[string]any)["fql"].([]any)[0] {
+		case "structured":
+			return http.StatusBadRequest, `{"error":{"code":"abort","message":"Query aborted.","abort":{"@object":{"code":"insufficient_funds","message":"Balance too low","data":{"balance":{"@int":"10"},"needed":{"@int":"25"}}}}},"summary":""}`
+		case "bare":
+			return http.StatusBadRequest, `{"error":{"code":"abort","message":"Query aborted.","abort":"oops"},"summary":""}`
+		}
+		return http.StatusBadRequest, errorBody("invalid_query", "bad query")
+	})
+	client := srv.client()
+	query := func(fql string) error {
+		q, _ := FQL(fql, nil)
+		_, err := client.Query(q)
+		return err
+	}
+
+	err := fmt.Errorf("transfer failed: %w", query("structured"))
+	assert.Equal(t, "insufficient_funds", AbortCode(err))
+
+	payload, ok := AbortDetails(err)
+	if assert.True(t, ok) {
+		assert.Equal(t, "Balance too low", payload.Message)
+	}
+
+	type funds struct {
+		Balance int `fauna:"balance"`
+		Needed  int `fauna:"needed"`
+	}
+	data, dataErr := AbortData[funds](err)
+	assert.NoError(t, dataErr)
+	assert.Equal(t, funds{Balance: 10, Needed: 25}, data)
+
+	for _, fql := range []string{"bare", "invalid"} {
+		err := query(fql)
+		assert.Empty(t, AbortCode(err), fql)
+		_, ok := AbortDetails(err)
+		assert.False(t, ok, fql)
+		_, dataErr := AbortData[funds](err)
+		assert.ErrorContains(t, dataErr, "not a structured abort", fql)
+	}
+}
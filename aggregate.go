@@ -0,0 +1,138 @@
+package fauna
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrEmptySet is returned by aggregates which have no value for an empty set,
+// such as [fauna.Avg], [fauna.Min], and [fauna.Max].
+var ErrEmptySet = errors.New("aggregate of an empty set")
+
+// Number is the numeric types an aggregate result can be decoded into.
+type Number interface {
+	~int | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// Count returns the number of items in set, a [fauna.Query] evaluating to a
+// Set such as `Users.where(.active)`.
+func Count(client *Client, set *Query, opts ...QueryOptFn) (int64, error) {
+	var count int64
+	err := aggregate(client, "${set}.count()", set, "", &count, opts)
+	return count, err
+}
+
+// CountedPage is the first page of a set and the number of items in the
+// whole set, from [fauna.PageWithCount].
+type CountedPage struct {
+	Page
+
+	// Total is the number of items in the set.
+	Total int64
+}
+
+// PageWithCount returns the first page of set and the number of items in it
+// from a single query, such as for a list endpoint which shows the total. A
+// positive size sets the page size. Later pages can be read by paginating
+// `Set.paginate(${after})` with the page's After cursor.
+func PageWithCount(client *Client, set *Query, size int, opts ...QueryOptFn) (*CountedPage, error) {
+	if set == nil {
+		return nil, fmt.Errorf("set must not be nil")
+	}
+
+	args := map[string]any{"set": set}
+	page := "set"
+	if size > 0 {
+		args["size"] = size
+		page = "set.pageSize(${size})"
+	}
+
+	q, err := FQL("let set = ${set}\n{ page: "+page+", total: set.count() }", args)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Query(q, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Page  *Page `fauna:"page"`
+		Total int64 `fauna:"total"`
+	}
+	if err := res.Unmarshal(&result); err != nil {
+		return nil, err
+	}
+
+	counted := &CountedPage{Total: result.Total}
+	if result.Page != nil {
+		counted.Page = *result.Page
+	}
+	return counted, nil
+}
+
+// Sum returns the sum of field across the items in set. field can be a dotted
+// path to a nested field, such as "address.zip".
+func Sum[N Number](client *Client, set *Query, field string, opts ...QueryOptFn) (N, error) {
+	var sum N
+	err := aggregate(client, "${set}.fold(0, (acc, item) => acc + item${field})", set, field, &sum, opts)
+	return sum, err
+}
+
+// Avg returns the mean of field across the items in set.
+func Avg(client *Client, set *Query, field string, opts ...QueryOptFn) (float64, error) {
+	var avg float64
+	err := aggregate(
+		client,
+		"let values = ${set}.map(item => item${field}).toArray()\nif (values.length == 0) null else Math.mean(values)",
+		set, field, &avg, opts,
+	)
+	return avg, err
+}
+
+// Min returns the smallest value of field across the items in set.
+func Min[N Number](client *Client, set *Query, field string, opts ...QueryOptFn) (N, error) {
+	var min N
+	err := aggregate(client, "${set}.map(item => item${field}).reduce((a, b) => if (b < a) b else a)", set, field, &min, opts)
+	return min, err
+}
+
+// Max returns the largest value of field across the items in set.
+func Max[N Number](client *Client, set *Query, field string, opts ...QueryOptFn) (N, error) {
+	var max N
+	err := aggregate(client, "${set}.map(item => item${field}).reduce((a, b) => if (b > a) b else a)", set, field, &max, opts)
+	return max, err
+}
+
+func aggregate(client *Client, template string, set *Query, field string, into any, opts []QueryOptFn) error {
+	if set == nil {
+		return fmt.Errorf("set must not be nil")
+	}
+
+	if strings.Contains(template, "${field}") {
+		path, err := fieldPath(field)
+		if err != nil {
+			return err
+		}
+		// the validated path is spliced into the template as FQL
+		template = strings.ReplaceAll(template, "${field}", path)
+	}
+
+	q, err := FQL(template, map[string]any{"set": set})
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Query(q, opts...)
+	if err != nil {
+		return err
+	}
+
+	if res.Data == nil {
+		return ErrEmptySet
+	}
+
+	return res.Unmarshal(into)
+}
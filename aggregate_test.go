@@ -0,0 +1,116 @@
+package fauna
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregates(t *testing.T) {
+	results := [][2]string{
+		{".count()", `{"@long":"12"}`},
+		{".fold(", `{"@int":"42"}`},
+		{"Math.mean", `{"@double":"3.5"}`},
+		{"b < a", `{"@int":"1"}`},
+		{"b > a", `null`},
+	}
+
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		var literals strings.Builder
+		for _, f := range req.Body["query"].(map[string]any)["fql"].([]any) {
+			if s, isLiteral := f.(string); isLiteral {
+				literals.WriteString(s)
+			}
+		}
+
+		for _, result := range results {
+			if strings.Contains(literals.String(), result[0]) {
+				return http.StatusOK, successBody(result[1])
+			}
+		}
+		return http.StatusBadRequest, errorBody("invalid_query", "unexpected")
+	})
+	client := srv.client()
+	set, _ := FQL("Orders.where(.status == ${status})", map[string]any{"status": "paid"})
+
+	t.Run("count", func(t *testing.T) {
+		count, err := Count(client, set)
+		if assert.NoError(t, err) {
+			assert.Equal(t, int64(12), count)
+		}
+	})
+
+	t.Run("sum", func(t *testing.T) {
+		sum, err := Sum[int](client, set, "total.amount")
+		if assert.NoError(t, err) {
+			assert.Equal(t, 42, sum)
+		}
+
+		received := srv.received()
+		bs, _ := marshal(received[len(received)-1].Body["query"])
+		assert.Contains(t, string(bs), `acc + item.total.amount`)
+
+		floatSum, err := Sum[float64](client, set, "total")
+		if assert.NoError(t, err) {
+			assert.Equal(t, float64(42), floatSum)
+		}
+	})
+
+	t.Run("avg", func(t *testing.T) {
+		avg, err := Avg(client, set, "total")
+		if assert.NoError(t, err) {
+			assert.Equal(t, 3.5, avg)
+		}
+	})
+
+	t.Run("min and max", func(t *testing.T) {
+		min, err := Min[int64](client, set, "total")
+		if assert.NoError(t, err) {
+			assert.Equal(t, int64(1), min)
+		}
+
+		_, err = Max[int64](client, set, "total")
+		assert.ErrorIs(t, err, ErrEmptySet)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := Sum[int](client, set, "total; Orders.all().delete()")
+		assert.Error(t, err)
+
+		_, err = Sum[int](client, set, "")
+		assert.Error(t, err)
+
+		_, err = Count(client, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestPageWithCount(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		return http.StatusOK, successBody(`{"page":{"@set":{"data":["a","b"],"after":"next"}},"total":{"@long":"5"}}`)
+	})
+	client := srv.client()
+	set, _ := FQL("Orders.all()", nil)
+
+	counted, err := PageWithCount(client, set, 2)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(5), counted.Total)
+		assert.Equal(t, []any{"a", "b"}, counted.Data)
+		assert.Equal(t, "next", counted.After)
+
+		var names []string
+		if assert.NoError(t, counted.Unmarshal(&names)) {
+			assert.Equal(t, []string{"a", "b"}, names)
+		}
+	}
+
+	received := srv.received()
+	bs, _ := marshal(received[len(received)-1].Body["query"])
+	assert.Contains(t, string(bs), `set.pageSize(`)
+	assert.Contains(t, string(bs), `total: set.count()`)
+
+	_, err = PageWithCount(client, nil, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package fauna
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ArrayFunc is a query argument encoded as an array of the values it passes
+// to emit, so a large array, such as the documents of a bulk write read from
+// a file, is encoded without first collecting its values into a slice. emit
+// returns an error if a value can't be encoded, which the func should return.
+//
+//	docs := fauna.ArrayFunc(func(emit func(any) error) error {
+//		for scanner.Scan() {
+//			if err := emit(parse(scanner.Text())); err != nil {
+//				return err
+//			}
+//		}
+//		return scanner.Err()
+//	})
+//	q, _ := fauna.FQL(`${docs}.forEach(doc => Dogs.create(doc))`, map[string]any{"docs": docs})
+//
+// The encoded query is still held in memory, so it can be retried. The func
+// is called each time the query is encoded, such as by [Client.Query], so it
+// must emit the same values each time it's called.
+type ArrayFunc func(emit func(v any) error) error
+
+// streamedArray encodes an [fauna.ArrayFunc] one value at a time as it's
+// marshaled.
+type streamedArray struct {
+	fn            ArrayFunc
+	deterministic bool
+	codec         codec
+}
+
+func (a streamedArray) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+
+	n := 0
+	err := a.fn(func(v any) error {
+		enc, err := a.codec.encodeSealed(v)
+		if err != nil {
+			return err
+		}
+		if a.deterministic {
+			if enc, err = normalize(enc); err != nil {
+				return err
+			}
+		}
+
+		bin, err := json.Marshal(enc)
+		if err != nil {
+			return err
+		}
+
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(bin)
+		n++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
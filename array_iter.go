@@ -0,0 +1,18 @@
+//go:build go1.23
+
+package fauna
+
+import "iter"
+
+// ArraySeq is a [fauna.ArrayFunc] emitting the values of seq, which must
+// yield the same values each time it's iterated.
+func ArraySeq[T any](seq iter.Seq[T]) ArrayFunc {
+	return func(emit func(v any) error) error {
+		for v := range seq {
+			if err := emit(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
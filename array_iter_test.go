@@ -0,0 +1,17 @@
+//go:build go1.23
+
+package fauna
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArraySeq(t *testing.T) {
+	bin, err := Marshal(ArraySeq(slices.Values([]int{1, 2})))
+	if assert.NoError(t, err) {
+		assert.Equal(t, `[{"@int":"1"},{"@int":"2"}]`, string(bin))
+	}
+}
@@ -0,0 +1,68 @@
+package fauna
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArrayFunc(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, successBody(`null`)
+	})
+
+	calls := 0
+	docs := ArrayFunc(func(emit func(any) error) error {
+		calls++
+		for i := 0; i < 3; i++ {
+			if err := emit(map[string]any{"n": i}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	q, err := FQL(`${docs}.forEach(doc => Dogs.create(doc))`, map[string]any{"docs": docs})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 0, calls, "values aren't emitted until the query is encoded")
+
+	_, err = srv.client().Query(q)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	if received := srv.received(); assert.Len(t, received, 1) {
+		query, _ := json.Marshal(received[0].Body["query"])
+		assert.JSONEq(t,
+			`{"fql":[{"value":[{"n":{"@int":"0"}},{"n":{"@int":"1"}},{"n":{"@int":"2"}}]},".forEach(doc => Dogs.create(doc))"]}`,
+			string(query))
+	}
+
+	bin, err := Marshal(ArrayFunc(nil))
+	if assert.NoError(t, err) {
+		assert.Equal(t, `null`, string(bin))
+	}
+
+	empty := ArrayFunc(func(func(any) error) error { return nil })
+	bin, err = Marshal(empty)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `[]`, string(bin))
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		failed := errors.New("read failed")
+		_, err := Marshal(ArrayFunc(func(emit func(any) error) error {
+			_ = emit("ok")
+			return failed
+		}))
+		assert.ErrorIs(t, err, failed)
+
+		_, err = Marshal(ArrayFunc(func(emit func(any) error) error {
+			return emit(make(chan int))
+		}))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,144 @@
+package fauna
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Token is a Fauna token created by [Client.Login]. Its Secret authenticates
+// queries as the identity document it was issued for.
+type Token struct {
+	Document
+
+	// Secret is the token's secret. It's only returned when the token is
+	// created.
+	Secret string `fauna:"secret"`
+
+	// TTL is when the token expires, or the zero time if it doesn't.
+	TTL time.Time `fauna:"ttl"`
+
+	// Identity is a reference to the document the token was issued for.
+	Identity *Ref `fauna:"document"`
+}
+
+// withSecret authenticates a single [Client.Query] with secret instead of the
+// [fauna.Client] secret.
+func withSecret(secret string) QueryOptFn {
+	return func(req *fqlRequest) { req.Secret = secret }
+}
+
+// CreateCredentials sets password as the credentials of identity, a
+// [fauna.Document] or [fauna.Ref], replacing any existing password.
+func (c *Client) CreateCredentials(identity any, password string, opts ...QueryOptFn) error {
+	q, err := FQL(
+		"let cred = Credential.byDocument(${doc})\n"+
+			"if (cred == null) Credential.create({ document: ${doc}, password: ${password} }) else cred!.update({ password: ${password} })\n"+
+			"null",
+		map[string]any{"doc": identity, "password": password},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Query(q, opts...)
+	return err
+}
+
+// Login exchanges identity's password for a [fauna.Token]. A positive ttl
+// expires the token after that duration, otherwise it never expires. An
+// invalid password or identity without credentials returns an
+// [fauna.ErrAuthentication].
+func (c *Client) Login(identity any, password string, ttl time.Duration, opts ...QueryOptFn) (*Token, error) {
+	q, err := FQL(
+		"let cred = Credential.byDocument(${doc})\n"+
+			"if (cred == null || !cred!.verify(${password})) abort(\"invalid credentials\")\n"+
+			"if (${ttl} > 0) cred!.login(${password}, Time.now().add(${ttl}, \"milliseconds\")) else cred!.login(${password})",
+		map[string]any{"doc": identity, "password": password, "ttl": ttl.Milliseconds()},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.Query(q, opts...)
+	if err != nil {
+		var abort *ErrAbort
+		if errors.As(err, &abort) {
+			return nil, &ErrAuthentication{abort.ErrFauna}
+		}
+		return nil, err
+	}
+
+	var token Token
+	if err := res.Unmarshal(&token); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// VerifyPassword reports whether password matches identity's credentials,
+// without creating a token.
+func (c *Client) VerifyPassword(identity any, password string, opts ...QueryOptFn) (bool, error) {
+	q, err := FQL(
+		"let cred = Credential.byDocument(${doc})\ncred != null && cred!.verify(${password})",
+		map[string]any{"doc": identity, "password": password},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := c.Query(q, opts...)
+	if err != nil {
+		return false, err
+	}
+
+	valid, _ := res.Data.(bool)
+	return valid, nil
+}
+
+// VerifyToken returns the identity document a token secret authenticates as.
+// An invalid or expired secret returns an [fauna.ErrAuthentication].
+func (c *Client) VerifyToken(secret string, opts ...QueryOptFn) (*Document, error) {
+	q, err := FQL(`Query.identity()`, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.Query(q, append(opts, withSecret(secret))...)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, isDoc := res.Data.(*Document)
+	if !isDoc {
+		return nil, fmt.Errorf("secret is not a token for an identity document")
+	}
+
+	return identity, nil
+}
+
+// Logout deletes the token a secret belongs to, so it can no longer be used.
+func (c *Client) Logout(secret string, opts ...QueryOptFn) error {
+	q, err := FQL("Query.token()!.delete()\nnull", nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Query(q, append(opts, withSecret(secret))...)
+	return err
+}
+
+// LogoutAll deletes every token issued for identity.
+func (c *Client) LogoutAll(identity any, opts ...QueryOptFn) error {
+	q, err := FQL(
+		"Token.byDocument(${doc}).forEach(.delete())\nnull",
+		map[string]any{"doc": identity},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Query(q, opts...)
+	return err
+}
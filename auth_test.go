@@ -0,0 +1,95 @@
+package fauna
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogin(t *testing.T) {
+	user := &Ref{ID: "123", Coll: &Module{"Users"}}
+
+	t.Run("token", func(t *testing.T) {
+		srv := newTestServer(t, func(req testRequest) (int, string) {
+			assert.Contains(t, req.Body["query"].(map[string]any)["fql"].([]any)[0], "Credential.byDocument(")
+			return http.StatusOK, successBody(`{"@doc":{"id":"456","coll":{"@mod":"Token"},"ts":{"@time":"2023-04-01T00:00:00Z"},` +
+				`"secret":"fnToken","ttl":{"@time":"2023-04-02T00:00:00Z"},"document":{"@ref":{"id":"123","coll":{"@mod":"Users"}}}}}`)
+		})
+
+		token, err := srv.client().Login(user, "hunter2", 24*time.Hour)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, "456", token.ID)
+		assert.Equal(t, "fnToken", token.Secret)
+		assert.Equal(t, time.Date(2023, 4, 2, 0, 0, 0, 0, time.UTC), token.TTL)
+		assert.Equal(t, user, token.Identity)
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		srv := newTestServer(t, func(req testRequest) (int, string) {
+			return http.StatusBadRequest, errorBody("abort", "Query aborted.")
+		})
+
+		_, err := srv.client().Login(user, "wrong", 0)
+		var authErr *ErrAuthentication
+		assert.ErrorAs(t, err, &authErr)
+
+		hooked := srv.client(WithErrorHook(func(err error, _ *QueryErrorInfo) error {
+			return fmt.Errorf("auth service: %w", err)
+		}))
+		_, err = hooked.Login(user, "wrong", 0)
+		assert.ErrorAs(t, err, &authErr, "errors wrapped by hooks are still recognized")
+	})
+}
+
+func TestCredentials(t *testing.T) {
+	user := &Ref{ID: "123", Coll: &Module{"Users"}}
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		if strings.Contains(req.Body["query"].(map[string]any)["fql"].([]any)[2].(string), ".verify(") {
+			return http.StatusOK, successBody(`true`)
+		}
+		return http.StatusOK, successBody(`null`)
+	})
+	client := srv.client()
+
+	assert.NoError(t, client.CreateCredentials(user, "hunter2"))
+
+	valid, err := client.VerifyPassword(user, "hunter2")
+	if assert.NoError(t, err) {
+		assert.True(t, valid)
+	}
+
+	assert.NoError(t, client.LogoutAll(user))
+	assert.Len(t, srv.received(), 3)
+}
+
+func TestTokenSecrets(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		if req.Header.Get(headerAuthorization) != "Bearer fnToken" {
+			return http.StatusUnauthorized, errorBody("unauthorized", "Access token required")
+		}
+		return http.StatusOK, successBody(`{"@doc":{"id":"123","coll":{"@mod":"Users"},"ts":{"@time":"2023-04-01T00:00:00Z"},"name":"Ada"}}`)
+	})
+	client := srv.client()
+
+	identity, err := client.VerifyToken("fnToken")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "123", identity.ID)
+		assert.Equal(t, "Ada", identity.Data["name"])
+	}
+
+	_, err = client.VerifyToken("expired")
+	var authErr *ErrAuthentication
+	assert.ErrorAs(t, err, &authErr)
+
+	assert.NoError(t, client.Logout("fnToken"))
+
+	reqs := srv.received()
+	assert.Equal(t, "Bearer fnToken", reqs[len(reqs)-1].Header.Get(headerAuthorization))
+}
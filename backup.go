@@ -0,0 +1,450 @@
+package fauna
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// BackupFormatVersion is the version of the archive format written by
+// [Client.Backup].
+const BackupFormatVersion = 1
+
+const (
+	backupManifestFile = "manifest.json"
+	backupSchemaDir    = "schema/"
+	backupDataDir      = "collections/"
+	backupDataExt      = ".jsonl"
+)
+
+// BackupManifest describes a backup archive.
+type BackupManifest struct {
+	// FormatVersion is the archive's [fauna.BackupFormatVersion].
+	FormatVersion int `json:"format_version"`
+
+	// CreatedAt is when the backup started.
+	CreatedAt time.Time `json:"created_at"`
+
+	// SchemaVersion is the version of the backed up schema.
+	SchemaVersion int64 `json:"schema_version"`
+
+	// Collections lists the backed up collections.
+	Collections []BackupCollection `json:"collections"`
+}
+
+// BackupCollection describes a collection in a backup archive.
+type BackupCollection struct {
+	Name      string `json:"name"`
+	Documents int    `json:"documents"`
+}
+
+// BackupOptFn configuration options for [Client.Backup]
+type BackupOptFn func(*backupOptions)
+
+type backupOptions struct {
+	collections []string
+	queryOpts   []QueryOptFn
+}
+
+// BackupCollections limits the backup to the named collections. By default
+// every collection is backed up.
+func BackupCollections(names ...string) BackupOptFn {
+	return func(o *backupOptions) { o.collections = names }
+}
+
+// BackupQueryOptions sets options on the queries reading documents. A context
+// set with [fauna.QueryContext] is also used to pull the schema.
+func BackupQueryOptions(opts ...QueryOptFn) BackupOptFn {
+	return func(o *backupOptions) { o.queryOpts = opts }
+}
+
+// Backup writes the schema and documents of the database the [fauna.Client]
+// is connected to as a gzipped tar archive, for [Client.Restore] to restore
+// into another database. The archive holds:
+//
+//	manifest.json             a [fauna.BackupManifest]
+//	schema/<file>.fsl         each FSL file, including indexes and functions
+//	collections/<name>.jsonl  a document per line, with its id and data
+//
+// Documents are written in Fauna's tagged JSON format, so references, times,
+// dates and numeric types survive the round trip. Each collection is paginated
+// separately, so the backup is not a consistent snapshot of a database which
+// is being written to.
+func (c *Client) Backup(w io.Writer, opts ...BackupOptFn) (*BackupManifest, error) {
+	var o backupOptions
+	for _, optFn := range opts {
+		optFn(&o)
+	}
+
+	manifest := &BackupManifest{FormatVersion: BackupFormatVersion, CreatedAt: time.Now().UTC()}
+
+	schema, err := c.PullSchema(queryContext(o.queryOpts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull schema: %w", err)
+	}
+	manifest.SchemaVersion = schema.Version
+
+	names := o.collections
+	if names == nil {
+		if names, err = c.collectionNames(o.queryOpts); err != nil {
+			return nil, fmt.Errorf("failed to list collections: %w", err)
+		}
+	}
+
+	// tar headers need each file's size, so documents are buffered to disk
+	data := make([]*os.File, 0, len(names))
+	defer func() {
+		for _, f := range data {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	for _, name := range names {
+		f, err := os.CreateTemp("", "fauna-backup-*"+backupDataExt)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, f)
+
+		count, err := c.backupCollection(name, f, o.queryOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to back up %s: %w", name, err)
+		}
+		manifest.Collections = append(manifest.Collections, BackupCollection{Name: name, Documents: count})
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	bin, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeTarFile(tw, backupManifestFile, int64(len(bin)), strings.NewReader(string(bin)), manifest.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	for _, file := range schema.Files {
+		if err := writeTarFile(tw, backupSchemaDir+file.Filename, int64(len(file.Content)), strings.NewReader(file.Content), manifest.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, f := range data {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if err := writeTarFile(tw, backupDataDir+names[i]+backupDataExt, info.Size(), f, manifest.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func (c *Client) collectionNames(opts []QueryOptFn) ([]string, error) {
+	q, err := FQL(`Collection.all().map(.name)`, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0)
+	for it := c.Paginate(q, opts...); it.HasNext(); {
+		page, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var pageNames []string
+		if err := page.Unmarshal(&pageNames); err != nil {
+			return nil, err
+		}
+		names = append(names, pageNames...)
+	}
+
+	return names, nil
+}
+
+// backupCollection writes each document in collection to w as a line of
+// tagged JSON, returning the number written. Documents are copied from the
+// raw responses rather than decoded, so that tags without a Go equivalent,
+// such as `@date`, are kept.
+func (c *Client) backupCollection(collection string, w io.Writer, opts []QueryOptFn) (int, error) {
+	q, err := FQL(`${coll}.all()`, map[string]any{"coll": &Module{collection}})
+	if err != nil {
+		return 0, err
+	}
+
+	bw := bufio.NewWriter(w)
+	count := 0
+	for q != nil {
+		page, err := c.rawPage(q, opts)
+		if err != nil {
+			return count, err
+		}
+
+		for _, item := range page.Data {
+			var doc struct {
+				Fields map[string]json.RawMessage `json:"@doc"`
+			}
+			if err := json.Unmarshal(item, &doc); err != nil || doc.Fields == nil {
+				return count, fmt.Errorf("expected a document, got %s", item)
+			}
+			delete(doc.Fields, "coll")
+			delete(doc.Fields, "ts")
+
+			bin, err := json.Marshal(doc.Fields)
+			if err != nil {
+				return count, err
+			}
+			if _, err := bw.Write(append(bin, '\n')); err != nil {
+				return count, err
+			}
+			count++
+		}
+
+		q = nil
+		if page.After != "" {
+			if q, err = FQL(`Set.paginate(${after})`, map[string]any{"after": page.After}); err != nil {
+				return count, err
+			}
+		}
+	}
+
+	return count, bw.Flush()
+}
+
+// rawPage is a page of a set, with its items as tagged JSON.
+type rawPage struct {
+	Data  []json.RawMessage `json:"data"`
+	After string            `json:"after"`
+}
+
+func (c *Client) rawPage(q *Query, opts []QueryOptFn) (*rawPage, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if _, err := c.QueryRaw(q, buf, opts...); err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	var set struct {
+		Page *rawPage `json:"@set"`
+	}
+	if err := json.Unmarshal(res.Data, &set); err == nil && set.Page != nil {
+		return set.Page, nil
+	}
+
+	var page rawPage
+	if err := json.Unmarshal(res.Data, &page); err != nil || page.Data == nil {
+		return nil, fmt.Errorf("expected a set, got %s", res.Data)
+	}
+
+	return &page, nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, size int64, r io.Reader, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: size, ModTime: modTime}); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	if _, err := io.Copy(tw, r); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// RestoreOptFn configuration options for [Client.Restore]
+type RestoreOptFn func(*restoreOptions)
+
+type restoreOptions struct {
+	skipSchema bool
+	schemaOpts []SchemaPushOptFn
+	bulkOpts   []BulkWriterConfigFn
+	queryOpts  []QueryOptFn
+}
+
+// RestoreSkipSchema restores only documents, leaving the schema unchanged.
+func RestoreSkipSchema() RestoreOptFn {
+	return func(o *restoreOptions) { o.skipSchema = true }
+}
+
+// RestoreSchemaOptions sets options on the push of the backed up schema, such
+// as [fauna.SchemaForce].
+func RestoreSchemaOptions(opts ...SchemaPushOptFn) RestoreOptFn {
+	return func(o *restoreOptions) { o.schemaOpts = opts }
+}
+
+// RestoreBulkOptions configures the [fauna.BulkWriter] documents are written
+// with.
+func RestoreBulkOptions(opts ...BulkWriterConfigFn) RestoreOptFn {
+	return func(o *restoreOptions) { o.bulkOpts = opts }
+}
+
+// RestoreQueryOptions sets options on the queries writing documents. A
+// context set with [fauna.QueryContext] is also used to push the schema.
+func RestoreQueryOptions(opts ...QueryOptFn) RestoreOptFn {
+	return func(o *restoreOptions) { o.queryOpts = opts }
+}
+
+// RestoreSummary reports the outcome of a [Client.Restore].
+type RestoreSummary struct {
+	// Manifest is the restored archive's manifest.
+	Manifest BackupManifest
+
+	// SchemaVersion is the database's schema version after the schema was
+	// pushed, or zero if it wasn't.
+	SchemaVersion int64
+
+	// Collections reports the documents written to each collection.
+	Collections map[string]*ImportSummary
+}
+
+// Restore pushes the schema in an archive written by [Client.Backup] to the
+// database the [fauna.Client] is connected to, replacing its schema, and then
+// creates the archived documents with their original IDs. Documents are
+// written as with [Client.ImportCollection], so a failed chunk doesn't stop
+// the restore, and an error is returned alongside the [fauna.RestoreSummary]
+// once every collection has been attempted.
+func (c *Client) Restore(r io.Reader, opts ...RestoreOptFn) (*RestoreSummary, error) {
+	var o restoreOptions
+	for _, optFn := range opts {
+		optFn(&o)
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != backupManifestFile {
+		return nil, fmt.Errorf("failed to read backup: missing %s", backupManifestFile)
+	}
+
+	summary := &RestoreSummary{Collections: map[string]*ImportSummary{}}
+	if err := json.NewDecoder(tr).Decode(&summary.Manifest); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", backupManifestFile, err)
+	}
+	if summary.Manifest.FormatVersion != BackupFormatVersion {
+		return nil, fmt.Errorf("unsupported backup format version: %d", summary.Manifest.FormatVersion)
+	}
+
+	var (
+		files      []SchemaFile
+		pushed     bool
+		failed     []string
+		importOpts = importOptions{preserveIDs: true, bulkOpts: o.bulkOpts, queryOpts: o.queryOpts}
+	)
+	pushSchema := func() error {
+		if pushed || o.skipSchema || len(files) == 0 {
+			pushed = true
+			return nil
+		}
+		pushed = true
+
+		version, err := c.PushSchema(queryContext(o.queryOpts), files, o.schemaOpts...)
+		if err != nil {
+			return fmt.Errorf("failed to push schema: %w", err)
+		}
+		summary.SchemaVersion = version
+		return nil
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return summary, fmt.Errorf("failed to read backup: %w", err)
+		}
+
+		switch {
+		case strings.HasPrefix(hdr.Name, backupSchemaDir):
+			content, err := io.ReadAll(tr)
+			if err != nil {
+				return summary, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+			}
+			files = append(files, SchemaFile{Filename: strings.TrimPrefix(hdr.Name, backupSchemaDir), Content: string(content)})
+
+		case strings.HasPrefix(hdr.Name, backupDataDir) && strings.HasSuffix(hdr.Name, backupDataExt):
+			if err := pushSchema(); err != nil {
+				return summary, err
+			}
+
+			name := strings.TrimSuffix(path.Base(hdr.Name), backupDataExt)
+			res, err := c.importDocuments(name, tr, decodeTaggedLine, importOpts)
+			summary.Collections[name] = res
+			if err != nil {
+				failed = append(failed, name)
+			}
+		}
+	}
+
+	if err := pushSchema(); err != nil {
+		return summary, err
+	}
+
+	if len(failed) > 0 {
+		return summary, fmt.Errorf("failed to restore %d of %d collections: %s",
+			len(failed), len(summary.Collections), strings.Join(failed, ", "))
+	}
+
+	return summary, nil
+}
+
+// taggedJSON is a value already in Fauna's tagged format, which is encoded
+// as is.
+type taggedJSON json.RawMessage
+
+// decodeTaggedLine decodes a line written by [Client.Backup], keeping each
+// field as tagged JSON apart from the document's id.
+func decodeTaggedLine(bin []byte) (map[string]any, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(bin, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+
+	doc := make(map[string]any, len(fields))
+	for k, v := range fields {
+		var id string
+		if k == "id" && json.Unmarshal(v, &id) == nil {
+			doc[k] = id
+			continue
+		}
+		doc[k] = taggedJSON(v)
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,134 @@
+package fauna
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	const (
+		scoutDoc = `{"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"},"name":"Scout","born":{"@date":"2020-01-02"},"owner":{"@ref":{"id":"7","coll":{"@mod":"People"}}}}}`
+		annaDoc  = `{"@doc":{"id":"7","coll":{"@mod":"People"},"ts":{"@time":"2023-04-01T00:00:00Z"},"name":"Anna","weight":{"@double":"61.5"}}}`
+	)
+
+	source := newTestServer(t, func(req testRequest) (int, string) {
+		switch req.URL.Path {
+		case "/schema/1/files":
+			return http.StatusOK, `{"version":100,"files":[{"filename":"main.fsl"}]}`
+		case "/schema/1/files/main.fsl":
+			return http.StatusOK, `{"version":100,"content":"collection Dogs {}\ncollection People {}"}`
+		}
+
+		switch {
+		case bytes.Contains(req.Raw, []byte("Collection.all()")):
+			return http.StatusOK, successBody(`{"@set":{"data":["Dogs","People"]}}`)
+		case bytes.Contains(req.Raw, []byte(`"Dogs"`)):
+			return http.StatusOK, successBody(`{"@set":{"data":[` + scoutDoc + `]}}`)
+		case bytes.Contains(req.Raw, []byte(`"People"`)):
+			return http.StatusOK, successBody(`{"@set":{"data":[` + annaDoc + `]}}`)
+		}
+		return http.StatusNotFound, errorBody("not_found", req.URL.Path)
+	})
+
+	var archive bytes.Buffer
+	manifest, err := source.client().Backup(&archive)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, int64(100), manifest.SchemaVersion)
+	assert.Equal(t, []BackupCollection{{"Dogs", 1}, {"People", 1}}, manifest.Collections)
+
+	t.Run("archive layout", func(t *testing.T) {
+		gz, err := gzip.NewReader(bytes.NewReader(archive.Bytes()))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		files := map[string]string{}
+		var names []string
+		tr := tar.NewReader(gz)
+		for hdr, err := tr.Next(); err == nil; hdr, err = tr.Next() {
+			content, _ := io.ReadAll(tr)
+			names = append(names, hdr.Name)
+			files[hdr.Name] = string(content)
+		}
+
+		assert.Equal(t, []string{"manifest.json", "schema/main.fsl", "collections/Dogs.jsonl", "collections/People.jsonl"}, names)
+		assert.Equal(t, "collection Dogs {}\ncollection People {}", files["schema/main.fsl"])
+		assert.Equal(t, `{"born":{"@date":"2020-01-02"},"id":"1","name":"Scout","owner":{"@ref":{"id":"7","coll":{"@mod":"People"}}}}`+"\n", files["collections/Dogs.jsonl"])
+	})
+
+	var (
+		pushed  string
+		written = map[string][]any{}
+	)
+	target := newTestServer(t, func(req testRequest) (int, string) {
+		switch req.URL.Path {
+		case "/schema/1/files":
+			return http.StatusOK, `{"version":5,"files":[]}`
+		case "/schema/1/update":
+			_, params, _ := mime.ParseMediaType(req.Header.Get(headerContentType))
+			form, err := multipart.NewReader(bytes.NewReader(req.Raw), params["boundary"]).ReadForm(1 << 20)
+			if assert.NoError(t, err) {
+				pushed = form.Value["main.fsl"][0]
+			}
+			return http.StatusOK, `{"version":6}`
+		}
+
+		fql := req.Body["query"].(map[string]any)["fql"].([]any)
+		ops := fql[0].(map[string]any)["value"].([]any)
+		coll := fql[4].(map[string]any)["value"].(map[string]any)["@mod"].(string)
+
+		ids := make([]string, len(ops))
+		for i, op := range ops {
+			data := op.(map[string]any)["data"].(map[string]any)
+			if data["name"] == "Anna" {
+				return http.StatusBadRequest, errorBody("constraint_failure", "duplicate")
+			}
+			written[coll] = append(written[coll], data)
+			ids[i] = fmt.Sprintf("%q", data["id"])
+		}
+		return http.StatusOK, successBody("[" + strings.Join(ids, ",") + "]")
+	})
+
+	summary, err := target.client(MaxBackoff(time.Millisecond)).Restore(bytes.NewReader(archive.Bytes()))
+	assert.ErrorContains(t, err, "failed to restore 1 of 2 collections: People")
+	if !assert.NotNil(t, summary) {
+		return
+	}
+
+	assert.Equal(t, *manifest, summary.Manifest)
+	assert.Equal(t, int64(6), summary.SchemaVersion)
+	assert.Equal(t, "collection Dogs {}\ncollection People {}", pushed)
+	assert.Equal(t, []string{"1"}, summary.Collections["Dogs"].IDs)
+	assert.Equal(t, 1, summary.Collections["People"].Failed)
+	assert.Equal(t, []any{map[string]any{
+		"id":    "1",
+		"name":  "Scout",
+		"born":  map[string]any{"@date": "2020-01-02"},
+		"owner": map[string]any{"@ref": map[string]any{"id": "7", "coll": map[string]any{"@mod": "People"}}},
+	}}, written["Dogs"], "documents keep their ids and types")
+
+	t.Run("skip schema", func(t *testing.T) {
+		pushed = ""
+		summary, _ := target.client().Restore(bytes.NewReader(archive.Bytes()), RestoreSkipSchema())
+		assert.Empty(t, pushed)
+		assert.Zero(t, summary.SchemaVersion)
+	})
+
+	t.Run("invalid archives", func(t *testing.T) {
+		_, err := target.client().Restore(strings.NewReader("not a backup"))
+		assert.ErrorContains(t, err, "failed to read backup")
+	})
+}
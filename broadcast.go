@@ -0,0 +1,243 @@
+package fauna
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// SlowConsumerPolicy is what a [fauna.Broadcaster] does when a subscriber's
+// buffer is full.
+type SlowConsumerPolicy int
+
+const (
+	// SlowConsumerDrop drops the event for that subscriber, counting it in
+	// [Subscription.Dropped].
+	SlowConsumerDrop SlowConsumerPolicy = iota
+
+	// SlowConsumerBlock waits for the subscriber to make room, which holds up
+	// every other subscriber too.
+	SlowConsumerBlock
+
+	// SlowConsumerDisconnect ends the subscription with
+	// [fauna.ErrSlowConsumer].
+	SlowConsumerDisconnect
+)
+
+// ErrSlowConsumer ends a [fauna.Subscription] with [fauna.SlowConsumerDisconnect]
+// whose buffer filled up.
+var ErrSlowConsumer = errors.New("subscriber fell behind the stream")
+
+// ErrBroadcasterClosed ends the subscriptions of a [fauna.Broadcaster] which
+// was closed.
+var ErrBroadcasterClosed = errors.New("broadcaster closed")
+
+// Broadcaster reads the events of a [fauna.Stream] and fans them out to any
+// number of in-process subscribers, each with its own buffer and
+// [fauna.SlowConsumerPolicy]. Subscribers share each [fauna.Event], which must
+// not be modified.
+type Broadcaster struct {
+	stream *Stream
+
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	closed bool
+	err    error
+}
+
+// NewBroadcaster creates a [fauna.Broadcaster] of stream's events. Events are
+// only read once [Broadcaster.Run] is called.
+func NewBroadcaster(stream *Stream) *Broadcaster {
+	return &Broadcaster{stream: stream, subs: map[*Subscription]struct{}{}}
+}
+
+// Subscription receives the events of a [fauna.Broadcaster].
+type Subscription struct {
+	events chan *Event
+	done   chan struct{}
+	policy SlowConsumerPolicy
+	b      *Broadcaster
+
+	// sendMu is held while sending, so events is only closed between sends
+	sendMu sync.Mutex
+	once   sync.Once
+
+	mu      sync.Mutex
+	err     error
+	dropped int
+}
+
+// Subscribe adds a subscriber which buffers up to buffer events, handling a
+// full buffer according to policy. Subscribing to a finished broadcaster
+// returns a subscription which is already closed.
+func (b *Broadcaster) Subscribe(buffer int, policy SlowConsumerPolicy) *Subscription {
+	sub := &Subscription{
+		events: make(chan *Event, buffer),
+		done:   make(chan struct{}),
+		policy: policy,
+		b:      b,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		sub.end(b.err)
+		return sub
+	}
+	b.subs[sub] = struct{}{}
+
+	return sub
+}
+
+// Run reads events from the stream and delivers them to subscribers until the
+// stream ends, then closes every subscription and returns the stream's error,
+// which is nil if the broadcaster or stream was closed.
+func (b *Broadcaster) Run() error {
+	for {
+		event, err := b.stream.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			b.finish(err)
+			return err
+		}
+
+		b.mu.Lock()
+		subs := make([]*Subscription, 0, len(b.subs))
+		for sub := range b.subs {
+			subs = append(subs, sub)
+		}
+		b.mu.Unlock()
+
+		for _, sub := range subs {
+			if !sub.deliver(event) {
+				b.remove(sub)
+				sub.end(ErrSlowConsumer)
+			}
+		}
+	}
+}
+
+// Close closes the stream, ending [Broadcaster.Run] and every subscription
+// with [fauna.ErrBroadcasterClosed].
+func (b *Broadcaster) Close() error {
+	b.mu.Lock()
+	if b.err == nil {
+		b.err = ErrBroadcasterClosed
+	}
+	b.mu.Unlock()
+
+	return b.stream.Close()
+}
+
+func (b *Broadcaster) finish(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.err == nil {
+		b.err = err
+	}
+	if b.err == nil {
+		b.err = io.EOF
+	}
+	b.closed = true
+
+	for sub := range b.subs {
+		sub.end(b.err)
+	}
+	b.subs = nil
+}
+
+// deliver sends event to the subscriber, reporting false if it should be
+// disconnected for falling behind.
+func (sub *Subscription) deliver(event *Event) bool {
+	sub.sendMu.Lock()
+	defer sub.sendMu.Unlock()
+
+	select {
+	case <-sub.done:
+		return true
+	default:
+	}
+
+	select {
+	case sub.events <- event:
+		return true
+	default:
+	}
+
+	switch sub.policy {
+	case SlowConsumerBlock:
+		select {
+		case <-sub.done:
+		case sub.events <- event:
+		}
+
+	case SlowConsumerDisconnect:
+		return false
+
+	default:
+		sub.mu.Lock()
+		sub.dropped++
+		sub.mu.Unlock()
+	}
+
+	return true
+}
+
+func (b *Broadcaster) remove(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subs, sub)
+}
+
+// end closes the subscription, recording why.
+func (sub *Subscription) end(err error) {
+	sub.once.Do(func() {
+		sub.mu.Lock()
+		sub.err = err
+		sub.mu.Unlock()
+
+		// closing done first unblocks a send waiting for room
+		close(sub.done)
+
+		sub.sendMu.Lock()
+		close(sub.events)
+		sub.sendMu.Unlock()
+	})
+}
+
+// Events returns the channel the subscription's events are delivered on,
+// which is closed when the subscription ends.
+func (sub *Subscription) Events() <-chan *Event {
+	return sub.events
+}
+
+// Err returns why the subscription ended: [fauna.ErrSlowConsumer],
+// [fauna.ErrBroadcasterClosed], the stream's error, or [io.EOF] if the stream
+// was closed. It returns nil while the subscription is active or if it was
+// closed with [Subscription.Close].
+func (sub *Subscription) Err() error {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	return sub.err
+}
+
+// Dropped returns the number of events dropped for the subscriber with
+// [fauna.SlowConsumerDrop].
+func (sub *Subscription) Dropped() int {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	return sub.dropped
+}
+
+// Close ends the subscription. Events already buffered can still be received.
+func (sub *Subscription) Close() {
+	sub.b.remove(sub)
+	sub.end(nil)
+}
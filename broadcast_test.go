@@ -0,0 +1,90 @@
+package fauna
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBroadcaster(t *testing.T) *Broadcaster {
+	srv := newTestStreamServer(t, []string{
+		`{"type":"start","txn_ts":10}`,
+		`{"type":"add","txn_ts":11,"cursor":"c1","data":{"@int":"1"}}`,
+		`{"type":"add","txn_ts":12,"cursor":"c2","data":{"@int":"2"}}`,
+		`{"type":"add","txn_ts":13,"cursor":"c3","data":{"@int":"3"}}`,
+	})
+
+	stream, err := NewClient("secret", DefaultTimeouts(), URL(srv.URL)).Stream(context.Background(), "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewBroadcaster(stream)
+}
+
+func receive(t *testing.T, sub *Subscription, n int) []string {
+	var cursors []string
+	for i := 0; i < n; i++ {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return cursors
+			}
+			cursors = append(cursors, event.Cursor)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for an event")
+		}
+	}
+
+	return cursors
+}
+
+func TestBroadcaster(t *testing.T) {
+	b := newTestBroadcaster(t)
+
+	fast := b.Subscribe(10, SlowConsumerDrop)
+	dropping := b.Subscribe(1, SlowConsumerDrop)
+	disconnecting := b.Subscribe(1, SlowConsumerDisconnect)
+	closed := b.Subscribe(1, SlowConsumerDrop)
+	closed.Close()
+
+	done := make(chan error)
+	go func() { done <- b.Run() }()
+
+	assert.Equal(t, []string{"c1", "c2", "c3"}, receive(t, fast, 3))
+	assert.NoError(t, b.Close())
+	assert.NoError(t, <-done)
+
+	assert.Equal(t, []string{"c1"}, receive(t, dropping, 2))
+	assert.Equal(t, 2, dropping.Dropped())
+	assert.ErrorIs(t, dropping.Err(), ErrBroadcasterClosed)
+
+	assert.Equal(t, []string{"c1"}, receive(t, disconnecting, 2))
+	assert.ErrorIs(t, disconnecting.Err(), ErrSlowConsumer)
+
+	assert.Empty(t, receive(t, closed, 1))
+	assert.NoError(t, closed.Err())
+
+	late := b.Subscribe(1, SlowConsumerDrop)
+	assert.Empty(t, receive(t, late, 1), "subscriptions to a finished broadcaster are closed")
+	assert.ErrorIs(t, late.Err(), ErrBroadcasterClosed)
+}
+
+func TestBroadcasterBlock(t *testing.T) {
+	b := newTestBroadcaster(t)
+	blocking := b.Subscribe(0, SlowConsumerBlock)
+
+	done := make(chan error)
+	go func() { done <- b.Run() }()
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, []string{"c1", "c2", "c3"}, receive(t, blocking, 3), "no events are dropped")
+
+	assert.NoError(t, b.stream.Close())
+	assert.NoError(t, <-done)
+	assert.Empty(t, receive(t, blocking, 1))
+	assert.ErrorIs(t, blocking.Err(), io.EOF)
+}
@@ -0,0 +1,287 @@
+package fauna
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	bulkChunkSizeDefault     = 100
+	bulkMaxChunkBytesDefault = 4 * 1024 * 1024
+	bulkChunkAttemptsDefault = 3
+)
+
+// BulkWriterConfigFn configuration options for the [fauna.BulkWriter]
+type BulkWriterConfigFn func(*BulkWriter)
+
+// BulkChunkSize sets the maximum number of documents the [fauna.BulkWriter]
+// writes in a single transaction. The default is 100.
+func BulkChunkSize(size int) BulkWriterConfigFn {
+	return func(w *BulkWriter) { w.chunkSize = size }
+}
+
+// BulkMaxChunkBytes sets the approximate maximum encoded size of a single
+// [fauna.BulkWriter] transaction. The default is 4MiB.
+func BulkMaxChunkBytes(size int) BulkWriterConfigFn {
+	return func(w *BulkWriter) { w.maxChunkBytes = size }
+}
+
+// BulkChunkAttempts sets the maximum number of times the [fauna.BulkWriter]
+// will attempt a chunk which was throttled or failed with contention, which
+// Fauna rejects without writing it. Chunks which time out or fail with an
+// internal error aren't retried, as they may have been written. The default
+// is 3.
+func BulkChunkAttempts(attempts int) BulkWriterConfigFn {
+	return func(w *BulkWriter) { w.chunkAttempts = attempts }
+}
+
+// BulkWriter batches document creates and updates for a collection into
+// appropriately sized transactions. Writes are queued with
+// [fauna.BulkWriter.Create] and [fauna.BulkWriter.Update] and sent by
+// [fauna.BulkWriter.Flush].
+//
+// Each chunk is a single transaction, so a chunk which fails writes none of
+// its documents. Other chunks are unaffected and still written.
+type BulkWriter struct {
+	client        *Client
+	coll          *Module
+	chunkSize     int
+	maxChunkBytes int
+	chunkAttempts int
+
+	mu      sync.Mutex
+	pending []bulkOp
+}
+
+type bulkOp struct {
+	id   string
+	data any
+	size int
+}
+
+type bulkChunk struct {
+	offset int
+	ops    []bulkOp
+}
+
+// BulkSummary reports the outcome of a [fauna.BulkWriter.Flush].
+type BulkSummary struct {
+	// Written is the number of documents written.
+	Written int
+
+	// Failed is the number of documents which weren't written.
+	Failed int
+
+	// Chunks is the number of transactions the writes were split into.
+	Chunks int
+
+	// IDs are the ids of the written documents, in the order they were queued.
+	// Documents in failed chunks are omitted.
+	IDs []string
+
+	// Errors describes each chunk which failed.
+	Errors []*ErrBulkChunk
+
+	// Duration is how long the flush took.
+	Duration time.Duration
+}
+
+// An ErrBulkChunk is reported when a chunk of a [fauna.BulkWriter.Flush]
+// could not be written.
+type ErrBulkChunk struct {
+	// Offset is the position of the chunk's first document in the flushed writes.
+	Offset int
+
+	// Count is the number of documents in the chunk.
+	Count int
+
+	// Attempts is the number of times the chunk was attempted.
+	Attempts int
+
+	// Err is the error from the last attempt.
+	Err error
+}
+
+// Error describes the failed chunk.
+func (e *ErrBulkChunk) Error() string {
+	return fmt.Sprintf("bulk write of documents %d-%d failed after %d attempts: %s",
+		e.Offset, e.Offset+e.Count-1, e.Attempts, e.Err)
+}
+
+// Unwrap returns the error from the last attempt.
+func (e *ErrBulkChunk) Unwrap() error {
+	return e.Err
+}
+
+// NewBulkWriter creates a [fauna.BulkWriter] for the named collection.
+func NewBulkWriter(client *Client, collection string, configFns ...BulkWriterConfigFn) *BulkWriter {
+	w := &BulkWriter{
+		client:        client,
+		coll:          &Module{Name: collection},
+		chunkSize:     bulkChunkSizeDefault,
+		maxChunkBytes: bulkMaxChunkBytesDefault,
+		chunkAttempts: bulkChunkAttemptsDefault,
+	}
+
+	for _, configFn := range configFns {
+		configFn(w)
+	}
+
+	return w
+}
+
+// Create queues documents to be created.
+func (w *BulkWriter) Create(docs ...any) error {
+	return w.queue("", docs...)
+}
+
+// Update queues a patch to the document with the given id.
+func (w *BulkWriter) Update(id string, patch any) error {
+	if id == "" {
+		return fmt.Errorf("update requires a document id")
+	}
+
+	return w.queue(id, patch)
+}
+
+// Pending returns the number of queued writes.
+func (w *BulkWriter) Pending() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.pending)
+}
+
+func (w *BulkWriter) queue(id string, docs ...any) error {
+	ops := make([]bulkOp, len(docs))
+	for i, doc := range docs {
+		bin, err := w.client.codec.marshal(doc)
+		if err != nil {
+			return fmt.Errorf("failed to encode document: %w", err)
+		}
+
+		ops[i] = bulkOp{id: id, data: doc, size: len(bin)}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending = append(w.pending, ops...)
+
+	return nil
+}
+
+// Flush writes all queued documents, optionally setting multiple [QueryOptFn]
+// on each chunk's query. Flush always returns a [fauna.BulkSummary], and an
+// error if any chunk failed.
+func (w *BulkWriter) Flush(opts ...QueryOptFn) (*BulkSummary, error) {
+	start := time.Now()
+
+	w.mu.Lock()
+	ops := w.pending
+	w.pending = nil
+	w.mu.Unlock()
+
+	summary := &BulkSummary{IDs: make([]string, 0, len(ops))}
+	for _, chunk := range w.chunks(ops) {
+		summary.Chunks++
+
+		ids, attempts, err := w.writeChunk(chunk.ops, opts)
+		if err != nil {
+			summary.Failed += len(chunk.ops)
+			summary.Errors = append(summary.Errors, &ErrBulkChunk{
+				Offset:   chunk.offset,
+				Count:    len(chunk.ops),
+				Attempts: attempts,
+				Err:      err,
+			})
+			continue
+		}
+
+		summary.Written += len(ids)
+		summary.IDs = append(summary.IDs, ids...)
+	}
+	summary.Duration = time.Since(start)
+
+	if len(summary.Errors) > 0 {
+		return summary, fmt.Errorf("%d of %d bulk write chunks failed: %w",
+			len(summary.Errors), summary.Chunks, summary.Errors[0])
+	}
+
+	return summary, nil
+}
+
+// chunks splits ops by count and encoded size.
+func (w *BulkWriter) chunks(ops []bulkOp) []bulkChunk {
+	chunks := make([]bulkChunk, 0)
+
+	start, size := 0, 0
+	for i, op := range ops {
+		if i > start && (i-start >= w.chunkSize || size+op.size > w.maxChunkBytes) {
+			chunks = append(chunks, bulkChunk{start, ops[start:i]})
+			start, size = i, 0
+		}
+		size += op.size
+	}
+
+	if start < len(ops) {
+		chunks = append(chunks, bulkChunk{start, ops[start:]})
+	}
+
+	return chunks
+}
+
+func (w *BulkWriter) writeChunk(chunk []bulkOp, opts []QueryOptFn) (ids []string, attempts int, err error) {
+	args := make([]map[string]any, len(chunk))
+	for i, op := range chunk {
+		args[i] = map[string]any{"data": op.data}
+		if op.id != "" {
+			args[i]["id"] = op.id
+		}
+	}
+
+	q, err := FQL(
+		"${ops}.map(op => if (op.id != null) ${coll}.byId(op.id)!.update(op.data) else ${coll}.create(op.data)).map(.id)",
+		map[string]any{"ops": args, "coll": w.coll},
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ctx := queryContext(opts)
+	for attempts = 1; ; attempts++ {
+		res, queryErr := w.client.Query(q, opts...)
+		if queryErr == nil {
+			if decodeErr := res.Unmarshal(&ids); decodeErr != nil {
+				return nil, attempts, decodeErr
+			}
+			return ids, attempts, nil
+		}
+
+		if attempts >= w.chunkAttempts || !bulkRetryable(queryErr) {
+			return nil, attempts, queryErr
+		}
+
+		timer := time.NewTimer(w.client.backoff(attempts))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, attempts, ctx.Err()
+		}
+	}
+}
+
+// bulkRetryable reports whether a chunk which failed with err certainly
+// wasn't written, so retrying it can't create documents twice. Timeouts and
+// internal errors aren't retried, as the transaction may have committed.
+func bulkRetryable(err error) bool {
+	var (
+		throttling *ErrThrottling
+		contention *ErrContendedTransaction
+	)
+
+	return errors.As(err, &throttling) || errors.As(err, &contention)
+}
@@ -0,0 +1,145 @@
+package fauna
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkWriter(t *testing.T) {
+	var calls int32
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		call := atomic.AddInt32(&calls, 1)
+
+		fql := req.Body["query"].(map[string]any)["fql"].([]any)
+		ops := fql[0].(map[string]any)["value"].([]any)
+
+		ids := make([]string, len(ops))
+		for i, op := range ops {
+			data := op.(map[string]any)["data"].(map[string]any)
+			if data["name"] == "broken" {
+				return http.StatusBadRequest, errorBody("constraint_failure", "broken")
+			}
+			if data["name"] == "contended" && call == 1 {
+				return http.StatusConflict, errorBody("contended_transaction", "contended")
+			}
+			if data["name"] == "timeout" && call == 1 {
+				return http.StatusServiceUnavailable, errorBody("time_out", "timed out")
+			}
+
+			ids[i] = fmt.Sprintf(`"%v"`, data["name"])
+		}
+
+		return http.StatusOK, successBody("[" + strings.Join(ids, ",") + "]")
+	})
+	client := srv.client(MaxBackoff(time.Millisecond))
+
+	t.Run("chunks by count", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		w := NewBulkWriter(client, "Dinos", BulkChunkSize(2))
+		for i := 0; i < 5; i++ {
+			assert.NoError(t, w.Create(map[string]any{"name": fmt.Sprintf("dino%d", i)}))
+		}
+		assert.NoError(t, w.Update("123", map[string]any{"name": "dino5"}))
+		assert.Equal(t, 6, w.Pending())
+
+		summary, err := w.Flush()
+		if assert.NoError(t, err) {
+			assert.Equal(t, 3, summary.Chunks)
+			assert.Equal(t, 6, summary.Written)
+			assert.Equal(t, []string{"dino0", "dino1", "dino2", "dino3", "dino4", "dino5"}, summary.IDs)
+			assert.Zero(t, w.Pending())
+		}
+	})
+
+	t.Run("chunks by size", func(t *testing.T) {
+		w := NewBulkWriter(client, "Dinos", BulkMaxChunkBytes(40))
+		for i := 0; i < 4; i++ {
+			assert.NoError(t, w.Create(map[string]any{"name": fmt.Sprintf("dino%d", i)}))
+		}
+
+		summary, err := w.Flush()
+		if assert.NoError(t, err) {
+			assert.Equal(t, 2, summary.Chunks)
+			assert.Equal(t, 4, summary.Written)
+		}
+	})
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		w := NewBulkWriter(client, "Dinos")
+		assert.NoError(t, w.Create(map[string]any{"name": "contended"}))
+
+		summary, err := w.Flush()
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, summary.Written)
+			assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+		}
+	})
+
+	t.Run("doesn't retry ambiguous failures", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		w := NewBulkWriter(client, "Dinos")
+		assert.NoError(t, w.Create(map[string]any{"name": "timeout"}))
+
+		summary, err := w.Flush()
+		assert.ErrorAs(t, err, new(*ErrServiceTimeout))
+		assert.Equal(t, 1, summary.Failed)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("reports partial failures", func(t *testing.T) {
+		w := NewBulkWriter(client, "Dinos", BulkChunkSize(2))
+		assert.NoError(t, w.Create(
+			map[string]any{"name": "dino0"},
+			map[string]any{"name": "dino1"},
+			map[string]any{"name": "broken"},
+			map[string]any{"name": "dino3"},
+			map[string]any{"name": "dino4"},
+		))
+
+		summary, err := w.Flush()
+		assert.Error(t, err)
+		assert.Equal(t, 3, summary.Written)
+		assert.Equal(t, 2, summary.Failed)
+		assert.Equal(t, []string{"dino0", "dino1", "dino4"}, summary.IDs)
+		if assert.Len(t, summary.Errors, 1) {
+			assert.Equal(t, 2, summary.Errors[0].Offset)
+			assert.Equal(t, 2, summary.Errors[0].Count)
+			assert.Equal(t, 1, summary.Errors[0].Attempts)
+
+			var runtimeErr *ErrQueryRuntime
+			assert.ErrorAs(t, err, &runtimeErr)
+		}
+	})
+
+	t.Run("update requires an id", func(t *testing.T) {
+		assert.Error(t, NewBulkWriter(client, "Dinos").Update("", map[string]any{}))
+	})
+}
+
+func TestBulkWriterBackoffCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		time.AfterFunc(10*time.Millisecond, cancel)
+		return http.StatusConflict, errorBody("contended_transaction", "contended")
+	})
+	client := srv.client(MaxBackoff(time.Minute))
+
+	w := NewBulkWriter(client, "Dinos", BulkChunkAttempts(10))
+	assert.NoError(t, w.Create(map[string]any{"name": "contended"}))
+
+	start := time.Now()
+	summary, err := w.Flush(QueryContext(ctx))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,149 @@
+package fauna
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// QueryCache is an in-memory cache of read query results, shared by the
+// [fauna.Client] instances configured with [fauna.WithQueryCache]. Results are
+// keyed by the encoded query and arguments, the secret, and the headers that
+// affect results, and are only cached if the query made no writes.
+//
+// Cached results can be stale by up to the cache's TTL. Use [fauna.NoCache]
+// on queries that must read the latest data, and [QueryCache.InvalidateTags]
+// or [QueryCache.Purge] after writes the cache should observe.
+type QueryCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+	now        func() time.Time
+}
+
+type cacheEntry struct {
+	key     string
+	res     *queryResponse
+	tags    []string
+	expires time.Time
+}
+
+// NewQueryCache creates a [fauna.QueryCache] holding up to maxEntries results
+// for ttl each. When full, the least recently used result is evicted.
+func NewQueryCache(ttl time.Duration, maxEntries int) *QueryCache {
+	return &QueryCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+		now:        time.Now,
+	}
+}
+
+// WithQueryCache caches the results of read queries run by the
+// [fauna.Client] in cache.
+func WithQueryCache(cache *QueryCache) ClientConfigFn {
+	return func(c *Client) { c.cache = cache }
+}
+
+// NoCache bypasses the [fauna.QueryCache] for a single [Client.Query]. The
+// result is neither read from nor stored in the cache.
+func NoCache() QueryOptFn {
+	return func(req *fqlRequest) { req.NoCache = true }
+}
+
+// CacheTags labels the cached result of a single [Client.Query] so it can be
+// invalidated with [QueryCache.InvalidateTags].
+func CacheTags(tags ...string) QueryOptFn {
+	return func(req *fqlRequest) { req.CacheTags = append(req.CacheTags, tags...) }
+}
+
+// InvalidateTags removes every cached result labelled with any of tags by
+// [fauna.CacheTags].
+func (q *QueryCache) InvalidateTags(tags ...string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	remove := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		remove[tag] = true
+	}
+
+	for el := q.lru.Front(); el != nil; {
+		next := el.Next()
+		for _, tag := range el.Value.(*cacheEntry).tags {
+			if remove[tag] {
+				q.removeElement(el)
+				break
+			}
+		}
+		el = next
+	}
+}
+
+// Purge removes every cached result.
+func (q *QueryCache) Purge() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.entries = map[string]*list.Element{}
+	q.lru.Init()
+}
+
+// Len returns the number of cached results, including any that have expired
+// but not yet been evicted.
+func (q *QueryCache) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.lru.Len()
+}
+
+// get returns a copy of the cached response, safe for the caller to modify.
+func (q *QueryCache) get(key string) (*queryResponse, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	el, found := q.entries[key]
+	if !found {
+		return nil, false
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if !q.now().Before(entry.expires) {
+		q.removeElement(el)
+		return nil, false
+	}
+
+	q.lru.MoveToFront(el)
+
+	return entry.res.clone(), true
+}
+
+func (q *QueryCache) set(key string, res *queryResponse, tags []string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.maxEntries < 1 || q.ttl <= 0 {
+		return
+	}
+
+	entry := &cacheEntry{key: key, res: res.clone(), tags: tags, expires: q.now().Add(q.ttl)}
+	if el, found := q.entries[key]; found {
+		el.Value = entry
+		q.lru.MoveToFront(el)
+		return
+	}
+
+	q.entries[key] = q.lru.PushFront(entry)
+	for q.lru.Len() > q.maxEntries {
+		q.removeElement(q.lru.Back())
+	}
+}
+
+func (q *QueryCache) removeElement(el *list.Element) {
+	q.lru.Remove(el)
+	delete(q.entries, el.Value.(*cacheEntry).key)
+}
@@ -0,0 +1,148 @@
+package fauna
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryCache(t *testing.T) {
+	newCache := func(ttl time.Duration, maxEntries int) (*QueryCache, *time.Time) {
+		now := time.Unix(1680000000, 0)
+		cache := NewQueryCache(ttl, maxEntries)
+		cache.now = func() time.Time { return now }
+		return cache, &now
+	}
+
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		if bytes.Contains(req.Raw, []byte("create")) {
+			return http.StatusOK, `{"data":"written","summary":"","txn_ts":1680000000000000,"stats":{"write_ops":1}}`
+		}
+		return http.StatusOK, successBody(`{"@int":"1"}`)
+	})
+
+	query := func(t *testing.T, client *Client, fql string, opts ...QueryOptFn) *QuerySuccess {
+		q, err := FQL(fql, nil)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		res, err := client.Query(q, opts...)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		return res
+	}
+
+	t.Run("serves reads from the cache", func(t *testing.T) {
+		before := len(srv.received())
+		cache, _ := newCache(time.Minute, 10)
+		client := srv.client(WithQueryCache(cache))
+
+		res := query(t, client, `Dogs.all().count()`)
+		assert.False(t, res.Cached)
+
+		res = query(t, client, `Dogs.all().count()`)
+		assert.True(t, res.Cached)
+		assert.Equal(t, int64(1), res.Data)
+		assert.Equal(t, 1, res.Stats.ComputeOps)
+		assert.Len(t, srv.received(), before+1)
+
+		query(t, client, `Cats.all().count()`)
+		assert.Len(t, srv.received(), before+2)
+		assert.Equal(t, 2, cache.Len())
+	})
+
+	t.Run("shares the cache between clients by secret", func(t *testing.T) {
+		before := len(srv.received())
+		cache, _ := newCache(time.Minute, 10)
+
+		query(t, srv.client(WithQueryCache(cache)), `Dogs.all()`)
+		assert.True(t, query(t, srv.client(WithQueryCache(cache)), `Dogs.all()`).Cached)
+
+		other := NewClient("other", DefaultTimeouts(), URL(srv.URL), WithQueryCache(cache))
+		assert.False(t, query(t, other, `Dogs.all()`).Cached)
+		assert.Len(t, srv.received(), before+2)
+	})
+
+	t.Run("expires results", func(t *testing.T) {
+		cache, now := newCache(time.Minute, 10)
+		client := srv.client(WithQueryCache(cache))
+
+		query(t, client, `Dogs.all()`)
+		*now = now.Add(59 * time.Second)
+		assert.True(t, query(t, client, `Dogs.all()`).Cached)
+
+		*now = now.Add(time.Second)
+		assert.False(t, query(t, client, `Dogs.all()`).Cached)
+	})
+
+	t.Run("evicts the least recently used result", func(t *testing.T) {
+		cache, _ := newCache(time.Minute, 2)
+		client := srv.client(WithQueryCache(cache))
+
+		query(t, client, `1`)
+		query(t, client, `2`)
+		query(t, client, `1`)
+		query(t, client, `3`)
+		assert.Equal(t, 2, cache.Len())
+
+		assert.True(t, query(t, client, `1`).Cached)
+		assert.False(t, query(t, client, `2`).Cached)
+	})
+
+	t.Run("does not cache writes", func(t *testing.T) {
+		cache, _ := newCache(time.Minute, 10)
+		client := srv.client(WithQueryCache(cache))
+
+		q, _ := FQL(`Dogs.create(${dog})`, map[string]any{"dog": map[string]any{"name": "Scout"}})
+		for i := 0; i < 2; i++ {
+			res, err := client.Query(q)
+			assert.NoError(t, err)
+			assert.False(t, res.Cached)
+		}
+		assert.Zero(t, cache.Len())
+	})
+
+	t.Run("NoCache bypasses the cache", func(t *testing.T) {
+		cache, _ := newCache(time.Minute, 10)
+		client := srv.client(WithQueryCache(cache))
+
+		query(t, client, `Dogs.all()`, NoCache())
+		assert.Zero(t, cache.Len())
+
+		query(t, client, `Dogs.all()`)
+		assert.False(t, query(t, client, `Dogs.all()`, NoCache()).Cached)
+	})
+
+	t.Run("invalidates by tag", func(t *testing.T) {
+		cache, _ := newCache(time.Minute, 10)
+		client := srv.client(WithQueryCache(cache))
+
+		query(t, client, `Dogs.all()`, CacheTags("Dogs"))
+		query(t, client, `Cats.all()`, CacheTags("Cats"))
+
+		cache.InvalidateTags("Dogs")
+		assert.Equal(t, 1, cache.Len())
+		assert.False(t, query(t, client, `Dogs.all()`).Cached)
+		assert.True(t, query(t, client, `Cats.all()`).Cached)
+
+		cache.Purge()
+		assert.Zero(t, cache.Len())
+		assert.False(t, query(t, client, `Cats.all()`).Cached)
+	})
+
+	t.Run("ignores trace headers", func(t *testing.T) {
+		cache, _ := newCache(time.Minute, 10)
+		client := srv.client(WithQueryCache(cache))
+
+		query(t, client, `Dogs.all()`, Traceparent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"))
+		assert.True(t, query(t, client, `Dogs.all()`).Cached)
+
+		assert.False(t, query(t, client, `Dogs.all()`, Typecheck(true)).Cached)
+	})
+}
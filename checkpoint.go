@@ -0,0 +1,174 @@
+package fauna
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CheckpointStore persists the cursor of the last event a consumer of a
+// [fauna.Stream] or [fauna.Feed] processed, so the consumer resumes after it
+// when restarted. Cursors are stored by name, so consumers can share a store.
+type CheckpointStore interface {
+	// Load returns the cursor stored for name, or an empty string if there
+	// isn't one.
+	Load(name string) (string, error)
+
+	// Save records cursor for name.
+	Save(name string, cursor string) error
+}
+
+// MemoryCheckpointStore is a [fauna.CheckpointStore] shared by the consumers
+// in a process.
+type MemoryCheckpointStore struct {
+	mu      sync.Mutex
+	cursors map[string]string
+}
+
+// NewMemoryCheckpointStore creates an empty [fauna.MemoryCheckpointStore].
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{cursors: map[string]string{}}
+}
+
+// Load returns the cursor stored for name.
+func (s *MemoryCheckpointStore) Load(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cursors[name], nil
+}
+
+// Save records cursor for name.
+func (s *MemoryCheckpointStore) Save(name string, cursor string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cursors[name] = cursor
+	return nil
+}
+
+// FileCheckpointStore is a [fauna.CheckpointStore] keeping a file for each
+// name in a directory, which is replaced atomically on each save.
+type FileCheckpointStore struct {
+	dir string
+}
+
+// NewFileCheckpointStore creates a [fauna.FileCheckpointStore] in dir, which
+// must exist.
+func NewFileCheckpointStore(dir string) *FileCheckpointStore {
+	return &FileCheckpointStore{dir: dir}
+}
+
+func (s *FileCheckpointStore) path(name string) string {
+	return filepath.Join(s.dir, url.PathEscape(name)+".cursor")
+}
+
+// Load reads the cursor stored for name, returning an empty string if its
+// file doesn't exist.
+func (s *FileCheckpointStore) Load(name string) (string, error) {
+	bin, err := os.ReadFile(s.path(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load checkpoint %s: %w", name, err)
+	}
+
+	return strings.TrimSpace(string(bin)), nil
+}
+
+// Save writes cursor for name.
+func (s *FileCheckpointStore) Save(name string, cursor string) error {
+	path := s.path(name)
+
+	tmp, err := os.CreateTemp(s.dir, filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(cursor); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save checkpoint %s: %w", name, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save checkpoint %s: %w", name, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save checkpoint %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// KVCheckpointStore is a [fauna.CheckpointStore] kept in the database, using
+// the [fauna.Client]'s key-value methods, so consumers on different hosts can
+// share it. Cursors are stored under `checkpoint/<name>`.
+type KVCheckpointStore struct {
+	client *Client
+}
+
+// NewKVCheckpointStore creates a [fauna.KVCheckpointStore] using client, whose
+// key-value collection must exist. See [Client.EnsureKVCollection].
+func NewKVCheckpointStore(client *Client) *KVCheckpointStore {
+	return &KVCheckpointStore{client: client}
+}
+
+// Load returns the cursor stored for name.
+func (s *KVCheckpointStore) Load(name string) (string, error) {
+	var cursor string
+	if _, err := s.client.GetKV("checkpoint/"+name, &cursor); err != nil {
+		return "", fmt.Errorf("failed to load checkpoint %s: %w", name, err)
+	}
+
+	return cursor, nil
+}
+
+// Save records cursor for name.
+func (s *KVCheckpointStore) Save(name string, cursor string) error {
+	if err := s.client.SetKV("checkpoint/"+name, cursor, 0); err != nil {
+		return fmt.Errorf("failed to save checkpoint %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// checkpointer saves a consumer's cursor once the event it follows has been
+// processed.
+type checkpointer struct {
+	store CheckpointStore
+	name  string
+	saved string
+}
+
+// load returns the stored cursor, if there's a store.
+func (c *checkpointer) load() (string, error) {
+	if c == nil {
+		return "", nil
+	}
+
+	cursor, err := c.store.Load(c.name)
+	c.saved = cursor
+	return cursor, err
+}
+
+// save stores cursor, if it's changed since it was last saved.
+func (c *checkpointer) save(cursor string) error {
+	if c == nil || cursor == "" || cursor == c.saved {
+		return nil
+	}
+
+	if err := c.store.Save(c.name, cursor); err != nil {
+		return err
+	}
+	c.saved = cursor
+
+	return nil
+}
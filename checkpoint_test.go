@@ -0,0 +1,140 @@
+package fauna
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckpointStore(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		store := NewMemoryCheckpointStore()
+
+		cursor, err := store.Load("dogs")
+		assert.NoError(t, err)
+		assert.Empty(t, cursor)
+
+		assert.NoError(t, store.Save("dogs", "c1"))
+		assert.NoError(t, store.Save("cats", "c9"))
+
+		cursor, _ = store.Load("dogs")
+		assert.Equal(t, "c1", cursor)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewFileCheckpointStore(dir)
+
+		cursor, err := store.Load("dogs/adds")
+		assert.NoError(t, err)
+		assert.Empty(t, cursor)
+
+		assert.NoError(t, store.Save("dogs/adds", "c1"))
+		assert.NoError(t, store.Save("dogs/adds", "c2"))
+
+		cursor, err = NewFileCheckpointStore(dir).Load("dogs/adds")
+		assert.NoError(t, err)
+		assert.Equal(t, "c2", cursor)
+
+		entries, _ := os.ReadDir(dir)
+		assert.Len(t, entries, 1, "names are escaped and temporary files removed")
+	})
+
+	t.Run("file in missing directory", func(t *testing.T) {
+		store := NewFileCheckpointStore(filepath.Join(t.TempDir(), "missing"))
+		assert.ErrorContains(t, store.Save("dogs", "c1"), "failed to save checkpoint dogs")
+	})
+}
+
+type failingCheckpointStore struct{ err error }
+
+func (s failingCheckpointStore) Load(string) (string, error) { return "", nil }
+func (s failingCheckpointStore) Save(string, string) error   { return s.err }
+
+func TestFeedCheckpoint(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		cursor, _ := req.Body["cursor"].(string)
+		return http.StatusOK, `{"events":[],"cursor":"` + cursor + `x","has_next":false,"stats":{}}`
+	})
+	client := srv.client()
+	store := NewMemoryCheckpointStore()
+	assert.NoError(t, store.Save("dogs", "c"))
+
+	feed := client.Feed("token", FeedCheckpoint(store, "dogs"))
+	_, err := feed.Next(context.Background())
+	assert.NoError(t, err)
+
+	cursor, _ := store.Load("dogs")
+	assert.Equal(t, "c", cursor, "a page isn't checkpointed until it's processed")
+
+	_, err = feed.Next(context.Background())
+	assert.NoError(t, err)
+	cursor, _ = store.Load("dogs")
+	assert.Equal(t, "cx", cursor)
+
+	assert.NoError(t, feed.Checkpoint())
+	cursor, _ = store.Load("dogs")
+	assert.Equal(t, "cxx", cursor)
+
+	_, err = client.Feed("token", FeedCursor("a"), FeedCheckpoint(store, "dogs")).Next(context.Background())
+	assert.NoError(t, err)
+
+	received := srv.received()
+	if assert.Len(t, received, 3) {
+		assert.Equal(t, "c", received[0].Body["cursor"], "the feed resumes from the stored cursor")
+		assert.Equal(t, "cx", received[1].Body["cursor"])
+		assert.Equal(t, "a", received[2].Body["cursor"], "the cursor takes precedence")
+	}
+
+	fail := errors.New("disk full")
+	feed = client.Feed("token", FeedCursor("a"), FeedCheckpoint(failingCheckpointStore{fail}, "dogs"))
+	_, err = feed.Next(context.Background())
+	assert.NoError(t, err)
+	_, err = feed.Next(context.Background())
+	assert.ErrorIs(t, err, fail)
+}
+
+func TestStreamCheckpoint(t *testing.T) {
+	events := []string{
+		`{"type":"start","txn_ts":10}`,
+		`{"type":"add","txn_ts":11,"cursor":"c1","data":{"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"}}}}`,
+		`{"type":"add","txn_ts":12,"cursor":"c2","data":{"@doc":{"id":"2","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"}}}}`,
+	}
+	srv := newTestStreamServer(t, events)
+	client := NewClient("secret", DefaultTimeouts(), URL(srv.URL), MaxBackoff(time.Millisecond))
+	store := NewMemoryCheckpointStore()
+
+	stream, err := client.Stream(context.Background(), "token", StreamCheckpoint(store, "dogs"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer stream.Close()
+
+	_, err = stream.Next()
+	assert.NoError(t, err)
+	cursor, _ := store.Load("dogs")
+	assert.Empty(t, cursor, "an event isn't checkpointed until it's processed")
+
+	_, err = stream.Next()
+	assert.NoError(t, err)
+	cursor, _ = store.Load("dogs")
+	assert.Equal(t, "c1", cursor)
+
+	assert.NoError(t, stream.Checkpoint())
+	cursor, _ = store.Load("dogs")
+	assert.Equal(t, "c2", cursor)
+
+	restarted := newTestStreamServer(t, events)
+	client = NewClient("secret", DefaultTimeouts(), URL(restarted.URL))
+	stream, err = client.Stream(context.Background(), "token", StreamCheckpoint(store, "dogs"))
+	if assert.NoError(t, err) {
+		defer stream.Close()
+		assert.Equal(t, []map[string]any{{"token": "token", "cursor": "c2"}}, restarted.received(), "the stream resumes from the stored cursor")
+	}
+}
@@ -36,11 +36,13 @@ const (
 
 	// Headers consumers might want to use
 
+	HeaderIdempotencyKey       = "Idempotency-Key"
 	HeaderLastTxnTs            = "X-Last-Txn-Ts"
 	HeaderLinearized           = "X-Linearized"
 	HeaderMaxContentionRetries = "X-Max-Contention-Retries"
 	HeaderTags                 = "X-Query-Tags"
 	HeaderQueryTimeoutMs       = "X-Query-Timeout-Ms"
+	HeaderRequestID            = "X-Request-Id"
 	HeaderTraceparent          = "Traceparent"
 	HeaderTypecheck            = "X-Typecheck"
 
@@ -51,24 +53,45 @@ const (
 	headerDriver        = "X-Driver"
 	headerDriverEnv     = "X-Driver-Env"
 	headerFormat        = "X-Format"
+	headerUserAgent     = "User-Agent"
 
 	retryMaxAttemptsDefault = 3
 	retryMaxBackoffDefault  = time.Second * 20
+	pageRetriesDefault      = 3
 )
 
 // Client is the Fauna Client.
 type Client struct {
-	url                 string
-	secret              string
+	endpoint            *atomic.Pointer[endpointConfig]
+	scopedSecret        string
 	headers             map[string]string
-	lastTxnTime         txnTime
+	lastTxnTime         *txnTime
 	typeCheckingEnabled bool
 
 	http *http.Client
-	ctx  context.Context
 
-	maxAttempts int
-	maxBackoff  time.Duration
+	maxAttempts  int
+	maxBackoff   time.Duration
+	pageRetries  int
+	plainSummary bool
+
+	prepared           *preparedQueries
+	kvCollection       string
+	codec              codec
+	cache              *QueryCache
+	coalescing         *flightGroup
+	concurrency        *concurrencyLimiter
+	rate               *rateLimiter
+	txnTimeStore       *txnTimePersister
+	txnTimeStoreErrors func(error)
+	metrics            *clientMetrics
+	stats              *clientStats
+	pool               *connPool
+	inflight           *inflightRequests
+	keepAlive          *keepAlive
+	propagator         Propagator
+	spanContext        SpanContext
+	errorHooks         []ErrorHook
 }
 
 // NewDefaultClient initialize a [fauna.Client] with recommend default settings
@@ -127,13 +150,17 @@ func NewClient(secret string, timeouts Timeouts, configFns ...ClientConfigFn) *C
 		Timeout: timeouts.ConnectionTimeout,
 	}
 
+	pool := &connPool{}
 	httpClient := &http.Client{
-		Transport: &http.Transport{
-			Proxy:             http.ProxyFromEnvironment,
-			DialContext:       dialer.DialContext,
-			ForceAttemptHTTP2: true,
-			MaxIdleConns:      20,
-			IdleConnTimeout:   timeouts.IdleConnectionTimeout,
+		Transport: &poolTransport{
+			base: &http.Transport{
+				Proxy:             http.ProxyFromEnvironment,
+				DialContext:       pool.dialer(dialer.DialContext),
+				ForceAttemptHTTP2: true,
+				MaxIdleConns:      20,
+				IdleConnTimeout:   timeouts.IdleConnectionTimeout,
+			},
+			pool: pool,
 		},
 		Timeout: timeouts.QueryTimeout + timeouts.ClientBufferTimeout,
 	}
@@ -151,20 +178,26 @@ func NewClient(secret string, timeouts Timeouts, configFns ...ClientConfigFn) *C
 		headerFormat: "tagged",
 	}
 
+	endpoint := endpointConfig{url: EndpointDefault, secret: secret}
 	if timeouts.QueryTimeout > 0 {
-		defaultHeaders[HeaderQueryTimeoutMs] = fmt.Sprintf("%v", timeouts.QueryTimeout.Milliseconds())
+		endpoint.queryTimeout = fmt.Sprintf("%v", timeouts.QueryTimeout.Milliseconds())
 	}
 
 	client := &Client{
-		ctx:                 context.TODO(),
-		secret:              secret,
+		endpoint:            newEndpoint(endpoint),
 		http:                httpClient,
-		url:                 EndpointDefault,
 		headers:             defaultHeaders,
-		lastTxnTime:         txnTime{},
+		lastTxnTime:         &txnTime{},
 		typeCheckingEnabled: false,
 		maxAttempts:         retryMaxAttemptsDefault,
 		maxBackoff:          retryMaxBackoffDefault,
+		pageRetries:         pageRetriesDefault,
+		prepared:            &preparedQueries{templates: map[string]string{}},
+		kvCollection:        KVCollectionDefault,
+		propagator:          W3CPropagator{},
+		stats:               &clientStats{},
+		pool:                pool,
+		inflight:            &inflightRequests{},
 	}
 
 	// set options to override defaults
@@ -172,30 +205,81 @@ func NewClient(secret string, timeouts Timeouts, configFns ...ClientConfigFn) *C
 		configFn(client)
 	}
 
+	if client.keepAlive != nil {
+		go client.keepAlive.run(client)
+	}
+
 	return client
 }
 
-func (c *Client) doWithRetry(req *http.Request, attemptNumber int) (attempts int, r *http.Response, err error) {
-	attempts = attemptNumber
-	r, err = c.http.Do(req)
-	if err != nil {
-		return
+// With returns a client derived from the [fauna.Client] with configFns
+// applied, such as to change its headers, retries or typechecking for some
+// queries. The derived client shares the client's transport and connection
+// pool, transaction time, caches, limits and stats, so is cheap to create, and
+// the client is unchanged.
+func (c *Client) With(configFns ...ClientConfigFn) *Client {
+	derived := c.derive()
+	for _, configFn := range configFns {
+		configFn(derived)
 	}
 
-	if attemptNumber <= c.maxAttempts {
-		switch r.StatusCode {
-		case http.StatusTooManyRequests:
-			defer r.Body.Close()
-			if _, err = io.Copy(io.Discard, io.LimitReader(r.Body, 4096)); err != nil {
+	if derived.keepAlive != nil && derived.keepAlive != c.keepAlive {
+		go derived.keepAlive.run(derived)
+	}
+
+	return derived
+}
+
+// doWithRetry sends req, retrying throttled requests until maxAttempts
+// attempts have been made, and returns a record of each attempt.
+func (c *Client) doWithRetry(req *http.Request, maxAttempts int) (history []RetryAttempt, r *http.Response, err error) {
+	fixedID := req.Header.Get(HeaderRequestID) != ""
+	for {
+		if !fixedID {
+			req.Header.Set(HeaderRequestID, newRequestID())
+		}
+		history = append(history, RetryAttempt{Time: time.Now()})
+		attempt := &history[len(history)-1]
+
+		r, err = c.http.Do(req)
+		if err != nil {
+			attempt.Err = err
+			return
+		}
+		attempt.StatusCode = r.StatusCode
+		if r.StatusCode != http.StatusTooManyRequests {
+			return
+		}
+		attempt.RetryAfter = retryAfter(r.Header)
+
+		c.stats.throttled.Add(1)
+		c.metrics.throttle(req.Context())
+		if len(history) >= maxAttempts || (req.Body != nil && req.GetBody == nil) {
+			return
+		}
+		c.stats.retries.Add(1)
+		c.metrics.retried(req.Context())
+
+		_, err = io.Copy(io.Discard, io.LimitReader(r.Body, 4096))
+		r.Body.Close()
+		if err != nil {
+			return
+		}
+
+		attempt.Delay = c.retryDelay(len(history)-1, attempt.RetryAfter)
+		select {
+		case <-req.Context().Done():
+			err = req.Context().Err()
+			return
+		case <-time.After(attempt.Delay):
+		}
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
 				return
 			}
-
-			time.Sleep(c.backoff(attemptNumber))
-			_, r, err = c.doWithRetry(req, attemptNumber+1)
 		}
 	}
-
-	return
 }
 
 func (c *Client) backoff(attempt int) (sleep time.Duration) {
@@ -209,19 +293,112 @@ func (c *Client) backoff(attempt int) (sleep time.Duration) {
 	return
 }
 
+// retryDelay returns how long to wait before retrying a throttled request,
+// which is the Retry-After of its response if it had one, up to the maximum
+// backoff.
+func (c *Client) retryDelay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter <= 0 {
+		return c.backoff(attempt)
+	}
+	if retryAfter > c.maxBackoff {
+		return c.maxBackoff
+	}
+	return retryAfter
+}
+
+// retryAfter parses the Retry-After header, in seconds or as an HTTP date,
+// returning 0 if it's missing or invalid.
+func retryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
 // Query invoke fql optionally set multiple [QueryOptFn]
 func (c *Client) Query(fql *Query, opts ...QueryOptFn) (*QuerySuccess, error) {
+	req, err := c.newRequest(fql, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.do(req)
+}
+
+// QueryInto invokes fql and decodes the result directly into `into`, a
+// pointer to a struct, slice, map, or scalar, optionally setting multiple
+// [QueryOptFn]. Unlike [Client.Query] followed by [QuerySuccess.Unmarshal],
+// the result is decoded without first building [QuerySuccess.Data], which is
+// left nil, cutting allocations for large results. A set decodes into a
+// slice only if it has no more pages; otherwise decode it into a
+// [fauna.Page] to paginate it.
+func (c *Client) QueryInto(fql *Query, into any, opts ...QueryOptFn) (*QuerySuccess, error) {
+	req, err := c.newRequest(fql, into, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.do(req)
+}
+
+// QueryRaw invokes fql and streams the raw response body, the JSON envelope
+// with the result in Fauna's tagged format, into w without buffering it,
+// optionally setting multiple [QueryOptFn]. It returns the number of bytes
+// written. Failed queries return the same errors as [Client.Query], with
+// nothing written.
+//
+// As the response isn't decoded, QueryRaw doesn't update the
+// [fauna.Client]'s last transaction time.
+func (c *Client) QueryRaw(fql *Query, w io.Writer, opts ...QueryOptFn) (int64, error) {
+	req, err := c.newRequest(fql, nil, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.doRaw(req, w)
+}
+
+// queryContext returns the context set by opts with [fauna.QueryContext],
+// for operations which run queries with opts but also make other requests.
+func queryContext(opts []QueryOptFn) context.Context {
+	req := &fqlRequest{Context: context.Background(), Headers: map[string]string{}}
+	for _, queryOptionFn := range opts {
+		queryOptionFn(req)
+	}
+
+	return req.Context
+}
+
+// newRequest builds the request for fql, with a copy of the client's headers
+// so options can change them without affecting other queries.
+func (c *Client) newRequest(fql *Query, into any, opts []QueryOptFn) (*fqlRequest, error) {
 	req := &fqlRequest{
-		Context: c.ctx,
-		Query:   fql,
-		Headers: c.headers,
+		Context:      context.Background(),
+		Query:        fql,
+		Headers:      make(map[string]string, len(c.headers)+len(opts)),
+		Into:         into,
+		PlainSummary: c.plainSummary,
+	}
+	for k, v := range c.headers {
+		req.Headers[k] = v
+	}
+	if timeout := c.endpoint.Load().queryTimeout; timeout != "" {
+		req.Headers[HeaderQueryTimeoutMs] = timeout
 	}
 
 	for _, queryOptionFn := range opts {
 		queryOptionFn(req)
 	}
 
-	return c.do(req)
+	return req, req.Err
 }
 
 // Paginate invoke fql with pagination optionally set multiple [QueryOptFn]
@@ -242,7 +419,7 @@ type QueryIterator struct {
 
 // Next returns the next page of results
 func (q *QueryIterator) Next() (*Page, error) {
-	res, queryErr := q.client.Query(q.fql, q.opts...)
+	res, queryErr := q.query()
 	if queryErr != nil {
 		return nil, queryErr
 	}
@@ -258,12 +435,12 @@ func (q *QueryIterator) Next() (*Page, error) {
 	var page Page
 	if results, isPage := res.Data.(map[string]any); isPage {
 		if after, hasAfter := results["after"].(string); hasAfter {
-			page = Page{After: after, Data: results["data"].([]any)}
+			page = Page{After: after, Data: results["data"].([]any), codec: res.codec}
 		} else {
-			page = Page{After: "", Data: results["data"].([]any)}
+			page = Page{After: "", Data: results["data"].([]any), codec: res.codec}
 		}
 	} else {
-		page = Page{After: "", Data: []any{res.Data}}
+		page = Page{After: "", Data: []any{res.Data}, codec: res.codec}
 	}
 
 	if pageErr := q.nextPage(page.After); pageErr != nil {
@@ -296,12 +473,7 @@ func (q *QueryIterator) HasNext() bool {
 // WARNING: This should be used only when coordinating timestamps across multiple clients.
 // Moving the timestamp arbitrarily forward into the future will cause transactions to stall.
 func (c *Client) SetLastTxnTime(txnTime time.Time) {
-	c.lastTxnTime.Lock()
-	defer c.lastTxnTime.Unlock()
-
-	if val := txnTime.UnixMicro(); val > c.lastTxnTime.Value {
-		c.lastTxnTime.Value = val
-	}
+	c.syncTxnTime(txnTime.UnixMicro())
 }
 
 // GetLastTxnTime gets the last txn timestamp seen by the [fauna.Client]
@@ -315,7 +487,7 @@ func (c *Client) GetLastTxnTime() int64 {
 // String fulfil Stringify interface for the [fauna.Client]
 // only returns the URL to prevent logging potentially sensitive headers.
 func (c *Client) String() string {
-	return c.url
+	return c.endpoint.Load().url
 }
 
 func (c *Client) setHeader(key, val string) {
@@ -339,15 +511,18 @@ func (t *txnTime) string() string {
 	return ""
 }
 
-func (t *txnTime) sync(newTxnTime int64) {
+// sync advances the time to newTxnTime, reporting whether it changed.
+func (t *txnTime) sync(newTxnTime int64) bool {
 	t.Lock()
 	defer t.Unlock()
 
 	for {
 		oldTxnTime := atomic.LoadInt64(&t.Value)
-		if oldTxnTime >= newTxnTime ||
-			atomic.CompareAndSwapInt64(&t.Value, oldTxnTime, newTxnTime) {
-			break
+		if oldTxnTime >= newTxnTime {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&t.Value, oldTxnTime, newTxnTime) {
+			return true
 		}
 	}
 }
@@ -1,7 +1,6 @@
 package fauna_test
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -53,7 +52,6 @@ func ExampleNewClient() {
 		fauna.DefaultTimeouts(),
 		fauna.HTTPClient(http.DefaultClient),
 		fauna.URL(fauna.EndpointLocal),
-		fauna.Context(context.Background()),
 		fauna.QueryTimeout(time.Minute*3),
 	)
 
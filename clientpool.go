@@ -0,0 +1,311 @@
+package fauna
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ClientHealth describes a member of a [fauna.ClientPool].
+type ClientHealth struct {
+	// Endpoint is the URL of the member's client.
+	Endpoint string
+
+	// Healthy is false while the member is quarantined.
+	Healthy bool
+
+	// Latency is a moving average of the member's query latency, or 0 if
+	// it hasn't run a query yet.
+	Latency time.Duration
+
+	// Failures is the number of the member's queries or health checks which
+	// failed in a row.
+	Failures int
+
+	// QuarantinedUntil is when the member can next be routed to, if it's
+	// quarantined.
+	QuarantinedUntil time.Time
+}
+
+// A Selector chooses which member of a [fauna.ClientPool] to send a query
+// to, returning its index in members. members are never empty.
+type Selector interface {
+	Select(members []ClientHealth) int
+}
+
+// RoundRobin is a [fauna.Selector] which routes queries to each member in
+// turn.
+func RoundRobin() Selector {
+	return &roundRobin{}
+}
+
+type roundRobin struct {
+	next atomic.Uint64
+}
+
+func (r *roundRobin) Select(members []ClientHealth) int {
+	return int((r.next.Add(1) - 1) % uint64(len(members)))
+}
+
+// LowestLatency is a [fauna.Selector] which routes queries to the member with
+// the lowest average latency, trying each member at least once.
+func LowestLatency() Selector {
+	return lowestLatency{}
+}
+
+type lowestLatency struct{}
+
+func (lowestLatency) Select(members []ClientHealth) int {
+	best := 0
+	for i, member := range members {
+		if member.Latency < members[best].Latency {
+			best = i
+		}
+	}
+
+	return best
+}
+
+// ClientPoolOptFn configuration options for [fauna.NewClientPool]
+type ClientPoolOptFn func(*ClientPool)
+
+// PoolSelector sets how the [fauna.ClientPool] chooses between its healthy
+// members. The default is [fauna.RoundRobin].
+func PoolSelector(selector Selector) ClientPoolOptFn {
+	return func(p *ClientPool) { p.selector = selector }
+}
+
+// PoolQuarantine quarantines a member of the [fauna.ClientPool] for d after
+// failures queries or health checks fail in a row. The default is 3 failures
+// and 30 seconds.
+func PoolQuarantine(failures int, d time.Duration) ClientPoolOptFn {
+	return func(p *ClientPool) {
+		p.maxFailures = failures
+		p.quarantine = d
+	}
+}
+
+// A ClientPool routes queries across several [fauna.Client]s, such as ones
+// connected to different regions or gateways, skipping members which are
+// failing. A member is quarantined when its queries fail with a network
+// error or a Fauna service error too many times in a row, and routed to again
+// once the quarantine ends or a health check succeeds. If every member is
+// quarantined, queries are routed to all of them.
+//
+// Queries aren't retried on another member, as a failed query may still have
+// been applied.
+type ClientPool struct {
+	clients     []*Client
+	selector    Selector
+	maxFailures int
+	quarantine  time.Duration
+	now         func() time.Time
+
+	mu     sync.Mutex
+	health []ClientHealth
+}
+
+// NewClientPool creates a [fauna.ClientPool] routing queries to clients.
+func NewClientPool(clients []*Client, opts ...ClientPoolOptFn) (*ClientPool, error) {
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("a client pool needs at least one client")
+	}
+
+	p := &ClientPool{
+		clients:     clients,
+		selector:    RoundRobin(),
+		maxFailures: 3,
+		quarantine:  30 * time.Second,
+		now:         time.Now,
+		health:      make([]ClientHealth, len(clients)),
+	}
+	for i, client := range clients {
+		p.health[i] = ClientHealth{Endpoint: client.String(), Healthy: true}
+	}
+
+	for _, optFn := range opts {
+		optFn(p)
+	}
+
+	return p, nil
+}
+
+// Health returns the health of each member of the pool, in the order their
+// clients were given to [fauna.NewClientPool].
+func (p *ClientPool) Health() []ClientHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.release()
+	return append([]ClientHealth{}, p.health...)
+}
+
+// Query runs fql on a healthy member of the pool, as with [Client.Query].
+func (p *ClientPool) Query(fql *Query, opts ...QueryOptFn) (*QuerySuccess, error) {
+	i := p.pick()
+
+	start := p.now()
+	res, err := p.clients[i].Query(fql, opts...)
+	// errors which mean the endpoint answered are the query's fault, and
+	// queries the caller gave up on say nothing about the endpoint
+	if !callerGaveUp(queryContext(opts), err) {
+		p.record(i, p.now().Sub(start), endpointFailure(err))
+	}
+
+	return res, err
+}
+
+// Client returns the client of a healthy member of the pool, for operations
+// other than [ClientPool.Query]. Their failures don't count against the
+// member.
+func (p *ClientPool) Client() *Client {
+	return p.clients[p.pick()]
+}
+
+// HealthCheck runs a no-op query on each member of the pool, ending the
+// quarantine of those which succeed and counting failures against those
+// which don't. It returns an error if every member failed.
+func (p *ClientPool) HealthCheck(ctx context.Context) error {
+	errs := make([]error, len(p.clients))
+
+	var wg sync.WaitGroup
+	for i, client := range p.clients {
+		wg.Add(1)
+		go func(i int, client *Client) {
+			defer wg.Done()
+
+			errs[i] = client.Warmup(ctx, WarmupQuery())
+			if !callerGaveUp(ctx, errs[i]) {
+				p.record(i, 0, errs[i] != nil)
+			}
+		}(i, client)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("every client in the pool failed its health check: %w", errs[0])
+}
+
+// Monitor runs [ClientPool.HealthCheck] every interval until ctx is done.
+// Monitor blocks, so is usually run in its own goroutine:
+//
+//	go pool.Monitor(ctx, 10*time.Second)
+func (p *ClientPool) Monitor(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			_ = p.HealthCheck(ctx)
+		}
+	}
+}
+
+// pick chooses a member with the pool's selector.
+func (p *ClientPool) pick() int {
+	p.mu.Lock()
+	p.release()
+
+	var healthy []int
+	for i, member := range p.health {
+		if member.Healthy {
+			healthy = append(healthy, i)
+		}
+	}
+
+	candidates := make([]ClientHealth, 0, len(p.health))
+	if len(healthy) == 0 {
+		candidates = append(candidates, p.health...)
+	} else {
+		for _, i := range healthy {
+			candidates = append(candidates, p.health[i])
+		}
+	}
+	p.mu.Unlock()
+
+	i := p.selector.Select(candidates)
+	if i < 0 || i >= len(candidates) {
+		i = 0
+	}
+	if len(healthy) == 0 {
+		return i
+	}
+
+	return healthy[i]
+}
+
+// release ends quarantines which are over. p.mu must be held.
+func (p *ClientPool) release() {
+	now := p.now()
+	for i := range p.health {
+		if !p.health[i].Healthy && !now.Before(p.health[i].QuarantinedUntil) {
+			p.health[i].Healthy = true
+			p.health[i].QuarantinedUntil = time.Time{}
+		}
+	}
+}
+
+// record records whether a request to member i failed, and if elapsed isn't
+// zero, how long it took.
+func (p *ClientPool) record(i int, elapsed time.Duration, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	member := &p.health[i]
+	if failed {
+		member.Failures++
+		if member.Failures >= p.maxFailures {
+			member.Healthy = false
+			member.QuarantinedUntil = p.now().Add(p.quarantine)
+		}
+		return
+	}
+
+	member.Failures = 0
+	member.Healthy = true
+	member.QuarantinedUntil = time.Time{}
+	switch {
+	case elapsed == 0:
+	case member.Latency == 0:
+		member.Latency = elapsed
+	default:
+		// exponentially weighted, so recent queries count the most
+		member.Latency = (member.Latency*4 + elapsed) / 5
+	}
+}
+
+// callerGaveUp reports whether err is because ctx, the caller's context,
+// was canceled or passed its deadline.
+func callerGaveUp(ctx context.Context, err error) bool {
+	return ctx.Err() != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded))
+}
+
+// endpointFailure reports whether err means the endpoint is unhealthy, rather
+// than the query being at fault.
+func endpointFailure(err error) bool {
+	var (
+		network  *ErrNetwork
+		internal *ErrServiceInternal
+		timeout  *ErrServiceTimeout
+	)
+
+	return errors.As(err, &network) ||
+		errors.As(err, &internal) ||
+		errors.As(err, &timeout) ||
+		errors.Is(err, ErrClientClosed)
+}
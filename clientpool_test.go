@@ -0,0 +1,141 @@
+package fauna
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientPool(t *testing.T) {
+	var failing atomic.Bool
+	up := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, successBody(`null`)
+	})
+	flaky := newTestServer(t, func(testRequest) (int, string) {
+		if failing.Load() {
+			return http.StatusServiceUnavailable, errorBody("time_out", "service timeout")
+		}
+		return http.StatusOK, successBody(`null`)
+	})
+	q, _ := FQL(`null`, nil)
+
+	_, err := NewClientPool(nil)
+	assert.Error(t, err)
+
+	t.Run("round robin", func(t *testing.T) {
+		pool, err := NewClientPool([]*Client{up.client(), flaky.client()})
+		if !assert.NoError(t, err) {
+			return
+		}
+		upBefore, flakyBefore := len(up.received()), len(flaky.received())
+
+		for i := 0; i < 4; i++ {
+			_, err := pool.Query(q)
+			assert.NoError(t, err)
+		}
+		assert.Len(t, up.received(), upBefore+2)
+		assert.Len(t, flaky.received(), flakyBefore+2)
+	})
+
+	t.Run("quarantines failing members", func(t *testing.T) {
+		now := time.Now()
+		pool, _ := NewClientPool([]*Client{flaky.client(), up.client()}, PoolQuarantine(2, time.Minute))
+		pool.now = func() time.Time { return now }
+
+		failing.Store(true)
+		defer failing.Store(false)
+
+		// round robin alternates, so the flaky member fails twice in four queries
+		for i := 0; i < 4; i++ {
+			_, _ = pool.Query(q)
+		}
+
+		health := pool.Health()
+		assert.False(t, health[0].Healthy)
+		assert.Equal(t, 2, health[0].Failures)
+		assert.Equal(t, now.Add(time.Minute), health[0].QuarantinedUntil)
+		assert.True(t, health[1].Healthy)
+
+		flakyBefore := len(flaky.received())
+		for i := 0; i < 3; i++ {
+			_, err := pool.Query(q)
+			assert.NoError(t, err)
+		}
+		assert.Len(t, flaky.received(), flakyBefore, "quarantined members aren't routed to")
+
+		failing.Store(false)
+		assert.NoError(t, pool.HealthCheck(context.Background()))
+		assert.True(t, pool.Health()[0].Healthy, "a passing health check ends the quarantine")
+
+		failing.Store(true)
+		for i := 0; i < 4; i++ {
+			_, _ = pool.Query(q)
+		}
+		assert.False(t, pool.Health()[0].Healthy)
+		now = now.Add(time.Minute)
+		assert.True(t, pool.Health()[0].Healthy, "the quarantine ends")
+	})
+
+	t.Run("query errors aren't failures", func(t *testing.T) {
+		srv := newTestServer(t, func(testRequest) (int, string) {
+			return http.StatusBadRequest, errorBody("invalid_query", "bad query")
+		})
+		pool, _ := NewClientPool([]*Client{srv.client()}, PoolQuarantine(1, time.Minute))
+
+		_, err := pool.Query(q)
+		assert.Error(t, err)
+		assert.True(t, pool.Health()[0].Healthy)
+	})
+
+	t.Run("canceled queries aren't failures", func(t *testing.T) {
+		slow := newTestServer(t, func(testRequest) (int, string) {
+			time.Sleep(100 * time.Millisecond)
+			return http.StatusOK, successBody(`null`)
+		})
+		pool, _ := NewClientPool([]*Client{slow.client()}, PoolQuarantine(1, time.Minute))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := pool.Query(q, QueryContext(ctx))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.True(t, pool.Health()[0].Healthy)
+		assert.Zero(t, pool.Health()[0].Failures)
+
+		canceled, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.Error(t, pool.HealthCheck(canceled))
+		assert.True(t, pool.Health()[0].Healthy)
+	})
+
+	t.Run("lowest latency", func(t *testing.T) {
+		slow := newTestServer(t, func(testRequest) (int, string) {
+			time.Sleep(20 * time.Millisecond)
+			return http.StatusOK, successBody(`null`)
+		})
+		pool, _ := NewClientPool([]*Client{slow.client(), up.client()}, PoolSelector(LowestLatency()))
+		upBefore := len(up.received())
+
+		for i := 0; i < 5; i++ {
+			_, err := pool.Query(q)
+			assert.NoError(t, err)
+		}
+		assert.Len(t, slow.received(), 1, "each member is tried")
+		assert.Len(t, up.received(), upBefore+4)
+	})
+
+	t.Run("every member quarantined", func(t *testing.T) {
+		pool, _ := NewClientPool([]*Client{NewClient("secret", DefaultTimeouts(), URL("http://127.0.0.1:1"))}, PoolQuarantine(1, time.Minute))
+
+		_, err := pool.Query(q)
+		assert.Error(t, err)
+		assert.False(t, pool.Health()[0].Healthy)
+
+		_, err = pool.Query(q)
+		assert.ErrorAs(t, err, new(*ErrNetwork), "queries are still routed")
+		assert.Error(t, pool.HealthCheck(context.Background()))
+	})
+}
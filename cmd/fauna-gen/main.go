@@ -0,0 +1,80 @@
+// Command fauna-gen generates Go structs from the collections of a Fauna
+// database.
+//
+// Each collection becomes a struct embedding fauna.Document with a field for
+// every declared and computed field, tagged for the driver, plus a function
+// returning a typed fauna.Collection for it. The database is read using the
+// FAUNA_SECRET and FAUNA_ENDPOINT environment variables.
+//
+// Usage:
+//
+//	fauna-gen [-pkg name] [-o file] [-collections Dogs,Cats]
+//
+// It is typically run with go:generate to keep models in sync:
+//
+//	//go:generate go run github.com/fauna/fauna-go/cmd/fauna-gen -pkg models -o models_gen.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fauna/fauna-go"
+	"github.com/fauna/fauna-go/schema"
+)
+
+func main() {
+	pkg := flag.String("pkg", "models", "package name of the generated file")
+	out := flag.String("o", "", "file to write, defaults to stdout")
+	only := flag.String("collections", "", "comma separated collections to generate, defaults to all")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: fauna-gen [-pkg name] [-o file] [-collections names]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	client, err := fauna.NewDefaultClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fauna-gen: %s\n", err)
+		os.Exit(2)
+	}
+
+	collections, err := schema.New(client).ListCollections()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fauna-gen: failed to list collections: %s\n", err)
+		os.Exit(1)
+	}
+
+	if *only != "" {
+		wanted := map[string]bool{}
+		for _, name := range strings.Split(*only, ",") {
+			wanted[strings.TrimSpace(name)] = true
+		}
+
+		filtered := collections[:0]
+		for _, coll := range collections {
+			if wanted[coll.Name] {
+				filtered = append(filtered, coll)
+			}
+		}
+		collections = filtered
+	}
+
+	src, err := schema.GenerateGo(*pkg, collections)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fauna-gen: %s\n", err)
+		os.Exit(1)
+	}
+
+	if *out == "" {
+		_, _ = os.Stdout.Write(src)
+		return
+	}
+
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "fauna-gen: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,59 @@
+// Command fauna-vet checks the FQL templates passed to fauna.FQL in Go sources.
+//
+// It reports templates with invalid placeholders, placeholders without a
+// matching argument, and arguments the template never uses. When given a
+// schema snapshot directory of .fsl files it also reports references to
+// collections the schema doesn't define.
+//
+// Usage:
+//
+//	fauna-vet [-schema dir] [packages]
+//
+// Packages are directories, and a trailing `/...` checks every directory
+// beneath. It is typically run with go:generate:
+//
+//	//go:generate go run github.com/fauna/fauna-go/cmd/fauna-vet -schema ./schema ./...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	schemaDir := flag.String("schema", "", "directory of .fsl files to check collection references against")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: fauna-vet [-schema dir] [packages]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	v := newVetter()
+	if *schemaDir != "" {
+		if err := v.loadSchema(*schemaDir); err != nil {
+			fmt.Fprintf(os.Stderr, "fauna-vet: failed to load schema: %s\n", err)
+			os.Exit(2)
+		}
+	}
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	for _, pattern := range patterns {
+		if err := v.vetPattern(pattern); err != nil {
+			fmt.Fprintf(os.Stderr, "fauna-vet: %s\n", err)
+			os.Exit(2)
+		}
+	}
+
+	for _, i := range v.issues {
+		fmt.Fprintln(os.Stderr, i)
+	}
+
+	if len(v.issues) > 0 {
+		os.Exit(1)
+	}
+}
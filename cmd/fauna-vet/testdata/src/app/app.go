@@ -0,0 +1,17 @@
+package app
+
+import (
+	fql "github.com/fauna/fauna-go"
+)
+
+func queries(dynamic map[string]any) {
+	_, _ = fql.FQL(`Users.byId(${id})`, map[string]any{"id": "1"})
+	_, _ = fql.FQL(`Users.byId(${id})`, map[string]any{"ident": "1"})
+	_, _ = fql.FQL(`Users.all()`, map[string]any{"unused": 1})
+	_, _ = fql.FQL(`Users.byId($id)`, nil)
+	_, _ = fql.FQL(`Orders.all()`, nil)
+	_, _ = fql.FQL(`Collection.byName("Widgets")`, nil)
+	_, _ = fql.FQL(`Users.`+`byId(${id})`, dynamic)
+	_, _ = fql.FQL(`Users.where(${f})`, map[string]any{"f": nil, "f.since": 1})
+	_, _ = fql.FQL(`Users.byId(${id})`, nil)
+}
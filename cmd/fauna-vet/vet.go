@@ -0,0 +1,300 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fauna/fauna-go"
+)
+
+const driverImportPath = "github.com/fauna/fauna-go"
+
+var (
+	placeholderRe      = regexp.MustCompile(`\$(?:\$|{([_a-zA-Z0-9]*)})`)
+	collectionRefRe    = regexp.MustCompile(`(?:^|[^.\w"'])([A-Z]\w*)\s*\.`)
+	fslCollectionRe    = regexp.MustCompile(`(?m)^\s*collection\s+(\w+)`)
+	fqlStringLiteralRe = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`)
+)
+
+// builtinModules are top level FQL modules which are never user collections.
+var builtinModules = map[string]bool{
+	"AccessProvider": true, "Array": true, "Boolean": true, "Bytes": true,
+	"Collection": true, "Credential": true, "Credentials": true, "Database": true,
+	"Date": true, "Double": true, "Function": true, "ID": true, "Int": true,
+	"Key": true, "Long": true, "Math": true, "Null": true, "Number": true,
+	"Object": true, "Query": true, "Role": true, "Set": true, "String": true,
+	"Time": true, "Token": true, "TransactionTime": true,
+}
+
+// issue is a single problem found in a Go source file.
+type issue struct {
+	Pos     token.Position
+	Message string
+}
+
+func (i issue) String() string {
+	return fmt.Sprintf("%s: %s", i.Pos, i.Message)
+}
+
+// vetter finds fauna.FQL calls in Go sources and checks their templates.
+type vetter struct {
+	fset        *token.FileSet
+	collections map[string]bool
+	issues      []issue
+}
+
+func newVetter() *vetter {
+	return &vetter{fset: token.NewFileSet()}
+}
+
+// loadSchema reads collection names from the .fsl files in dir so templates
+// can be checked against them.
+func (v *vetter) loadSchema(dir string) error {
+	v.collections = map[string]bool{}
+
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || filepath.Ext(p) != ".fsl" {
+			return nil
+		}
+
+		bin, readErr := os.ReadFile(p)
+		if readErr != nil {
+			return readErr
+		}
+
+		for _, m := range fslCollectionRe.FindAllStringSubmatch(string(bin), -1) {
+			v.collections[m[1]] = true
+		}
+
+		return nil
+	})
+}
+
+// vetPattern checks a directory, or every directory beneath it when the
+// pattern ends in `/...`.
+func (v *vetter) vetPattern(pattern string) error {
+	if !strings.HasSuffix(pattern, "...") {
+		return v.vetDir(pattern)
+	}
+
+	root := filepath.Clean(strings.TrimSuffix(pattern, "..."))
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if name := d.Name(); p != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+			return filepath.SkipDir
+		}
+
+		return v.vetDir(p)
+	})
+}
+
+func (v *vetter) vetDir(dir string) error {
+	entries, readErr := os.ReadDir(dir)
+	if readErr != nil {
+		return readErr
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".go" {
+			continue
+		}
+
+		if err := v.vetFile(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *vetter) vetFile(path string) error {
+	file, parseErr := parser.ParseFile(v.fset, path, nil, 0)
+	if parseErr != nil {
+		return parseErr
+	}
+
+	driverName, importsDriver := driverImportName(file)
+	if !importsDriver && file.Name.Name != "fauna" {
+		return nil
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, isCall := n.(*ast.CallExpr)
+		if !isCall || !isFQLCall(call, driverName, importsDriver) || len(call.Args) != 2 {
+			return true
+		}
+
+		v.vetCall(call)
+		return true
+	})
+
+	return nil
+}
+
+func (v *vetter) vetCall(call *ast.CallExpr) {
+	text, isConst := stringConstant(call.Args[0])
+	if !isConst {
+		// templates built at runtime can't be checked statically
+		return
+	}
+
+	used := map[string]bool{}
+	for _, m := range placeholderRe.FindAllStringSubmatch(text, -1) {
+		if m[1] != "" {
+			used[m[1]] = true
+		}
+	}
+
+	args, argsKnown := argumentNames(call.Args[1])
+	if !argsKnown {
+		// without a literal map we can still check the template parses
+		args = make(map[string]ast.Node, len(used))
+		for name := range used {
+			args[name] = call.Args[1]
+		}
+	}
+
+	var fqlArgs map[string]any
+	if len(args) > 0 {
+		fqlArgs = make(map[string]any, len(args))
+		for name := range args {
+			fqlArgs[name] = nil
+		}
+	}
+
+	if _, err := fauna.FQL(text, fqlArgs); err != nil {
+		v.report(call.Args[0], err.Error())
+	}
+
+	unused := make([]string, 0)
+	for name := range args {
+		// dotted names are namespaced arguments for an embedded fauna.Fragment
+		if !used[name] && !strings.Contains(name, ".") {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+
+	for _, name := range unused {
+		v.report(args[name], fmt.Sprintf("argument %s is not used in the template", name))
+	}
+
+	v.vetCollections(call.Args[0], text)
+}
+
+func (v *vetter) vetCollections(node ast.Node, text string) {
+	if v.collections == nil {
+		return
+	}
+
+	code := fqlStringLiteralRe.ReplaceAllString(placeholderRe.ReplaceAllString(text, "x"), `""`)
+	for _, m := range collectionRefRe.FindAllStringSubmatch(code, -1) {
+		if name := m[1]; !builtinModules[name] && !v.collections[name] {
+			v.report(node, fmt.Sprintf("collection %s is not defined in the schema", name))
+		}
+	}
+}
+
+func (v *vetter) report(node ast.Node, message string) {
+	v.issues = append(v.issues, issue{Pos: v.fset.Position(node.Pos()), Message: message})
+}
+
+func driverImportName(file *ast.File) (string, bool) {
+	for _, imp := range file.Imports {
+		if path, _ := strconv.Unquote(imp.Path.Value); path == driverImportPath {
+			if imp.Name != nil {
+				return imp.Name.Name, true
+			}
+			return "fauna", true
+		}
+	}
+
+	return "", false
+}
+
+func isFQLCall(call *ast.CallExpr, driverName string, importsDriver bool) bool {
+	switch fn := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		pkg, isIdent := fn.X.(*ast.Ident)
+		return importsDriver && isIdent && pkg.Name == driverName && fn.Sel.Name == "FQL"
+	case *ast.Ident:
+		return !importsDriver && fn.Name == "FQL"
+	}
+
+	return false
+}
+
+// stringConstant resolves string literals and concatenations of them.
+func stringConstant(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+
+	case *ast.ParenExpr:
+		return stringConstant(e.X)
+
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+
+		left, leftOk := stringConstant(e.X)
+		right, rightOk := stringConstant(e.Y)
+		return left + right, leftOk && rightOk
+	}
+
+	return "", false
+}
+
+// argumentNames returns the keys of a literal args map, or false when the
+// arguments aren't known statically.
+func argumentNames(expr ast.Expr) (map[string]ast.Node, bool) {
+	if ident, isIdent := expr.(*ast.Ident); isIdent && ident.Name == "nil" {
+		return map[string]ast.Node{}, true
+	}
+
+	lit, isLit := expr.(*ast.CompositeLit)
+	if !isLit {
+		return nil, false
+	}
+
+	names := make(map[string]ast.Node, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		kv, isKV := elt.(*ast.KeyValueExpr)
+		if !isKV {
+			return nil, false
+		}
+
+		name, isConst := stringConstant(kv.Key)
+		if !isConst {
+			return nil, false
+		}
+		names[name] = kv.Key
+	}
+
+	return names, true
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVetter(t *testing.T) {
+	messages := func(v *vetter) []string {
+		out := make([]string, len(v.issues))
+		for i, iss := range v.issues {
+			out[i] = filepath.Base(iss.Pos.Filename) + ":" + iss.Message
+			assert.NotZero(t, iss.Pos.Line)
+		}
+		return out
+	}
+
+	t.Run("checks placeholders and arguments", func(t *testing.T) {
+		v := newVetter()
+		if !assert.NoError(t, v.vetPattern("testdata/src/...")) {
+			return
+		}
+
+		assert.Equal(t, []string{
+			"app.go:template variable id not found in args",
+			"app.go:argument ident is not used in the template",
+			"app.go:argument unused is not used in the template",
+			"app.go:invalid placeholder in template: position 12",
+			"app.go:found template variable, but args is nil",
+		}, messages(v))
+	})
+
+	t.Run("checks collections against a schema", func(t *testing.T) {
+		v := newVetter()
+		if !assert.NoError(t, v.loadSchema("testdata/schema")) {
+			return
+		}
+
+		if assert.NoError(t, v.vetDir("testdata/src/app")) {
+			assert.Contains(t, messages(v), "app.go:collection Orders is not defined in the schema")
+			assert.NotContains(t, messages(v), "app.go:collection Users is not defined in the schema")
+			assert.NotContains(t, messages(v), "app.go:collection Widgets is not defined in the schema")
+		}
+	})
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fauna/fauna-go"
+	"github.com/fauna/fauna-go/schema"
+)
+
+// usageError is an error in a command's arguments.
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+type command struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+}
+
+func (c *command) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("faunago "+name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	return fs
+}
+
+func (c *command) parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+
+	return nil
+}
+
+func (c *command) query(args []string) error {
+	fs := c.flags("query")
+	tagged := fs.Bool("tagged", false, "print results in Fauna's tagged format")
+	file := fs.String("f", "", "file to read the query from")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+
+	var src string
+	switch {
+	case fs.NArg() > 0 && *file != "":
+		return usageError("pass a query or -f, not both")
+	case fs.NArg() > 0:
+		src = strings.Join(fs.Args(), " ")
+	case *file != "":
+		bin, err := os.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+		src = string(bin)
+	default:
+		bin, err := io.ReadAll(c.stdin)
+		if err != nil {
+			return err
+		}
+		src = string(bin)
+	}
+
+	client, err := fauna.NewDefaultClient()
+	if err != nil {
+		return err
+	}
+
+	return c.runQuery(client, strings.TrimSpace(src), *tagged)
+}
+
+func (c *command) shell(args []string) error {
+	fs := c.flags("shell")
+	tagged := fs.Bool("tagged", false, "print results in Fauna's tagged format")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+
+	client, err := fauna.NewDefaultClient()
+	if err != nil {
+		return err
+	}
+
+	interactive := false
+	if f, isFile := c.stdin.(*os.File); isFile {
+		if info, err := f.Stat(); err == nil {
+			interactive = info.Mode()&os.ModeCharDevice != 0
+		}
+	}
+	prompt := func(p string) {
+		if interactive {
+			fmt.Fprint(c.stdout, p)
+		}
+	}
+
+	var pending strings.Builder
+	scanner := bufio.NewScanner(c.stdin)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for prompt("> "); scanner.Scan(); {
+		line := scanner.Text()
+		if cont := strings.TrimSuffix(line, `\`); cont != line {
+			pending.WriteString(cont + "\n")
+			prompt(". ")
+			continue
+		}
+		pending.WriteString(line)
+
+		src := strings.TrimSpace(pending.String())
+		pending.Reset()
+
+		switch src {
+		case "":
+		case ".exit", ".quit":
+			return nil
+		default:
+			if err := c.runQuery(client, src, *tagged); err != nil {
+				fmt.Fprintf(c.stderr, "error: %s\n", err)
+			}
+		}
+		prompt("> ")
+	}
+
+	return scanner.Err()
+}
+
+// runQuery runs src and prints its result.
+func (c *command) runQuery(client *fauna.Client, src string, tagged bool) error {
+	q, err := fauna.FQL(src, nil)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if _, err := client.QueryRaw(q, &buf); err != nil {
+		return err
+	}
+
+	var res struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	var out bytes.Buffer
+	if tagged {
+		if err := json.Indent(&out, res.Data, "", "  "); err != nil {
+			return err
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(res.Data))
+		dec.UseNumber()
+
+		var data any
+		if err := dec.Decode(&data); err != nil {
+			return fmt.Errorf("failed to decode result: %w", err)
+		}
+
+		bin, err := json.MarshalIndent(simplify(data), "", "  ")
+		if err != nil {
+			return err
+		}
+		out.Write(bin)
+	}
+	out.WriteByte('\n')
+
+	_, err = out.WriteTo(c.stdout)
+	return err
+}
+
+func (c *command) schema(args []string) error {
+	if len(args) == 0 {
+		return usageError("schema requires a subcommand: pull, push or diff")
+	}
+
+	sub := args[0]
+	fs := c.flags("schema " + sub)
+	dir := fs.String("dir", "schema", "directory of .fsl files")
+	staged := fs.Bool("staged", false, "stage the pushed schema rather than applying it")
+	force := fs.Bool("force", false, "push even if the schema has changed or data would be deleted")
+	if err := c.parse(fs, args[1:]); err != nil {
+		return err
+	}
+
+	client, err := fauna.NewDefaultClient()
+	if err != nil {
+		return err
+	}
+	m := schema.New(client)
+	ctx := context.Background()
+
+	switch sub {
+	case "pull":
+		version, err := m.Pull(ctx, *dir)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stdout, "pulled schema version %d into %s\n", version, *dir)
+
+	case "push":
+		var opts []fauna.SchemaPushOptFn
+		if *staged {
+			opts = append(opts, fauna.SchemaStaged())
+		}
+		if *force {
+			opts = append(opts, fauna.SchemaForce())
+		}
+
+		version, err := m.Push(ctx, *dir, opts...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stdout, "pushed schema version %d\n", version)
+
+	case "diff":
+		diff, err := m.DiffDir(ctx, *dir)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.stdout, diff.Diff)
+
+	default:
+		return usageError(fmt.Sprintf("unknown schema subcommand %q", sub))
+	}
+
+	return nil
+}
+
+func (c *command) export(args []string) error {
+	fs := c.flags("export")
+	collection := fs.String("collection", "", "collection to export")
+	format := fs.String("format", string(fauna.ExportJSONL), "jsonl or csv")
+	fields := fs.String("fields", "", "comma separated fields to export, defaults to all")
+	out := fs.String("o", "", "file to write, defaults to stdout")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	if *collection == "" {
+		return usageError("export requires -collection")
+	}
+
+	client, err := fauna.NewDefaultClient()
+	if err != nil {
+		return err
+	}
+
+	var opts []fauna.ExportOptFn
+	if *fields != "" {
+		opts = append(opts, fauna.ExportFields(strings.Split(*fields, ",")...))
+	}
+
+	w := c.stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	progress, err := client.ExportCollection(*collection, w, fauna.ExportFormat(*format), opts...)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stderr, "exported %d documents\n", progress.Documents)
+
+	return nil
+}
+
+func (c *command) importDocs(args []string) error {
+	fs := c.flags("import")
+	collection := fs.String("collection", "", "collection to import into")
+	preserveIDs := fs.Bool("preserve-ids", false, "create documents with the id of each line")
+	dryRun := fs.Bool("dry-run", false, "validate the input without writing it")
+	if err := c.parse(fs, args); err != nil {
+		return err
+	}
+	if *collection == "" {
+		return usageError("import requires -collection")
+	}
+
+	client, err := fauna.NewDefaultClient()
+	if err != nil {
+		return err
+	}
+
+	var opts []fauna.ImportOptFn
+	if *preserveIDs {
+		opts = append(opts, fauna.ImportPreserveIDs())
+	}
+	if *dryRun {
+		opts = append(opts, fauna.ImportDryRun())
+	}
+
+	r := c.stdin
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	summary, err := client.ImportCollection(*collection, r, opts...)
+	if summary != nil {
+		if *dryRun {
+			fmt.Fprintf(c.stderr, "validated %d documents\n", summary.Read)
+		} else {
+			fmt.Fprintf(c.stderr, "imported %d of %d documents\n", summary.Written, summary.Read)
+		}
+	}
+
+	return err
+}
+
+// simplify converts a result in Fauna's tagged format to plain JSON: numbers
+// are untagged, times, dates and modules become strings, documents their
+// fields, and sets their page.
+func simplify(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 1 {
+			for tag, inner := range v {
+				switch tag {
+				case "@int", "@long", "@double":
+					if s, isString := inner.(string); isString {
+						if json.Valid([]byte(s)) {
+							return json.Number(s)
+						}
+					}
+					return inner
+				case "@time", "@date", "@mod":
+					return inner
+				case "@ref", "@doc", "@set":
+					return simplify(inner)
+				case "@object":
+					if obj, isObject := inner.(map[string]any); isObject {
+						return simplifyFields(obj)
+					}
+				}
+			}
+		}
+		return simplifyFields(v)
+
+	case []any:
+		for i, elem := range v {
+			v[i] = simplify(elem)
+		}
+	}
+
+	return v
+}
+
+func simplifyFields(obj map[string]any) map[string]any {
+	for k, field := range obj {
+		obj[k] = simplify(field)
+	}
+
+	return obj
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fauna/fauna-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// newServer is a fake Fauna endpoint, which replies to each query with the
+// data handler returns for its FQL.
+func newServer(t *testing.T, handler func(fql string) string) {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json;charset=utf-8")
+
+		switch r.URL.Path {
+		case "/schema/1/files":
+			_, _ = io.WriteString(w, `{"version":7,"files":[{"filename":"main.fsl"}]}`)
+			return
+		case "/schema/1/files/main.fsl":
+			_, _ = io.WriteString(w, `{"version":7,"content":"collection Dogs {}"}`)
+			return
+		}
+
+		bin, _ := io.ReadAll(r.Body)
+		var body struct {
+			Query struct {
+				FQL []any `json:"fql"`
+			} `json:"query"`
+		}
+		_ = json.Unmarshal(bin, &body)
+
+		var fql strings.Builder
+		for _, frag := range body.Query.FQL {
+			if lit, isLit := frag.(string); isLit {
+				fql.WriteString(lit)
+			} else {
+				fql.WriteString("$")
+			}
+		}
+
+		data := handler(fql.String())
+		if strings.HasPrefix(data, `{"error"`) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, data)
+			return
+		}
+		_, _ = io.WriteString(w, `{"data":`+data+`,"summary":"","txn_ts":1680000000000000,"stats":{}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Setenv(fauna.EnvFaunaSecret, "secret")
+	t.Setenv(fauna.EnvFaunaEndpoint, srv.URL)
+}
+
+func runCmd(stdin string, args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = run(args, strings.NewReader(stdin), &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+const scoutDoc = `{"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"},"name":"Scout","age":{"@int":"3"}}}`
+
+func TestQuery(t *testing.T) {
+	newServer(t, func(fql string) string {
+		switch fql {
+		case "Dogs.byId('1')":
+			return scoutDoc
+		case "1 + 1":
+			return `{"@int":"2"}`
+		}
+		return `{"error":{"code":"invalid_query","message":"bad query"},"summary":"","stats":{}}`
+	})
+
+	code, stdout, _ := runCmd("", "query", "Dogs.byId('1')")
+	assert.Equal(t, 0, code)
+	assert.JSONEq(t, `{"id":"1","coll":"Dogs","ts":"2023-04-01T00:00:00Z","name":"Scout","age":3}`, stdout)
+
+	code, stdout, _ = runCmd("", "query", "-tagged", "Dogs.byId('1')")
+	assert.Equal(t, 0, code)
+	assert.JSONEq(t, scoutDoc, stdout)
+
+	code, stdout, _ = runCmd("1 + 1\n", "query")
+	assert.Equal(t, 0, code, "queries are read from stdin")
+	assert.Equal(t, "2\n", stdout)
+
+	file := filepath.Join(t.TempDir(), "query.fql")
+	assert.NoError(t, os.WriteFile(file, []byte("1 + 1"), 0o644))
+	code, stdout, _ = runCmd("", "query", "-f", file)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "2\n", stdout)
+
+	code, _, stderr := runCmd("", "query", "nope")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "bad query")
+
+	code, _, _ = runCmd("", "query", "-f", file, "1 + 1")
+	assert.Equal(t, 2, code)
+}
+
+func TestShell(t *testing.T) {
+	newServer(t, func(fql string) string {
+		switch fql {
+		case "1 + 1":
+			return `{"@int":"2"}`
+		case "let x = 1\nx + 1":
+			return `{"@int":"2"}`
+		}
+		return `{"error":{"code":"invalid_query","message":"bad query"},"summary":"","stats":{}}`
+	})
+
+	code, stdout, stderr := runCmd("1 + 1\n\nnope\nlet x = 1\\\nx + 1\n.exit\n1 + 1\n", "shell")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "2\n2\n", stdout)
+	assert.Equal(t, 1, strings.Count(stderr, "error: bad query"), "the shell continues after errors")
+}
+
+func TestSchema(t *testing.T) {
+	newServer(t, func(string) string { return "null" })
+
+	dir := t.TempDir()
+	code, stdout, _ := runCmd("", "schema", "pull", "-dir", dir)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "pulled schema version 7 into "+dir+"\n", stdout)
+
+	content, err := os.ReadFile(filepath.Join(dir, "main.fsl"))
+	assert.NoError(t, err)
+	assert.Equal(t, "collection Dogs {}", string(content))
+
+	code, _, _ = runCmd("", "schema", "drop")
+	assert.Equal(t, 2, code)
+}
+
+func TestExportAndImport(t *testing.T) {
+	var imported []string
+	newServer(t, func(fql string) string {
+		switch {
+		case fql == "$.all()":
+			return `{"@set":{"data":[` + scoutDoc + `]}}`
+		case strings.Contains(fql, "create(op.data)"):
+			imported = append(imported, fql)
+			return `["1"]`
+		}
+		return "null"
+	})
+
+	code, stdout, stderr := runCmd("", "export", "-collection", "Dogs", "-format", "csv", "-fields", "id,name")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "id,name\n1,Scout\n", stdout)
+	assert.Equal(t, "exported 1 documents\n", stderr)
+
+	code, _, stderr = runCmd(`{"name":"Scout"}`, "import", "-collection", "Dogs")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "imported 1 of 1 documents\n", stderr)
+	assert.Len(t, imported, 1)
+
+	code, _, stderr = runCmd(`{"name":"Scout"}`, "import", "-collection", "Dogs", "-dry-run")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "validated 1 documents\n", stderr)
+	assert.Len(t, imported, 1)
+
+	code, _, _ = runCmd("", "export")
+	assert.Equal(t, 2, code, "-collection is required")
+}
+
+func TestUsage(t *testing.T) {
+	code, _, stderr := runCmd("")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "usage: faunago")
+
+	code, _, stderr = runCmd("", "frobnicate")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `unknown command "frobnicate"`)
+}
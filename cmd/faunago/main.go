@@ -0,0 +1,79 @@
+// Command faunago runs queries and manages the schema and data of a Fauna
+// database from the command line.
+//
+// Usage:
+//
+//	faunago query [-tagged] [-f file] [fql]
+//	faunago shell [-tagged]
+//	faunago schema pull [-dir dir]
+//	faunago schema push [-dir dir] [-staged] [-force]
+//	faunago schema diff [-dir dir]
+//	faunago export -collection name [-format jsonl|csv] [-fields a,b] [-o file]
+//	faunago import -collection name [-preserve-ids] [-dry-run] [file]
+//
+// Queries are read from the argument, the file given with -f, or stdin, and
+// their results printed as JSON, simplified unless -tagged is set. The shell
+// runs each line as a query, continuing lines which end with a backslash.
+// The database is read using the FAUNA_SECRET and FAUNA_ENDPOINT environment
+// variables.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+const usage = `usage: faunago <command> [arguments]
+
+commands:
+  query    run a query from an argument, file or stdin
+  shell    run queries interactively
+  schema   pull, push or diff FSL files
+  export   write a collection as JSONL or CSV
+  import   create documents from JSONL
+`
+
+// run runs the command in args, returning the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	cmd := &command{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	var err error
+	switch args[0] {
+	case "query":
+		err = cmd.query(args[1:])
+	case "shell":
+		err = cmd.shell(args[1:])
+	case "schema":
+		err = cmd.schema(args[1:])
+	case "export":
+		err = cmd.export(args[1:])
+	case "import":
+		err = cmd.importDocs(args[1:])
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "faunago: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "faunago: %s\n", err)
+		if _, isUsage := err.(usageError); isUsage {
+			return 2
+		}
+		return 1
+	}
+
+	return 0
+}
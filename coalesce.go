@@ -0,0 +1,110 @@
+package fauna
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// CoalesceReads makes identical queries run concurrently by the
+// [fauna.Client] share a single request to Fauna, fanning its result out to
+// each caller. Queries are identical if they have the same query, arguments,
+// secret and headers, other than traceparent and query tags.
+//
+// Coalescing is intended to protect Fauna from bursts of the same read, such
+// as a popular key expiring from a cache. A query that turns out to have
+// written is re-run for each waiting caller, so writes are never deduplicated,
+// but holding concurrent writes for the first to finish delays them; use
+// [fauna.NoCoalesce] on writes that shouldn't wait.
+//
+// A query only shares a request sent after the client's last transaction
+// time, so a read issued after the client's own write never shares one that
+// started before it. A waiting query whose context ends stops waiting.
+func CoalesceReads() ClientConfigFn {
+	return func(c *Client) { c.coalescing = &flightGroup{stats: c.stats} }
+}
+
+// NoCoalesce runs a single [Client.Query] on its own, even if the
+// [fauna.Client] was configured with [fauna.CoalesceReads].
+func NoCoalesce() QueryOptFn {
+	return func(req *fqlRequest) { req.NoCoalesce = true }
+}
+
+// flightGroup tracks the requests in flight by key.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+	stats   *clientStats
+}
+
+type flight struct {
+	done     chan struct{}
+	txnTime  int64
+	res      *queryResponse
+	attempts int
+	err      error
+}
+
+// do runs send, unless a request with the same key sent with a last
+// transaction time of at least txnTime is already in flight, in which case it
+// waits for and returns that request's result instead, or ctx's error if ctx
+// ends first. shared reports whether the result came from another caller's
+// request.
+func (g *flightGroup) do(ctx context.Context, key string, txnTime int64, send func() (*queryResponse, int, error)) (res *queryResponse, attempts int, err error, shared bool) {
+	g.mu.Lock()
+	if f, found := g.flights[key]; found {
+		if f.txnTime < txnTime {
+			// the request may not see the caller's latest writes
+			g.mu.Unlock()
+			res, attempts, err = send()
+			return res, attempts, err, false
+		}
+
+		g.mu.Unlock()
+		g.stats.coalesced.Add(1)
+
+		var ctxDone <-chan struct{}
+		if ctx != nil {
+			ctxDone = ctx.Done()
+		}
+		select {
+		case <-f.done:
+			return f.res, f.attempts, f.err, true
+		case <-ctxDone:
+			return nil, 0, ctx.Err(), true
+		}
+	}
+
+	if g.flights == nil {
+		g.flights = map[string]*flight{}
+	}
+	f := &flight{done: make(chan struct{}), txnTime: txnTime}
+	g.flights[key] = f
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.flights, key)
+		g.mu.Unlock()
+		close(f.done)
+	}()
+
+	f.res, f.attempts, f.err = send()
+
+	return f.res, f.attempts, f.err, false
+}
+
+// sharable reports whether a result from another caller's request can be
+// returned for request; otherwise request must be sent itself.
+func sharable(request *fqlRequest, res *queryResponse, err error) bool {
+	if err == nil {
+		return res.Stats == nil || res.Stats.WriteOps == 0
+	}
+
+	// the other caller's context ended, which says nothing about this one
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return request.Context == nil || request.Context.Err() != nil
+	}
+
+	return true
+}
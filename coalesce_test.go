@@ -0,0 +1,193 @@
+package fauna
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitForCoalesced waits until n of client's queries have waited on queries
+// in flight.
+func waitForCoalesced(t *testing.T, client *Client, n int64) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		return client.StatsSnapshot().Coalesced == n
+	}, time.Second, time.Millisecond)
+}
+
+// newBlockingServer is a test server which holds requests until release is
+// closed.
+func newBlockingServer(t *testing.T) (srv *testServer, release chan struct{}) {
+	release = make(chan struct{})
+	srv = newTestServer(t, func(req testRequest) (int, string) {
+		<-release
+		if bytes.Contains(req.Raw, []byte("create")) {
+			return http.StatusOK, `{"data":"written","summary":"","txn_ts":1680000000000000,"stats":{"write_ops":1}}`
+		}
+		return http.StatusOK, successBody(`{"@int":"1"}`)
+	})
+
+	return srv, release
+}
+
+func TestCoalesceReads(t *testing.T) {
+	received := func(srv *testServer, n int) func() bool {
+		return func() bool { return len(srv.received()) == n }
+	}
+
+	t.Run("shares identical reads", func(t *testing.T) {
+		srv, release := newBlockingServer(t)
+		client := srv.client(CoalesceReads())
+		q, _ := FQL(`Dogs.byName(${name}).first()`, map[string]any{"name": "Scout"})
+
+		var dogs [5]int
+		results := make([]*QuerySuccess, 5)
+		var wg sync.WaitGroup
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				res, err := client.QueryInto(q, &dogs[i])
+				assert.NoError(t, err)
+				results[i] = res
+			}(i)
+		}
+		waitForCoalesced(t, client, 4)
+
+		close(release)
+		wg.Wait()
+
+		assert.Len(t, srv.received(), 1)
+		assert.Equal(t, ClientStats{Queries: 1, ComputeOps: 1, Coalesced: 4}, client.StatsSnapshot())
+		for i, res := range results {
+			assert.Equal(t, 1, dogs[i])
+			assert.Equal(t, 1, res.Stats.ComputeOps)
+		}
+	})
+
+	t.Run("re-runs writes", func(t *testing.T) {
+		srv, release := newBlockingServer(t)
+		client := srv.client(CoalesceReads())
+		q, _ := FQL(`Dogs.create({ name: "Scout" })`, nil)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				res, err := client.Query(q)
+				if assert.NoError(t, err) {
+					assert.Equal(t, "written", res.Data)
+				}
+			}()
+		}
+		waitForCoalesced(t, client, 2)
+
+		close(release)
+		wg.Wait()
+
+		assert.Len(t, srv.received(), 3)
+	})
+
+	t.Run("NoCoalesce runs separately", func(t *testing.T) {
+		srv, release := newBlockingServer(t)
+		client := srv.client(CoalesceReads())
+		q, _ := FQL(`Dogs.all()`, nil)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.Query(q, NoCoalesce())
+				assert.NoError(t, err)
+			}()
+		}
+		assert.Eventually(t, received(srv, 3), time.Second, time.Millisecond)
+
+		close(release)
+		wg.Wait()
+	})
+
+	t.Run("re-runs when the first caller's context ends", func(t *testing.T) {
+		srv, release := newBlockingServer(t)
+		client := srv.client(CoalesceReads())
+		q, _ := FQL(`Dogs.all()`, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error, 1)
+		go func() {
+			_, err := client.Query(q, QueryContext(ctx))
+			errs <- err
+		}()
+		assert.Eventually(t, received(srv, 1), time.Second, time.Millisecond)
+
+		done := make(chan *QuerySuccess, 1)
+		go func() {
+			res, err := client.Query(q)
+			assert.NoError(t, err)
+			done <- res
+		}()
+		waitForCoalesced(t, client, 1)
+
+		cancel()
+		assert.ErrorIs(t, <-errs, context.Canceled)
+		assert.Eventually(t, received(srv, 2), time.Second, time.Millisecond)
+
+		close(release)
+		if res := <-done; assert.NotNil(t, res) {
+			assert.Equal(t, int64(1), res.Data)
+		}
+	})
+
+	t.Run("waiting callers keep their own deadline", func(t *testing.T) {
+		srv, release := newBlockingServer(t)
+		defer close(release)
+		client := srv.client(CoalesceReads())
+		q, _ := FQL(`Dogs.all()`, nil)
+
+		go func() { _, _ = client.Query(q) }()
+		assert.Eventually(t, received(srv, 1), time.Second, time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := client.Query(q, QueryContext(ctx))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Len(t, srv.received(), 1, "the caller waited for the first request")
+	})
+
+	t.Run("reads after a write don't share earlier requests", func(t *testing.T) {
+		srv, release := newBlockingServer(t)
+		client := srv.client(CoalesceReads())
+		q, _ := FQL(`Dogs.all()`, nil)
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := client.Query(q)
+			assert.NoError(t, err)
+		}()
+		assert.Eventually(t, received(srv, 1), time.Second, time.Millisecond)
+
+		// the client's own write committed while the first read was in flight
+		client.SetLastTxnTime(time.Now())
+		go func() {
+			defer wg.Done()
+			_, err := client.Query(q)
+			assert.NoError(t, err)
+		}()
+		assert.Eventually(t, received(srv, 2), time.Second, time.Millisecond)
+
+		close(release)
+		wg.Wait()
+	})
+}
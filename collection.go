@@ -0,0 +1,214 @@
+package fauna
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Collection provides typed CRUD access to the documents of a single Fauna
+// collection. Documents are decoded into T, which will typically embed
+// [fauna.Document] to expose the document's id, coll, and ts.
+//
+// Methods addressing a document that doesn't exist return an
+// [fauna.ErrDocumentNotFound].
+type Collection[T any] struct {
+	client *Client
+	mod    *Module
+	hooks  CollectionHooks[T]
+}
+
+// NewCollection creates a [fauna.Collection] for the named collection.
+func NewCollection[T any](client *Client, name string) *Collection[T] {
+	return &Collection[T]{client: client, mod: &Module{Name: name}}
+}
+
+// Name returns the name of the collection.
+func (c *Collection[T]) Name() string {
+	return c.mod.Name
+}
+
+// Create creates a new document from doc and returns the stored document.
+func (c *Collection[T]) Create(doc T, opts ...QueryOptFn) (*T, error) {
+	if err := c.beforeWrite(OpCreate, "", &doc); err != nil {
+		return nil, err
+	}
+
+	created, err := c.queryOne(`${coll}.create(${doc})`, map[string]any{"doc": documentData(doc)}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.afterWrite(OpCreate, "", created)
+
+	return created, nil
+}
+
+// Get returns the document with the given id.
+func (c *Collection[T]) Get(id string, opts ...QueryOptFn) (*T, error) {
+	return c.queryOne(`${coll}.byId(${id})`, map[string]any{"id": id}, opts)
+}
+
+// Update merges patch into the document with the given id and returns the
+// updated document. patch can be a map or struct containing only the fields
+// to change.
+func (c *Collection[T]) Update(id string, patch any, opts ...QueryOptFn) (*T, error) {
+	if err := c.beforeWrite(OpUpdate, id, patch); err != nil {
+		return nil, err
+	}
+
+	updated, err := c.queryOne(
+		"let doc = ${coll}.byId(${id})\nif (doc.exists()) doc.update(${patch}) else doc",
+		map[string]any{"id": id, "patch": patch},
+		opts,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.afterWrite(OpUpdate, id, updated)
+
+	return updated, nil
+}
+
+// Replace replaces the contents of the document with the given id with doc
+// and returns the replaced document.
+func (c *Collection[T]) Replace(id string, doc T, opts ...QueryOptFn) (*T, error) {
+	if err := c.beforeWrite(OpReplace, id, &doc); err != nil {
+		return nil, err
+	}
+
+	replaced, err := c.queryOne(
+		"let doc = ${coll}.byId(${id})\nif (doc.exists()) doc.replace(${doc}) else doc",
+		map[string]any{"id": id, "doc": documentData(doc)},
+		opts,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.afterWrite(OpReplace, id, replaced)
+
+	return replaced, nil
+}
+
+// Delete deletes the document with the given id.
+func (c *Collection[T]) Delete(id string, opts ...QueryOptFn) error {
+	if err := c.beforeWrite(OpDelete, id, nil); err != nil {
+		return err
+	}
+
+	q, err := c.fql(
+		"let doc = ${coll}.byId(${id})\nlet found = doc.exists()\nif (found) doc.delete()\nfound",
+		map[string]any{"id": id},
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.Query(q, opts...)
+	if err != nil {
+		return err
+	}
+
+	if found, _ := res.Data.(bool); !found {
+		return ErrDocumentNotFound{&NullDocument{Ref: &Ref{ID: id, Coll: c.mod}, Cause: "not found"}}
+	}
+
+	c.afterWrite(OpDelete, id, nil)
+
+	return nil
+}
+
+// List paginates every document in the collection. Pages can be decoded with
+// [fauna.Page.Unmarshal] into a `[]T`.
+func (c *Collection[T]) List(opts ...QueryOptFn) (*QueryIterator, error) {
+	q, err := c.fql(`${coll}.all()`, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.client.Paginate(q, opts...), nil
+}
+
+// Where paginates the documents in the collection matching predicate, an FQL
+// predicate such as:
+//
+//	predicate, _ := fauna.FQL(`.age >= ${age}`, map[string]any{"age": 18})
+func (c *Collection[T]) Where(predicate *Query, opts ...QueryOptFn) (*QueryIterator, error) {
+	if predicate == nil {
+		return nil, fmt.Errorf("predicate must not be nil")
+	}
+
+	q, err := c.fql(`${coll}.where(${predicate})`, map[string]any{"predicate": predicate})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.client.Paginate(q, opts...), nil
+}
+
+func (c *Collection[T]) fql(query string, args map[string]any) (*Query, error) {
+	collArgs := map[string]any{"coll": c.mod}
+	for k, v := range args {
+		collArgs[k] = v
+	}
+
+	return FQL(query, collArgs)
+}
+
+func (c *Collection[T]) queryOne(query string, args map[string]any, opts []QueryOptFn) (*T, error) {
+	q, err := c.fql(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.client.Query(q, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if nullDoc, isNull := res.Data.(*NullDocument); isNull {
+		return nil, ErrDocumentNotFound{nullDoc}
+	}
+
+	var doc T
+	if err := res.Unmarshal(&doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s document: %w", c.mod.Name, err)
+	}
+
+	if c.hooks.AfterRead != nil {
+		if err := c.hooks.AfterRead(&doc); err != nil {
+			return nil, err
+		}
+	}
+
+	return &doc, nil
+}
+
+var documentType = reflect.TypeOf(Document{})
+
+// documentData returns doc with its embedded [fauna.Document] metadata
+// cleared, so a document which was fetched, modified, and written back is
+// encoded as its data rather than as a reference to itself.
+func documentData(doc any) any {
+	v := reflect.ValueOf(doc)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return doc
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return doc
+	}
+
+	data := reflect.New(v.Type()).Elem()
+	data.Set(v)
+	for i := 0; i < v.NumField(); i++ {
+		if field := v.Type().Field(i); field.Anonymous && field.Type == documentType {
+			data.Field(i).Set(reflect.Zero(documentType))
+		}
+	}
+
+	return data.Interface()
+}
@@ -0,0 +1,120 @@
+package fauna
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type collectionTestDino struct {
+	Document
+	Name string `fauna:"name"`
+	Age  int    `fauna:"age"`
+}
+
+func TestCollection(t *testing.T) {
+	const dinoDoc = `{"@doc":{"id":"1","coll":{"@mod":"Dinos"},"ts":{"@time":"2023-02-28T18:10:10.00001Z"},"name":"Dino","age":{"@int":"3"}}}`
+	const nullDoc = `{"@ref":{"id":"2","coll":{"@mod":"Dinos"},"exists":false,"cause":"not found"}}`
+
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		id := ""
+		for _, f := range req.Body["query"].(map[string]any)["fql"].([]any) {
+			if v, isValue := f.(map[string]any); isValue {
+				if s, isString := v["value"].(string); isString {
+					id = s
+				}
+			}
+		}
+
+		switch id {
+		case "2":
+			return http.StatusOK, successBody(nullDoc)
+		case "3":
+			return http.StatusOK, successBody(`false`)
+		case "4":
+			return http.StatusOK, successBody(`true`)
+		default:
+			return http.StatusOK, successBody(dinoDoc)
+		}
+	})
+	dinos := NewCollection[collectionTestDino](srv.client(), "Dinos")
+	assert.Equal(t, "Dinos", dinos.Name())
+
+	t.Run("create", func(t *testing.T) {
+		dino, err := dinos.Create(collectionTestDino{Name: "Dino", Age: 3})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "1", dino.ID)
+			assert.Equal(t, "Dino", dino.Name)
+			assert.Equal(t, 3, dino.Age)
+		}
+
+		received := srv.received()
+		assert.Equal(t, map[string]any{"fql": []any{
+			map[string]any{"value": map[string]any{"@mod": "Dinos"}},
+			".create(",
+			map[string]any{"value": map[string]any{"name": "Dino", "age": map[string]any{"@int": "3"}}},
+			")",
+		}}, received[len(received)-1].Body["query"])
+	})
+
+	t.Run("get", func(t *testing.T) {
+		dino, err := dinos.Get("1")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Dino", dino.Name)
+		}
+
+		_, err = dinos.Get("2")
+		var notFound ErrDocumentNotFound
+		if assert.ErrorAs(t, err, &notFound) {
+			assert.Equal(t, "2", notFound.Ref.ID)
+			assert.Equal(t, "document 2 in Dinos not found: not found", notFound.Error())
+		}
+	})
+
+	t.Run("update and replace", func(t *testing.T) {
+		_, err := dinos.Update("1", map[string]any{"age": 4})
+		assert.NoError(t, err)
+
+		_, err = dinos.Replace("2", collectionTestDino{Name: "Dino"})
+		assert.ErrorAs(t, err, &ErrDocumentNotFound{})
+	})
+
+	t.Run("replace a fetched document", func(t *testing.T) {
+		dino, err := dinos.Get("1")
+		if !assert.NoError(t, err) || !assert.NotNil(t, dino.TS) {
+			return
+		}
+
+		dino.Age = 4
+		_, err = dinos.Replace("1", *dino)
+		assert.NoError(t, err)
+
+		received := srv.received()
+		fql := received[len(received)-1].Body["query"].(map[string]any)["fql"].([]any)
+		assert.Contains(t, fql, map[string]any{"value": map[string]any{"name": "Dino", "age": map[string]any{"@int": "4"}}})
+
+		_, err = dinos.Create(*dino)
+		assert.NoError(t, err)
+
+		received = srv.received()
+		fql = received[len(received)-1].Body["query"].(map[string]any)["fql"].([]any)
+		assert.Contains(t, fql, map[string]any{"value": map[string]any{"name": "Dino", "age": map[string]any{"@int": "4"}}})
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, dinos.Delete("4"))
+		assert.ErrorAs(t, dinos.Delete("3"), &ErrDocumentNotFound{})
+	})
+
+	t.Run("where", func(t *testing.T) {
+		_, err := dinos.Where(nil)
+		assert.Error(t, err)
+
+		predicate, _ := FQL(".age > ${age}", map[string]any{"age": 2})
+		it, err := dinos.Where(predicate)
+		if assert.NoError(t, err) {
+			assert.True(t, it.HasNext())
+		}
+	})
+}
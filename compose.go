@@ -0,0 +1,137 @@
+package fauna
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var identifierRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reservedWords can't be used as FQL identifiers.
+var reservedWords = map[string]bool{
+	"at": true, "else": true, "false": true, "if": true,
+	"isa": true, "let": true, "null": true, "true": true,
+}
+
+func validIdentifier(name string) error {
+	if !identifierRe.MatchString(name) {
+		return fmt.Errorf("invalid identifier %q", name)
+	}
+
+	if reservedWords[name] {
+		return fmt.Errorf("identifier %q is a reserved word", name)
+	}
+
+	return nil
+}
+
+// fieldPath validates a dotted field path, such as "address.zip", and returns
+// its FQL accessor, `.address.zip`.
+func fieldPath(field string) (string, error) {
+	if field == "" {
+		return "", fmt.Errorf("field must not be empty")
+	}
+
+	segments := strings.Split(field, ".")
+	for _, segment := range segments {
+		if err := validIdentifier(segment); err != nil {
+			return "", fmt.Errorf("invalid field %q: %w", field, err)
+		}
+	}
+
+	return "." + strings.Join(segments, "."), nil
+}
+
+// Let creates a [fauna.Query] binding value to name, `let name = value`. The
+// value can be a [fauna.Query] to bind the result of a sub-query, or any value
+// [fauna.FQL] accepts as an argument.
+//
+// Let statements are intended to be combined with [fauna.Block].
+func Let(name string, value any) (*Query, error) {
+	if err := validIdentifier(name); err != nil {
+		return nil, err
+	}
+
+	if q, isQuery := value.(*Query); isQuery && q == nil {
+		return nil, fmt.Errorf("let %s: value is a nil query", name)
+	}
+
+	return &Query{fragments: []*queryFragment{
+		{true, fmt.Sprintf("let %s = ", name)},
+		{false, value},
+	}}, nil
+}
+
+// Block creates a [fauna.Query] running each statement in order. The block
+// evaluates to the result of its final statement, and bindings made with
+// [fauna.Let] are visible to all statements that follow them.
+func Block(statements ...*Query) (*Query, error) {
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("block must have at least one statement")
+	}
+
+	fragments := []*queryFragment{{true, "{\n"}}
+	for i, statement := range statements {
+		if statement == nil {
+			return nil, fmt.Errorf("block statement %d is nil", i)
+		}
+
+		fragments = append(fragments, &queryFragment{false, statement}, &queryFragment{true, "\n"})
+	}
+	fragments = append(fragments, &queryFragment{true, "}"})
+
+	return &Query{fragments: fragments}, nil
+}
+
+// Ident creates a [fauna.Query] for an identifier such as a collection, field,
+// or function name, for positions where an argument can't be used. The name is
+// validated rather than escaped, so anything other than a plain identifier is
+// an error.
+//
+//	field, err := fauna.Ident(userProvidedField)
+//	q, err := fauna.FQL(`Users.all().order(.${field})`, map[string]any{"field": field})
+func Ident(name string) (*Query, error) {
+	if err := validIdentifier(name); err != nil {
+		return nil, err
+	}
+
+	return &Query{fragments: []*queryFragment{{true, name}}}, nil
+}
+
+// StringLit creates a [fauna.Query] for an FQL string literal containing s.
+// The literal is single quoted, so it is never interpolated, and every quote,
+// backslash, and control character is escaped.
+//
+// Prefer passing strings as [fauna.FQL] arguments, StringLit is for places the
+// FQL grammar requires a literal.
+func StringLit(s string) *Query {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\'':
+			b.WriteString(`\'`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if unicode.IsControl(r) {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('\'')
+
+	return &Query{fragments: []*queryFragment{{true, b.String()}}}
+}
@@ -0,0 +1,104 @@
+package fauna
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLet(t *testing.T) {
+	t.Run("binds a value", func(t *testing.T) {
+		q, err := Let("x", 5)
+		if assert.NoError(t, err) {
+			bs := marshalAndCheck(t, q)
+			assert.JSONEq(t, `{"fql":["let x = ",{"value":{"@int":"5"}}]}`, string(bs))
+		}
+	})
+
+	t.Run("binds a sub query", func(t *testing.T) {
+		user, _ := FQL("Users.byId(${id})", map[string]any{"id": "1"})
+		q, err := Let("user", user)
+		if assert.NoError(t, err) {
+			bs := marshalAndCheck(t, q)
+			assert.JSONEq(t, `{"fql":["let user = ",{"fql":["Users.byId(",{"value":"1"},")"]}]}`, string(bs))
+		}
+	})
+
+	t.Run("rejects invalid names", func(t *testing.T) {
+		for _, name := range []string{"", "1x", "x y", "x}", "let", "null"} {
+			_, err := Let(name, 1)
+			assert.Error(t, err, name)
+		}
+	})
+
+	t.Run("rejects nil queries", func(t *testing.T) {
+		var q *Query
+		_, err := Let("x", q)
+		assert.Error(t, err)
+	})
+}
+
+func TestBlock(t *testing.T) {
+	t.Run("composes statements", func(t *testing.T) {
+		x, _ := Let("x", 2)
+		y, _ := FQL("x * ${factor}", map[string]any{"factor": 3})
+
+		q, err := Block(x, y)
+		if assert.NoError(t, err) {
+			bs := marshalAndCheck(t, q)
+			assert.JSONEq(t, `{"fql":[
+        "{\n",
+        {"fql":["let x = ",{"value":{"@int":"2"}}]},
+        "\n",
+        {"fql":["x * ",{"value":{"@int":"3"}}]},
+        "\n",
+        "}"
+      ]}`, string(bs))
+		}
+	})
+
+	t.Run("requires statements", func(t *testing.T) {
+		_, err := Block()
+		assert.Error(t, err)
+
+		_, err = Block(nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestIdent(t *testing.T) {
+	t.Run("valid identifiers", func(t *testing.T) {
+		field, err := Ident("first_name")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		q, err := FQL("Users.all().order(.${field})", map[string]any{"field": field})
+		if assert.NoError(t, err) {
+			bs := marshalAndCheck(t, q)
+			assert.JSONEq(t, `{"fql":["Users.all().order(.",{"fql":["first_name"]},")"]}`, string(bs))
+		}
+	})
+
+	t.Run("rejects injection", func(t *testing.T) {
+		for _, name := range []string{"name) ; Users.all().delete(", "a.b", "", "if", "${x}"} {
+			_, err := Ident(name)
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestStringLit(t *testing.T) {
+	testCases := map[string]string{
+		"plain":                      `'plain'`,
+		"it's":                       `'it\'s'`,
+		`back\slash`:                 `'back\\slash'`,
+		"line\nbreak\ttab\r":         `'line\nbreak\ttab\r'`,
+		"#{interpolation} \"quote\"": `'#{interpolation} "quote"'`,
+		"bell\a":                     `'bell\u0007'`,
+	}
+
+	for given, wants := range testCases {
+		assert.Equal(t, &Query{fragments: []*queryFragment{{true, wants}}}, StringLit(given))
+	}
+}
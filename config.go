@@ -2,6 +2,8 @@ package fauna
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -12,9 +14,13 @@ import (
 // ClientConfigFn configuration options for the [fauna.Client]
 type ClientConfigFn func(*Client)
 
-// Context specify the context to be used for the [fauna.Client]
+// Context has no effect. The [fauna.Client] doesn't hold a context, as one
+// context rarely fits every call made with a client.
+//
+// Deprecated: pass each query's context with [fauna.QueryContext], and pass a
+// context directly to methods which take one, such as [Client.PullSchema].
 func Context(ctx context.Context) ClientConfigFn {
-	return func(c *Client) { c.ctx = ctx }
+	return func(c *Client) {}
 }
 
 // HTTPClient set the http.Client for the [fauna.Client]
@@ -22,6 +28,33 @@ func HTTPClient(client *http.Client) ClientConfigFn {
 	return func(c *Client) { c.http = client }
 }
 
+// AppName identifies the application using the [fauna.Client] to Fauna, so its
+// traffic can be told apart in logs and support cases. `app=name/version` is
+// added to the driver environment header, and `name/version` to the
+// User-Agent header. version may be empty.
+func AppName(name, version string) ClientConfigFn {
+	return func(c *Client) {
+		app := appToken(name)
+		if version != "" {
+			app += "/" + appToken(version)
+		}
+
+		c.setHeader(headerDriverEnv, c.headers[headerDriverEnv]+"; app="+app)
+		c.setHeader(headerUserAgent, "fauna-go/"+strings.TrimSpace(driverVersion)+" "+app)
+	}
+}
+
+// appToken replaces the characters of s which would break the headers set by
+// [fauna.AppName].
+func appToken(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f || strings.ContainsRune(`;,/()"`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(s))
+}
+
 // AdditionalHeaders specify headers for the [fauna.Client]
 func AdditionalHeaders(headers map[string]string) ClientConfigFn {
 	return func(c *Client) {
@@ -37,12 +70,36 @@ func MaxAttempts(attempts int) ClientConfigFn {
 	return func(c *Client) { c.maxAttempts = attempts }
 }
 
+// NoRetries makes the [fauna.Client] attempt each query exactly once, returning
+// [fauna.ErrThrottling] rather than retrying throttled queries, for
+// non-idempotent work or callers that retry queries themselves. It's the same
+// as MaxAttempts(1).
+func NoRetries() ClientConfigFn {
+	return MaxAttempts(1)
+}
+
+// PlainSummary removes terminal escape sequences, such as colors, from the
+// summaries of the [fauna.Client]'s queries and the messages of their errors,
+// for logging them where the sequences aren't rendered, such as JSON logs.
+func PlainSummary() ClientConfigFn {
+	return func(c *Client) { c.plainSummary = true }
+}
+
 // MaxBackoff sets the maximum duration the [fauna.Client] will wait
 // before retrying.
 func MaxBackoff(backoff time.Duration) ClientConfigFn {
 	return func(c *Client) { c.maxBackoff = backoff }
 }
 
+// PageRetries sets how many more times a [fauna.QueryIterator] fetches a
+// page with the same cursor when it's still throttled after the
+// [fauna.Client]'s MaxAttempts, waiting for the response's Retry-After or
+// the backoff before each, so a burst of throttling doesn't end a long
+// pagination. The default is 3, and 0 returns the error.
+func PageRetries(retries int) ClientConfigFn {
+	return func(c *Client) { c.pageRetries = retries }
+}
+
 // DefaultTypecheck set header on the [fauna.Client]
 // Enable or disable typechecking of the query before evaluation. If
 // not set, Fauna will use the value of the "typechecked" flag on
@@ -73,23 +130,30 @@ func MaxContentionRetries(i int) ClientConfigFn {
 // QueryTimeout set header on the [fauna.Client]
 func QueryTimeout(d time.Duration) ClientConfigFn {
 	return func(c *Client) {
-		c.setHeader(HeaderQueryTimeoutMs, fmt.Sprintf("%v", d.Milliseconds()))
+		c.updateEndpoint(func(e *endpointConfig) { e.queryTimeout = fmt.Sprintf("%v", d.Milliseconds()) })
 	}
 }
 
 // QueryTags sets header on the [fauna.Client]
 // Set tags to associate with the query. See [logging]
 //
+// Client tags are defaults for every query, such as the service name and
+// version, and are merged with those set on each query with [fauna.Tags]. A
+// tag set more than once takes the last value set, and tags set on a query
+// take precedence over the client's.
+//
 // [logging]: https://docs.fauna.com/fauna/current/build/logs/query_log/
 func QueryTags(tags map[string]string) ClientConfigFn {
 	return func(c *Client) {
-		c.setHeader(HeaderTags, argsStringFromMap(tags))
+		c.setHeader(HeaderTags, mergeTags(c.headers[HeaderTags], tags))
 	}
 }
 
 // URL set the [fauna.Client] URL
 func URL(url string) ClientConfigFn {
-	return func(c *Client) { c.url = url }
+	return func(c *Client) {
+		c.updateEndpoint(func(e *endpointConfig) { e.url = url })
+	}
 }
 
 // QueryOptFn function to set options on the [Client.Query]
@@ -102,14 +166,12 @@ func QueryContext(ctx context.Context) QueryOptFn {
 	}
 }
 
-// Tags set the tags header on a single [Client.Query]
+// Tags set the tags header on a single [Client.Query], merged with the
+// [fauna.Client]'s [fauna.QueryTags]. Where both set a tag the query's value
+// is used.
 func Tags(tags map[string]string) QueryOptFn {
 	return func(req *fqlRequest) {
-		if val, exists := req.Headers[HeaderTags]; exists {
-			req.Headers[HeaderTags] = argsStringFromMap(tags, strings.Split(val, ",")...)
-		} else {
-			req.Headers[HeaderTags] = argsStringFromMap(tags)
-		}
+		req.Headers[HeaderTags] = mergeTags(req.Headers[HeaderTags], tags)
 	}
 }
 
@@ -118,6 +180,80 @@ func Traceparent(id string) QueryOptFn {
 	return func(req *fqlRequest) { req.Headers[HeaderTraceparent] = id }
 }
 
+// WithSpanContext propagates the [fauna.SpanContext] carried by ctx on a single
+// [Client.Query], using the [fauna.Client]'s [fauna.Propagator], without
+// making the query with ctx. It's ignored if ctx has no span context.
+func WithSpanContext(ctx context.Context) QueryOptFn {
+	return func(req *fqlRequest) {
+		if sc, ok := SpanContextFromContext(ctx); ok {
+			req.SpanContext = sc
+		}
+	}
+}
+
+// reservedHeaders are set by the driver, so can't be set with [fauna.Header].
+var reservedHeaders = map[string]bool{
+	headerAuthorization: true,
+	headerContentType:   true,
+	headerDriver:        true,
+	headerDriverEnv:     true,
+	headerFormat:        true,
+	headerUserAgent:     true,
+	HeaderLastTxnTs:     true,
+	"Connection":        true,
+	"Content-Length":    true,
+	"Host":              true,
+	"Transfer-Encoding": true,
+}
+
+// Header sets an arbitrary header on a single [Client.Query], such as one
+// read by a gateway in front of Fauna, or one the driver has no option for
+// yet. The query fails without being sent if key isn't a valid header name,
+// value contains control characters, or the header is one the driver sets
+// itself, such as `Authorization`.
+func Header(key, value string) QueryOptFn {
+	return func(req *fqlRequest) {
+		key := http.CanonicalHeaderKey(key)
+		switch {
+		case !validHeaderName(key):
+			req.Err = fmt.Errorf("invalid header name %q", key)
+		case !validHeaderValue(value):
+			req.Err = fmt.Errorf("invalid value for header %s", key)
+		case reservedHeaders[key]:
+			req.Err = fmt.Errorf("header %s is set by the driver", key)
+		default:
+			req.Headers[key] = value
+		}
+	}
+}
+
+func validHeaderName(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func validHeaderValue(value string) bool {
+	for _, r := range value {
+		if (r < ' ' && r != '\t') || r == 0x7f {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Timeout set the query timeout on a single [Client.Query]
 func Timeout(dur time.Duration) QueryOptFn {
 	return func(req *fqlRequest) {
@@ -125,20 +261,94 @@ func Timeout(dur time.Duration) QueryOptFn {
 	}
 }
 
+// MaxResponseSize fails a single [Client.Query] with [fauna.ErrResponseTooLarge]
+// if the response body is longer than limit bytes, rather than reading it into
+// memory. [Client.QueryRaw] writes nothing if the response is too large, so
+// buffers responses without a Content-Length to check their size.
+func MaxResponseSize(limit int64) QueryOptFn {
+	return func(req *fqlRequest) { req.MaxResponseSize = limit }
+}
+
+// IdempotencyKey sends key in the `Idempotency-Key` header of a single
+// [Client.Query], so a server or gateway which supports idempotency keys
+// applies a write at most once however many times it's sent. The key is sent
+// with every attempt, including retries by the [fauna.Client]. To retry a
+// query yourself, such as after an [fauna.ErrNetwork] leaves it unknown
+// whether the write was applied, pass the same key again; [NewIdempotencyKey]
+// generates one. An empty key generates a key for this query alone.
+func IdempotencyKey(key string) QueryOptFn {
+	return func(req *fqlRequest) {
+		if key == "" {
+			req.Headers[HeaderIdempotencyKey] = NewIdempotencyKey()
+		} else {
+			req.Headers[HeaderIdempotencyKey] = key
+		}
+	}
+}
+
+// NewIdempotencyKey returns a random key for [fauna.IdempotencyKey].
+func NewIdempotencyKey() string {
+	var key [16]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		panic(fmt.Sprintf("failed to generate idempotency key: %s", err))
+	}
+
+	return hex.EncodeToString(key[:])
+}
+
+// newRequestID returns a random ID for the `x-request-id` header, identifying
+// a single attempt to send a request.
+func newRequestID() string {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		panic(fmt.Sprintf("failed to generate request ID: %s", err))
+	}
+
+	return hex.EncodeToString(id[:])
+}
+
+// QueryNoRetries attempts a single [Client.Query] exactly once, as with
+// [fauna.NoRetries].
+func QueryNoRetries() QueryOptFn {
+	return func(req *fqlRequest) { req.NoRetries = true }
+}
+
+// QueryPlainSummary removes terminal escape sequences from the summary of a
+// single [Client.Query], as with [fauna.PlainSummary].
+func QueryPlainSummary() QueryOptFn {
+	return func(req *fqlRequest) { req.PlainSummary = true }
+}
+
 // Typecheck sets the header on a single [Client.Query]
 func Typecheck(enabled bool) QueryOptFn {
 	return func(req *fqlRequest) { req.Headers[HeaderTypecheck] = fmt.Sprintf("%v", enabled) }
 }
 
-func argsStringFromMap(input map[string]string, currentArgs ...string) string {
+// mergeTags adds tags to the encoded tags header, replacing the values of
+// tags already in it. Tags are encoded in key order, so the header is the same
+// however the tags were merged.
+func mergeTags(header string, tags map[string]string) string {
 	params := url.Values{}
 
-	for _, c := range currentArgs {
-		s := strings.Split(c, "=")
-		params.Set(s[0], s[1])
+	for _, tag := range strings.Split(header, ",") {
+		if tag == "" {
+			continue
+		}
+
+		k, v := tag, ""
+		if i := strings.IndexByte(tag, '='); i >= 0 {
+			k, v = tag[:i], tag[i+1:]
+		}
+		if unescaped, err := url.QueryUnescape(k); err == nil {
+			k = unescaped
+		}
+		if unescaped, err := url.QueryUnescape(v); err == nil {
+			v = unescaped
+		}
+		params.Set(k, v)
 	}
 
-	for k, v := range input {
+	for k, v := range tags {
 		params.Set(k, v)
 	}
 
@@ -0,0 +1,117 @@
+package fauna
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCursor is returned by [fauna.CursorCodec] Open for a token which
+// it didn't seal or which was changed.
+var ErrInvalidCursor = errors.New("invalid cursor token")
+
+// A CursorCodec seals pagination cursors into opaque tokens before they're
+// given to untrusted clients, such as in the response of a list endpoint, and
+// opens them when the client asks for the next page, so clients can't change
+// a cursor to read other data. Tokens are signed with HMAC-SHA256, and
+// optionally encrypted with AES-GCM so clients can't read the cursor either.
+// Tokens are URL safe.
+type CursorCodec struct {
+	signingKey    []byte
+	encryptionKey []byte
+}
+
+// NewCursorCodec creates a [fauna.CursorCodec] which signs tokens with
+// signingKey, which should be at least 32 random bytes. If encryptionKey isn't
+// nil, tokens are also encrypted with it, and it must be 16, 24, or 32 bytes
+// long.
+func NewCursorCodec(signingKey []byte, encryptionKey []byte) (*CursorCodec, error) {
+	if len(signingKey) == 0 {
+		return nil, fmt.Errorf("signing key must not be empty")
+	}
+	if encryptionKey != nil {
+		if _, err := newGCM(encryptionKey); err != nil {
+			return nil, fmt.Errorf("invalid encryption key: %w", err)
+		}
+	}
+
+	return &CursorCodec{signingKey: signingKey, encryptionKey: encryptionKey}, nil
+}
+
+// Seal returns the token for cursor, such as a [fauna.Page] After cursor.
+// The token of an empty cursor, for the last page, is empty.
+func (c *CursorCodec) Seal(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+
+	payload := []byte(cursor)
+	if c.encryptionKey != nil {
+		gcm, err := newGCM(c.encryptionKey)
+		if err != nil {
+			return "", err
+		}
+
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", fmt.Errorf("failed to seal cursor: %w", err)
+		}
+		payload = gcm.Seal(nonce, nonce, payload, nil)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(append(payload, c.sign(payload)...)), nil
+}
+
+// Open verifies token and returns the cursor sealed in it, or
+// [fauna.ErrInvalidCursor] if it can't be verified.
+func (c *CursorCodec) Open(token string) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(sealed) < sha256.Size {
+		return "", ErrInvalidCursor
+	}
+
+	payload, sig := sealed[:len(sealed)-sha256.Size], sealed[len(sealed)-sha256.Size:]
+	if !hmac.Equal(sig, c.sign(payload)) {
+		return "", ErrInvalidCursor
+	}
+
+	if c.encryptionKey != nil {
+		gcm, err := newGCM(c.encryptionKey)
+		if err != nil {
+			return "", err
+		}
+		if len(payload) < gcm.NonceSize() {
+			return "", ErrInvalidCursor
+		}
+
+		payload, err = gcm.Open(nil, payload[:gcm.NonceSize()], payload[gcm.NonceSize():], nil)
+		if err != nil {
+			return "", ErrInvalidCursor
+		}
+	}
+
+	return string(payload), nil
+}
+
+// Resume opens token and paginates the set from the cursor sealed in it.
+func (c *CursorCodec) Resume(client *Client, token string, opts ...QueryOptFn) (*QueryIterator, error) {
+	cursor, err := c.Open(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.PaginateSet(&Page{After: cursor}, opts...), nil
+}
+
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.signingKey)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
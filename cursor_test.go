@@ -0,0 +1,105 @@
+package fauna
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorCodec(t *testing.T) {
+	signingKey := []byte(strings.Repeat("s", 32))
+	encryptionKey := []byte(strings.Repeat("e", 32))
+
+	signed, err := NewCursorCodec(signingKey, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	encrypted, err := NewCursorCodec(signingKey, encryptionKey)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		for name, codec := range map[string]*CursorCodec{"signed": signed, "encrypted": encrypted} {
+			token, err := codec.Seal("hdWCxmd0aGU/")
+			if !assert.NoError(t, err, name) {
+				continue
+			}
+			assert.NotContains(t, token, "/", name)
+
+			cursor, err := codec.Open(token)
+			if assert.NoError(t, err, name) {
+				assert.Equal(t, "hdWCxmd0aGU/", cursor, name)
+			}
+		}
+	})
+
+	t.Run("encrypted tokens hide the cursor", func(t *testing.T) {
+		a, _ := encrypted.Seal("cursor")
+		b, _ := encrypted.Seal("cursor")
+		assert.NotEqual(t, a, b)
+
+		signedToken, _ := signed.Seal("cursor")
+		_, err := encrypted.Open(signedToken)
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("tampered tokens", func(t *testing.T) {
+		token, _ := signed.Seal("cursor")
+		raw := []byte(token)
+		raw[0] ^= 1
+
+		for _, bad := range []string{string(raw), token[:10], "not base64!", "YQ"} {
+			_, err := signed.Open(bad)
+			assert.ErrorIs(t, err, ErrInvalidCursor, bad)
+		}
+
+		other, _ := NewCursorCodec([]byte(strings.Repeat("o", 32)), nil)
+		_, err := other.Open(token)
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("empty cursors", func(t *testing.T) {
+		token, err := signed.Seal("")
+		if assert.NoError(t, err) {
+			assert.Empty(t, token)
+		}
+
+		cursor, err := signed.Open("")
+		if assert.NoError(t, err) {
+			assert.Empty(t, cursor)
+		}
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		_, err := NewCursorCodec(nil, nil)
+		assert.Error(t, err)
+
+		_, err = NewCursorCodec(signingKey, []byte("short"))
+		assert.Error(t, err)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		srv := newTestServer(t, func(req testRequest) (int, string) {
+			return http.StatusOK, successBody(`{"@set":{"data":["b"]}}`)
+		})
+		client := srv.client()
+
+		token, _ := encrypted.Seal("next")
+		it, err := encrypted.Resume(client, token)
+		if assert.NoError(t, err) {
+			page, err := it.Next()
+			if assert.NoError(t, err) {
+				assert.Equal(t, []any{"b"}, page.Data)
+			}
+
+			bs, _ := marshal(srv.received()[0].Body["query"])
+			assert.Contains(t, string(bs), `"next"`)
+		}
+
+		_, err = encrypted.Resume(client, token+"x")
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
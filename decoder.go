@@ -0,0 +1,699 @@
+package fauna
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// decodeDirect decodes the tagged JSON in data straight into `into`, which
+// must be a non-nil pointer, without first building the map[string]any tree
+// that [fauna.QuerySuccess.Data] holds. Structs, slices, maps, and scalars are
+// decoded from the bytes as they're scanned. Values without a fast path, such
+// as refs or interface fields, fall back to the generic decoder for just that
+// value, so results match [fauna.QuerySuccess.Unmarshal].
+func decodeDirect(data []byte, into any) error {
+	return codec{}.decodeDirect(data, into)
+}
+
+func (c codec) decodeDirect(data []byte, into any) error {
+	rv := reflect.ValueOf(into)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer, got %T", into)
+	}
+
+	s := &scanner{data: data, codec: c}
+	if err := s.value(rv.Elem()); err != nil {
+		return err
+	}
+
+	if s.skipSpace(); s.pos != len(s.data) {
+		return s.errorf("unexpected data after value")
+	}
+
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// scanner walks a single JSON document held in memory.
+type scanner struct {
+	data  []byte
+	pos   int
+	codec codec
+}
+
+func (s *scanner) errorf(format string, args ...any) error {
+	return fmt.Errorf("failed to decode at offset %d: %s", s.pos, fmt.Sprintf(format, args...))
+}
+
+func (s *scanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (s *scanner) peek() byte {
+	s.skipSpace()
+	if s.pos >= len(s.data) {
+		return 0
+	}
+
+	return s.data[s.pos]
+}
+
+func (s *scanner) expect(c byte) error {
+	if s.peek() != c {
+		return s.errorf("expected %q", c)
+	}
+
+	s.pos++
+	return nil
+}
+
+// literal consumes lit, such as `null` or `true`.
+func (s *scanner) literal(lit string) error {
+	s.skipSpace()
+	if !bytes.HasPrefix(s.data[s.pos:], []byte(lit)) {
+		return s.errorf("expected %s", lit)
+	}
+
+	s.pos += len(lit)
+	return nil
+}
+
+// str consumes a string, returning its contents. The result aliases the
+// scanned data unless the string contains escapes.
+func (s *scanner) str() ([]byte, error) {
+	if err := s.expect('"'); err != nil {
+		return nil, err
+	}
+
+	start, escaped := s.pos, false
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '\\':
+			escaped = true
+			s.pos += 2
+			continue
+		case '"':
+			raw := s.data[start:s.pos]
+			s.pos++
+
+			if !escaped {
+				return raw, nil
+			}
+
+			var unquoted string
+			if err := json.Unmarshal(s.data[start-1:s.pos], &unquoted); err != nil {
+				return nil, err
+			}
+			return []byte(unquoted), nil
+		}
+		s.pos++
+	}
+
+	return nil, s.errorf("unterminated string")
+}
+
+// skip consumes a value of any type, returning its raw bytes.
+func (s *scanner) skip() ([]byte, error) {
+	s.skipSpace()
+	start := s.pos
+
+	switch s.peek() {
+	case '"':
+		if _, err := s.str(); err != nil {
+			return nil, err
+		}
+
+	case '{', '[':
+		depth := 0
+		for s.pos < len(s.data) {
+			switch s.data[s.pos] {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			case '"':
+				if _, err := s.str(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			s.pos++
+
+			if depth == 0 {
+				return s.data[start:s.pos], nil
+			}
+		}
+		return nil, s.errorf("unterminated value")
+
+	default:
+		for s.pos < len(s.data) && !endsScalar(s.data[s.pos]) {
+			s.pos++
+		}
+		if s.pos == start {
+			return nil, s.errorf("expected value")
+		}
+	}
+
+	return s.data[start:s.pos], nil
+}
+
+func endsScalar(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', ',', ']', '}':
+		return true
+	}
+
+	return false
+}
+
+// fallback decodes the next value with the generic decoder.
+func (s *scanner) fallback(v reflect.Value) error {
+	raw, err := s.skip()
+	if err != nil {
+		return err
+	}
+
+	target := v
+	if !v.CanAddr() {
+		target = reflect.New(v.Type()).Elem()
+	}
+
+	if err := s.codec.unmarshal(raw, target.Addr().Interface()); err != nil {
+		return err
+	}
+
+	if !v.CanAddr() {
+		v.Set(target)
+	}
+
+	return nil
+}
+
+func (s *scanner) value(v reflect.Value) error {
+	if v.Kind() == reflect.Struct && v.CanAddr() && isNullable(v.Type()) {
+		n := v.Addr().Interface().(nullableDecoder)
+		if s.peek() == 'n' {
+			n.reset(true)
+			return s.literal("null")
+		}
+		return s.value(n.reset(false))
+	}
+
+	switch s.peek() {
+	case 'n':
+		if err := s.literal("null"); err != nil {
+			return err
+		}
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+
+	case 0:
+		return s.errorf("unexpected end of data")
+	}
+
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return s.value(v.Elem())
+	}
+
+	if v.Kind() == reflect.Interface || (v.Kind() == reflect.Struct && v.Type() != timeType && !s.codec.hasFastPath(v.Type())) {
+		return s.fallback(v)
+	}
+
+	switch s.peek() {
+	case '{':
+		return s.object(v)
+
+	case '[':
+		return s.array(v)
+
+	case '"':
+		raw, err := s.str()
+		if err != nil {
+			return err
+		}
+		if v.Kind() != reflect.String {
+			return s.errorf("cannot decode string into %s", v.Type())
+		}
+		v.SetString(string(raw))
+		return nil
+
+	case 't', 'f':
+		if v.Kind() != reflect.Bool {
+			return s.errorf("cannot decode bool into %s", v.Type())
+		}
+		if s.peek() == 't' {
+			v.SetBool(true)
+			return s.literal("true")
+		}
+		v.SetBool(false)
+		return s.literal("false")
+	}
+
+	raw, err := s.skip()
+	if err != nil {
+		return err
+	}
+	return s.number(v, raw)
+}
+
+// number sets v from the text of an int or double.
+func (s *scanner) number(v reflect.Value, raw []byte) error {
+	text := string(raw)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			f, floatErr := strconv.ParseFloat(text, 64)
+			if floatErr != nil {
+				return err
+			}
+			i = int64(f)
+		}
+		if v.OverflowInt(i) {
+			return s.errorf("%s overflows %s", text, v.Type())
+		}
+		v.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return err
+		}
+		if i < 0 || v.OverflowUint(uint64(i)) {
+			return s.errorf("%s overflows %s", text, v.Type())
+		}
+		v.SetUint(uint64(i))
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return err
+		}
+		if v.Kind() == reflect.Float32 && math.Abs(f) > math.MaxFloat32 && !math.IsInf(f, 0) {
+			return s.errorf("%s overflows %s", text, v.Type())
+		}
+		v.SetFloat(f)
+
+	default:
+		return s.errorf("cannot decode number into %s", v.Type())
+	}
+
+	return nil
+}
+
+func (s *scanner) array(v reflect.Value) error {
+	start := s.pos
+	if err := s.expect('['); err != nil {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(v.Type(), 0, 0)
+		for i := 0; ; i++ {
+			if s.peek() == ']' {
+				s.pos++
+				break
+			}
+			if i > 0 {
+				if err := s.expect(','); err != nil {
+					return err
+				}
+			}
+
+			slice = reflect.Append(slice, reflect.Zero(v.Type().Elem()))
+			if err := s.value(slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+
+	case reflect.Array:
+		for i := 0; ; i++ {
+			if s.peek() == ']' {
+				s.pos++
+				return nil
+			}
+			if i > 0 {
+				if err := s.expect(','); err != nil {
+					return err
+				}
+			}
+
+			if i >= v.Len() {
+				if _, err := s.skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := s.value(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.pos = start
+	return s.errorf("cannot decode array into %s", v.Type())
+}
+
+// object decodes an object, unwrapping the tagged values the fast path
+// supports and falling back for the rest.
+func (s *scanner) object(v reflect.Value) error {
+	start := s.pos
+	if err := s.expect('{'); err != nil {
+		return err
+	}
+
+	if s.peek() == '"' {
+		key, err := s.str()
+		if err != nil {
+			return err
+		}
+
+		if len(key) > 0 && key[0] == '@' {
+			if err := s.expect(':'); err != nil {
+				return err
+			}
+
+			handled, err := s.tagged(typeTag(key), v)
+			if err != nil {
+				return err
+			}
+
+			if handled {
+				return s.expect('}')
+			}
+
+			s.pos = start
+			return s.fallback(v)
+		}
+	}
+
+	s.pos = start
+	return s.fields(v)
+}
+
+// tagged decodes the value of a tagged object, reporting false if the tag
+// has no fast path into v.
+func (s *scanner) tagged(tag typeTag, v reflect.Value) (bool, error) {
+	switch tag {
+	case typeTagInt, typeTagLong, typeTagDouble:
+		raw, err := s.str()
+		if err != nil {
+			return false, err
+		}
+		return true, s.number(v, raw)
+
+	case typeTagTime, typeTagDate:
+		if v.Type() != timeType {
+			return false, nil
+		}
+
+		raw, err := s.str()
+		if err != nil {
+			return false, err
+		}
+
+		var t time.Time
+		if tag == typeTagDate {
+			t, err = time.Parse(dateFormat, string(raw))
+		} else {
+			t, err = s.codec.parseTime(string(raw))
+		}
+		if err != nil {
+			return false, err
+		}
+		v.Set(reflect.ValueOf(t))
+		return true, nil
+
+	case typeTagObject:
+		return true, s.fields(v)
+
+	case typeTagStream:
+		if v.Kind() != reflect.String {
+			return false, nil
+		}
+
+		raw, err := s.str()
+		if err != nil {
+			return false, err
+		}
+		v.SetString(string(raw))
+		return true, nil
+
+	case typeTagDoc:
+		if v.Kind() != reflect.Struct {
+			return false, nil
+		}
+		return true, s.fields(v)
+
+	case typeTagSet:
+		if v.Kind() != reflect.Slice || s.peek() != '{' {
+			return false, nil
+		}
+
+		// a set with more pages can't be paginated once it's a slice, so
+		// only its last page decodes into one
+		return true, s.eachKey(func(key []byte) (bool, error) {
+			switch string(key) {
+			case "data":
+				return true, s.array(v)
+			case "after":
+				if s.peek() != 'n' {
+					return true, s.errorf("cannot decode a set with more pages into %s, decode it into a fauna.Page to paginate it", v.Type())
+				}
+			}
+			return false, nil
+		})
+	}
+
+	return false, nil
+}
+
+// fields decodes an untagged object into a struct or map.
+func (s *scanner) fields(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		info := s.codec.structInfoFor(v.Type())
+		return s.eachKey(func(key []byte) (bool, error) {
+			idx, found := info.fields[string(key)]
+			if !found {
+				idx, found = info.folded[strings.ToLower(string(key))]
+			}
+			if !found {
+				return false, nil
+			}
+			return true, s.value(fieldByIndex(v, idx))
+		})
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+
+		elemType := v.Type().Elem()
+		return s.eachKey(func(key []byte) (bool, error) {
+			elem := reflect.New(elemType).Elem()
+			if err := s.value(elem); err != nil {
+				return true, err
+			}
+
+			v.SetMapIndex(reflect.ValueOf(string(key)).Convert(v.Type().Key()), elem)
+			return true, nil
+		})
+	}
+
+	return s.errorf("cannot decode object into %s", v.Type())
+}
+
+// eachKey calls fn for each key of an object with the scanner positioned at
+// its value. fn reports whether it consumed the value, otherwise it's skipped.
+func (s *scanner) eachKey(fn func(key []byte) (bool, error)) error {
+	if err := s.expect('{'); err != nil {
+		return err
+	}
+
+	for i := 0; ; i++ {
+		if s.peek() == '}' {
+			s.pos++
+			return nil
+		}
+		if i > 0 {
+			if err := s.expect(','); err != nil {
+				return err
+			}
+		}
+
+		key, err := s.str()
+		if err != nil {
+			return err
+		}
+		if err := s.expect(':'); err != nil {
+			return err
+		}
+
+		consumed, err := fn(key)
+		if err != nil {
+			return err
+		}
+		if !consumed {
+			if _, err := s.skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// fieldByIndex is [reflect.Value.FieldByIndex], allocating nil embedded
+// struct pointers on the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+type structInfo struct {
+	fields map[string][]int
+	folded map[string][]int
+
+	// encrypted are the lowercased names of fields tagged `encrypted`.
+	encrypted map[string]bool
+
+	// renamed maps the names of fields named by [fauna.FallbackTags] to
+	// their Go field names, and ignored are the lowercased Go names of
+	// fields they ignore.
+	renamed map[string]string
+	ignored map[string]bool
+}
+
+// structInfoKey is the key of a struct type's info, which depends on the
+// fallback tags it's read with.
+type structInfoKey struct {
+	t    reflect.Type
+	tags string
+}
+
+var structInfoCache sync.Map
+
+// structInfoFor maps the document field names a struct decodes to the index
+// of their Go field, flattening embedded structs like the generic decoder.
+func (c codec) structInfoFor(t reflect.Type) *structInfo {
+	key := structInfoKey{t: t, tags: strings.Join(c.tags, ",")}
+	if info, found := structInfoCache.Load(key); found {
+		return info.(*structInfo)
+	}
+
+	info := &structInfo{
+		fields:    map[string][]int{},
+		folded:    map[string][]int{},
+		encrypted: map[string]bool{},
+		renamed:   map[string]string{},
+		ignored:   map[string]bool{},
+	}
+	c.collectFields(t, nil, info)
+
+	actual, _ := structInfoCache.LoadOrStore(key, info)
+	return actual.(*structInfo)
+}
+
+func (c codec) collectFields(t reflect.Type, parent []int, info *structInfo) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		index := append(append([]int{}, parent...), i)
+
+		tag, fallback := c.structTag(field)
+		tags := strings.Split(tag, ",")
+		name := tags[0]
+		if name == "-" {
+			if fallback {
+				info.ignored[strings.ToLower(field.Name)] = true
+			}
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				c.collectFields(embedded, index, info)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		} else if fallback {
+			info.renamed[name] = field.Name
+		}
+
+		if _, exists := info.fields[name]; !exists {
+			info.fields[name] = index
+		}
+		if folded := strings.ToLower(name); info.folded[folded] == nil {
+			info.folded[folded] = index
+		}
+		for _, opt := range tags[1:] {
+			if opt == tagOptEncrypted {
+				info.encrypted[strings.ToLower(name)] = true
+			}
+		}
+	}
+}
+
+// driverTypes are decoded by the generic decoder, which builds them from
+// their tagged form.
+var driverTypes = map[reflect.Type]bool{
+	reflect.TypeOf(Module{}):            true,
+	reflect.TypeOf(Ref{}):               true,
+	reflect.TypeOf(NamedRef{}):          true,
+	reflect.TypeOf(Document{}):          true,
+	reflect.TypeOf(NamedDocument{}):     true,
+	reflect.TypeOf(NullDocument{}):      true,
+	reflect.TypeOf(NullNamedDocument{}): true,
+	reflect.TypeOf(Page{}):              true,
+}
+
+// hasFastPath reports whether values of the struct type t can be decoded
+// directly. Structs with encrypted fields are decrypted by the generic
+// decoder.
+func (c codec) hasFastPath(t reflect.Type) bool {
+	return !driverTypes[t] && len(c.structInfoFor(t).encrypted) == 0
+}
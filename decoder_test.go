@@ -0,0 +1,171 @@
+package fauna
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type decoderTestAddress struct {
+	City string `fauna:"city"`
+	Zip  *int   `fauna:"zip"`
+}
+
+type decoderTestDog struct {
+	Document
+	Name     string              `fauna:"name"`
+	Age      int32               `fauna:"age"`
+	Weight   float64             `fauna:"weight"`
+	Good     bool                `fauna:"good"`
+	Born     time.Time           `fauna:"born"`
+	LastSeen *time.Time          `fauna:"last_seen"`
+	Tags     []string            `fauna:"tags"`
+	Address  *decoderTestAddress `fauna:"address"`
+	Extra    map[string]any      `fauna:"extra"`
+	Scores   map[string]int      `fauna:"scores"`
+	Owner    *Ref                `fauna:"owner"`
+	Anything any                 `fauna:"anything"`
+	Nickname string
+	Skipped  string `fauna:"-"`
+}
+
+const decoderTestDogJSON = `{"@doc":{
+	"id":"123","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"},
+	"name":"Sc\"outé","age":{"@int":"3"},"weight":{"@double":"12.5"},"good":true,
+	"born":{"@date":"2020-01-02"},"last_seen":{"@time":"2023-03-31T12:00:00.5Z"},
+	"tags":["good","dog"],"address":{"city":"Paris","zip":null},
+	"extra":{"toy":"ball","@odd":{"@object":{"@int":"not a number"}}},
+	"scores":{"fetch":{"@int":"10"}},
+	"owner":{"@ref":{"id":"9","coll":{"@mod":"Users"}}},
+	"anything":[{"@long":"1"},{"@double":"2.5"}],
+	"nickname":"Scooter","skipped":"ignored","unknown":{"deep":[1,2,{"x":"]"}]}
+}}`
+
+func TestDecodeDirect(t *testing.T) {
+	t.Run("matches generic decoder", func(t *testing.T) {
+		var direct, generic decoderTestDog
+		if !assert.NoError(t, decodeDirect([]byte(decoderTestDogJSON), &direct)) {
+			return
+		}
+		if !assert.NoError(t, unmarshal([]byte(decoderTestDogJSON), &generic)) {
+			return
+		}
+
+		assert.Equal(t, generic, direct)
+		assert.Equal(t, "Sc\"outé", direct.Name)
+		assert.Equal(t, "123", direct.ID)
+		assert.Equal(t, &Module{"Dogs"}, direct.Coll)
+		assert.Equal(t, int32(3), direct.Age)
+		assert.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), direct.Born)
+		assert.Equal(t, "Scooter", direct.Nickname)
+		assert.Empty(t, direct.Skipped)
+	})
+
+	t.Run("scalars and containers", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			json string
+			into any
+			want any
+		}{
+			"int":          {`{"@int":"42"}`, new(int), 42},
+			"long to uint": {`{"@long":"42"}`, new(uint64), uint64(42)},
+			"double":       {`{"@double":"1.5"}`, new(float32), float32(1.5)},
+			"string":       {`"hi"`, new(string), "hi"},
+			"nil pointer":  {`null`, new(*int), (*int)(nil)},
+			"slice":        {`[{"@int":"1"},{"@int":"2"}]`, new([]int), []int{1, 2}},
+			"array":        {`["a","b","c"]`, new([2]string), [2]string{"a", "b"}},
+			"map":          {`{"a":{"@int":"1"}}`, new(map[string]int64), map[string]int64{"a": 1}},
+			"set":          {`{"@set":{"data":["a","b"]}}`, new([]string), []string{"a", "b"}},
+			"last page":    {`{"@set":{"data":["a","b"],"after":null}}`, new([]string), []string{"a", "b"}},
+			"any":          {`{"a":{"@int":"1"}}`, new(any), map[string]any{"a": int64(1)}},
+		} {
+			if assert.NoError(t, decodeDirect([]byte(tc.json), tc.into), name) {
+				assert.Equal(t, tc.want, derefTestValue(tc.into), name)
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var i8 int8
+		assert.ErrorContains(t, decodeDirect([]byte(`{"@int":"300"}`), &i8), "overflows int8")
+
+		var s string
+		assert.ErrorContains(t, decodeDirect([]byte(`true`), &s), "cannot decode bool into string")
+		assert.ErrorContains(t, decodeDirect([]byte(`"a" "b"`), &s), "unexpected data after value")
+		assert.ErrorContains(t, decodeDirect([]byte(`"a`), &s), "unterminated string")
+		assert.ErrorContains(t, decodeDirect([]byte(`"a"`), s), "must be a non-nil pointer")
+
+		var items []string
+		assert.ErrorContains(t, decodeDirect([]byte(`{"@set":{"data":["a"],"after":"next"}}`), &items), "set with more pages")
+	})
+}
+
+func derefTestValue(v any) any {
+	switch p := v.(type) {
+	case *int:
+		return *p
+	case *uint64:
+		return *p
+	case *float32:
+		return *p
+	case *string:
+		return *p
+	case **int:
+		return *p
+	case *[]int:
+		return *p
+	case *[2]string:
+		return *p
+	case *map[string]int64:
+		return *p
+	case *[]string:
+		return *p
+	case *any:
+		return *p
+	}
+	return v
+}
+
+func TestQueryInto(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, successBody(decoderTestDogJSON)
+	})
+
+	var dog decoderTestDog
+	res, err := srv.client().QueryInto(&Query{}, &dog)
+	if assert.NoError(t, err) {
+		assert.Nil(t, res.Data)
+		assert.Equal(t, "123", dog.ID)
+		assert.Equal(t, []string{"good", "dog"}, dog.Tags)
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	data := []byte(`{"@set":{"data":[` + decoderTestDogJSON + `,` + decoderTestDogJSON + `,` + decoderTestDogJSON + `]}}`)
+
+	b.Run("generic", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var page Page
+			if err := unmarshal(data, &page); err != nil {
+				b.Fatal(err)
+			}
+			var dogs []decoderTestDog
+			if err := page.Unmarshal(&dogs); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("direct", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var dogs []decoderTestDog
+			if err := decodeDirect(data, &dogs); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
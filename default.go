@@ -0,0 +1,48 @@
+package fauna
+
+import (
+	"sync"
+)
+
+// defaultClient is the client returned by [fauna.Default], built on first
+// use.
+var defaultClient struct {
+	once   sync.Once
+	client *Client
+	err    error
+}
+
+// Default returns a [fauna.Client] shared by the whole program, built with
+// [fauna.NewDefaultClient] from the FAUNA_SECRET and FAUNA_ENDPOINT
+// environment variables the first time it's called. Later calls return the
+// same client, or the same error if it couldn't be built. It's meant for
+// small tools and scripts; libraries should take a client from their caller.
+func Default() (*Client, error) {
+	defaultClient.once.Do(func() {
+		defaultClient.client, defaultClient.err = NewDefaultClient()
+	})
+
+	return defaultClient.client, defaultClient.err
+}
+
+// DefaultQuery runs fql with the [fauna.Default] client, as with
+// [Client.Query].
+func DefaultQuery(fql *Query, opts ...QueryOptFn) (*QuerySuccess, error) {
+	client, err := Default()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Query(fql, opts...)
+}
+
+// DefaultPaginate paginates fql with the [fauna.Default] client, as with
+// [Client.Paginate].
+func DefaultPaginate(fql *Query, opts ...QueryOptFn) (*QueryIterator, error) {
+	client, err := Default()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Paginate(fql, opts...), nil
+}
@@ -0,0 +1,61 @@
+package fauna
+
+import (
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefault(t *testing.T) {
+	reset := func() {
+		defaultClient.once = sync.Once{}
+		defaultClient.client, defaultClient.err = nil, nil
+	}
+	t.Cleanup(reset)
+
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, successBody(`{"data":[{"@int":"1"}]}`)
+	})
+	q, _ := FQL(`[1]`, nil)
+
+	t.Run("without a secret", func(t *testing.T) {
+		reset()
+		t.Setenv(EnvFaunaSecret, "")
+		_ = os.Unsetenv(EnvFaunaSecret)
+
+		_, err := Default()
+		assert.Error(t, err)
+		_, queryErr := DefaultQuery(q)
+		assert.Equal(t, err, queryErr, "the error is kept")
+		_, err = DefaultPaginate(q)
+		assert.Error(t, err)
+	})
+
+	t.Run("from the environment", func(t *testing.T) {
+		reset()
+		t.Setenv(EnvFaunaSecret, "secret")
+		t.Setenv(EnvFaunaEndpoint, srv.URL)
+
+		client, err := Default()
+		if !assert.NoError(t, err) {
+			return
+		}
+		again, _ := Default()
+		assert.Same(t, client, again, "the client is built once")
+
+		_, err = DefaultQuery(q)
+		assert.NoError(t, err)
+
+		it, err := DefaultPaginate(q)
+		if assert.NoError(t, err) {
+			page, err := it.Next()
+			assert.NoError(t, err)
+			assert.Equal(t, []any{int64(1)}, page.Data)
+		}
+
+		assert.Len(t, srv.received(), 2)
+	})
+}
@@ -0,0 +1,263 @@
+package fauna
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	tagOptEncrypted = "encrypted"
+
+	// encryptedPrefix marks an encrypted field value, which is followed by
+	// the ID of its key and the base64 encoded nonce and ciphertext:
+	// `fauna-enc:v1:<key id>:<data>`.
+	encryptedPrefix = "fauna-enc:v1:"
+)
+
+// A KeyProvider supplies the AES keys struct fields tagged `encrypted` are
+// encrypted with. Keys must be 16, 24, or 32 bytes long.
+type KeyProvider interface {
+	// EncryptionKey returns the key to encrypt values with and its ID, which
+	// is stored with each value so it can be decrypted after the key is
+	// rotated.
+	EncryptionKey() (id string, key []byte, err error)
+
+	// DecryptionKey returns the key with the given ID.
+	DecryptionKey(id string) ([]byte, error)
+}
+
+// StaticKeys is a [fauna.KeyProvider] with a fixed set of keys by ID,
+// encrypting with the key current.
+func StaticKeys(current string, keys map[string][]byte) KeyProvider {
+	return staticKeys{current: current, keys: keys}
+}
+
+type staticKeys struct {
+	current string
+	keys    map[string][]byte
+}
+
+func (s staticKeys) EncryptionKey() (string, []byte, error) {
+	key, err := s.DecryptionKey(s.current)
+	return s.current, key, err
+}
+
+func (s staticKeys) DecryptionKey(id string) ([]byte, error) {
+	key, found := s.keys[id]
+	if !found {
+		return nil, fmt.Errorf("no key with ID %q", id)
+	}
+	return key, nil
+}
+
+// WithKeyProvider sets the keys the client encrypts struct fields tagged
+// `encrypted` with, such as `fauna:"ssn,encrypted"`. Their values are
+// encrypted with AES-GCM when encoded and stored in Fauna as strings, then
+// decrypted when decoded into the same struct. Each value is bound to the
+// name of its field, so it can't be decrypted as another field. Unencrypted
+// values are decoded as they are, so existing documents can be read while
+// they're migrated.
+//
+// Encrypted fields can't be indexed or used in queries, other than being
+// read and written whole. Encoding or decoding them without a provider fails.
+func WithKeyProvider(provider KeyProvider) ClientConfigFn {
+	return func(c *Client) {
+		c.codec.keys = provider
+	}
+}
+
+// EncryptionKeys encrypts struct fields tagged `encrypted` with provider's
+// keys, as a client configured [fauna.WithKeyProvider] does.
+func EncryptionKeys(provider KeyProvider) EncodeOptFn {
+	return func(o *encodeOptions) { o.codec.keys = provider }
+}
+
+// encryptedField is the encoded value of a struct field tagged `encrypted`,
+// which is encrypted by sealFields once the keys to encrypt it with are known.
+type encryptedField struct {
+	name  string
+	value any
+}
+
+func (f encryptedField) MarshalJSON() ([]byte, error) {
+	return nil, fmt.Errorf("failed to encrypt field %s: %w", f.name, errNoKeyProvider)
+}
+
+var errNoKeyProvider = errors.New("no key provider is set, see fauna.WithKeyProvider")
+
+// sealFields encrypts the encrypted fields of enc, an encoded value, in
+// place. Without keys they're left to fail when they're marshaled.
+func (c codec) sealFields(enc any) (any, error) {
+	if c.keys == nil {
+		return enc, nil
+	}
+
+	var err error
+	switch v := enc.(type) {
+	case encryptedField:
+		if v.value, err = c.sealFields(v.value); err != nil {
+			return nil, err
+		}
+		return c.encryptField(v.name, v.value)
+
+	case map[typeTag]any:
+		for k, elem := range v {
+			if v[k], err = c.sealFields(elem); err != nil {
+				return nil, err
+			}
+		}
+
+	case map[string]any:
+		for k, elem := range v {
+			if v[k], err = c.sealFields(elem); err != nil {
+				return nil, err
+			}
+		}
+
+	case []any:
+		for i, elem := range v {
+			if v[i], err = c.sealFields(elem); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return enc, nil
+}
+
+// fieldData is the additional data a field's value is sealed with, which
+// binds it to the field's name.
+func fieldData(name string) []byte {
+	return []byte(encryptedPrefix + name)
+}
+
+// encryptField encrypts the tagged JSON of enc, an encoded field value.
+func (c codec) encryptField(name string, enc any) (any, error) {
+	if c.keys == nil {
+		return nil, fmt.Errorf("failed to encrypt field %s: %w", name, errNoKeyProvider)
+	}
+
+	id, key, err := c.keys.EncryptionKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt field %s: %w", name, err)
+	}
+	if strings.Contains(id, ":") {
+		return nil, fmt.Errorf("failed to encrypt field %s: key ID %q contains a colon", name, id)
+	}
+
+	plaintext, err := json.Marshal(enc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt field %s: %w", name, err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt field %s: %w", name, err)
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to encrypt field %s: %w", name, err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plaintext, fieldData(name))
+	return encryptedPrefix + id + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptField decrypts value, as encrypted by encryptField, into the value
+// decode returns for its tagged JSON.
+func (c codec) decryptField(name string, value string) (any, error) {
+	id, data, found := strings.Cut(strings.TrimPrefix(value, encryptedPrefix), ":")
+	if !found {
+		return nil, fmt.Errorf("failed to decrypt field %s: malformed value", name)
+	}
+
+	if c.keys == nil {
+		return nil, fmt.Errorf("failed to decrypt field %s: %w", name, errNoKeyProvider)
+	}
+
+	key, err := c.keys.DecryptionKey(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt field %s: %w", name, err)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt field %s: %w", name, err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt field %s: %w", name, err)
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("failed to decrypt field %s: malformed value", name)
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], fieldData(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt field %s: %w", name, err)
+	}
+
+	return c.decode(plaintext)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// decryptFields is a decode hook which decrypts the encrypted fields of a
+// document before it's decoded into the struct type t.
+func (c codec) decryptFields(_ reflect.Type, t reflect.Type, data any) (any, error) {
+	if t.Kind() != reflect.Struct {
+		return data, nil
+	}
+
+	fields, isMap := data.(map[string]any)
+	if !isMap {
+		return data, nil
+	}
+
+	info := c.structInfoFor(t)
+	if len(info.encrypted) == 0 {
+		return data, nil
+	}
+
+	var out map[string]any
+	for key, value := range fields {
+		str, isStr := value.(string)
+		if !isStr || !strings.HasPrefix(str, encryptedPrefix) || !info.encrypted[strings.ToLower(key)] {
+			continue
+		}
+
+		plain, err := c.decryptField(key, str)
+		if err != nil {
+			return nil, err
+		}
+
+		if out == nil {
+			// the document may be shared, so it's copied rather than changed
+			out = make(map[string]any, len(fields))
+			for k, v := range fields {
+				out[k] = v
+			}
+		}
+		out[key] = plain
+	}
+
+	if out == nil {
+		return data, nil
+	}
+	return out, nil
+}
@@ -0,0 +1,180 @@
+package fauna
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type encryptTestPerson struct {
+	Document
+	Name    string     `fauna:"name"`
+	SSN     string     `fauna:"ssn,encrypted"`
+	Born    *time.Time `fauna:"born,date,encrypted"`
+	Visits  int        `fauna:"visits,encrypted"`
+	Comment string     `fauna:"comment,omitempty,encrypted"`
+}
+
+func TestFieldEncryption(t *testing.T) {
+	keys := map[string][]byte{
+		"old": bytes.Repeat([]byte{1}, 32),
+		"new": bytes.Repeat([]byte{2}, 16),
+	}
+
+	born := time.Date(1990, 3, 4, 0, 0, 0, 0, time.UTC)
+	person := encryptTestPerson{Name: "Ada", SSN: "123-45-6789", Born: &born, Visits: 3}
+
+	_, err := marshal(person)
+	assert.ErrorContains(t, err, "no key provider")
+
+	bin, err := codec{keys: StaticKeys("old", keys)}.marshal(person)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotContains(t, string(bin), "123-45-6789")
+	assert.NotContains(t, string(bin), "1990")
+	assert.Contains(t, string(bin), `"ssn":"fauna-enc:v1:old:`)
+	assert.Contains(t, string(bin), `"name":"Ada"`)
+	assert.NotContains(t, string(bin), "comment", "empty fields are omitted")
+
+	// rotating the key still decrypts values encrypted with the old one
+	c := codec{keys: StaticKeys("new", keys)}
+
+	t.Run("generic decoder", func(t *testing.T) {
+		var got encryptTestPerson
+		if assert.NoError(t, c.unmarshal(bin, &got)) {
+			assert.Equal(t, person, got)
+		}
+	})
+
+	t.Run("direct decoder", func(t *testing.T) {
+		var got []encryptTestPerson
+		if assert.NoError(t, c.decodeDirect([]byte(`[`+string(bin)+`]`), &got)) {
+			assert.Equal(t, []encryptTestPerson{person}, got)
+		}
+	})
+
+	t.Run("documents", func(t *testing.T) {
+		doc := `{"@doc":{"id":"1","coll":{"@mod":"People"},"ts":{"@time":"2023-01-01T00:00:00Z"},` + string(bin[1:]) + `}`
+		var got encryptTestPerson
+		if assert.NoError(t, c.unmarshal([]byte(doc), &got)) {
+			assert.Equal(t, "1", got.ID)
+			assert.Equal(t, "123-45-6789", got.SSN)
+		}
+	})
+
+	t.Run("unencrypted values", func(t *testing.T) {
+		var got encryptTestPerson
+		if assert.NoError(t, c.unmarshal([]byte(`{"ssn":"plain","visits":{"@int":"1"}}`), &got)) {
+			assert.Equal(t, "plain", got.SSN)
+			assert.Equal(t, 1, got.Visits)
+		}
+	})
+
+	t.Run("no keys", func(t *testing.T) {
+		var got encryptTestPerson
+		assert.ErrorContains(t, unmarshal(bin, &got), "no key provider")
+	})
+
+	t.Run("unknown keys", func(t *testing.T) {
+		c := codec{keys: StaticKeys("new", map[string][]byte{"new": keys["new"]})}
+
+		var got encryptTestPerson
+		assert.ErrorContains(t, c.unmarshal(bin, &got), `no key with ID "old"`)
+	})
+
+	t.Run("tampering", func(t *testing.T) {
+		tampered, _ := c.marshal(person)
+		i := bytes.Index(tampered, []byte("fauna-enc:v1:new:")) + len("fauna-enc:v1:new:") + 20
+		tampered[i] ^= 'A' ^ 'B'
+		if tampered[i] == '"' {
+			tampered[i] = 'C'
+		}
+
+		var got encryptTestPerson
+		assert.ErrorContains(t, c.unmarshal(tampered, &got), "failed to decrypt field")
+	})
+
+	t.Run("values moved to another field", func(t *testing.T) {
+		var fields map[string]any
+		if !assert.NoError(t, json.Unmarshal(bin, &fields)) {
+			return
+		}
+		fields["comment"] = fields["ssn"]
+		moved, _ := json.Marshal(fields)
+
+		var got encryptTestPerson
+		assert.ErrorContains(t, c.unmarshal(moved, &got), "failed to decrypt field comment")
+	})
+
+	t.Run("array funcs", func(t *testing.T) {
+		people := ArrayFunc(func(emit func(any) error) error {
+			return emit(person)
+		})
+
+		_, err := Marshal(people)
+		assert.ErrorContains(t, err, "no key provider")
+
+		bin, err := Marshal(people, EncryptionKeys(c.keys))
+		if assert.NoError(t, err) {
+			assert.NotContains(t, string(bin), "123-45-6789")
+
+			var got []encryptTestPerson
+			if assert.NoError(t, c.unmarshal(bin, &got)) {
+				assert.Equal(t, []encryptTestPerson{person}, got)
+			}
+		}
+	})
+}
+
+func TestClientKeyProvider(t *testing.T) {
+	keys := StaticKeys("k", map[string][]byte{"k": bytes.Repeat([]byte{3}, 32)})
+	person := encryptTestPerson{Name: "Ada", SSN: "123-45-6789", Visits: 3}
+
+	var stored string
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		fql := req.Body["query"].(map[string]any)["fql"].([]any)
+		if len(fql) > 1 {
+			bin, _ := json.Marshal(fql[1].(map[string]any)["value"])
+			stored = string(bin)
+		}
+		return http.StatusOK, successBody(stored)
+	})
+
+	create, _ := FQL(`People.create(${person})`, map[string]any{"person": person})
+	read, _ := FQL(`People.all().first()`, nil)
+
+	client := srv.client(WithKeyProvider(keys))
+	if _, err := client.Query(create); !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, stored, `"ssn":"fauna-enc:v1:k:`)
+	assert.NotContains(t, stored, "123-45-6789")
+
+	res, err := client.Query(read)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var got encryptTestPerson
+	if assert.NoError(t, res.Unmarshal(&got)) {
+		assert.Equal(t, person, got)
+	}
+
+	var direct encryptTestPerson
+	_, err = client.QueryInto(read, &direct)
+	if assert.NoError(t, err) {
+		assert.Equal(t, person, direct)
+	}
+
+	_, err = srv.client().Query(create)
+	assert.ErrorContains(t, err, "no key provider")
+
+	var other encryptTestPerson
+	_, err = srv.client().QueryInto(read, &other)
+	assert.ErrorContains(t, err, "no key provider")
+}
@@ -1,11 +1,71 @@
 package fauna
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const httpStatusQueryTimeout = 440
 
+// ErrorCode is the `code` of an error returned by Fauna, as in
+// [fauna.ErrFauna.Code] and [fauna.ErrEvent.Code].
+type ErrorCode string
+
+// Error codes returned by Fauna
+const (
+	ErrorCodeAbort                ErrorCode = "abort"
+	ErrorCodeConstraintFailure    ErrorCode = "constraint_failure"
+	ErrorCodeContendedTransaction ErrorCode = "contended_transaction"
+	ErrorCodeDivideByZero         ErrorCode = "divide_by_zero"
+	ErrorCodeDocumentIDExists     ErrorCode = "document_id_exists"
+	ErrorCodeDocumentNotFound     ErrorCode = "document_not_found"
+	ErrorCodeForbidden            ErrorCode = "forbidden"
+	ErrorCodeIndexOutOfBounds     ErrorCode = "index_out_of_bounds"
+	ErrorCodeInternalError        ErrorCode = "internal_error"
+	ErrorCodeInvalidArgument      ErrorCode = "invalid_argument"
+	ErrorCodeInvalidCursor        ErrorCode = "invalid_cursor"
+	ErrorCodeInvalidQuery         ErrorCode = "invalid_query"
+	ErrorCodeInvalidRequest       ErrorCode = "invalid_request"
+	ErrorCodeInvalidSchema        ErrorCode = "invalid_schema"
+	ErrorCodeInvalidType          ErrorCode = "invalid_type"
+	ErrorCodeInvalidWrite         ErrorCode = "invalid_write"
+	ErrorCodeLimitExceeded        ErrorCode = "limit_exceeded"
+	ErrorCodeNullValue            ErrorCode = "null_value"
+	ErrorCodePermissionDenied     ErrorCode = "permission_denied"
+	ErrorCodeRequestSizeExceeded  ErrorCode = "request_size_exceeded"
+	ErrorCodeServiceUnavailable   ErrorCode = "service_unavailable"
+	ErrorCodeStackOverflow        ErrorCode = "stack_overflow"
+	ErrorCodeTimeOut              ErrorCode = "time_out"
+	ErrorCodeUnauthorized         ErrorCode = "unauthorized"
+	ErrorCodeValueTooLarge        ErrorCode = "value_too_large"
+)
+
+// ErrorCodeOf returns the code of the Fauna error or [fauna.ErrEvent] in err's
+// chain, or an empty code if there isn't one, to match errors on their code:
+//
+//	if fauna.ErrorCodeOf(err) == fauna.ErrorCodeDocumentIDExists {
+//		// ...
+//	}
+func ErrorCodeOf(err error) ErrorCode {
+	var fe interface{ base() *ErrFauna }
+	if errors.As(err, &fe) {
+		if base := fe.base(); base != nil {
+			return ErrorCode(base.Code)
+		}
+	}
+
+	var eventErr *ErrEvent
+	if errors.As(err, &eventErr) {
+		return ErrorCode(eventErr.Code)
+	}
+
+	return ""
+}
+
 // An ErrFauna is the base of all errors and provides the underlying `code`,
 // `message`, and any [fauna.QueryInfo].
 type ErrFauna struct {
@@ -15,6 +75,9 @@ type ErrFauna struct {
 	Message            string                 `json:"message"`
 	Abort              any                    `json:"abort"`
 	ConstraintFailures []ErrConstraintFailure `json:"constraint_failures"`
+
+	// Provenance identifies the query which failed.
+	Provenance *Provenance `json:"-"`
 }
 
 type ErrConstraintFailure struct {
@@ -28,6 +91,11 @@ func (e ErrFauna) Error() string {
 	return e.Message
 }
 
+// base returns the [fauna.ErrFauna] embedded by the driver's error types.
+func (e *ErrFauna) base() *ErrFauna {
+	return e
+}
+
 // An ErrAbort is returned when the `abort()` function was called, which will
 // return custom abort data in the error response.
 type ErrAbort struct {
@@ -51,21 +119,199 @@ type ErrAuthorization struct {
 	*ErrFauna
 }
 
+// An ErrClientLimit is returned when a query would exceed a limit set with
+// [fauna.MaxConcurrentRequests] or [fauna.RateLimit] in [fauna.LimitFail]
+// mode. The query isn't sent.
+type ErrClientLimit struct {
+	// Limit is the exceeded limit, "concurrency" or "rate".
+	Limit string
+}
+
+// Error describes the exceeded limit.
+func (e ErrClientLimit) Error() string {
+	return fmt.Sprintf("client %s limit exceeded", e.Limit)
+}
+
 // ErrContendedTransaction is returned when a transaction is aborted due
 // to concurrent modification.
 type ErrContendedTransaction struct {
 	*ErrFauna
 }
 
+// An ErrDocumentNotFound is returned by [fauna.Collection] methods when the
+// requested document doesn't exist.
+type ErrDocumentNotFound struct {
+	*NullDocument
+}
+
+// Error describes the document that was not found.
+func (e ErrDocumentNotFound) Error() string {
+	if e.NullDocument == nil || e.Ref == nil {
+		return "document not found"
+	}
+
+	coll := ""
+	if e.Ref.Coll != nil {
+		coll = e.Ref.Coll.Name
+	}
+
+	if e.Cause != "" {
+		return fmt.Sprintf("document %s in %s not found: %s", e.Ref.ID, coll, e.Cause)
+	}
+
+	return fmt.Sprintf("document %s in %s not found", e.Ref.ID, coll)
+}
+
+// An ErrVersionConflict is returned by [fauna.Client.UpdateIfTs] when the
+// document was modified after the expected timestamp.
+type ErrVersionConflict struct {
+	Ref *Ref
+
+	// ExpectedTS is the timestamp the update expected the document to have.
+	ExpectedTS time.Time
+
+	// CurrentTS is the document's timestamp when the update was attempted.
+	CurrentTS time.Time
+}
+
+// Error describes the conflicting versions.
+func (e ErrVersionConflict) Error() string {
+	return fmt.Sprintf("version conflict updating document %s: expected ts %s, found %s",
+		e.Ref.ID, e.ExpectedTS.Format(timeFormat), e.CurrentTS.Format(timeFormat))
+}
+
 // An ErrInvalidRequest is returned when the request body is not valid JSON, or
 // does not conform to the API specification
 type ErrInvalidRequest struct {
 	*ErrFauna
 }
 
-// An ErrNetwork is returned when an unknown error is encountered when attempting
-// to send a request to Fauna.
-type ErrNetwork error
+// An ErrNetwork is returned when a request couldn't be sent to Fauna, or its
+// response couldn't be received, such as when dialing, the TLS handshake, or
+// the connection fails. Err is the underlying error.
+//
+// ErrNetwork used to be defined as `type ErrNetwork error`. It's now a struct
+// returned as a pointer, so code converting to or asserting the old type must
+// use [errors.As] with a *ErrNetwork instead:
+//
+//	var netErr *fauna.ErrNetwork
+//	if errors.As(err, &netErr) {
+//		log.Printf("attempt %d to %s failed: %v", netErr.Attempt, netErr.Endpoint, netErr.Err)
+//	}
+type ErrNetwork struct {
+	// Endpoint is the URL the request was sent to.
+	Endpoint string
+
+	// Attempt is the number of the attempt which failed, starting at 1.
+	Attempt int
+
+	// Elapsed is the time from the first attempt until the failure.
+	Elapsed time.Duration
+
+	// RequestID is the `x-request-id` header sent with the failed attempt.
+	RequestID string
+
+	Err error
+}
+
+func newErrNetwork(req *http.Request, attempt int, start time.Time, err error) *ErrNetwork {
+	return &ErrNetwork{
+		Endpoint:  req.URL.String(),
+		Attempt:   attempt,
+		Elapsed:   time.Since(start),
+		RequestID: req.Header.Get(HeaderRequestID),
+		Err:       err,
+	}
+}
+
+// Error describes the failed request.
+func (e *ErrNetwork) Error() string {
+	return fmt.Sprintf("network error: %s failed on attempt %d after %s: %v",
+		e.Endpoint, e.Attempt, e.Elapsed.Round(time.Millisecond), e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ErrNetwork) Unwrap() error {
+	return e.Err
+}
+
+// A RetryAttempt records one attempt at sending a request, as reported by
+// [fauna.ErrRetriesExhausted].
+type RetryAttempt struct {
+	// Time is when the attempt was sent.
+	Time time.Time
+
+	// StatusCode is the HTTP status of the attempt's response, or 0 if no
+	// response was received.
+	StatusCode int
+
+	// Err is why no response was received, if one wasn't.
+	Err error
+
+	// Delay is how long the client backed off before the next attempt, or 0
+	// for the last attempt.
+	Delay time.Duration
+
+	// RetryAfter is the delay the response's Retry-After header asked for,
+	// if it had one.
+	RetryAfter time.Duration
+}
+
+// An ErrRetriesExhausted is returned when a request still failed after the
+// [fauna.Client]'s MaxAttempts. Attempts records each attempt, so it's
+// clear whether they were throttled, failed to connect, or a mix. Err is the
+// error of the last attempt, such as an [fauna.ErrThrottling].
+type ErrRetriesExhausted struct {
+	Attempts []RetryAttempt
+	Err      error
+}
+
+// Error describes the outcome of each attempt.
+func (e *ErrRetriesExhausted) Error() string {
+	outcomes := make([]string, len(e.Attempts))
+	for i, attempt := range e.Attempts {
+		if attempt.Err != nil {
+			outcomes[i] = "error"
+		} else {
+			outcomes[i] = strconv.Itoa(attempt.StatusCode)
+		}
+	}
+
+	return fmt.Sprintf("gave up after %d attempts [%s]: %v",
+		len(e.Attempts), strings.Join(outcomes, ", "), e.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *ErrRetriesExhausted) Unwrap() error {
+	return e.Err
+}
+
+// An ErrResponseTooLarge is returned when a response body is longer than the
+// limit set with [fauna.MaxResponseSize].
+type ErrResponseTooLarge struct {
+	// Limit is the maximum response size, in bytes.
+	Limit int64
+}
+
+// Error describes the exceeded limit.
+func (e ErrResponseTooLarge) Error() string {
+	return fmt.Sprintf("response exceeds the limit of %d bytes", e.Limit)
+}
+
+// An ErrInvalidArgument is returned by [fauna.FQL] when a query argument can't
+// be sent to Fauna, such as a channel, a NaN, or a value nested too deeply.
+type ErrInvalidArgument struct {
+	// Path locates the value within the arguments, such as `dog.toys[2]`.
+	Path string
+
+	// Reason describes why the value can't be sent.
+	Reason string
+}
+
+// Error describes the invalid argument.
+func (e *ErrInvalidArgument) Error() string {
+	return fmt.Sprintf("invalid query argument %s: %s", e.Path, e.Reason)
+}
 
 // An ErrQueryCheck is returned when the query fails one or more validation checks.
 type ErrQueryCheck struct {
@@ -102,7 +348,9 @@ type ErrThrottling struct {
 	*ErrFauna
 }
 
-func getErrFauna(httpStatus int, res *queryResponse) error {
+// getErrFauna returns the error for a response with httpStatus, decoding any
+// abort data with c.
+func (c codec) getErrFauna(httpStatus int, res *queryResponse) error {
 	if res.Error != nil {
 		res.Error.QueryInfo = newQueryInfo(res)
 		res.Error.StatusCode = httpStatus
@@ -121,18 +369,18 @@ func getErrFauna(httpStatus int, res *queryResponse) error {
 			return err
 		}
 
-		switch res.Error.Code {
-		case "invalid_query":
+		switch ErrorCode(res.Error.Code) {
+		case ErrorCodeInvalidQuery:
 			err := &ErrQueryCheck{res.Error}
 			err.Message += "\n" + res.Summary
 			return err
-		case "invalid_argument", "constraint_failure":
+		case ErrorCodeInvalidArgument, ErrorCodeConstraintFailure:
 			err := &ErrQueryRuntime{res.Error}
 			err.Message += "\n" + res.Summary
 			return err
-		case "abort":
+		case ErrorCodeAbort:
 			err := &ErrAbort{res.Error}
-			abort, cErr := convert(false, res.Error.Abort)
+			abort, cErr := c.convert(false, res.Error.Abort)
 			if cErr != nil {
 				return cErr
 			}
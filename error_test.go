@@ -1,7 +1,10 @@
 package fauna
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
@@ -131,7 +134,7 @@ func TestGetErrFauna(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			res := &queryResponse{Error: tt.args.serviceError, Summary: ""}
-			err := getErrFauna(tt.args.httpStatus, res)
+			err := codec{}.getErrFauna(tt.args.httpStatus, res)
 			if tt.wantErr {
 				assert.ErrorAs(t, err, &tt.args.errType)
 				assert.NotZero(t, res.Error.StatusCode)
@@ -142,6 +145,40 @@ func TestGetErrFauna(t *testing.T) {
 	}
 }
 
+func TestErrorCodeOf(t *testing.T) {
+	res := &queryResponse{Error: &ErrFauna{Code: "contended_transaction", Message: "retry"}}
+	contended := codec{}.getErrFauna(http.StatusConflict, res)
+
+	res = &queryResponse{Error: &ErrFauna{Code: "invalid_query", Message: "bad"}}
+	invalid := codec{}.getErrFauna(http.StatusBadRequest, res)
+
+	assert.Equal(t, ErrorCodeContendedTransaction, ErrorCodeOf(contended))
+	assert.Equal(t, ErrorCodeInvalidQuery, ErrorCodeOf(invalid))
+	assert.Equal(t, ErrorCodeInvalidQuery, ErrorCodeOf(fmt.Errorf("wrapped: %w", invalid)))
+	assert.Equal(t, ErrorCodePermissionDenied, ErrorCodeOf(&ErrEvent{Code: "permission_denied"}))
+	assert.Empty(t, ErrorCodeOf(errors.New("not from fauna")))
+	assert.Empty(t, ErrorCodeOf(nil))
+}
+
+func TestErrNetwork(t *testing.T) {
+	client := NewClient("secret", DefaultTimeouts(), URL("http://127.0.0.1:1"))
+
+	q, _ := FQL(`null`, nil)
+	_, err := client.Query(q)
+
+	var netErr *ErrNetwork
+	if assert.ErrorAs(t, err, &netErr) {
+		assert.Equal(t, "http://127.0.0.1:1/query/1", netErr.Endpoint)
+		assert.Equal(t, 1, netErr.Attempt)
+		assert.Positive(t, netErr.Elapsed)
+		assert.Contains(t, err.Error(), "http://127.0.0.1:1/query/1 failed on attempt 1")
+		assert.ErrorAs(t, err, new(*url.Error), "the transport's error is wrapped")
+	}
+
+	res := &queryResponse{Error: &ErrFauna{Code: "invalid_query", Message: "bad"}}
+	assert.False(t, errors.As(codec{}.getErrFauna(http.StatusBadRequest, res), &netErr), "Fauna errors aren't network errors")
+}
+
 func TestErrAbort(t *testing.T) {
 	t.Setenv(EnvFaunaEndpoint, EndpointLocal)
 	t.Setenv(EnvFaunaSecret, "secret")
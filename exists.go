@@ -0,0 +1,121 @@
+package fauna
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Exists reports whether the document with the given id exists in coll.
+func (c *Client) Exists(coll string, id string, opts ...QueryOptFn) (bool, error) {
+	return c.exists(&Module{Name: coll}, id, opts)
+}
+
+// Exists reports whether the document with the given id exists.
+func (c *Collection[T]) Exists(id string, opts ...QueryOptFn) (bool, error) {
+	return c.client.exists(c.mod, id, opts)
+}
+
+// GetOrCreate returns the first document in coll whose fields equal those in
+// matcher, creating it from matcher and defaults if there is none. The
+// returned bool reports whether the document was created. Fields in matcher
+// take precedence over defaults in the created document.
+//
+// The lookup and create run in a single transaction. Matching scans the
+// collection, so large collections should be matched through an index.
+func (c *Client) GetOrCreate(coll string, matcher map[string]any, defaults map[string]any, opts ...QueryOptFn) (*Document, bool, error) {
+	res, created, err := c.getOrCreate(&Module{Name: coll}, matcher, defaults, opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	doc, isDoc := res.(*Document)
+	if !isDoc {
+		return nil, false, fmt.Errorf("unexpected result from get or create: %v", res)
+	}
+
+	return doc, created, nil
+}
+
+// GetOrCreate returns the first document whose fields equal those in matcher,
+// creating it if there is none. See [fauna.Client.GetOrCreate].
+func (c *Collection[T]) GetOrCreate(matcher map[string]any, defaults map[string]any, opts ...QueryOptFn) (*T, bool, error) {
+	res, created, err := c.client.getOrCreate(c.mod, matcher, defaults, opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var doc T
+	if err := c.client.codec.decodeInto(res, &doc); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal %s document: %w", c.mod.Name, err)
+	}
+
+	return &doc, created, nil
+}
+
+func (c *Client) exists(coll *Module, id string, opts []QueryOptFn) (bool, error) {
+	q, err := FQL("${coll}.byId(${id}).exists()", map[string]any{"coll": coll, "id": id})
+	if err != nil {
+		return false, err
+	}
+
+	res, err := c.Query(q, opts...)
+	if err != nil {
+		return false, err
+	}
+
+	exists, _ := res.Data.(bool)
+	return exists, nil
+}
+
+func (c *Client) getOrCreate(coll *Module, matcher map[string]any, defaults map[string]any, opts []QueryOptFn) (any, bool, error) {
+	if len(matcher) == 0 {
+		return nil, false, fmt.Errorf("matcher must have at least one field")
+	}
+
+	fields := make([]string, 0, len(matcher))
+	for field := range matcher {
+		if err := validIdentifier(field); err != nil {
+			return nil, false, fmt.Errorf("invalid matcher field: %w", err)
+		}
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	predicate := []*queryFragment{{true, "doc => "}}
+	for i, field := range fields {
+		if i > 0 {
+			predicate = append(predicate, &queryFragment{true, " && "})
+		}
+		predicate = append(predicate, &queryFragment{true, "doc." + field + " == "}, &queryFragment{false, matcher[field]})
+	}
+
+	data := make(map[string]any, len(defaults)+len(matcher))
+	for k, v := range defaults {
+		data[k] = v
+	}
+	for k, v := range matcher {
+		data[k] = v
+	}
+
+	q, err := FQL(
+		"let doc = ${coll}.where(${predicate}).first()\n"+
+			"if (doc != null) [doc, false] else [${coll}.create(${data}), true]",
+		map[string]any{"coll": coll, "predicate": &Query{fragments: predicate}, "data": data},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	res, err := c.Query(q, opts...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	pair, isPair := res.Data.([]any)
+	if !isPair || len(pair) != 2 {
+		return nil, false, fmt.Errorf("unexpected result from get or create: %v", res.Data)
+	}
+
+	created, _ := pair[1].(bool)
+	return pair[0], created, nil
+}
@@ -0,0 +1,77 @@
+package fauna
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExists(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		fql := req.Body["query"].(map[string]any)["fql"].([]any)
+		id := fql[2].(map[string]any)["value"]
+		if id == "1" {
+			return http.StatusOK, successBody("true")
+		}
+		return http.StatusOK, successBody("false")
+	})
+
+	exists, err := srv.client().Exists("Dinos", "1")
+	if assert.NoError(t, err) {
+		assert.True(t, exists)
+	}
+
+	exists, err = NewCollection[collectionTestDino](srv.client(), "Dinos").Exists("2")
+	if assert.NoError(t, err) {
+		assert.False(t, exists)
+	}
+}
+
+func TestGetOrCreate(t *testing.T) {
+	const dinoDoc = `{"@doc":{"id":"1","coll":{"@mod":"Dinos"},"ts":{"@time":"2023-02-28T18:10:10.00001Z"},"name":"Dino","age":{"@int":"3"}}}`
+
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		bs, _ := marshal(req.Body["query"])
+		if strings.Contains(string(bs), `"Dino"`) {
+			return http.StatusOK, successBody(`[` + dinoDoc + `,false]`)
+		}
+		return http.StatusOK, successBody(`[` + dinoDoc + `,true]`)
+	})
+	client := srv.client()
+
+	t.Run("renders a predicate from the matcher", func(t *testing.T) {
+		doc, created, err := client.GetOrCreate("Dinos", map[string]any{"name": "Dino", "age": 3}, map[string]any{"extinct": true})
+		if assert.NoError(t, err) {
+			assert.False(t, created)
+			assert.Equal(t, "1", doc.ID)
+		}
+
+		received := srv.received()
+		fql := received[len(received)-1].Body["query"].(map[string]any)["fql"].([]any)
+		assert.Equal(t, map[string]any{"fql": []any{
+			"doc => ", "doc.age == ", map[string]any{"value": map[string]any{"@int": "3"}},
+			" && ", "doc.name == ", map[string]any{"value": "Dino"},
+		}}, fql[3])
+		assert.Equal(t, map[string]any{"value": map[string]any{
+			"name": "Dino", "age": map[string]any{"@int": "3"}, "extinct": true,
+		}}, fql[7])
+	})
+
+	t.Run("collection", func(t *testing.T) {
+		dino, created, err := NewCollection[collectionTestDino](client, "Dinos").GetOrCreate(map[string]any{"name": "Other"}, nil)
+		if assert.NoError(t, err) {
+			assert.True(t, created)
+			assert.Equal(t, 3, dino.Age)
+		}
+	})
+
+	t.Run("invalid matchers", func(t *testing.T) {
+		_, _, err := client.GetOrCreate("Dinos", nil, nil)
+		assert.Error(t, err)
+
+		_, _, err = client.GetOrCreate("Dinos", map[string]any{"name) || (true": 1}, nil)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,313 @@
+package fauna
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ExportFormat is the format [Client.Export] writes documents in.
+type ExportFormat string
+
+// ExportFormats supported by [Client.Export]
+const (
+	// ExportJSONL writes a JSON object per line.
+	ExportJSONL ExportFormat = "jsonl"
+
+	// ExportCSV writes a header row of field names followed by a row per
+	// document.
+	ExportCSV ExportFormat = "csv"
+)
+
+// ExportProgress counts what [Client.Export] has written so far.
+type ExportProgress struct {
+	Pages     int
+	Documents int
+}
+
+// ExportOptFn configuration options for [Client.Export]
+type ExportOptFn func(*exportOptions)
+
+type exportOptions struct {
+	fields    []string
+	progress  func(ExportProgress)
+	queryOpts []QueryOptFn
+}
+
+// ExportFields selects the top-level fields to export, in order. By default,
+// JSONL rows have every field, and CSV columns are the fields of the first
+// document: its metadata followed by its data in name order.
+func ExportFields(fields ...string) ExportOptFn {
+	return func(o *exportOptions) { o.fields = fields }
+}
+
+// ExportOnProgress calls fn after each page is written.
+func ExportOnProgress(fn func(ExportProgress)) ExportOptFn {
+	return func(o *exportOptions) { o.progress = fn }
+}
+
+// ExportQueryOptions sets options on the queries fetching each page.
+func ExportQueryOptions(opts ...QueryOptFn) ExportOptFn {
+	return func(o *exportOptions) { o.queryOpts = opts }
+}
+
+// ExportCollection writes every document in collection to w, see
+// [Client.Export].
+func (c *Client) ExportCollection(collection string, w io.Writer, format ExportFormat, opts ...ExportOptFn) (ExportProgress, error) {
+	q, err := FQL(`${coll}.all()`, map[string]any{"coll": &Module{collection}})
+	if err != nil {
+		return ExportProgress{}, err
+	}
+
+	return c.Export(q, w, format, opts...)
+}
+
+// Export paginates fql, such as an [fauna.IndexQuery.Query], writing each
+// result to w in format. Documents are flattened to their metadata and data
+// fields, with the collection as its name, times in RFC 3339 format, and
+// references as objects of their `id` or `name` and `coll`. CSV cells that
+// aren't scalars hold JSON, except references, which hold the referenced ID or
+// name.
+//
+// Pages are written as they're fetched, so on failure w holds the pages
+// written before the error, as counted by the returned [fauna.ExportProgress].
+func (c *Client) Export(fql *Query, w io.Writer, format ExportFormat, opts ...ExportOptFn) (ExportProgress, error) {
+	var o exportOptions
+	for _, optFn := range opts {
+		optFn(&o)
+	}
+
+	var write func(items []any) error
+	switch format {
+	case ExportJSONL:
+		write = jsonlExporter(w, o.fields)
+	case ExportCSV:
+		write = csvExporter(w, o.fields)
+	default:
+		return ExportProgress{}, fmt.Errorf("unsupported export format: %q", format)
+	}
+
+	var progress ExportProgress
+	iter := c.Paginate(fql, o.queryOpts...)
+	for iter.HasNext() {
+		page, err := iter.Next()
+		if err != nil {
+			return progress, err
+		}
+
+		if err := write(page.Data); err != nil {
+			return progress, fmt.Errorf("failed to write page %d: %w", progress.Pages+1, err)
+		}
+
+		progress.Pages++
+		progress.Documents += len(page.Data)
+		if o.progress != nil {
+			o.progress(progress)
+		}
+	}
+
+	if format == ExportCSV {
+		// an empty export with selected fields still has its header
+		if err := write(nil); err != nil {
+			return progress, err
+		}
+	}
+
+	return progress, nil
+}
+
+func jsonlExporter(w io.Writer, fields []string) func([]any) error {
+	enc := json.NewEncoder(w)
+	return func(items []any) error {
+		for _, item := range items {
+			row := exportFields(item)
+			if fields != nil {
+				selected := make(map[string]any, len(fields))
+				for _, field := range fields {
+					if v, ok := row[field]; ok {
+						selected[field] = v
+					}
+				}
+				row = selected
+			}
+
+			for k, v := range row {
+				row[k] = exportValue(v)
+			}
+
+			if err := enc.Encode(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func csvExporter(w io.Writer, fields []string) func([]any) error {
+	cw := csv.NewWriter(w)
+	wroteHeader := false
+	return func(items []any) error {
+		if !wroteHeader && (len(items) > 0 || fields != nil) {
+			if fields == nil {
+				fields = exportColumns(items[0])
+			}
+			if err := cw.Write(fields); err != nil {
+				return err
+			}
+			wroteHeader = true
+		}
+
+		record := make([]string, len(fields))
+		for _, item := range items {
+			row := exportFields(item)
+			for i, field := range fields {
+				cell, err := csvCell(row[field])
+				if err != nil {
+					return fmt.Errorf("failed to encode %s: %w", field, err)
+				}
+				record[i] = cell
+			}
+
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+
+		cw.Flush()
+		return cw.Error()
+	}
+}
+
+// exportColumns lists the fields of item: document metadata first, then its
+// data in name order.
+func exportColumns(item any) []string {
+	var meta []string
+	switch item.(type) {
+	case *Document:
+		meta = []string{"id", "coll", "ts"}
+	case *NamedDocument:
+		meta = []string{"name", "coll", "ts"}
+	}
+
+	var names []string
+	for name := range exportFields(item) {
+		isMeta := false
+		for _, m := range meta {
+			isMeta = isMeta || m == name
+		}
+		if !isMeta {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return append(meta, names...)
+}
+
+// exportFields flattens a result into its fields. Results that aren't
+// documents or objects have a single field, `value`.
+func exportFields(item any) map[string]any {
+	switch v := item.(type) {
+	case *Document:
+		fields := map[string]any{"id": v.ID, "coll": v.Coll, "ts": v.TS}
+		for k, field := range v.Data {
+			fields[k] = field
+		}
+		return fields
+	case *NamedDocument:
+		fields := map[string]any{"name": v.Name, "coll": v.Coll, "ts": v.TS}
+		for k, field := range v.Data {
+			fields[k] = field
+		}
+		return fields
+	case map[string]any:
+		fields := make(map[string]any, len(v))
+		for k, field := range v {
+			fields[k] = field
+		}
+		return fields
+	}
+
+	return map[string]any{"value": item}
+}
+
+// exportValue converts a decoded FQL value to plain JSON.
+func exportValue(v any) any {
+	switch v := v.(type) {
+	case *Module:
+		if v == nil {
+			return nil
+		}
+		return v.Name
+	case *Ref:
+		return map[string]any{"id": v.ID, "coll": exportValue(v.Coll)}
+	case *NamedRef:
+		return map[string]any{"name": v.Name, "coll": exportValue(v.Coll)}
+	case *Document, *NamedDocument:
+		return exportValue(exportFields(v))
+	case *NullDocument, *NullNamedDocument:
+		return nil
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.Format(time.RFC3339Nano)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case *Page:
+		return exportValue(v.Data)
+	case map[string]any:
+		obj := make(map[string]any, len(v))
+		for k, field := range v {
+			obj[k] = exportValue(field)
+		}
+		return obj
+	case []any:
+		arr := make([]any, len(v))
+		for i, elem := range v {
+			arr[i] = exportValue(elem)
+		}
+		return arr
+	}
+
+	return v
+}
+
+func csvCell(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case *Ref:
+		return v.ID, nil
+	case *NamedRef:
+		return v.Name, nil
+	case *Document:
+		return v.ID, nil
+	case *NamedDocument:
+		return v.Name, nil
+	}
+
+	switch plain := exportValue(v).(type) {
+	case nil:
+		return "", nil
+	case string:
+		return plain, nil
+	default:
+		bin, err := json.Marshal(plain)
+		return string(bin), err
+	}
+}
@@ -0,0 +1,79 @@
+package fauna
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExport(t *testing.T) {
+	const (
+		scoutDoc = `{"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"},"name":"Scout","age":{"@int":"3"},"owner":{"@ref":{"id":"7","coll":{"@mod":"People"}}},"tags":["good","loud"]}}`
+		rexDoc   = `{"@doc":{"id":"2","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-02T00:00:00Z"},"name":"Rex, Jr."}}`
+	)
+
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		if bytes.Contains(req.Raw, []byte("Set.paginate")) {
+			return http.StatusOK, successBody(`{"@set":{"data":[` + rexDoc + `]}}`)
+		}
+		if bytes.Contains(req.Raw, []byte("Cats")) {
+			return http.StatusOK, successBody(`{"@set":{"data":[]}}`)
+		}
+		return http.StatusOK, successBody(`{"@set":{"data":[` + scoutDoc + `],"after":"next"}}`)
+	})
+	client := srv.client()
+
+	t.Run("jsonl", func(t *testing.T) {
+		var out bytes.Buffer
+		var progress []ExportProgress
+		res, err := client.ExportCollection("Dogs", &out, ExportJSONL, ExportOnProgress(func(p ExportProgress) {
+			progress = append(progress, p)
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, ExportProgress{Pages: 2, Documents: 2}, res)
+		assert.Equal(t, []ExportProgress{{1, 1}, {2, 2}}, progress)
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Equal(t, []string{
+			`{"age":3,"coll":"Dogs","id":"1","name":"Scout","owner":{"coll":"People","id":"7"},"tags":["good","loud"],"ts":"2023-04-01T00:00:00Z"}`,
+			`{"coll":"Dogs","id":"2","name":"Rex, Jr.","ts":"2023-04-02T00:00:00Z"}`,
+		}, lines)
+	})
+
+	t.Run("selected fields", func(t *testing.T) {
+		var out bytes.Buffer
+		_, err := client.ExportCollection("Dogs", &out, ExportJSONL, ExportFields("id", "age"))
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"age\":3,\"id\":\"1\"}\n{\"id\":\"2\"}\n", out.String())
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var out bytes.Buffer
+		_, err := client.ExportCollection("Dogs", &out, ExportCSV)
+		assert.NoError(t, err)
+		assert.Equal(t, "id,coll,ts,age,name,owner,tags\n"+
+			"1,Dogs,2023-04-01T00:00:00Z,3,Scout,7,\"[\"\"good\"\",\"\"loud\"\"]\"\n"+
+			"2,Dogs,2023-04-02T00:00:00Z,,\"Rex, Jr.\",,\n", out.String())
+
+		out.Reset()
+		_, err = client.ExportCollection("Dogs", &out, ExportCSV, ExportFields("name", "id"))
+		assert.NoError(t, err)
+		assert.Equal(t, "name,id\nScout,1\n\"Rex, Jr.\",2\n", out.String())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var out bytes.Buffer
+		res, err := client.ExportCollection("Cats", &out, ExportCSV, ExportFields("id", "name"))
+		assert.NoError(t, err)
+		assert.Equal(t, ExportProgress{Pages: 1}, res)
+		assert.Equal(t, "id,name\n", out.String())
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := client.ExportCollection("Dogs", &bytes.Buffer{}, "xml")
+		assert.ErrorContains(t, err, "unsupported export format")
+	})
+}
@@ -0,0 +1,376 @@
+package fauna
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// EventSource is a token for the changes to a set, returned by a query calling
+// `eventSource()` or `eventsOn()` on the set. Read its events with
+// [Client.Feed].
+type EventSource string
+
+// Event is a change to the set of an [fauna.EventSource].
+type Event struct {
+	// Type is `add`, `update` or `remove` for changes to the set, or `error`
+	// if reading events failed, in which case Error is set.
+	Type string
+
+	// TxnTime is the transaction time of the change, in microseconds.
+	TxnTime int64
+
+	// Cursor is the position of the event, to resume reading after it with
+	// [fauna.FeedCursor].
+	Cursor string
+
+	// Data is the decoded document, or projection of it, that changed.
+	Data any
+
+	// Error describes why reading events failed, for `error` events.
+	Error *ErrEvent
+
+	// Raw is the event's data in Fauna's tagged format.
+	Raw json.RawMessage
+
+	Stats Stats
+
+	// codec decodes Raw in Unmarshal as the client which read the event
+	// would.
+	codec codec
+}
+
+// Unmarshal decodes the event's data into the provided object.
+func (e *Event) Unmarshal(into any) error {
+	if len(e.Raw) == 0 {
+		return nil
+	}
+
+	return e.codec.decodeDirect(e.Raw, into)
+}
+
+// ErrEvent is the error of an `error` [fauna.Event].
+type ErrEvent struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error provides the underlying error message.
+func (e *ErrEvent) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// retryableEventCodes are the codes of error events caused by a transient
+// problem with Fauna, rather than with the event source.
+var retryableEventCodes = map[ErrorCode]bool{
+	ErrorCodeInternalError:      true,
+	ErrorCodeLimitExceeded:      true,
+	ErrorCodeServiceUnavailable: true,
+	ErrorCodeTimeOut:            true,
+}
+
+// Retryable reports whether the error is transient, such as Fauna restarting,
+// so reading the events again from the last cursor can succeed. Errors such as
+// the secret losing access to the source, or the source's start time falling
+// outside the database's history retention, aren't.
+func (e *ErrEvent) Retryable() bool {
+	return retryableEventCodes[ErrorCode(e.Code)]
+}
+
+// FeedPage is a page of events read by [Feed.Next].
+type FeedPage struct {
+	Events []Event
+
+	// Cursor is the position after the page's last event.
+	Cursor string
+
+	// HasNext is true if more events were available when the page was read.
+	HasNext bool
+
+	Stats Stats
+}
+
+type feedOptions struct {
+	cursor     string
+	startTime  time.Time
+	pageSize   int
+	checkpoint checkpointer
+}
+
+// FeedOptFn function to set options on [Client.Feed]
+type FeedOptFn func(o *feedOptions)
+
+// FeedCursor resumes the feed after the event at cursor, as given by
+// [fauna.Event] or [Feed.Cursor].
+func FeedCursor(cursor string) FeedOptFn {
+	return func(o *feedOptions) { o.cursor = cursor }
+}
+
+// FeedStartTime starts the feed with the events after t, such as an hour
+// ago, which must be within the database's history retention. It's ignored
+// if [fauna.FeedCursor] is set.
+func FeedStartTime(t time.Time) FeedOptFn {
+	return func(o *feedOptions) { o.startTime = t }
+}
+
+// FeedPageSize sets the maximum number of events in each page, trading
+// throughput against the memory each page needs. Fauna's default is used if
+// size is zero.
+func FeedPageSize(size int) FeedOptFn {
+	return func(o *feedOptions) { o.pageSize = size }
+}
+
+// FeedCheckpoint saves the feed's cursor in store under name once a page has
+// been processed, which is when [Feed.Next] is called for the page after it,
+// or [Feed.Checkpoint] is called. A feed without [fauna.FeedCursor] resumes
+// from the stored cursor, so a consumer restarted after failing while
+// processing a page reads the page again.
+func FeedCheckpoint(store CheckpointStore, name string) FeedOptFn {
+	return func(o *feedOptions) { o.checkpoint = checkpointer{store: store, name: name} }
+}
+
+// Feed reads the events of an [fauna.EventSource] a page at a time. A Feed is
+// not safe for concurrent use.
+type Feed struct {
+	client    *Client
+	source    EventSource
+	cursor    string
+	startTime time.Time
+	pageSize  int
+
+	checkpoint *checkpointer
+	loaded     bool
+}
+
+// Feed returns a [fauna.Feed] of the events of source, starting from when the
+// source was created unless [fauna.FeedCursor] or [fauna.FeedStartTime] is
+// set.
+func (c *Client) Feed(source EventSource, opts ...FeedOptFn) *Feed {
+	var o feedOptions
+	for _, optFn := range opts {
+		optFn(&o)
+	}
+
+	f := &Feed{
+		client:    c,
+		source:    source,
+		cursor:    o.cursor,
+		startTime: o.startTime,
+		pageSize:  o.pageSize,
+		loaded:    o.cursor != "",
+	}
+	if o.checkpoint.store != nil {
+		o.checkpoint.saved = o.cursor
+		f.checkpoint = &o.checkpoint
+	}
+
+	return f
+}
+
+// Cursor returns the position the next page is read from, which is empty
+// before the first page of a feed without [fauna.FeedCursor].
+func (f *Feed) Cursor() string {
+	return f.cursor
+}
+
+// Checkpoint saves the feed's cursor with its [fauna.FeedCheckpoint] store,
+// such as once the last page read has been processed before shutting down.
+// It does nothing for a feed without a store.
+func (f *Feed) Checkpoint() error {
+	return f.checkpoint.save(f.cursor)
+}
+
+type feedRequest struct {
+	Token    EventSource `json:"token"`
+	Cursor   string      `json:"cursor,omitempty"`
+	StartTS  int64       `json:"start_ts,omitempty"`
+	PageSize int         `json:"page_size,omitempty"`
+}
+
+type feedEvent struct {
+	Type    string          `json:"type"`
+	TxnTime int64           `json:"txn_ts"`
+	Cursor  string          `json:"cursor"`
+	Data    json.RawMessage `json:"data"`
+	Error   *ErrEvent       `json:"error"`
+	Stats   Stats           `json:"stats"`
+}
+
+func (ev *feedEvent) event(c codec) (Event, error) {
+	event := Event{
+		Type:    ev.Type,
+		TxnTime: ev.TxnTime,
+		Cursor:  ev.Cursor,
+		Error:   ev.Error,
+		Stats:   ev.Stats,
+		Raw:     ev.Data,
+		codec:   c,
+	}
+
+	if len(ev.Data) > 0 {
+		var err error
+		if event.Data, err = c.decode(ev.Data); err != nil {
+			return event, fmt.Errorf("failed to decode event: %w", err)
+		}
+	}
+
+	return event, nil
+}
+
+type feedResponse struct {
+	Events  []feedEvent `json:"events"`
+	Cursor  string      `json:"cursor"`
+	HasNext bool        `json:"has_next"`
+	Stats   Stats       `json:"stats"`
+}
+
+// Next reads the next page of events, advancing the feed's cursor past them.
+// A page can be empty when no changes have been made since the last page.
+func (f *Feed) Next(ctx context.Context) (*FeedPage, error) {
+	if !f.loaded {
+		cursor, err := f.checkpoint.load()
+		if err != nil {
+			return nil, err
+		}
+		f.cursor, f.loaded = cursor, true
+	} else if err := f.Checkpoint(); err != nil {
+		return nil, err
+	}
+
+	req := feedRequest{Token: f.source, Cursor: f.cursor, PageSize: f.pageSize}
+	if f.cursor == "" && !f.startTime.IsZero() {
+		req.StartTS = f.startTime.UnixMicro()
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request failed: %w", err)
+	}
+
+	var res feedResponse
+	if err := f.client.doFeed(ctx, body, &res); err != nil {
+		return nil, err
+	}
+
+	page := &FeedPage{
+		Events:  make([]Event, 0, len(res.Events)),
+		Cursor:  res.Cursor,
+		HasNext: res.HasNext,
+		Stats:   res.Stats,
+	}
+	for _, ev := range res.Events {
+		event, err := ev.event(f.client.codec)
+		if err != nil {
+			return nil, err
+		}
+		page.Events = append(page.Events, event)
+	}
+
+	if res.Cursor != "" {
+		f.cursor = res.Cursor
+	}
+	f.client.syncTxnTime(lastTxnTime(page.Events))
+
+	return page, nil
+}
+
+func lastTxnTime(events []Event) int64 {
+	if len(events) == 0 {
+		return 0
+	}
+
+	return events[len(events)-1].TxnTime
+}
+
+func (c *Client) doFeed(ctx context.Context, body []byte, into *feedResponse) error {
+	ctx, done, closedErr := c.inflight.start(ctx)
+	if closedErr != nil {
+		return closedErr
+	}
+	defer done()
+
+	req, reqErr := c.eventRequest(ctx, "feed", body)
+	if reqErr != nil {
+		return reqErr
+	}
+
+	release, limitErr := c.limit(ctx)
+	if limitErr != nil {
+		return limitErr
+	}
+	defer release()
+
+	start := time.Now()
+	history, r, doErr := c.doWithRetry(req, c.maxAttempts)
+	if doErr != nil {
+		return c.feedRetriesExhausted(history, newErrNetwork(req, len(history), start, doErr))
+	}
+	defer r.Body.Close()
+
+	bin, readErr := io.ReadAll(r.Body)
+	if readErr != nil {
+		return fmt.Errorf("failed to read response body: %w", readErr)
+	}
+
+	if r.StatusCode != http.StatusOK {
+		var res queryResponse
+		if err := json.Unmarshal(bin, &res); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		if serviceErr := c.codec.getErrFauna(r.StatusCode, &res); serviceErr != nil {
+			return c.feedRetriesExhausted(history, serviceErr)
+		}
+
+		return fmt.Errorf("unexpected status %d from event feed", r.StatusCode)
+	}
+
+	if err := json.Unmarshal(bin, into); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
+
+// feedRetriesExhausted wraps err in an [ErrRetriesExhausted] if a feed
+// request failed after being retried up to the client's MaxAttempts.
+func (c *Client) feedRetriesExhausted(history []RetryAttempt, err error) error {
+	if c.maxAttempts < 2 || len(history) < c.maxAttempts {
+		return err
+	}
+	return &ErrRetriesExhausted{Attempts: history, Err: err}
+}
+
+// eventRequest builds a request to the event API at path, such as `feed`.
+func (c *Client) eventRequest(ctx context.Context, path string, body []byte) (*http.Request, error) {
+	ep := c.endpointFor(nil)
+	reqURL, urlErr := url.Parse(ep.url)
+	if urlErr != nil {
+		return nil, urlErr
+	}
+
+	if p, err := url.JoinPath(reqURL.Path, path, "1"); err != nil {
+		return nil, err
+	} else {
+		reqURL.Path = p
+	}
+
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodPost, reqURL.String(), bytes.NewReader(body))
+	if reqErr != nil {
+		return nil, fmt.Errorf("failed to init request: %w", reqErr)
+	}
+
+	req.Header.Set(headerAuthorization, `Bearer `+ep.secret)
+	for _, k := range []string{headerContentType, headerDriver, headerDriverEnv, headerFormat, headerUserAgent} {
+		if v, ok := c.headers[k]; ok {
+			req.Header.Set(k, v)
+		}
+	}
+
+	return req, nil
+}
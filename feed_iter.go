@@ -0,0 +1,44 @@
+//go:build go1.23
+
+package fauna
+
+import (
+	"context"
+	"iter"
+)
+
+// Events returns an iterator over the feed's events, reading pages with
+// [Feed.Next] until the feed has caught up with the events available, for use
+// in a range loop:
+//
+//	for event, err := range feed.Events(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(event.Type, event.Data)
+//	}
+//
+// A failed read yields its error and ends the iteration. The feed's cursor
+// advances as pages are read, so ranging over Events again later continues
+// with the events after them.
+func (f *Feed) Events(ctx context.Context) iter.Seq2[*Event, error] {
+	return func(yield func(*Event, error) bool) {
+		for {
+			page, err := f.Next(ctx)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for i := range page.Events {
+				if !yield(&page.Events[i], nil) {
+					return
+				}
+			}
+
+			if !page.HasNext {
+				return
+			}
+		}
+	}
+}
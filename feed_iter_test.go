@@ -0,0 +1,59 @@
+//go:build go1.23
+
+package fauna
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedEvents(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		switch req.Body["cursor"] {
+		case nil:
+			return http.StatusOK, `{"events":[{"type":"add","txn_ts":1,"cursor":"c1"},{"type":"add","txn_ts":2,"cursor":"c2"}],"cursor":"c2","has_next":true,"stats":{}}`
+		case "c2":
+			return http.StatusOK, `{"events":[{"type":"remove","txn_ts":3,"cursor":"c3"}],"cursor":"c3","has_next":false,"stats":{}}`
+		case "c3":
+			return http.StatusOK, `{"events":[],"cursor":"c3","has_next":false,"stats":{}}`
+		}
+		return http.StatusUnauthorized, errorBody("unauthorized", "invalid secret")
+	})
+	client := srv.client()
+
+	feed := client.Feed("token")
+	var cursors []string
+	for event, err := range feed.Events(context.Background()) {
+		if !assert.NoError(t, err) {
+			return
+		}
+		cursors = append(cursors, event.Cursor)
+	}
+	assert.Equal(t, []string{"c1", "c2", "c3"}, cursors, "pages are read until the feed has caught up")
+
+	for range feed.Events(context.Background()) {
+		t.Fatal("a feed which has caught up has no events")
+	}
+
+	t.Run("stops early", func(t *testing.T) {
+		before := len(srv.received())
+		for range client.Feed("token").Events(context.Background()) {
+			break
+		}
+		assert.Len(t, srv.received(), before+1)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var errs []error
+		for event, err := range client.Feed("token", FeedCursor("bad")).Events(context.Background()) {
+			assert.Nil(t, event)
+			errs = append(errs, err)
+		}
+		if assert.Len(t, errs, 1) {
+			assert.ErrorAs(t, errs[0], new(*ErrAuthentication))
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package fauna
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeed(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		switch req.URL.Path {
+		case "/query/1":
+			return http.StatusOK, successBody(`{"@stream":"token"}`)
+		case "/feed/1":
+			if req.Body["cursor"] == nil {
+				return http.StatusOK, `{"events":[
+					{"type":"add","txn_ts":1680000000000001,"cursor":"c1","data":{"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"},"name":"Scout"}},"stats":{"read_ops":1}},
+					{"type":"remove","txn_ts":1680000000000002,"cursor":"c2","data":{"@doc":{"id":"2","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"},"name":"Rex"}}}
+				],"cursor":"c2","has_next":false,"stats":{"read_ops":2}}`
+			}
+			return http.StatusOK, `{"events":[],"cursor":"c2","has_next":false,"stats":{}}`
+		}
+		return http.StatusNotFound, errorBody("not_found", "unexpected path")
+	})
+	client := srv.client()
+
+	q, _ := FQL(`Dogs.all().eventSource()`, nil)
+	res, err := client.Query(q)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var source EventSource
+	if !assert.NoError(t, res.Unmarshal(&source)) {
+		return
+	}
+	assert.Equal(t, EventSource("token"), source)
+	assert.Equal(t, EventSource("token"), res.Data)
+
+	feed := client.Feed(source)
+	page, err := feed.Next(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "c2", feed.Cursor())
+	assert.Equal(t, 2, page.Stats.ReadOps)
+	if assert.Len(t, page.Events, 2) {
+		assert.Equal(t, "add", page.Events[0].Type)
+		assert.Equal(t, int64(1680000000000001), page.Events[0].TxnTime)
+		assert.Equal(t, "c1", page.Events[0].Cursor)
+		assert.IsType(t, &Document{}, page.Events[0].Data)
+
+		var dog struct {
+			ID   string `fauna:"id"`
+			Name string `fauna:"name"`
+		}
+		assert.NoError(t, page.Events[1].Unmarshal(&dog))
+		assert.Equal(t, "2", dog.ID)
+		assert.Equal(t, "Rex", dog.Name)
+	}
+
+	page, err = feed.Next(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, page.Events)
+
+	received := srv.received()
+	if assert.Len(t, received, 3) {
+		assert.Equal(t, map[string]any{"token": "token"}, received[1].Body)
+		assert.Equal(t, map[string]any{"token": "token", "cursor": "c2"}, received[2].Body)
+		assert.Equal(t, "Bearer secret", received[2].Header.Get(headerAuthorization))
+		assert.Equal(t, "tagged", received[2].Header.Get(headerFormat))
+	}
+
+	t.Run("resumes from a cursor", func(t *testing.T) {
+		_, err := client.Feed(source, FeedCursor("c1")).Next(context.Background())
+		assert.NoError(t, err)
+		received := srv.received()
+		assert.Equal(t, "c1", received[len(received)-1].Body["cursor"])
+	})
+}
+
+func TestFeedError(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusBadRequest, errorBody("invalid_stream_start_time", "stream start time is too far in the past")
+	})
+
+	_, err := srv.client().Feed("token").Next(context.Background())
+	var queryErr *ErrInvalidRequest
+	if assert.ErrorAs(t, err, &queryErr) {
+		assert.Equal(t, "invalid_stream_start_time", queryErr.Code)
+	}
+}
+
+func TestFeedOptions(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, `{"events":[],"cursor":"c1","has_next":false,"stats":{}}`
+	})
+	client := srv.client()
+	start := time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	feed := client.Feed("token", FeedStartTime(start), FeedPageSize(50))
+	_, err := feed.Next(context.Background())
+	assert.NoError(t, err)
+	_, err = feed.Next(context.Background())
+	assert.NoError(t, err)
+
+	_, err = client.Feed("token", FeedCursor("c0"), FeedStartTime(start)).Next(context.Background())
+	assert.NoError(t, err)
+
+	received := srv.received()
+	if assert.Len(t, received, 3) {
+		assert.Equal(t, map[string]any{"token": "token", "start_ts": float64(start.UnixMicro()), "page_size": float64(50)}, received[0].Body)
+		assert.Equal(t, map[string]any{"token": "token", "cursor": "c1", "page_size": float64(50)}, received[1].Body, "later pages resume from the cursor")
+		assert.Equal(t, map[string]any{"token": "token", "cursor": "c0"}, received[2].Body, "the cursor takes precedence")
+	}
+}
+
+func TestFeedContext(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusTooManyRequests, errorBody("limit_exceeded", "too many requests")
+	})
+	client := srv.client(MaxAttempts(10), MaxBackoff(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Feed("token").Next(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "backing off is interrupted")
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,212 @@
+package fauna
+
+import (
+	"fmt"
+)
+
+// Filter is a condition on document fields, built with [fauna.Eq],
+// [fauna.Gt], [fauna.And], and friends. Field names are validated and values
+// are always sent as query arguments, so filters built from user input can't
+// inject FQL.
+type Filter interface {
+	render() ([]*queryFragment, error)
+}
+
+type comparison struct {
+	field string
+	op    string
+	value any
+}
+
+func (c comparison) render() ([]*queryFragment, error) {
+	path, err := fieldPath(c.field)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.op == "in" {
+		return []*queryFragment{{false, c.value}, {true, ".includes(doc" + path + ")"}}, nil
+	}
+
+	return []*queryFragment{{true, "doc" + path + " " + c.op + " "}, {false, c.value}}, nil
+}
+
+type junction struct {
+	op      string
+	filters []Filter
+}
+
+func (j junction) render() ([]*queryFragment, error) {
+	if len(j.filters) == 0 {
+		return nil, fmt.Errorf("%s requires at least one filter", j.op)
+	}
+
+	fragments := []*queryFragment{{true, "("}}
+	for i, f := range j.filters {
+		if f == nil {
+			return nil, fmt.Errorf("%s filter %d is nil", j.op, i)
+		}
+
+		rendered, err := f.render()
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			fragments = append(fragments, &queryFragment{true, " " + j.op + " "})
+		}
+		fragments = append(fragments, rendered...)
+	}
+
+	return append(fragments, &queryFragment{true, ")"}), nil
+}
+
+type negation struct {
+	filter Filter
+}
+
+func (n negation) render() ([]*queryFragment, error) {
+	if n.filter == nil {
+		return nil, fmt.Errorf("not filter is nil")
+	}
+
+	rendered, err := n.filter.render()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append([]*queryFragment{{true, "!("}}, rendered...), &queryFragment{true, ")"}), nil
+}
+
+// filterOperators are the operators [fauna.Where] accepts.
+var filterOperators = map[string]string{
+	"==": "==", "eq": "==",
+	"!=": "!=", "ne": "!=",
+	">": ">", "gt": ">",
+	">=": ">=", "gte": ">=",
+	"<": "<", "lt": "<",
+	"<=": "<=", "lte": "<=",
+	"in": "in",
+}
+
+// Where creates a [fauna.Filter] comparing field to value using op, for
+// filters driven by user input such as `?filter=age,gte,18`. op must be one of
+// ==, !=, >, >=, <, <=, their names eq, ne, gt, gte, lt, lte, or in.
+func Where(field string, op string, value any) (Filter, error) {
+	fqlOp, found := filterOperators[op]
+	if !found {
+		return nil, fmt.Errorf("unsupported filter operator %q", op)
+	}
+
+	if _, err := fieldPath(field); err != nil {
+		return nil, err
+	}
+
+	return comparison{field, fqlOp, value}, nil
+}
+
+// Eq matches documents where field equals value.
+func Eq(field string, value any) Filter { return comparison{field, "==", value} }
+
+// Ne matches documents where field doesn't equal value.
+func Ne(field string, value any) Filter { return comparison{field, "!=", value} }
+
+// Gt matches documents where field is greater than value.
+func Gt(field string, value any) Filter { return comparison{field, ">", value} }
+
+// Gte matches documents where field is greater than or equal to value.
+func Gte(field string, value any) Filter { return comparison{field, ">=", value} }
+
+// Lt matches documents where field is less than value.
+func Lt(field string, value any) Filter { return comparison{field, "<", value} }
+
+// Lte matches documents where field is less than or equal to value.
+func Lte(field string, value any) Filter { return comparison{field, "<=", value} }
+
+// In matches documents where field equals one of values.
+func In(field string, values ...any) Filter { return comparison{field, "in", values} }
+
+// And matches documents matching every filter.
+func And(filters ...Filter) Filter { return junction{"&&", filters} }
+
+// Or matches documents matching any filter.
+func Or(filters ...Filter) Filter { return junction{"||", filters} }
+
+// Not matches documents not matching filter.
+func Not(filter Filter) Filter { return negation{filter} }
+
+// Search builds a parameterized query over a collection from a
+// [fauna.Filter], ordering, and limit.
+//
+//	q, err := fauna.NewSearch("Users").
+//		Filter(fauna.And(fauna.Eq("status", "active"), fauna.Gte("age", 18))).
+//		Desc("created_at").
+//		Limit(20).
+//		Query()
+type Search struct {
+	coll   *Module
+	filter Filter
+	order  []indexOrder
+	limit  int
+}
+
+// NewSearch creates a [fauna.Search] over the named collection.
+func NewSearch(collection string) *Search {
+	return &Search{coll: &Module{Name: collection}}
+}
+
+// Filter sets the filter documents must match.
+func (s *Search) Filter(filter Filter) *Search {
+	s.filter = filter
+	return s
+}
+
+// Asc orders the results by field, ascending.
+func (s *Search) Asc(field string) *Search {
+	s.order = append(s.order, indexOrder{field, false})
+	return s
+}
+
+// Desc orders the results by field, descending.
+func (s *Search) Desc(field string) *Search {
+	s.order = append(s.order, indexOrder{field, true})
+	return s
+}
+
+// Limit limits the number of results.
+func (s *Search) Limit(limit int) *Search {
+	s.limit = limit
+	return s
+}
+
+// Query renders the [fauna.Search] as a [fauna.Query].
+func (s *Search) Query() (*Query, error) {
+	fragments := []*queryFragment{{false, s.coll}}
+
+	if s.filter != nil {
+		rendered, err := s.filter.render()
+		if err != nil {
+			return nil, err
+		}
+
+		fragments = append(fragments, &queryFragment{true, ".where(doc => "})
+		fragments = append(fragments, rendered...)
+		fragments = append(fragments, &queryFragment{true, ")"})
+	} else {
+		fragments = append(fragments, &queryFragment{true, ".all()"})
+	}
+
+	if len(s.order) > 0 {
+		orderFragments, err := renderOrder(s.order)
+		if err != nil {
+			return nil, err
+		}
+		fragments = append(fragments, orderFragments...)
+	}
+
+	if s.limit > 0 {
+		fragments = append(fragments, &queryFragment{true, fmt.Sprintf(".take(%d)", s.limit)})
+	}
+
+	return &Query{fragments: fragments}, nil
+}
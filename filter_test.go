@@ -0,0 +1,83 @@
+package fauna
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	t.Run("filters, orders and limits", func(t *testing.T) {
+		q, err := NewSearch("Users").
+			Filter(And(
+				Eq("status", "active"),
+				Or(Gte("age", 18), Not(In("role", "guest", "anon"))),
+			)).
+			Desc("created_at").
+			Limit(20).
+			Query()
+		if assert.NoError(t, err) {
+			bs := marshalAndCheck(t, q)
+			assert.JSONEq(t, `{"fql":[
+        {"value":{"@mod":"Users"}},
+        ".where(doc => ",
+        "(",
+        "doc.status == ", {"value":"active"},
+        " && ",
+        "(",
+        "doc.age >= ", {"value":{"@int":"18"}},
+        " || ",
+        "!(", {"value":["guest","anon"]}, ".includes(doc.role)", ")",
+        ")",
+        ")",
+        ")",
+        ".order(", "desc(.created_at)", ")",
+        ".take(20)"
+      ]}`, string(bs))
+		}
+	})
+
+	t.Run("no filter", func(t *testing.T) {
+		q, err := NewSearch("Users").Query()
+		if assert.NoError(t, err) {
+			bs := marshalAndCheck(t, q)
+			assert.JSONEq(t, `{"fql":[{"value":{"@mod":"Users"}},".all()"]}`, string(bs))
+		}
+	})
+
+	t.Run("user driven filters", func(t *testing.T) {
+		f, err := Where("address.zip", "eq", "94107")
+		if assert.NoError(t, err) {
+			q, qErr := NewSearch("Users").Filter(f).Query()
+			if assert.NoError(t, qErr) {
+				bs := marshalAndCheck(t, q)
+				assert.JSONEq(t, `{"fql":[
+          {"value":{"@mod":"Users"}},
+          ".where(doc => ",
+          "doc.address.zip == ", {"value":"94107"},
+          ")"
+        ]}`, string(bs))
+			}
+		}
+
+		_, err = Where("age", "; Users.all().delete()", 1)
+		assert.Error(t, err)
+
+		_, err = Where("age == 1 ||", "eq", 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		_, err := NewSearch("Users").Filter(Eq("bad field", 1)).Query()
+		assert.Error(t, err)
+
+		_, err = NewSearch("Users").Filter(And()).Query()
+		assert.Error(t, err)
+
+		_, err = NewSearch("Users").Filter(Or(nil)).Query()
+		assert.Error(t, err)
+
+		_, err = NewSearch("Users").Desc("x)").Query()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,88 @@
+package fauna
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Fragment is a reusable, parameterized partial query, such as a common
+// filter or projection, that can be embedded in many [fauna.FQL] templates.
+//
+// A fragment's placeholders are namespaced by the placeholder it is embedded
+// with. Embedding a fragment as `${active}` resolves its `${since}` placeholder
+// from the "active.since" argument, falling back to any value bound with
+// [fauna.Fragment.Bind]. Fragments with the same placeholder names can be used
+// together without their arguments colliding.
+//
+//	active, _ := fauna.NewFragment(`.status == "active" && .created_at > ${since}`)
+//	q, _ := fauna.FQL(`Users.where(${active})`, map[string]any{
+//		"active":       active,
+//		"active.since": since,
+//	})
+type Fragment struct {
+	template string
+	args     map[string]any
+}
+
+// NewFragment creates a [fauna.Fragment] from an FQL template. The template is
+// parsed immediately so errors surface when the fragment is defined.
+func NewFragment(template string) (*Fragment, error) {
+	if _, err := newTemplate(template).Parse(); err != nil {
+		return nil, err
+	}
+
+	return &Fragment{template: template, args: map[string]any{}}, nil
+}
+
+// Bind returns a copy of the [fauna.Fragment] with args bound. Bound values
+// are used for any placeholder not provided when the fragment is embedded.
+func (f *Fragment) Bind(args map[string]any) *Fragment {
+	bound := make(map[string]any, len(f.args)+len(args))
+	for k, v := range f.args {
+		bound[k] = v
+	}
+	for k, v := range args {
+		bound[k] = v
+	}
+
+	return &Fragment{template: f.template, args: bound}
+}
+
+// Query renders the [fauna.Fragment] as a standalone [fauna.Query], with args
+// taking precedence over bound values.
+func (f *Fragment) Query(args map[string]any) (*Query, error) {
+	return FQL(f.template, f.Bind(args).args)
+}
+
+// String returns the fragment's template.
+func (f *Fragment) String() string {
+	return f.template
+}
+
+// namespacedArgs returns the args prefixed with `namespace.`, with the prefix
+// removed.
+func namespacedArgs(namespace string, args map[string]any) map[string]any {
+	prefix := namespace + "."
+	out := map[string]any{}
+
+	for k, v := range args {
+		if strings.HasPrefix(k, prefix) {
+			out[strings.TrimPrefix(k, prefix)] = v
+		}
+	}
+
+	return out
+}
+
+func expandFragment(namespace string, f *Fragment, args map[string]any) (*Query, error) {
+	if f == nil {
+		return nil, fmt.Errorf("template variable %s is a nil fragment", namespace)
+	}
+
+	q, err := f.Query(namespacedArgs(namespace, args))
+	if err != nil {
+		return nil, fmt.Errorf("fragment %s: %w", namespace, err)
+	}
+
+	return q, nil
+}
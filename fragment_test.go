@@ -0,0 +1,92 @@
+package fauna
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFragment(t *testing.T) {
+	since, err := NewFragment(".created_at > ${since}")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	t.Run("namespaced arguments", func(t *testing.T) {
+		q, err := FQL("Users.where(${a}).where(${b}).take(${since})", map[string]any{
+			"a":       since,
+			"a.since": 1,
+			"b":       since,
+			"b.since": 2,
+			"since":   3,
+		})
+		if assert.NoError(t, err) {
+			bs := marshalAndCheck(t, q)
+			assert.JSONEq(t, `{"fql":[
+        "Users.where(",
+        {"fql":[".created_at > ",{"value":{"@int":"1"}}]},
+        ").where(",
+        {"fql":[".created_at > ",{"value":{"@int":"2"}}]},
+        ").take(",
+        {"value":{"@int":"3"}},
+        ")"
+      ]}`, string(bs))
+		}
+	})
+
+	t.Run("bound arguments", func(t *testing.T) {
+		bound := since.Bind(map[string]any{"since": 5})
+
+		q, err := FQL("Users.where(${a})", map[string]any{"a": bound})
+		if assert.NoError(t, err) {
+			bs := marshalAndCheck(t, q)
+			assert.JSONEq(t, `{"fql":["Users.where(",{"fql":[".created_at > ",{"value":{"@int":"5"}}]},")"]}`, string(bs))
+		}
+
+		q, err = FQL("Users.where(${a})", map[string]any{"a": bound, "a.since": 6})
+		if assert.NoError(t, err) {
+			bs := marshalAndCheck(t, q)
+			assert.JSONEq(t, `{"fql":["Users.where(",{"fql":[".created_at > ",{"value":{"@int":"6"}}]},")"]}`, string(bs))
+		}
+
+		assert.NotSame(t, since, bound)
+		_, unboundErr := since.Query(nil)
+		assert.Error(t, unboundErr, "binding should not modify the original fragment")
+	})
+
+	t.Run("nested fragments", func(t *testing.T) {
+		outer, outerErr := NewFragment("${inner} && .active == ${active}")
+		if !assert.NoError(t, outerErr) {
+			return
+		}
+
+		q, err := FQL("Users.where(${f})", map[string]any{
+			"f":             outer,
+			"f.active":      true,
+			"f.inner":       since,
+			"f.inner.since": 7,
+		})
+		if assert.NoError(t, err) {
+			bs := marshalAndCheck(t, q)
+			assert.JSONEq(t, `{"fql":[
+        "Users.where(",
+        {"fql":[
+          {"fql":[".created_at > ",{"value":{"@int":"7"}}]},
+          " && .active == ",
+          {"value":true}
+        ]},
+        ")"
+      ]}`, string(bs))
+		}
+	})
+
+	t.Run("missing arguments", func(t *testing.T) {
+		_, err := FQL("Users.where(${a})", map[string]any{"a": since, "since": 1})
+		assert.ErrorContains(t, err, "fragment a")
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := NewFragment(".created_at > $since")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,282 @@
+package fauna
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SchemaFile is a Fauna Schema Language (FSL) file in a database's schema.
+type SchemaFile struct {
+	// Filename is the name of the file, such as `main.fsl`.
+	Filename string `json:"filename"`
+
+	// Content is the FSL source of the file.
+	Content string `json:"content"`
+}
+
+// Schema is the set of FSL files that make up a database's schema.
+type Schema struct {
+	// Version is the schema version the files were read at. Pass it to
+	// [fauna.SchemaVersion] to only push if the schema hasn't changed since.
+	Version int64
+
+	Files []SchemaFile
+}
+
+// StagedSchemaStatus describes a schema change staged with [fauna.SchemaStaged].
+type StagedSchemaStatus struct {
+	// Version is the schema version the status was read at.
+	Version int64 `json:"version"`
+
+	// Status is one of `none`, `pending`, `ready`, or `failed`. A staged
+	// schema can only be committed once it's `ready`.
+	Status string `json:"status"`
+
+	// Diff is a human readable description of the staged changes.
+	Diff string `json:"diff"`
+}
+
+// SchemaDiff describes the changes between local FSL files and a database's
+// schema.
+type SchemaDiff struct {
+	// Version is the schema version the files were compared against.
+	Version int64 `json:"version"`
+
+	// Diff is a human readable description of the changes.
+	Diff string `json:"diff"`
+}
+
+type schemaPush struct {
+	staged  bool
+	force   bool
+	version int64
+}
+
+// SchemaPushOptFn function to set options on [Client.PushSchema]
+type SchemaPushOptFn func(p *schemaPush)
+
+// SchemaStaged stages the pushed schema rather than applying it immediately. A
+// staged schema is built in the background and applied by
+// [Client.CommitStagedSchema].
+func SchemaStaged() SchemaPushOptFn {
+	return func(p *schemaPush) { p.staged = true }
+}
+
+// SchemaForce pushes the schema even if it has changed since it was read, and even
+// if the change would delete data.
+func SchemaForce() SchemaPushOptFn {
+	return func(p *schemaPush) { p.force = true }
+}
+
+// SchemaVersion only pushes the schema if the database's schema is still at
+// version, as returned by [Client.PullSchema].
+func SchemaVersion(version int64) SchemaPushOptFn {
+	return func(p *schemaPush) { p.version = version }
+}
+
+// PullSchema reads the FSL files of the database the [fauna.Client] is
+// connected to.
+func (c *Client) PullSchema(ctx context.Context) (*Schema, error) {
+	var listing struct {
+		Version int64        `json:"version"`
+		Files   []SchemaFile `json:"files"`
+	}
+	if err := c.doSchema(ctx, http.MethodGet, nil, nil, "", &listing, "files"); err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{Version: listing.Version, Files: make([]SchemaFile, 0, len(listing.Files))}
+	for _, file := range listing.Files {
+		var content struct {
+			Content string `json:"content"`
+		}
+		if err := c.doSchema(ctx, http.MethodGet, url.Values{"version": {strconv.FormatInt(listing.Version, 10)}}, nil, "", &content, "files", file.Filename); err != nil {
+			return nil, err
+		}
+
+		schema.Files = append(schema.Files, SchemaFile{Filename: file.Filename, Content: content.Content})
+	}
+
+	return schema, nil
+}
+
+// PushSchema replaces the schema of the database the [fauna.Client] is
+// connected to with files, returning the new schema version. Any existing
+// file not in files is deleted. Unless [fauna.SchemaForce] is set the push
+// fails if the schema has changed since the version given by
+// [fauna.SchemaVersion], which defaults to the current version.
+func (c *Client) PushSchema(ctx context.Context, files []SchemaFile, opts ...SchemaPushOptFn) (int64, error) {
+	push := &schemaPush{}
+	for _, optFn := range opts {
+		optFn(push)
+	}
+
+	params := url.Values{}
+	switch {
+	case push.force:
+		params.Set("force", "true")
+	case push.version != 0:
+		params.Set("version", strconv.FormatInt(push.version, 10))
+	default:
+		var listing struct {
+			Version int64 `json:"version"`
+		}
+		if err := c.doSchema(ctx, http.MethodGet, nil, nil, "", &listing, "files"); err != nil {
+			return 0, err
+		}
+		params.Set("version", strconv.FormatInt(listing.Version, 10))
+	}
+
+	if push.staged {
+		params.Set("staged", "true")
+	}
+
+	body, contentType, err := schemaForm(files)
+	if err != nil {
+		return 0, err
+	}
+
+	var res struct {
+		Version int64 `json:"version"`
+	}
+	if err := c.doSchema(ctx, http.MethodPost, params, body, contentType, &res, "update"); err != nil {
+		return 0, err
+	}
+
+	return res.Version, nil
+}
+
+// DiffSchema compares files against the schema of the database the
+// [fauna.Client] is connected to without changing it, returning a human
+// readable description of the changes [Client.PushSchema] would make.
+func (c *Client) DiffSchema(ctx context.Context, files []SchemaFile) (*SchemaDiff, error) {
+	body, contentType, err := schemaForm(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var diff SchemaDiff
+	if err := c.doSchema(ctx, http.MethodPost, url.Values{"force": {"true"}, "diff": {"semantic"}}, body, contentType, &diff, "validate"); err != nil {
+		return nil, err
+	}
+
+	return &diff, nil
+}
+
+// StagedSchemaStatus returns the status of the schema staged with
+// [fauna.SchemaStaged], if there is one.
+func (c *Client) StagedSchemaStatus(ctx context.Context) (*StagedSchemaStatus, error) {
+	var status StagedSchemaStatus
+	if err := c.doSchema(ctx, http.MethodGet, url.Values{"format": {"semantic"}}, nil, "", &status, "staged", "status"); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// CommitStagedSchema applies the staged schema at version, as returned by
+// [Client.PushSchema].
+func (c *Client) CommitStagedSchema(ctx context.Context, version int64) error {
+	return c.doSchema(ctx, http.MethodPost, url.Values{"version": {strconv.FormatInt(version, 10)}}, nil, "", nil, "staged", "commit")
+}
+
+// AbandonStagedSchema discards the staged schema at version, as returned by
+// [Client.PushSchema].
+func (c *Client) AbandonStagedSchema(ctx context.Context, version int64) error {
+	return c.doSchema(ctx, http.MethodPost, url.Values{"version": {strconv.FormatInt(version, 10)}}, nil, "", nil, "staged", "abandon")
+}
+
+func schemaForm(files []SchemaFile) (io.Reader, string, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for _, file := range files {
+		if err := form.WriteField(file.Filename, file.Content); err != nil {
+			return nil, "", fmt.Errorf("failed to write %s: %w", file.Filename, err)
+		}
+	}
+	if err := form.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to write schema files: %w", err)
+	}
+
+	return &body, form.FormDataContentType(), nil
+}
+
+func (c *Client) doSchema(ctx context.Context, method string, params url.Values, body io.Reader, contentType string, into any, path ...string) error {
+	ep := c.endpointFor(nil)
+	reqURL, urlErr := url.Parse(ep.url)
+	if urlErr != nil {
+		return urlErr
+	}
+
+	if p, err := url.JoinPath(reqURL.Path, append([]string{"schema", "1"}, path...)...); err != nil {
+		return err
+	} else {
+		reqURL.Path = p
+	}
+	reqURL.RawQuery = params.Encode()
+
+	ctx, done, closedErr := c.inflight.start(ctx)
+	if closedErr != nil {
+		return closedErr
+	}
+	defer done()
+
+	req, reqErr := http.NewRequestWithContext(ctx, method, reqURL.String(), body)
+	if reqErr != nil {
+		return fmt.Errorf("failed to init request: %w", reqErr)
+	}
+
+	req.Header.Set(headerAuthorization, `Bearer `+ep.secret)
+	req.Header.Set(headerDriver, c.headers[headerDriver])
+	req.Header.Set(headerDriverEnv, c.headers[headerDriverEnv])
+	if ua, ok := c.headers[headerUserAgent]; ok {
+		req.Header.Set(headerUserAgent, ua)
+	}
+	if contentType != "" {
+		req.Header.Set(headerContentType, contentType)
+	}
+	req.Header.Set(HeaderRequestID, newRequestID())
+
+	start := time.Now()
+	r, doErr := c.http.Do(req)
+	if doErr != nil {
+		return newErrNetwork(req, 1, start, doErr)
+	}
+	defer r.Body.Close()
+
+	bin, readErr := io.ReadAll(r.Body)
+	if readErr != nil {
+		return fmt.Errorf("failed to read response body: %w", readErr)
+	}
+
+	if r.StatusCode != http.StatusOK {
+		var res queryResponse
+		if err := json.Unmarshal(bin, &res); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		if serviceErr := c.codec.getErrFauna(r.StatusCode, &res); serviceErr != nil {
+			return serviceErr
+		}
+
+		return fmt.Errorf("unexpected status %d from schema API", r.StatusCode)
+	}
+
+	if into == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(bin, into); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package fauna
+
+import (
+	"bytes"
+	"context"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullSchema(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		switch req.URL.Path {
+		case "/schema/1/files":
+			return http.StatusOK, `{"version":100,"files":[{"filename":"main.fsl"},{"filename":"roles.fsl"}]}`
+		case "/schema/1/files/main.fsl":
+			assert.Equal(t, "100", req.URL.Query().Get("version"))
+			return http.StatusOK, `{"version":100,"content":"collection Dogs {}"}`
+		case "/schema/1/files/roles.fsl":
+			return http.StatusOK, `{"version":100,"content":"role reader {}"}`
+		}
+		return http.StatusNotFound, errorBody("not_found", req.URL.Path)
+	})
+
+	schema, err := srv.client().PullSchema(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, &Schema{Version: 100, Files: []SchemaFile{
+		{Filename: "main.fsl", Content: "collection Dogs {}"},
+		{Filename: "roles.fsl", Content: "role reader {}"},
+	}}, schema)
+
+	reqs := srv.received()
+	assert.Equal(t, "Bearer secret", reqs[0].Header.Get(headerAuthorization))
+	assert.Equal(t, http.MethodGet, reqs[0].Method)
+}
+
+func TestPushSchema(t *testing.T) {
+	files := []SchemaFile{{Filename: "main.fsl", Content: "collection Dogs {}"}}
+
+	pushed := func(t *testing.T, req testRequest) map[string]string {
+		_, params, err := mime.ParseMediaType(req.Header.Get(headerContentType))
+		if !assert.NoError(t, err) {
+			return nil
+		}
+
+		form, err := multipart.NewReader(bytes.NewReader(req.Raw), params["boundary"]).ReadForm(1 << 20)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+
+		ret := map[string]string{}
+		for k, v := range form.Value {
+			ret[k] = v[0]
+		}
+		return ret
+	}
+
+	t.Run("current version", func(t *testing.T) {
+		srv := newTestServer(t, func(req testRequest) (int, string) {
+			if req.URL.Path == "/schema/1/files" {
+				return http.StatusOK, `{"version":100,"files":[]}`
+			}
+
+			assert.Equal(t, "/schema/1/update", req.URL.Path)
+			assert.Equal(t, "100", req.URL.Query().Get("version"))
+			assert.Equal(t, map[string]string{"main.fsl": "collection Dogs {}"}, pushed(t, req))
+			return http.StatusOK, `{"version":101}`
+		})
+
+		version, err := srv.client().PushSchema(context.Background(), files)
+		if assert.NoError(t, err) {
+			assert.Equal(t, int64(101), version)
+		}
+	})
+
+	t.Run("staged and forced", func(t *testing.T) {
+		srv := newTestServer(t, func(req testRequest) (int, string) {
+			assert.Equal(t, "/schema/1/update", req.URL.Path)
+			assert.Equal(t, "true", req.URL.Query().Get("force"))
+			assert.Equal(t, "true", req.URL.Query().Get("staged"))
+			assert.False(t, req.URL.Query().Has("version"))
+			return http.StatusOK, `{"version":102}`
+		})
+
+		_, err := srv.client().PushSchema(context.Background(), files, SchemaForce(), SchemaStaged())
+		assert.NoError(t, err)
+		assert.Len(t, srv.received(), 1)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		srv := newTestServer(t, func(req testRequest) (int, string) {
+			assert.Equal(t, "99", req.URL.Query().Get("version"))
+			return http.StatusConflict, errorBody("conflict", "schema has changed")
+		})
+
+		_, err := srv.client().PushSchema(context.Background(), files, SchemaVersion(99))
+		var contended *ErrContendedTransaction
+		if assert.ErrorAs(t, err, &contended) {
+			assert.Equal(t, "schema has changed", contended.Message)
+		}
+	})
+}
+
+func TestDiffSchema(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		assert.Equal(t, "/schema/1/validate", req.URL.Path)
+		assert.Equal(t, "semantic", req.URL.Query().Get("diff"))
+		return http.StatusOK, `{"version":100,"diff":"* Adding collection Dogs"}`
+	})
+
+	diff, err := srv.client().DiffSchema(context.Background(), []SchemaFile{{Filename: "main.fsl", Content: "collection Dogs {}"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, &SchemaDiff{Version: 100, Diff: "* Adding collection Dogs"}, diff)
+	}
+}
+
+func TestStagedSchema(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		switch req.URL.Path {
+		case "/schema/1/staged/status":
+			return http.StatusOK, `{"version":102,"status":"ready","diff":"* Adding collection Dogs"}`
+		case "/schema/1/staged/commit", "/schema/1/staged/abandon":
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, "102", req.URL.Query().Get("version"))
+			return http.StatusOK, `{"version":102}`
+		}
+		return http.StatusNotFound, errorBody("not_found", req.URL.Path)
+	})
+	client := srv.client()
+
+	status, err := client.StagedSchemaStatus(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, &StagedSchemaStatus{Version: 102, Status: "ready", Diff: "* Adding collection Dogs"}, status)
+	}
+
+	assert.NoError(t, client.CommitStagedSchema(context.Background(), 102))
+	assert.NoError(t, client.AbandonStagedSchema(context.Background(), 102))
+}
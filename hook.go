@@ -0,0 +1,73 @@
+package fauna
+
+import "context"
+
+// QueryErrorInfo describes a failed query, for an [fauna.ErrorHook].
+type QueryErrorInfo struct {
+	// Context is the query's context.
+	Context context.Context
+
+	// Name is the name of the prepared query run with [Client.Execute], or
+	// empty for other queries.
+	Name string
+
+	// Tags are the query tags sent with the query.
+	Tags map[string]string
+
+	// Traceparent is the `traceparent` header sent with the query, or empty
+	// if there wasn't one.
+	Traceparent string
+
+	// Attempts is the number of attempts made to send the query, which is
+	// zero if it failed before being sent.
+	Attempts int
+
+	// RequestID is the `x-request-id` header sent with the last attempt, or
+	// empty if the query wasn't sent.
+	RequestID string
+
+	// Provenance identifies the query.
+	Provenance *Provenance
+}
+
+// ErrorHook is called with the error of each failed query, returning the
+// error the query fails with instead, such as err wrapped with details for an
+// organization's error reporting. Returning nil leaves err unchanged. Hooks
+// are called from the goroutine which made the query.
+type ErrorHook func(err error, info *QueryErrorInfo) error
+
+// WithErrorHook calls hook with the error of each of the [fauna.Client]'s
+// failed queries. Hooks are called in the order they're added, each with the
+// error returned by the one before.
+func WithErrorHook(hook ErrorHook) ClientConfigFn {
+	return func(c *Client) { c.errorHooks = append(c.errorHooks, hook) }
+}
+
+// hookError passes err through the [fauna.Client]'s error hooks.
+func (c *Client) hookError(request *fqlRequest, err error) error {
+	if len(c.errorHooks) == 0 {
+		return err
+	}
+
+	info := &QueryErrorInfo{
+		Context:  request.Context,
+		Name:     request.Name,
+		Tags:     (&queryResponse{Tags: request.Headers[HeaderTags]}).queryTags(),
+		Attempts: request.Attempts,
+	}
+	if request.SentHeader != nil {
+		info.Traceparent = request.SentHeader.Get(HeaderTraceparent)
+		info.RequestID = request.SentHeader.Get(HeaderRequestID)
+	} else {
+		info.Traceparent = request.Headers[HeaderTraceparent]
+	}
+	info.Provenance = queryProvenance(request)
+
+	for _, hook := range c.errorHooks {
+		if hooked := hook(err, info); hooked != nil {
+			err = hooked
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,70 @@
+package fauna
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorHook(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusBadRequest, errorBody("invalid_query", "bad query")
+	})
+
+	var infos []*QueryErrorInfo
+	client := srv.client(
+		QueryTags(map[string]string{"service": "dogs"}),
+		WithErrorHook(func(err error, info *QueryErrorInfo) error {
+			infos = append(infos, info)
+			return fmt.Errorf("dogs service: %w", err)
+		}),
+		WithErrorHook(func(err error, info *QueryErrorInfo) error {
+			return nil
+		}),
+	)
+
+	sc, _ := ParseTraceparent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	ctx := ContextWithSpanContext(context.Background(), sc)
+
+	q, _ := FQL(`Dogs.byId(${id})`, map[string]any{"id": "1"})
+	_, err := client.Query(q, QueryContext(ctx), Tags(map[string]string{"route": "show"}))
+
+	assert.ErrorContains(t, err, "dogs service: bad query", "the first hook wraps the error, the second leaves it")
+	assert.ErrorAs(t, err, new(*ErrQueryCheck))
+
+	if assert.Len(t, infos, 1) {
+		info := infos[0]
+		assert.Equal(t, ctx, info.Context)
+		assert.Equal(t, map[string]string{"service": "dogs", "route": "show"}, info.Tags)
+		assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", info.Traceparent)
+		assert.Equal(t, 1, info.Attempts)
+		assert.Equal(t, "Dogs.byId(${...})", info.Provenance.Template)
+	}
+
+	_, err = client.QueryRaw(q, io.Discard)
+	assert.ErrorContains(t, err, "dogs service: bad query", "raw queries are hooked too")
+
+	assert.NoError(t, client.Prepare("dogById", `Dogs.byId(${id})`))
+	_, err = client.Execute("dogById", map[string]any{"id": "1"})
+	assert.Error(t, err)
+	if assert.Len(t, infos, 3) {
+		assert.Empty(t, infos[0].Name)
+		assert.Equal(t, "dogById", infos[2].Name)
+	}
+
+	success := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, successBody(`null`)
+	})
+	hooked := false
+	client = success.client(WithErrorHook(func(err error, _ *QueryErrorInfo) error {
+		hooked = true
+		return err
+	}))
+	_, err = client.Query(q)
+	assert.NoError(t, err)
+	assert.False(t, hooked, "hooks are only called for errors")
+}
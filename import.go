@@ -0,0 +1,203 @@
+package fauna
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ImportOptFn configuration options for [Client.ImportCollection]
+type ImportOptFn func(*importOptions)
+
+type importOptions struct {
+	preserveIDs bool
+	dryRun      bool
+	bulkOpts    []BulkWriterConfigFn
+	queryOpts   []QueryOptFn
+}
+
+// ImportPreserveIDs creates documents with the `id` field of each line as
+// their ID, rather than having Fauna generate one.
+func ImportPreserveIDs() ImportOptFn {
+	return func(o *importOptions) { o.preserveIDs = true }
+}
+
+// ImportDryRun reads and validates every line without writing anything.
+func ImportDryRun() ImportOptFn {
+	return func(o *importOptions) { o.dryRun = true }
+}
+
+// ImportBulkOptions configures the [fauna.BulkWriter] documents are written
+// with, such as its chunk size.
+func ImportBulkOptions(opts ...BulkWriterConfigFn) ImportOptFn {
+	return func(o *importOptions) { o.bulkOpts = opts }
+}
+
+// ImportQueryOptions sets options on the query writing each chunk.
+func ImportQueryOptions(opts ...QueryOptFn) ImportOptFn {
+	return func(o *importOptions) { o.queryOpts = opts }
+}
+
+// ImportSummary reports the outcome of a [Client.ImportCollection].
+type ImportSummary struct {
+	BulkSummary
+
+	// Read is the number of documents read.
+	Read int
+}
+
+// ImportCollection creates a document in collection for each line of r, a
+// JSON object per line as written by [Client.Export] with [fauna.ExportJSONL].
+// The `coll` and `ts` fields are ignored, as is `id` unless
+// [fauna.ImportPreserveIDs] is set. JSON numbers are imported as integers if
+// they're whole, and floats otherwise.
+//
+// Documents are written in chunks as they're read, each its own transaction,
+// by a [fauna.BulkWriter]. A chunk which fails doesn't stop the import, but a
+// line which can't be decoded does, after the chunks read before it are
+// written. An error is returned if any chunk failed, alongside an
+// [fauna.ImportSummary] whose [fauna.ErrBulkChunk] offsets count documents
+// from the start of r.
+func (c *Client) ImportCollection(collection string, r io.Reader, opts ...ImportOptFn) (*ImportSummary, error) {
+	var o importOptions
+	for _, optFn := range opts {
+		optFn(&o)
+	}
+
+	return c.importDocuments(collection, r, decodeJSONLine, o)
+}
+
+// importDocuments creates a document in collection for each line of r,
+// decoded by decodeLine.
+func (c *Client) importDocuments(collection string, r io.Reader, decodeLine func([]byte) (map[string]any, error), o importOptions) (*ImportSummary, error) {
+	start := time.Now()
+
+	w := NewBulkWriter(c, collection, o.bulkOpts...)
+	summary := &ImportSummary{BulkSummary: BulkSummary{IDs: []string{}}}
+
+	flushed := 0
+	flush := func() {
+		if w.Pending() == 0 {
+			return
+		}
+
+		res, _ := w.Flush(o.queryOpts...)
+		summary.Written += res.Written
+		summary.Failed += res.Failed
+		summary.Chunks += res.Chunks
+		summary.IDs = append(summary.IDs, res.IDs...)
+		for _, chunkErr := range res.Errors {
+			chunkErr.Offset += flushed
+			summary.Errors = append(summary.Errors, chunkErr)
+		}
+		flushed = summary.Read
+	}
+
+	readErr := readJSONL(r, decodeLine, func(line int, doc map[string]any) error {
+		delete(doc, "coll")
+		delete(doc, "ts")
+		if !o.preserveIDs {
+			delete(doc, "id")
+		} else if id, isString := doc["id"].(string); !isString || id == "" {
+			return fmt.Errorf("line %d: expected a string id", line)
+		}
+
+		if o.dryRun {
+			if _, err := c.codec.marshal(doc); err != nil {
+				return fmt.Errorf("line %d: failed to encode document: %w", line, err)
+			}
+			summary.Read++
+			return nil
+		}
+
+		if err := w.Create(doc); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		summary.Read++
+
+		if w.Pending() >= w.chunkSize {
+			flush()
+		}
+
+		return nil
+	})
+	flush()
+	summary.Duration = time.Since(start)
+
+	if readErr != nil {
+		return summary, readErr
+	}
+
+	if len(summary.Errors) > 0 {
+		return summary, fmt.Errorf("%d of %d import chunks failed: %w",
+			len(summary.Errors), summary.Chunks, summary.Errors[0])
+	}
+
+	return summary, nil
+}
+
+// readJSONL calls fn with each line of r decoded by decode, skipping blank
+// lines.
+func readJSONL(r io.Reader, decode func([]byte) (map[string]any, error), fn func(line int, doc map[string]any) error) error {
+	br := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		bin, err := br.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read line %d: %w", line, err)
+		}
+
+		if trimmed := bytes.TrimSpace(bin); len(trimmed) > 0 {
+			doc, decodeErr := decode(trimmed)
+			if decodeErr != nil {
+				return fmt.Errorf("line %d: %w", line, decodeErr)
+			}
+
+			if fnErr := fn(line, doc); fnErr != nil {
+				return fnErr
+			}
+		}
+
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// decodeJSONLine decodes a line of plain JSON.
+func decodeJSONLine(bin []byte) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(bin))
+	dec.UseNumber()
+
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil || dec.More() || doc == nil {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+
+	return jsonNumbers(doc).(map[string]any), nil
+}
+
+// jsonNumbers converts the [json.Number]s in v to int64 or float64.
+func jsonNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, elem := range v {
+			v[k] = jsonNumbers(elem)
+		}
+	case []any:
+		for i, elem := range v {
+			v[i] = jsonNumbers(elem)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,92 @@
+package fauna
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportCollection(t *testing.T) {
+	var written []map[string]any
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		fql := req.Body["query"].(map[string]any)["fql"].([]any)
+		ops := fql[0].(map[string]any)["value"].([]any)
+
+		ids := make([]string, len(ops))
+		for i, op := range ops {
+			data := op.(map[string]any)["data"].(map[string]any)
+			if data["name"] == "broken" {
+				return http.StatusBadRequest, errorBody("constraint_failure", "broken")
+			}
+
+			written = append(written, data)
+			ids[i] = fmt.Sprintf(`"%d"`, len(written))
+		}
+
+		return http.StatusOK, successBody("[" + strings.Join(ids, ",") + "]")
+	})
+	client := srv.client()
+
+	const dinos = `{"id":"10","coll":"Dinos","ts":"2023-04-01T00:00:00Z","name":"Dino","age":3,"weight":1.5}
+
+{"id":"11","name":"Baby","tags":["small"]}
+{"id":"12","name":"Rex"}
+`
+
+	t.Run("chunks as it reads", func(t *testing.T) {
+		written = nil
+		summary, err := client.ImportCollection("Dinos", strings.NewReader(dinos), ImportBulkOptions(BulkChunkSize(2)))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, 3, summary.Read)
+		assert.Equal(t, 3, summary.Written)
+		assert.Equal(t, 2, summary.Chunks)
+		assert.Equal(t, []string{"1", "2", "3"}, summary.IDs)
+		assert.Equal(t, []map[string]any{
+			{"name": "Dino", "age": map[string]any{"@int": "3"}, "weight": map[string]any{"@double": "1.5"}},
+			{"name": "Baby", "tags": []any{"small"}},
+			{"name": "Rex"},
+		}, written, "metadata is dropped")
+	})
+
+	t.Run("preserves ids", func(t *testing.T) {
+		written = nil
+		_, err := client.ImportCollection("Dinos", strings.NewReader(dinos), ImportPreserveIDs())
+		assert.NoError(t, err)
+		if assert.Len(t, written, 3) {
+			assert.Equal(t, "10", written[0]["id"])
+		}
+
+		_, err = client.ImportCollection("Dinos", strings.NewReader(`{"name":"Anon"}`), ImportPreserveIDs())
+		assert.ErrorContains(t, err, "line 1: expected a string id")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		written = nil
+		summary, err := client.ImportCollection("Dinos", strings.NewReader(dinos), ImportDryRun())
+		assert.NoError(t, err)
+		assert.Equal(t, 3, summary.Read)
+		assert.Zero(t, summary.Written)
+		assert.Empty(t, written)
+
+		_, err = client.ImportCollection("Dinos", strings.NewReader(dinos+"[1, 2]\n"), ImportDryRun())
+		assert.ErrorContains(t, err, "line 5: expected a JSON object")
+	})
+
+	t.Run("failed chunks", func(t *testing.T) {
+		written = nil
+		input := dinos + `{"name":"broken"}` + "\n" + `{"name":"Last"}`
+		summary, err := client.ImportCollection("Dinos", strings.NewReader(input), ImportBulkOptions(BulkChunkSize(2)))
+		assert.ErrorContains(t, err, "1 of 3 import chunks failed")
+		assert.Equal(t, 5, summary.Read)
+		assert.Equal(t, 3, summary.Written)
+		if assert.Len(t, summary.Errors, 1) {
+			assert.Equal(t, 2, summary.Errors[0].Offset, "offsets count from the start of the input")
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package fauna
+
+import (
+	"fmt"
+)
+
+// IndexQuery builds a query against a collection index, rendering the index's
+// terms, range, and ordering into FQL.
+//
+//	q, err := fauna.NewIndexQuery("Products", "byCategory").
+//		Terms("cups").
+//		From(10).To(20).
+//		Desc("price").
+//		Query()
+type IndexQuery struct {
+	coll     *Module
+	index    string
+	terms    []any
+	from     any
+	to       any
+	hasFrom  bool
+	hasTo    bool
+	order    []indexOrder
+	reverse  bool
+	pageSize int
+}
+
+type indexOrder struct {
+	field string
+	desc  bool
+}
+
+// NewIndexQuery creates an [fauna.IndexQuery] for the named index on collection.
+func NewIndexQuery(collection string, index string) *IndexQuery {
+	return &IndexQuery{coll: &Module{Name: collection}, index: index}
+}
+
+// Terms sets the values matched against the index's terms, in the order the
+// terms are defined.
+func (q *IndexQuery) Terms(terms ...any) *IndexQuery {
+	q.terms = terms
+	return q
+}
+
+// From sets the inclusive lower bound of the index's values.
+func (q *IndexQuery) From(value any) *IndexQuery {
+	q.from, q.hasFrom = value, true
+	return q
+}
+
+// To sets the inclusive upper bound of the index's values.
+func (q *IndexQuery) To(value any) *IndexQuery {
+	q.to, q.hasTo = value, true
+	return q
+}
+
+// Asc orders the results by field, ascending.
+func (q *IndexQuery) Asc(field string) *IndexQuery {
+	q.order = append(q.order, indexOrder{field, false})
+	return q
+}
+
+// Desc orders the results by field, descending.
+func (q *IndexQuery) Desc(field string) *IndexQuery {
+	q.order = append(q.order, indexOrder{field, true})
+	return q
+}
+
+// Reverse reverses the order of the results.
+func (q *IndexQuery) Reverse() *IndexQuery {
+	q.reverse = true
+	return q
+}
+
+// PageSize sets the number of results in each page.
+func (q *IndexQuery) PageSize(size int) *IndexQuery {
+	q.pageSize = size
+	return q
+}
+
+// Query renders the [fauna.IndexQuery] as a [fauna.Query].
+func (q *IndexQuery) Query() (*Query, error) {
+	if err := validIdentifier(q.index); err != nil {
+		return nil, fmt.Errorf("invalid index name: %w", err)
+	}
+
+	fragments := []*queryFragment{
+		{false, q.coll},
+		{true, "." + q.index + "("},
+	}
+
+	for i, term := range q.terms {
+		if i > 0 {
+			fragments = append(fragments, &queryFragment{true, ", "})
+		}
+		fragments = append(fragments, &queryFragment{false, term})
+	}
+
+	if q.hasFrom || q.hasTo {
+		if len(q.terms) > 0 {
+			fragments = append(fragments, &queryFragment{true, ", "})
+		}
+
+		fragments = append(fragments, &queryFragment{true, "{ "})
+		if q.hasFrom {
+			fragments = append(fragments, &queryFragment{true, "from: "}, &queryFragment{false, q.from})
+		}
+		if q.hasFrom && q.hasTo {
+			fragments = append(fragments, &queryFragment{true, ", "})
+		}
+		if q.hasTo {
+			fragments = append(fragments, &queryFragment{true, "to: "}, &queryFragment{false, q.to})
+		}
+		fragments = append(fragments, &queryFragment{true, " }"})
+	}
+	fragments = append(fragments, &queryFragment{true, ")"})
+
+	if len(q.order) > 0 {
+		orderFragments, err := renderOrder(q.order)
+		if err != nil {
+			return nil, err
+		}
+		fragments = append(fragments, orderFragments...)
+	}
+
+	if q.reverse {
+		fragments = append(fragments, &queryFragment{true, ".reverse()"})
+	}
+
+	if q.pageSize > 0 {
+		fragments = append(fragments, &queryFragment{true, fmt.Sprintf(".pageSize(%d)", q.pageSize)})
+	}
+
+	return &Query{fragments: fragments}, nil
+}
+
+func renderOrder(order []indexOrder) ([]*queryFragment, error) {
+	fragments := []*queryFragment{{true, ".order("}}
+	for i, o := range order {
+		path, err := fieldPath(o.field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid order field: %w", err)
+		}
+
+		if i > 0 {
+			fragments = append(fragments, &queryFragment{true, ", "})
+		}
+
+		if o.desc {
+			fragments = append(fragments, &queryFragment{true, "desc(" + path + ")"})
+		} else {
+			fragments = append(fragments, &queryFragment{true, "asc(" + path + ")"})
+		}
+	}
+
+	return append(fragments, &queryFragment{true, ")"}), nil
+}
+
+// PaginateIndex paginates the results of an [fauna.IndexQuery], optionally
+// setting multiple [QueryOptFn].
+func (c *Client) PaginateIndex(q *IndexQuery, opts ...QueryOptFn) (*QueryIterator, error) {
+	fql, err := q.Query()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Paginate(fql, opts...), nil
+}
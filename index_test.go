@@ -0,0 +1,70 @@
+package fauna
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexQuery(t *testing.T) {
+	t.Run("terms", func(t *testing.T) {
+		q, err := NewIndexQuery("Users", "byEmail").Terms("a@b.com").Query()
+		if assert.NoError(t, err) {
+			bs := marshalAndCheck(t, q)
+			assert.JSONEq(t, `{"fql":[{"value":{"@mod":"Users"}},".byEmail(",{"value":"a@b.com"},")"]}`, string(bs))
+		}
+	})
+
+	t.Run("terms, range and ordering", func(t *testing.T) {
+		q, err := NewIndexQuery("Products", "byCategory").
+			Terms("cups", "green").
+			From(10).To(20).
+			Desc("price").Asc("name").
+			Reverse().
+			PageSize(50).
+			Query()
+		if assert.NoError(t, err) {
+			bs := marshalAndCheck(t, q)
+			assert.JSONEq(t, `{"fql":[
+        {"value":{"@mod":"Products"}},
+        ".byCategory(",
+        {"value":"cups"}, ", ", {"value":"green"},
+        ", ", "{ ", "from: ", {"value":{"@int":"10"}}, ", ", "to: ", {"value":{"@int":"20"}}, " }",
+        ")",
+        ".order(", "desc(.price)", ", ", "asc(.name)", ")",
+        ".reverse()",
+        ".pageSize(50)"
+      ]}`, string(bs))
+		}
+	})
+
+	t.Run("open range", func(t *testing.T) {
+		q, err := NewIndexQuery("Products", "byPrice").To(20).Query()
+		if assert.NoError(t, err) {
+			bs := marshalAndCheck(t, q)
+			assert.JSONEq(t, `{"fql":[
+        {"value":{"@mod":"Products"}},
+        ".byPrice(", "{ ", "to: ", {"value":{"@int":"20"}}, " }", ")"
+      ]}`, string(bs))
+		}
+	})
+
+	t.Run("invalid names", func(t *testing.T) {
+		_, err := NewIndexQuery("Products", "all().delete").Query()
+		assert.Error(t, err)
+
+		_, err = NewIndexQuery("Products", "byPrice").Asc("price)").Query()
+		assert.Error(t, err)
+	})
+
+	t.Run("paginate", func(t *testing.T) {
+		client := NewClient("secret", DefaultTimeouts())
+		it, err := client.PaginateIndex(NewIndexQuery("Products", "byPrice"))
+		if assert.NoError(t, err) {
+			assert.True(t, it.HasNext())
+		}
+
+		_, err = client.PaginateIndex(NewIndexQuery("Products", ""))
+		assert.Error(t, err)
+	})
+}
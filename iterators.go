@@ -0,0 +1,138 @@
+package fauna
+
+// A PageIterator iterates over pages of results, such as a
+// [fauna.QueryIterator], a [fauna.PrefetchIterator], or iterators combined
+// with [fauna.Concat], [fauna.Interleave], or [fauna.Merge].
+//
+// Combined iterators handle errors like a QueryIterator: a page which fails
+// to be fetched is returned as an error, and as its iterator's cursor isn't
+// advanced, the next call to Next fetches it again.
+type PageIterator interface {
+	// Next returns the next page.
+	Next() (*Page, error)
+
+	// HasNext returns whether there is another page.
+	HasNext() bool
+}
+
+// Concat iterates over the pages of each of its in turn.
+func Concat(its ...PageIterator) PageIterator {
+	return &concatIterator{its: its}
+}
+
+type concatIterator struct {
+	its []PageIterator
+}
+
+func (c *concatIterator) Next() (*Page, error) {
+	if !c.HasNext() {
+		return nil, errNoMorePages
+	}
+	return c.its[0].Next()
+}
+
+func (c *concatIterator) HasNext() bool {
+	for len(c.its) > 0 && !c.its[0].HasNext() {
+		c.its = c.its[1:]
+	}
+	return len(c.its) > 0
+}
+
+// Interleave iterates over a page of each of its in turn, skipping those
+// without more pages, such as to show the results of several lookups
+// together as they're read.
+func Interleave(its ...PageIterator) PageIterator {
+	return &interleaveIterator{its: its}
+}
+
+type interleaveIterator struct {
+	its  []PageIterator
+	next int
+}
+
+func (in *interleaveIterator) Next() (*Page, error) {
+	if !in.HasNext() {
+		return nil, errNoMorePages
+	}
+
+	page, err := in.its[in.next].Next()
+	if err != nil {
+		return nil, err
+	}
+
+	in.next = (in.next + 1) % len(in.its)
+	return page, nil
+}
+
+func (in *interleaveIterator) HasNext() bool {
+	for len(in.its) > 0 {
+		if in.its[in.next].HasNext() {
+			return true
+		}
+
+		in.its = append(in.its[:in.next:in.next], in.its[in.next+1:]...)
+		if in.next >= len(in.its) {
+			in.next = 0
+		}
+	}
+	return false
+}
+
+// Merge fetches the pages of its concurrently, one page of each at a time,
+// and iterates over them in the order they arrive, such as for the union of
+// several index lookups. Each of its is only used by one goroutine at a time.
+func Merge(its ...PageIterator) PageIterator {
+	return &mergeIterator{
+		its:     its,
+		pending: make([]bool, len(its)),
+		results: make(chan mergeResult, len(its)),
+	}
+}
+
+type mergeResult struct {
+	index int
+	page  *Page
+	err   error
+}
+
+type mergeIterator struct {
+	its      []PageIterator
+	pending  []bool
+	inflight int
+	results  chan mergeResult
+}
+
+func (m *mergeIterator) Next() (*Page, error) {
+	m.fetch()
+	if m.inflight == 0 {
+		return nil, errNoMorePages
+	}
+
+	result := <-m.results
+	m.inflight--
+	m.pending[result.index] = false
+	return result.page, result.err
+}
+
+func (m *mergeIterator) HasNext() bool {
+	m.fetch()
+	return m.inflight > 0
+}
+
+// fetch starts fetching the next page of each iterator which isn't already
+// fetching one. results has room for a page of each, so fetches never block
+// when the merged iterator is abandoned.
+func (m *mergeIterator) fetch() {
+	for i, it := range m.its {
+		if m.pending[i] || !it.HasNext() {
+			continue
+		}
+
+		m.pending[i] = true
+		m.inflight++
+		go func(i int, it PageIterator) {
+			page, err := it.Next()
+			m.results <- mergeResult{index: i, page: page, err: err}
+		}(i, it)
+	}
+}
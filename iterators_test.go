@@ -0,0 +1,104 @@
+package fauna
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePages is a [fauna.PageIterator] over fixed pages, failing to fetch the
+// page at failAt once.
+type fakePages struct {
+	pages  [][]any
+	failAt int
+}
+
+func (f *fakePages) Next() (*Page, error) {
+	if len(f.pages) == 0 {
+		return nil, errNoMorePages
+	}
+	if f.failAt == 0 {
+		f.failAt = -1
+		return nil, assert.AnError
+	}
+	f.failAt--
+
+	page := &Page{Data: f.pages[0]}
+	f.pages = f.pages[1:]
+	return page, nil
+}
+
+func (f *fakePages) HasNext() bool {
+	return len(f.pages) > 0
+}
+
+func newFakePages(failAt int, pages ...[]any) *fakePages {
+	return &fakePages{pages: pages, failAt: failAt}
+}
+
+// drain reads every item of it, counting the errors it returns.
+func drain(it PageIterator) (items []any, errs int) {
+	for it.HasNext() {
+		page, err := it.Next()
+		if err != nil {
+			errs++
+			continue
+		}
+		items = append(items, page.Data...)
+	}
+	return items, errs
+}
+
+func TestComposeIterators(t *testing.T) {
+	t.Run("concat", func(t *testing.T) {
+		items, errs := drain(Concat(
+			newFakePages(-1, []any{1, 2}, []any{3}),
+			newFakePages(-1),
+			newFakePages(1, []any{4}, []any{5}),
+		))
+		assert.Equal(t, []any{1, 2, 3, 4, 5}, items)
+		assert.Equal(t, 1, errs, "the failed page is fetched again")
+	})
+
+	t.Run("interleave", func(t *testing.T) {
+		items, errs := drain(Interleave(
+			newFakePages(-1, []any{1}, []any{4}, []any{6}),
+			newFakePages(0, []any{2}),
+			newFakePages(-1),
+			newFakePages(-1, []any{3}, []any{5}),
+		))
+		assert.Equal(t, []any{1, 2, 3, 4, 5, 6}, items)
+		assert.Equal(t, 1, errs)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		items, errs := drain(Merge(
+			newFakePages(-1, []any{1, 2}, []any{3}),
+			newFakePages(1, []any{4}, []any{5}),
+			newFakePages(-1),
+		))
+		sort.Slice(items, func(i, j int) bool { return items[i].(int) < items[j].(int) })
+		assert.Equal(t, []any{1, 2, 3, 4, 5}, items)
+		assert.Equal(t, 1, errs)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		for _, it := range []PageIterator{Concat(), Interleave(), Merge()} {
+			assert.False(t, it.HasNext())
+			_, err := it.Next()
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("map items", func(t *testing.T) {
+		var sum int
+		err := MapItems(Concat(newFakePages(-1, []any{1, 2}), newFakePages(-1, []any{3})), func(n int) error {
+			sum += n
+			return nil
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, 6, sum)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package fauna
+
+import (
+	"fmt"
+	"time"
+)
+
+// KVCollectionDefault is the collection the key-value methods use unless
+// configured with [fauna.KVCollection].
+const KVCollectionDefault = "KeyValue"
+
+// KVCollection sets the collection used by the [fauna.Client] key-value
+// methods.
+func KVCollection(name string) ClientConfigFn {
+	return func(c *Client) { c.kvCollection = name }
+}
+
+// EnsureKVCollection creates the collection used by the key-value methods,
+// with the `byKey` index and unique constraint they rely on, if it doesn't
+// already exist.
+func (c *Client) EnsureKVCollection(opts ...QueryOptFn) error {
+	q, err := FQL(
+		"if (Collection.byName(${name}) == null) Collection.create({\n"+
+			"  name: ${name},\n"+
+			"  indexes: { byKey: { terms: [{ field: \".key\" }] } },\n"+
+			"  constraints: [{ unique: [\".key\"] }]\n"+
+			"})",
+		map[string]any{"name": c.kvCollection},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Query(q, opts...)
+	return err
+}
+
+// GetKV decodes the value stored under key into `into`, reporting whether the
+// key was found. Expired keys are not found.
+func (c *Client) GetKV(key string, into any, opts ...QueryOptFn) (bool, error) {
+	res, err := c.kvQuery(
+		"let doc = ${coll}.byKey(${key}).first()\nif (doc == null) null else [doc.value]",
+		key, nil, opts,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	wrapped, found := res.Data.([]any)
+	if !found || len(wrapped) != 1 {
+		return false, nil
+	}
+
+	if err := c.codec.decodeInto(wrapped[0], into); err != nil {
+		return true, fmt.Errorf("failed to unmarshal value for key %s: %w", key, err)
+	}
+
+	return true, nil
+}
+
+// SetKV stores value under key, replacing any existing value. A positive ttl
+// expires the key after that duration, otherwise the key never expires.
+func (c *Client) SetKV(key string, value any, ttl time.Duration, opts ...QueryOptFn) error {
+	_, err := c.kvQuery(
+		"let doc = ${coll}.byKey(${key}).first()\n"+
+			"let data = {\n"+
+			"  key: ${key},\n"+
+			"  value: ${value},\n"+
+			"  ttl: if (${ttl} > 0) Time.now().add(${ttl}, \"milliseconds\") else null\n"+
+			"}\n"+
+			"if (doc == null) ${coll}.create(data) else doc!.replace(data)\n"+
+			"null",
+		key,
+		map[string]any{"value": value, "ttl": ttl.Milliseconds()},
+		opts,
+	)
+
+	return err
+}
+
+// DeleteKV removes key. Deleting a key which doesn't exist is not an error.
+func (c *Client) DeleteKV(key string, opts ...QueryOptFn) error {
+	_, err := c.kvQuery("${coll}.byKey(${key}).first()?.delete()\nnull", key, nil, opts)
+	return err
+}
+
+func (c *Client) kvQuery(template string, key string, args map[string]any, opts []QueryOptFn) (*QuerySuccess, error) {
+	if key == "" {
+		return nil, fmt.Errorf("key must not be empty")
+	}
+
+	kvArgs := map[string]any{"coll": &Module{Name: c.kvCollection}, "key": key}
+	for k, v := range args {
+		kvArgs[k] = v
+	}
+
+	q, err := FQL(template, kvArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Query(q, opts...)
+}
@@ -0,0 +1,84 @@
+package fauna
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKV(t *testing.T) {
+	store := map[string]string{}
+
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		var (
+			literals strings.Builder
+			values   []any
+		)
+		for _, f := range req.Body["query"].(map[string]any)["fql"].([]any) {
+			switch ft := f.(type) {
+			case string:
+				literals.WriteString(ft)
+			case map[string]any:
+				values = append(values, ft["value"])
+			}
+		}
+
+		assert.Equal(t, map[string]any{"@mod": "Flags"}, values[0])
+		key := values[1].(string)
+
+		switch fql := literals.String(); {
+		case strings.Contains(fql, "replace"):
+			bs, _ := json.Marshal(values[3])
+			store[key] = string(bs)
+			return http.StatusOK, successBody("null")
+		case strings.Contains(fql, "delete"):
+			delete(store, key)
+			return http.StatusOK, successBody("null")
+		default:
+			if v, found := store[key]; found {
+				return http.StatusOK, successBody("[" + v + "]")
+			}
+			return http.StatusOK, successBody("null")
+		}
+	})
+	client := srv.client(KVCollection("Flags"))
+
+	type flag struct {
+		Enabled bool `fauna:"enabled"`
+		Rollout int  `fauna:"rollout"`
+	}
+
+	t.Run("set and get", func(t *testing.T) {
+		assert.NoError(t, client.SetKV("beta", flag{Enabled: true, Rollout: 20}, time.Hour))
+
+		var got flag
+		found, err := client.GetKV("beta", &got)
+		if assert.NoError(t, err) && assert.True(t, found) {
+			assert.Equal(t, flag{Enabled: true, Rollout: 20}, got)
+		}
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		var got flag
+		found, err := client.GetKV("missing", &got)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, client.DeleteKV("beta"))
+
+		var got flag
+		found, err := client.GetKV("beta", &got)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("empty key", func(t *testing.T) {
+		assert.Error(t, client.SetKV("", 1, 0))
+	})
+}
@@ -0,0 +1,168 @@
+package fauna
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// LimitMode controls what a [fauna.Client] does with a query that would
+// exceed a limit set with [fauna.MaxConcurrentRequests] or [fauna.RateLimit].
+type LimitMode int
+
+const (
+	// LimitQueue holds the query until it's within the limit, or its context
+	// ends.
+	LimitQueue LimitMode = iota
+
+	// LimitFail fails the query immediately with [fauna.ErrClientLimit].
+	LimitFail
+)
+
+// MaxConcurrentRequests limits the [fauna.Client] to n requests to Fauna in
+// flight at once. A request is in flight until its response has been read.
+// An n of zero or less removes the limit.
+func MaxConcurrentRequests(n int, mode LimitMode) ClientConfigFn {
+	return func(c *Client) {
+		if n <= 0 {
+			c.concurrency = nil
+			return
+		}
+		c.concurrency = &concurrencyLimiter{mode: mode, slots: make(chan struct{}, n)}
+	}
+}
+
+// RateLimit limits the [fauna.Client] to an average of perSecond requests to
+// Fauna per second, allowing bursts of up to burst requests. A perSecond of
+// zero or less removes the limit, and a burst of less than one is one.
+func RateLimit(perSecond float64, burst int, mode LimitMode) ClientConfigFn {
+	return func(c *Client) {
+		if !(perSecond > 0) {
+			c.rate = nil
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		c.rate = &rateLimiter{mode: mode, perSecond: perSecond, burst: float64(burst), tokens: float64(burst), now: time.Now}
+	}
+}
+
+type concurrencyLimiter struct {
+	mode  LimitMode
+	slots chan struct{}
+}
+
+// acquire takes a slot, which must be released.
+func (l *concurrencyLimiter) acquire(ctx context.Context) error {
+	if l.mode == LimitFail {
+		select {
+		case l.slots <- struct{}{}:
+			return nil
+		default:
+			return ErrClientLimit{Limit: "concurrency"}
+		}
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *concurrencyLimiter) release() {
+	<-l.slots
+}
+
+// rateLimiter is a token bucket. Queued requests reserve a token in advance,
+// taking the balance negative, so they're let through in order.
+type rateLimiter struct {
+	mu        sync.Mutex
+	mode      LimitMode
+	perSecond float64
+	burst     float64
+	tokens    float64
+	last      time.Time
+	now       func() time.Time
+}
+
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.perSecond
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return nil
+	}
+
+	if l.mode == LimitFail {
+		l.mu.Unlock()
+		return ErrClientLimit{Limit: "rate"}
+	}
+
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.perSecond * float64(time.Second))
+	l.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// return the reservation so later requests don't wait for it
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// limit waits for the [fauna.Client]'s limits to allow a request, returning a
+// func that must be called once the request is complete.
+func (c *Client) limit(ctx context.Context) (func(), error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if c.rate != nil {
+		if err := c.rate.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.concurrency != nil {
+		if err := c.concurrency.acquire(ctx); err != nil {
+			return nil, err
+		}
+		return c.concurrency.release, nil
+	}
+
+	return func() {}, nil
+}
+
+// releaseBody calls release when the response body is closed.
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
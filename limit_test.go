@@ -0,0 +1,169 @@
+package fauna
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	q, _ := FQL(`Dogs.all()`, nil)
+
+	t.Run("fails fast", func(t *testing.T) {
+		srv, release := newBlockingServer(t)
+		client := srv.client(MaxConcurrentRequests(2, LimitFail))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.Query(q)
+				assert.NoError(t, err)
+			}()
+		}
+		assert.Eventually(t, func() bool { return len(srv.received()) == 2 }, time.Second, time.Millisecond)
+
+		_, err := client.Query(q)
+		var limitErr ErrClientLimit
+		if assert.ErrorAs(t, err, &limitErr) {
+			assert.Equal(t, "concurrency", limitErr.Limit)
+		}
+
+		close(release)
+		wg.Wait()
+
+		_, err = client.Query(q)
+		assert.NoError(t, err, "slots are released once responses are read")
+	})
+
+	t.Run("queues", func(t *testing.T) {
+		srv, release := newBlockingServer(t)
+		client := srv.client(MaxConcurrentRequests(2, LimitQueue))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.Query(q)
+				assert.NoError(t, err)
+			}()
+		}
+		assert.Eventually(t, func() bool { return len(srv.received()) == 2 }, time.Second, time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := client.Query(q, QueryContext(ctx))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Len(t, srv.received(), 2)
+
+		close(release)
+		wg.Wait()
+		assert.Len(t, srv.received(), 3)
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		srv := newTestServer(t, func(testRequest) (int, string) {
+			return http.StatusOK, successBody(`null`)
+		})
+
+		for _, n := range []int{0, -1} {
+			for _, mode := range []LimitMode{LimitQueue, LimitFail} {
+				client := srv.client(MaxConcurrentRequests(2, mode), MaxConcurrentRequests(n, mode))
+				assert.Nil(t, client.concurrency)
+
+				_, err := client.Query(q)
+				assert.NoError(t, err, "n=%d mode=%d", n, mode)
+			}
+		}
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, successBody(`null`)
+	})
+	q, _ := FQL(`null`, nil)
+
+	t.Run("fails fast", func(t *testing.T) {
+		now := time.Unix(1680000000, 0)
+		client := srv.client(RateLimit(1, 2, LimitFail))
+		client.rate.now = func() time.Time { return now }
+
+		for i := 0; i < 2; i++ {
+			_, err := client.Query(q)
+			assert.NoError(t, err)
+		}
+
+		_, err := client.Query(q)
+		var limitErr ErrClientLimit
+		if assert.ErrorAs(t, err, &limitErr) {
+			assert.Equal(t, "rate", limitErr.Limit)
+		}
+
+		now = now.Add(time.Second)
+		_, err = client.Query(q)
+		assert.NoError(t, err)
+
+		now = now.Add(time.Hour)
+		for i := 0; i < 2; i++ {
+			_, err := client.Query(q)
+			assert.NoError(t, err, "bursts are capped")
+		}
+		_, err = client.Query(q)
+		assert.ErrorAs(t, err, &ErrClientLimit{})
+	})
+
+	t.Run("queues", func(t *testing.T) {
+		client := srv.client(RateLimit(100, 1, LimitQueue))
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			_, err := client.Query(q)
+			assert.NoError(t, err)
+		}
+		assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+	})
+
+	t.Run("returns reservations of cancelled queries", func(t *testing.T) {
+		client := srv.client(RateLimit(1, 1, LimitQueue))
+
+		_, err := client.Query(q)
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = client.Query(q, QueryContext(ctx))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		client.rate.mu.Lock()
+		assert.Greater(t, client.rate.tokens, -1.0)
+		client.rate.mu.Unlock()
+	})
+	t.Run("no limit", func(t *testing.T) {
+		for _, perSecond := range []float64{0, -1, math.NaN()} {
+			client := srv.client(RateLimit(1, 1, LimitFail), RateLimit(perSecond, 1, LimitFail))
+			assert.Nil(t, client.rate)
+
+			for i := 0; i < 3; i++ {
+				_, err := client.Query(q)
+				assert.NoError(t, err, "perSecond=%v", perSecond)
+			}
+		}
+	})
+
+	t.Run("bursts of at least one", func(t *testing.T) {
+		client := srv.client(RateLimit(1, 0, LimitFail))
+
+		_, err := client.Query(q)
+		assert.NoError(t, err)
+		_, err = client.Query(q)
+		assert.ErrorAs(t, err, &ErrClientLimit{})
+	})
+}
@@ -0,0 +1,90 @@
+package fauna
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+const queryFileExt = ".fql"
+
+// QueryLoader holds named FQL templates read from `.fql` files.
+type QueryLoader struct {
+	templates map[string]string
+}
+
+// LoadQueries reads every `.fql` file beneath dir in fsys and returns a
+// [fauna.QueryLoader] to build queries from them. Queries are named by their
+// path relative to dir without the extension, so `users/get.fql` is available
+// as "users/get". Templates are parsed up front so placeholder errors surface
+// at load time rather than on first use.
+//
+// fsys will typically be an [embed.FS]:
+//
+//	//go:embed queries
+//	var queries embed.FS
+//
+//	loader, err := fauna.LoadQueries(queries, "queries")
+func LoadQueries(fsys fs.FS, dir string) (*QueryLoader, error) {
+	loader := &QueryLoader{templates: map[string]string{}}
+
+	walkErr := fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || path.Ext(p) != queryFileExt {
+			return nil
+		}
+
+		bin, readErr := fs.ReadFile(fsys, p)
+		if readErr != nil {
+			return readErr
+		}
+
+		text := string(bin)
+		if _, parseErr := newTemplate(text).Parse(); parseErr != nil {
+			return fmt.Errorf("failed to parse %s: %w", p, parseErr)
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(p, dir+"/"), queryFileExt)
+		loader.templates[name] = text
+
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("failed to load queries: %w", walkErr)
+	}
+
+	return loader, nil
+}
+
+// Query creates a [fauna.Query] from the named template and set of arguments.
+// See [fauna.FQL] for how args are applied.
+func (l *QueryLoader) Query(name string, args map[string]any) (*Query, error) {
+	text, found := l.templates[name]
+	if !found {
+		return nil, fmt.Errorf("query %s not found", name)
+	}
+
+	return FQL(text, args)
+}
+
+// Template returns the raw text of the named template.
+func (l *QueryLoader) Template(name string) (string, bool) {
+	text, found := l.templates[name]
+	return text, found
+}
+
+// Names returns the sorted names of all loaded queries.
+func (l *QueryLoader) Names() []string {
+	names := make([]string, 0, len(l.templates))
+	for name := range l.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,68 @@
+package fauna
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadQueries(t *testing.T) {
+	fsys := fstest.MapFS{
+		"queries/users/get.fql":   {Data: []byte("Users.byId(${id})")},
+		"queries/users/all.fql":   {Data: []byte("Users.all()")},
+		"queries/README.md":       {Data: []byte("not a query")},
+		"other/ignored.fql":       {Data: []byte("Ignored.all()")},
+		"broken/invalid.fql":      {Data: []byte("Users.byId($id)")},
+		"queries/nested/a/b.fql":  {Data: []byte("${x} + 1")},
+		"queries/nested/a/c.fqlx": {Data: []byte("not a query")},
+	}
+
+	t.Run("loads named queries", func(t *testing.T) {
+		loader, err := LoadQueries(fsys, "queries")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, []string{"nested/a/b", "users/all", "users/get"}, loader.Names())
+
+		q, qErr := loader.Query("users/get", map[string]any{"id": "1234"})
+		if assert.NoError(t, qErr) {
+			assert.Equal(t, &Query{fragments: []*queryFragment{
+				{true, "Users.byId("},
+				{false, "1234"},
+				{true, ")"},
+			}}, q)
+		}
+
+		text, found := loader.Template("users/all")
+		assert.True(t, found)
+		assert.Equal(t, "Users.all()", text)
+	})
+
+	t.Run("missing query", func(t *testing.T) {
+		loader, err := LoadQueries(fsys, "queries")
+		if assert.NoError(t, err) {
+			_, qErr := loader.Query("users/missing", nil)
+			assert.ErrorContains(t, qErr, "users/missing")
+		}
+	})
+
+	t.Run("missing arguments", func(t *testing.T) {
+		loader, err := LoadQueries(fsys, "queries")
+		if assert.NoError(t, err) {
+			_, qErr := loader.Query("users/get", nil)
+			assert.Error(t, qErr)
+		}
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := LoadQueries(fsys, "broken")
+		assert.ErrorContains(t, err, "broken/invalid.fql")
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := LoadQueries(fsys, "nope")
+		assert.Error(t, err)
+	})
+}
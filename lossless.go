@@ -0,0 +1,63 @@
+package fauna
+
+import (
+	"reflect"
+	"time"
+)
+
+// Long is a Fauna Long, decoded into interface values such as those of a
+// map[string]any with [fauna.LosslessDecoding], so it's encoded as a Long
+// again even if it's small enough to be an Int.
+type Long int64
+
+// Date is a Fauna Date, decoded into interface values such as those of a
+// map[string]any with [fauna.LosslessDecoding], so it's encoded as a Date
+// again rather than a Time. It decodes from and into [time.Time] fields.
+type Date struct {
+	time.Time
+}
+
+// LosslessDecoding sets whether the client decodes the Fauna types which
+// share a Go type with another, Longs and Dates, as [fauna.Long] and
+// [fauna.Date] when they're decoded into interface values, such as the values
+// of a map[string]any or [fauna.QuerySuccess.Data]. Documents, refs, and other values already decode into
+// types which encode as they were, so with it enabled, a value decoded into
+// a map[string]any can be re-encoded without changing any types, such as by
+// middleware rewriting documents.
+//
+// It's off by default, as code checking for int64 or *time.Time values would
+// no longer see Longs and Dates. A client derived with [fauna.Client.With]
+// can enable it for only the queries which need it.
+func LosslessDecoding(enabled bool) ClientConfigFn {
+	return func(c *Client) {
+		c.codec.lossless = enabled
+	}
+}
+
+var (
+	longType = reflect.TypeOf(Long(0))
+	dateType = reflect.TypeOf(Date{})
+)
+
+// convertDates is a decode hook which decodes [fauna.Date] values into
+// [time.Time] fields and the other way around.
+func convertDates(_ reflect.Type, t reflect.Type, data any) (any, error) {
+	switch t {
+	case timeType:
+		if date, isDate := data.(Date); isDate {
+			return date.Time, nil
+		}
+
+	case dateType:
+		switch date := data.(type) {
+		case time.Time:
+			return Date{date}, nil
+		case *time.Time:
+			if date != nil {
+				return Date{*date}, nil
+			}
+		}
+	}
+
+	return data, nil
+}
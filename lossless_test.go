@@ -0,0 +1,112 @@
+package fauna
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLosslessDecoding(t *testing.T) {
+	const doc = `{"@doc":{
+		"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00.123456789Z"},
+		"name":"Scout","age":{"@int":"3"},"visits":{"@long":"5"},"weight":{"@double":"12.5"},
+		"born":{"@date":"2020-01-02"},"seen":{"@time":"2023-03-31T12:00:00.5Z"},
+		"owner":{"@ref":{"id":"7","coll":{"@mod":"People"}}},
+		"breed":{"@ref":{"name":"Collie","coll":{"@mod":"Breeds"}}},
+		"friends":{"@set":"after"},"changes":{"@stream":"token"},
+		"extra":{"@object":{"@ref":"not a ref","@int":"not an int"}},
+		"tags":["good",{"@long":"1"}]
+	}}`
+
+	c := codec{lossless: true}
+
+	var data map[string]any
+	if !assert.NoError(t, c.unmarshal([]byte(`{"data":`+doc+`}`), &data)) {
+		return
+	}
+	assert.Equal(t, Long(5), data["data"].(*Document).Data["visits"])
+	assert.Equal(t, Date{time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)}, data["data"].(*Document).Data["born"])
+
+	bin, err := marshal(data["data"])
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, doc, string(bin))
+	}
+
+	t.Run("into typed fields", func(t *testing.T) {
+		var dog struct {
+			Visits int          `fauna:"visits"`
+			Born   time.Time    `fauna:"born"`
+			Date   Date         `fauna:"date"`
+			Any    any          `fauna:"any"`
+			Nested []*time.Time `fauna:"nested"`
+		}
+		body := []byte(`{"visits":{"@long":"5"},"born":{"@date":"2020-01-02"},"date":{"@date":"2021-01-02"},"any":{"@date":"2022-01-02"},"nested":[{"@date":"2023-01-02"}]}`)
+
+		for name, decode := range map[string]func([]byte, any) error{"generic": c.unmarshal, "direct": c.decodeDirect} {
+			if assert.NoError(t, decode(body, &dog), name) {
+				assert.Equal(t, 5, dog.Visits, name)
+				assert.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), dog.Born, name)
+				assert.Equal(t, Date{time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)}, dog.Date, name)
+				assert.Equal(t, Date{time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)}, dog.Any, name)
+				assert.Equal(t, time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), *dog.Nested[0], name)
+			}
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var v any
+		if assert.NoError(t, unmarshal([]byte(`[{"@long":"5"},{"@date":"2020-01-02"}]`), &v)) {
+			date := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+			assert.Equal(t, []any{int64(5), &date}, v)
+		}
+	})
+}
+
+func TestClientLosslessDecoding(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		return http.StatusOK, successBody(`{"@set":{"data":[{"@long":"5"},{"@date":"2020-01-02"}]}}`)
+	})
+	q, _ := FQL(`Dogs.all()`, nil)
+	date := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	client := srv.client(LosslessDecoding(true))
+	res, err := client.Query(q)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []any{Long(5), Date{date}}, res.Data.(*Page).Data)
+
+		var items []any
+		if assert.NoError(t, res.Data.(*Page).Unmarshal(&items)) {
+			assert.Equal(t, []any{Long(5), Date{date}}, items)
+		}
+	}
+
+	res, err = client.With(LosslessDecoding(false)).Query(q)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []any{int64(5), &date}, res.Data.(*Page).Data)
+	}
+
+	res, err = srv.client().Query(q)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []any{int64(5), &date}, res.Data.(*Page).Data)
+	}
+}
+
+func TestClientLosslessAbort(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		return http.StatusBadRequest, `{"error":{"code":"abort","message":"aborted","abort":{"count":{"@long":"5"}}},"summary":""}`
+	})
+	q, _ := FQL(`abort({ count: 5 })`, nil)
+
+	var abortErr *ErrAbort
+	_, err := srv.client(LosslessDecoding(true)).Query(q)
+	if assert.ErrorAs(t, err, &abortErr) {
+		assert.Equal(t, map[string]any{"count": Long(5)}, abortErr.Abort)
+	}
+
+	_, err = srv.client().Query(q)
+	if assert.ErrorAs(t, err, &abortErr) {
+		assert.Equal(t, map[string]any{"count": int64(5)}, abortErr.Abort)
+	}
+}
@@ -0,0 +1,264 @@
+package fauna
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Metadata identifies a document decoded into a struct.
+type Metadata struct {
+	// ID is the document's ID, for documents in user collections.
+	ID string
+
+	// Name is the document's name, for named documents such as collections
+	// and functions.
+	Name string
+
+	// Coll is the name of the document's collection.
+	Coll string
+
+	// TS is the time the document was last written.
+	TS *time.Time
+}
+
+// MetadataOf returns the metadata of v, a struct or pointer to one which
+// embeds [fauna.Document] or [fauna.NamedDocument] or has fields tagged `id`
+// or `name`, `coll`, and `ts`. Structs with an `id` field have no name, so
+// their `name` field is treated as data. It reports false if v has no ID or
+// name, such as a struct for a document that hasn't been created yet.
+func MetadataOf(v any) (Metadata, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return Metadata{}, false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return Metadata{}, false
+	}
+
+	info := codec{}.structInfoFor(rv.Type())
+	field := func(name string) any {
+		index, found := info.fields[name]
+		if !found {
+			return nil
+		}
+		if fv, ok := fieldValue(rv, index); ok {
+			return fv.Interface()
+		}
+		return nil
+	}
+
+	var meta Metadata
+	meta.ID, _ = field("id").(string)
+	if _, hasID := info.fields["id"]; !hasID {
+		meta.Name, _ = field("name").(string)
+	}
+
+	switch coll := field("coll").(type) {
+	case *Module:
+		if coll != nil {
+			meta.Coll = coll.Name
+		}
+	case Module:
+		meta.Coll = coll.Name
+	case string:
+		meta.Coll = coll
+	}
+
+	switch ts := field("ts").(type) {
+	case *time.Time:
+		meta.TS = ts
+	case time.Time:
+		if !ts.IsZero() {
+			meta.TS = &ts
+		}
+	}
+
+	return meta, meta.ID != "" || meta.Name != ""
+}
+
+// fieldValue is [reflect.Value.FieldByIndex], reporting false rather than
+// panicking at nil embedded pointers.
+func fieldValue(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+// MappedField describes how a struct field maps to a document field.
+type MappedField struct {
+	// Name is the document field name.
+	Name string
+
+	// Field is the Go struct field, with Index relative to the mapped struct
+	// for use with [reflect.Value.FieldByIndex].
+	Field reflect.StructField
+}
+
+// StructFields lists the document fields the decoder maps v, a struct, pointer
+// to one, or [reflect.Type] of either, to, in struct field order. Fields of
+// embedded structs are flattened, including the metadata fields of
+// [fauna.Document].
+func StructFields(v any) ([]MappedField, error) {
+	t, isType := v.(reflect.Type)
+	if !isType {
+		t = reflect.TypeOf(v)
+	}
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct, got %v", t)
+	}
+
+	info := codec{}.structInfoFor(t)
+	fields := make([]MappedField, 0, len(info.fields))
+	for name, index := range info.fields {
+		field := t.FieldByIndex(index)
+		field.Index = index
+		fields = append(fields, MappedField{Name: name, Field: field})
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i].Field.Index, fields[j].Field.Index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+
+	return fields, nil
+}
+
+// metadataFields can't be written, so aren't part of [fauna.Changes].
+var metadataFields = map[string]bool{"id": true, "coll": true, "ts": true}
+
+// Changes compares before and after, structs of the same type or pointers to
+// them, returning a patch of the document fields that differ, with the values
+// from after, for use with [Collection.Update] to write only what changed.
+// Fields are compared by their encoded values, so times compare by instant.
+// Metadata fields such as `id` and `ts` are ignored.
+func Changes(before, after any) (map[string]any, error) {
+	bv, av := reflect.ValueOf(before), reflect.ValueOf(after)
+	for bv.Kind() == reflect.Pointer && av.Kind() == reflect.Pointer {
+		if bv.IsNil() || av.IsNil() {
+			return nil, fmt.Errorf("cannot compare nil documents")
+		}
+		bv, av = bv.Elem(), av.Elem()
+	}
+	if bv.Type() != av.Type() || bv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected structs of the same type, got %T and %T", before, after)
+	}
+
+	fields, err := StructFields(bv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	var c codec
+	patch := map[string]any{}
+	for _, field := range fields {
+		if metadataFields[field.Name] {
+			continue
+		}
+
+		bf, bOk := fieldValue(bv, field.Field.Index)
+		af, aOk := fieldValue(av, field.Field.Index)
+		if !aOk {
+			if bOk {
+				patch[field.Name] = nil
+			}
+			continue
+		}
+
+		var bEnc any
+		if bOk {
+			if bEnc, err = c.encode(bf.Interface(), ""); err != nil {
+				return nil, fmt.Errorf("failed to encode %s: %w", field.Name, err)
+			}
+		}
+
+		aEnc, err := c.encode(af.Interface(), "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", field.Name, err)
+		}
+
+		if !bOk || !reflect.DeepEqual(bEnc, aEnc) {
+			patch[field.Name] = af.Interface()
+		}
+	}
+
+	return patch, nil
+}
+
+// WriteOp is a write made by a [fauna.Collection].
+type WriteOp string
+
+// WriteOps passed to [fauna.CollectionHooks]
+const (
+	OpCreate  WriteOp = "create"
+	OpUpdate  WriteOp = "update"
+	OpReplace WriteOp = "replace"
+	OpDelete  WriteOp = "delete"
+)
+
+// CollectionHooks are called by a [fauna.Collection] around reads and writes,
+// for layers such as data mappers and caches built on top of it.
+type CollectionHooks[T any] struct {
+	// BeforeWrite is called before each write, and can modify doc or fail
+	// the write by returning an error. doc is the *T being written for
+	// creates and replaces, the patch for updates, and nil for deletes. id
+	// is empty for creates.
+	BeforeWrite func(op WriteOp, id string, doc any) error
+
+	// AfterRead is called with each document returned by the collection's
+	// methods, including those returned by writes, and fails the method if
+	// it returns an error.
+	AfterRead func(doc *T) error
+
+	// AfterWrite is called after each successful write, with the written
+	// document, or nil for deletes, to track changes.
+	AfterWrite func(op WriteOp, id string, doc *T)
+}
+
+// WithHooks returns a copy of the [fauna.Collection] calling hooks.
+func (c *Collection[T]) WithHooks(hooks CollectionHooks[T]) *Collection[T] {
+	copied := *c
+	copied.hooks = hooks
+	return &copied
+}
+
+func (c *Collection[T]) beforeWrite(op WriteOp, id string, doc any) error {
+	if c.hooks.BeforeWrite == nil {
+		return nil
+	}
+
+	return c.hooks.BeforeWrite(op, id, doc)
+}
+
+func (c *Collection[T]) afterWrite(op WriteOp, id string, doc *T) {
+	if c.hooks.AfterWrite == nil {
+		return
+	}
+
+	if id == "" && doc != nil {
+		meta, _ := MetadataOf(doc)
+		id = meta.ID
+	}
+
+	c.hooks.AfterWrite(op, id, doc)
+}
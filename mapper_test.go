@@ -0,0 +1,154 @@
+package fauna
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataOf(t *testing.T) {
+	ts := time.Date(2023, 2, 28, 18, 10, 10, 0, time.UTC)
+
+	meta, ok := MetadataOf(&collectionTestDino{Document: Document{ID: "1", Coll: &Module{"Dinos"}, TS: &ts}, Name: "Dino"})
+	assert.True(t, ok)
+	assert.Equal(t, Metadata{ID: "1", Coll: "Dinos", TS: &ts}, meta, "name is data on documents with ids")
+
+	meta, ok = MetadataOf(struct {
+		NamedDocument
+	}{NamedDocument{Name: "Dinos", Coll: &Module{"Collection"}}})
+	assert.True(t, ok)
+	assert.Equal(t, Metadata{Name: "Dinos", Coll: "Collection"}, meta)
+
+	meta, ok = MetadataOf(struct {
+		Key  string    `fauna:"id"`
+		Coll string    `fauna:"coll"`
+		At   time.Time `fauna:"ts"`
+	}{"2", "Dinos", ts})
+	assert.True(t, ok)
+	assert.Equal(t, Metadata{ID: "2", Coll: "Dinos", TS: &ts}, meta)
+
+	_, ok = MetadataOf(collectionTestDino{Name: "Dino"})
+	assert.False(t, ok, "uncreated documents have no metadata")
+
+	_, ok = MetadataOf("Dino")
+	assert.False(t, ok)
+}
+
+func TestStructFields(t *testing.T) {
+	fields, err := StructFields(collectionTestDino{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	assert.Equal(t, []string{"id", "coll", "ts", "name", "age"}, names)
+	assert.Equal(t, "Age", fields[4].Field.Name)
+	assert.Equal(t, reflect.TypeOf(0), fields[4].Field.Type)
+
+	dino := collectionTestDino{Age: 3}
+	assert.Equal(t, 3, reflect.ValueOf(dino).FieldByIndex(fields[4].Field.Index).Interface())
+
+	_, err = StructFields(reflect.TypeOf(&dino))
+	assert.NoError(t, err)
+
+	_, err = StructFields(3)
+	assert.ErrorContains(t, err, "expected a struct")
+}
+
+func TestChanges(t *testing.T) {
+	ts := time.Now()
+	before := collectionTestDino{Document: Document{ID: "1", TS: &ts}, Name: "Dino", Age: 3}
+
+	after := before
+	later := ts.Add(time.Hour)
+	after.TS = &later
+	after.Age = 4
+
+	patch, err := Changes(&before, &after)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"age": 4}, patch)
+
+	patch, err = Changes(before, before)
+	assert.NoError(t, err)
+	assert.Empty(t, patch)
+
+	_, err = Changes(before, struct{}{})
+	assert.ErrorContains(t, err, "same type")
+}
+
+func TestCollectionHooks(t *testing.T) {
+	const dinoDoc = `{"@doc":{"id":"1","coll":{"@mod":"Dinos"},"ts":{"@time":"2023-02-28T18:10:10.00001Z"},"name":"Dino","age":{"@int":"3"}}}`
+
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		if bytes.Contains(req.Raw, []byte("doc.delete()")) {
+			return http.StatusOK, successBody(`true`)
+		}
+		return http.StatusOK, successBody(dinoDoc)
+	})
+
+	type write struct {
+		op WriteOp
+		id string
+	}
+	var (
+		before []write
+		after  []write
+		reads  int
+	)
+
+	dinos := NewCollection[collectionTestDino](srv.client(), "Dinos").WithHooks(CollectionHooks[collectionTestDino]{
+		BeforeWrite: func(op WriteOp, id string, doc any) error {
+			before = append(before, write{op, id})
+			if dino, ok := doc.(*collectionTestDino); ok {
+				if dino.Name == "" {
+					return errors.New("dinos need names")
+				}
+				dino.Age++
+			}
+			return nil
+		},
+		AfterRead: func(doc *collectionTestDino) error {
+			reads++
+			return nil
+		},
+		AfterWrite: func(op WriteOp, id string, doc *collectionTestDino) {
+			after = append(after, write{op, id})
+		},
+	})
+
+	_, err := dinos.Create(collectionTestDino{Age: 2})
+	assert.ErrorContains(t, err, "need names")
+	assert.Empty(t, srv.received(), "failed hooks stop the write")
+
+	_, err = dinos.Create(collectionTestDino{Name: "Dino", Age: 2})
+	assert.NoError(t, err)
+	received := srv.received()
+	sent := received[len(received)-1].Body["query"].(map[string]any)["fql"].([]any)[2].(map[string]any)["value"]
+	assert.Equal(t, map[string]any{"name": "Dino", "age": map[string]any{"@int": "3"}}, sent, "hooks can modify documents")
+
+	_, err = dinos.Update("1", map[string]any{"age": 4})
+	assert.NoError(t, err)
+	_, err = dinos.Get("1")
+	assert.NoError(t, err)
+	assert.NoError(t, dinos.Delete("1"))
+
+	assert.Equal(t, []write{{OpCreate, ""}, {OpCreate, ""}, {OpUpdate, "1"}, {OpDelete, "1"}}, before)
+	assert.Equal(t, []write{{OpCreate, "1"}, {OpUpdate, "1"}, {OpDelete, "1"}}, after)
+	assert.Equal(t, 3, reads)
+
+	t.Run("read errors fail the method", func(t *testing.T) {
+		failing := dinos.WithHooks(CollectionHooks[collectionTestDino]{
+			AfterRead: func(*collectionTestDino) error { return errors.New("stale") },
+		})
+		_, err := failing.Get("1")
+		assert.ErrorContains(t, err, "stale")
+	})
+}
@@ -0,0 +1,99 @@
+package fauna
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+type encodeOptions struct {
+	deterministic bool
+	codec         codec
+}
+
+// EncodeOptFn configuration options for [fauna.Marshal]
+type EncodeOptFn func(*encodeOptions)
+
+// Deterministic normalizes the encoding so equal values always encode to the
+// same bytes, such as for hashing queries or golden-file tests. Object keys
+// are always sorted and times are always encoded in UTC; with Deterministic,
+// negative zero is also encoded as zero, and a [fauna.Long] small enough to be
+// an Int as an Int.
+func Deterministic() EncodeOptFn {
+	return func(o *encodeOptions) { o.deterministic = true }
+}
+
+// Marshal encodes v in Fauna's tagged format, as it's sent as a query
+// argument. A [fauna.Query] is encoded as it's sent as a query.
+func Marshal(v any, opts ...EncodeOptFn) ([]byte, error) {
+	var o encodeOptions
+	for _, optFn := range opts {
+		optFn(&o)
+	}
+
+	enc, err := o.codec.encodeSealed(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.deterministic {
+		if enc, err = normalize(enc); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(enc)
+}
+
+// normalize rewrites an encoded value so that equal values have the same
+// encoding.
+func normalize(enc any) (any, error) {
+	switch v := enc.(type) {
+	case streamedArray:
+		v.deterministic = true
+		return v, nil
+
+	case map[typeTag]any:
+		if double, isDouble := v[typeTagDouble]; isDouble && double == "-0" {
+			return map[typeTag]any{typeTagDouble: "0"}, nil
+		}
+		if long, isLong := v[typeTagLong].(string); isLong {
+			if i, err := strconv.ParseInt(long, 10, 64); err == nil {
+				return encodeInt(i)
+			}
+		}
+
+		out := make(map[typeTag]any, len(v))
+		for k, elem := range v {
+			normalized, err := normalize(elem)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = normalized
+		}
+		return out, nil
+
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, elem := range v {
+			normalized, err := normalize(elem)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = normalized
+		}
+		return out, nil
+
+	case []any:
+		out := make([]any, len(v))
+		for i, elem := range v {
+			normalized, err := normalize(elem)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = normalized
+		}
+		return out, nil
+	}
+
+	return enc, nil
+}
@@ -0,0 +1,35 @@
+package fauna
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshal(t *testing.T) {
+	q, _ := FQL(`Dogs.create(${dog})`, map[string]any{"dog": map[string]any{
+		"name":   "Scout",
+		"weight": math.Copysign(0, -1),
+		"born":   time.Date(2020, 1, 2, 3, 0, 0, 0, time.FixedZone("", 3600)),
+		"raw":    taggedJSON(`{ "@int" : "1" }`),
+		"visits": Long(2),
+	}})
+
+	bin, err := Marshal(q)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(bin), `{"@double":"-0"}`)
+		assert.Contains(t, string(bin), `{"@long":"2"}`)
+	}
+
+	bin, err = Marshal(q, Deterministic())
+	if assert.NoError(t, err) {
+		assert.Equal(t,
+			`{"fql":["Dogs.create(",{"value":{"born":{"@time":"2020-01-02T02:00:00Z"},"name":"Scout","raw":{"@int":"1"},"visits":{"@int":"2"},"weight":{"@double":"0"}}},")"]}`,
+			string(bin))
+	}
+
+	again, _ := Marshal(q, Deterministic())
+	assert.Equal(t, bin, again)
+}
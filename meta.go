@@ -0,0 +1,44 @@
+package fauna
+
+import (
+	"reflect"
+	"time"
+)
+
+// DocumentMeta is the metadata of a document, for embedding in the structs
+// documents are decoded into, which populates it when decoding a document:
+//
+//	type Dog struct {
+//		fauna.DocumentMeta
+//		Name string `fauna:"name"`
+//	}
+//
+// Unlike [fauna.Document], a struct embedding DocumentMeta is encoded as the
+// document's data rather than a reference to it, so it can be passed to
+// `create` and `update`. Only TTL is encoded, as the other fields can't be
+// written.
+type DocumentMeta struct {
+	// ID is the document's ID.
+	ID string `fauna:"id"`
+
+	// Coll is the document's collection.
+	Coll *Module `fauna:"coll"`
+
+	// TS is when the document was last written.
+	TS *time.Time `fauna:"ts"`
+
+	// TTL is when the document expires, or nil if it doesn't.
+	TTL *time.Time `fauna:"ttl"`
+}
+
+// Ref returns a reference to the document, or nil if the metadata has no ID
+// or collection, such as for a document which hasn't been created yet.
+func (m DocumentMeta) Ref() *Ref {
+	if m.ID == "" || m.Coll == nil {
+		return nil
+	}
+
+	return &Ref{ID: m.ID, Coll: m.Coll}
+}
+
+var documentMetaType = reflect.TypeOf(DocumentMeta{})
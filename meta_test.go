@@ -0,0 +1,50 @@
+package fauna
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type metaTestDog struct {
+	DocumentMeta
+	Name string `fauna:"name"`
+}
+
+func TestDocumentMeta(t *testing.T) {
+	const body = `{"@doc":{
+		"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"},
+		"ttl":{"@time":"2024-04-01T00:00:00Z"},"name":"Scout"
+	}}`
+	ts := time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
+	ttl := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	want := metaTestDog{
+		DocumentMeta: DocumentMeta{ID: "1", Coll: &Module{"Dogs"}, TS: &ts, TTL: &ttl},
+		Name:         "Scout",
+	}
+
+	for name, decode := range map[string]func([]byte, any) error{"generic": unmarshal, "direct": decodeDirect} {
+		var got metaTestDog
+		if assert.NoError(t, decode([]byte(body), &got), name) {
+			assert.Equal(t, want, got, name)
+		}
+	}
+
+	assert.Equal(t, &Ref{ID: "1", Coll: &Module{"Dogs"}}, want.Ref())
+	assert.Nil(t, metaTestDog{}.Ref())
+
+	meta, found := MetadataOf(want)
+	assert.True(t, found)
+	assert.Equal(t, Metadata{ID: "1", Coll: "Dogs", TS: &ts}, meta)
+
+	bin, err := marshal(want)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"name":"Scout","ttl":{"@time":"2024-04-01T00:00:00Z"}}`, string(bin))
+	}
+
+	bin, err = marshal(metaTestDog{Name: "Rex"})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"name":"Rex"}`, string(bin))
+	}
+}
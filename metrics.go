@@ -0,0 +1,214 @@
+package fauna
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Metric names recorded with a [fauna.Meter]
+const (
+	// MetricQueryDuration is a histogram of the time taken by each request
+	// to Fauna, in seconds, including retries.
+	MetricQueryDuration = "fauna.query.duration"
+
+	// MetricComputeOps counts the Transactional Compute Ops consumed.
+	MetricComputeOps = "fauna.query.compute_ops"
+
+	// MetricReadOps counts the Transactional Read Ops consumed.
+	MetricReadOps = "fauna.query.read_ops"
+
+	// MetricWriteOps counts the Transactional Write Ops consumed.
+	MetricWriteOps = "fauna.query.write_ops"
+
+	// MetricRetries counts the requests retried by the client.
+	MetricRetries = "fauna.query.retries"
+
+	// MetricThrottled counts the requests Fauna throttled, whether or not
+	// they were retried.
+	MetricThrottled = "fauna.query.throttled"
+
+	// MetricStreamEvents counts the events received by streams.
+	MetricStreamEvents = "fauna.stream.events"
+
+	// MetricStreamReconnects counts the times streams reconnected after their
+	// connection dropped.
+	MetricStreamReconnects = "fauna.stream.reconnects"
+
+	// MetricStreamLag is a histogram of how far behind streams are, in
+	// seconds, measured from the transaction time of each message they
+	// receive.
+	MetricStreamLag = "fauna.stream.lag"
+)
+
+// Attribute is a key and value describing a metric measurement.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Int64Counter is a monotonic counter, such as an OpenTelemetry
+// metric.Int64Counter.
+type Int64Counter interface {
+	Add(ctx context.Context, incr int64, attrs ...Attribute)
+}
+
+// Float64Histogram records a distribution of values, such as an OpenTelemetry
+// metric.Float64Histogram.
+type Float64Histogram interface {
+	Record(ctx context.Context, value float64, attrs ...Attribute)
+}
+
+// Meter creates the instruments a [fauna.Client] records metrics with. It's
+// shaped like an OpenTelemetry metric.Meter, so can be implemented by
+// wrapping one without the driver depending on OpenTelemetry:
+//
+//	type otelMeter struct{ metric.Meter }
+//
+//	func (m otelMeter) Int64Counter(name, unit, desc string) fauna.Int64Counter {
+//		c, _ := m.Meter.Int64Counter(name, metric.WithUnit(unit), metric.WithDescription(desc))
+//		return otelCounter{c}
+//	}
+//
+// where otelCounter converts each [fauna.Attribute] to an attribute.String.
+type Meter interface {
+	Int64Counter(name, unit, description string) Int64Counter
+	Float64Histogram(name, unit, description string) Float64Histogram
+}
+
+// WithMeter records the [fauna.Client]'s metrics with meter. Every
+// measurement has the attribute `db.system` set to "fauna", and failed
+// requests have `error.type` set to the error code, or "network" if Fauna
+// wasn't reached. Queries run with [Client.Execute] have `fauna.query.name`
+// set to the name of the prepared query. Stream measurements have
+// `fauna.stream` set to the name given with [fauna.StreamName].
+func WithMeter(meter Meter) ClientConfigFn {
+	return func(c *Client) {
+		c.metrics = &clientMetrics{
+			duration:   meter.Float64Histogram(MetricQueryDuration, "s", "Duration of requests to Fauna"),
+			computeOps: meter.Int64Counter(MetricComputeOps, "{op}", "Transactional Compute Ops consumed"),
+			readOps:    meter.Int64Counter(MetricReadOps, "{op}", "Transactional Read Ops consumed"),
+			writeOps:   meter.Int64Counter(MetricWriteOps, "{op}", "Transactional Write Ops consumed"),
+			retries:    meter.Int64Counter(MetricRetries, "{request}", "Requests retried by the client"),
+			throttled:  meter.Int64Counter(MetricThrottled, "{request}", "Requests throttled by Fauna"),
+			events:     meter.Int64Counter(MetricStreamEvents, "{event}", "Events received by streams"),
+			reconnects: meter.Int64Counter(MetricStreamReconnects, "{reconnect}", "Stream reconnections"),
+			lag:        meter.Float64Histogram(MetricStreamLag, "s", "Time streams are behind the database"),
+		}
+	}
+}
+
+var systemAttribute = Attribute{Key: "db.system", Value: "fauna"}
+
+type clientMetrics struct {
+	duration   Float64Histogram
+	computeOps Int64Counter
+	readOps    Int64Counter
+	writeOps   Int64Counter
+	retries    Int64Counter
+	throttled  Int64Counter
+	events     Int64Counter
+	reconnects Int64Counter
+	lag        Float64Histogram
+}
+
+// query records a request to Fauna for the query named name, if it has one,
+// which took elapsed, and either succeeded with res or failed with err.
+func (m *clientMetrics) query(ctx context.Context, name string, elapsed time.Duration, res *queryResponse, err error) {
+	if m == nil {
+		return
+	}
+
+	attrs := []Attribute{systemAttribute}
+	if name != "" {
+		attrs = append(attrs, Attribute{Key: "fauna.query.name", Value: name})
+	}
+	if err != nil {
+		attrs = append(attrs, Attribute{Key: "error.type", Value: errorType(err)})
+	}
+
+	m.duration.Record(ctx, elapsed.Seconds(), attrs...)
+
+	var stats *Stats
+	if res != nil {
+		stats = res.Stats
+	} else if info := errorInfo(err); info != nil {
+		stats = info.Stats
+	}
+
+	if stats != nil {
+		m.computeOps.Add(ctx, int64(stats.ComputeOps), attrs...)
+		m.readOps.Add(ctx, int64(stats.ReadOps), attrs...)
+		m.writeOps.Add(ctx, int64(stats.WriteOps), attrs...)
+	}
+}
+
+func (m *clientMetrics) retried(ctx context.Context) {
+	if m != nil {
+		m.retries.Add(ctx, 1, systemAttribute)
+	}
+}
+
+func (m *clientMetrics) throttle(ctx context.Context) {
+	if m != nil {
+		m.throttled.Add(ctx, 1, systemAttribute)
+	}
+}
+
+func streamAttributes(name string) []Attribute {
+	if name == "" {
+		return []Attribute{systemAttribute}
+	}
+
+	return []Attribute{systemAttribute, {Key: "fauna.stream", Value: name}}
+}
+
+// streamMessage records a message received by the stream named name, lagging
+// behind by lag, counting it if it's an event.
+func (m *clientMetrics) streamMessage(ctx context.Context, name string, event bool, lag time.Duration) {
+	if m == nil {
+		return
+	}
+
+	attrs := streamAttributes(name)
+	if event {
+		m.events.Add(ctx, 1, attrs...)
+	}
+	m.lag.Record(ctx, lag.Seconds(), attrs...)
+}
+
+func (m *clientMetrics) streamReconnect(ctx context.Context, name string) {
+	if m != nil {
+		m.reconnects.Add(ctx, 1, streamAttributes(name)...)
+	}
+}
+
+// errorInfo returns the [fauna.QueryInfo] of a Fauna error, or nil.
+func errorInfo(err error) *QueryInfo {
+	var fe interface{ base() *ErrFauna }
+	if errors.As(err, &fe) {
+		if base := fe.base(); base != nil {
+			return base.QueryInfo
+		}
+	}
+
+	return nil
+}
+
+// errorType describes err for the `error.type` attribute.
+func errorType(err error) string {
+	var fe interface{ base() *ErrFauna }
+	if errors.As(err, &fe) {
+		if base := fe.base(); base != nil && base.Code != "" {
+			return base.Code
+		}
+		return "fauna"
+	}
+
+	var netErr *ErrNetwork
+	if errors.As(err, &netErr) {
+		return "network"
+	}
+
+	return "client"
+}
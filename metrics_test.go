@@ -0,0 +1,129 @@
+package fauna
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testMeter records measurements by instrument name.
+type testMeter struct {
+	mu           sync.Mutex
+	units        map[string]string
+	measurements map[string][]testMeasurement
+}
+
+type testMeasurement struct {
+	value float64
+	attrs []Attribute
+}
+
+type testInstrument struct {
+	meter *testMeter
+	name  string
+}
+
+func (i testInstrument) Add(ctx context.Context, incr int64, attrs ...Attribute) {
+	i.Record(ctx, float64(incr), attrs...)
+}
+
+func (i testInstrument) Record(_ context.Context, value float64, attrs ...Attribute) {
+	i.meter.mu.Lock()
+	defer i.meter.mu.Unlock()
+
+	i.meter.measurements[i.name] = append(i.meter.measurements[i.name], testMeasurement{value, attrs})
+}
+
+func (m *testMeter) Int64Counter(name, unit, _ string) Int64Counter {
+	m.units[name] = unit
+	return testInstrument{m, name}
+}
+
+func (m *testMeter) Float64Histogram(name, unit, _ string) Float64Histogram {
+	m.units[name] = unit
+	return testInstrument{m, name}
+}
+
+func (m *testMeter) sum(name string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sum := 0.0
+	for _, measurement := range m.measurements[name] {
+		sum += measurement.value
+	}
+	return sum
+}
+
+func (m *testMeter) last(name string) testMeasurement {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	measurements := m.measurements[name]
+	return measurements[len(measurements)-1]
+}
+
+func newTestMeter() *testMeter {
+	return &testMeter{units: map[string]string{}, measurements: map[string][]testMeasurement{}}
+}
+
+func TestWithMeter(t *testing.T) {
+	var throttles int32
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		switch req.Body["query"].(map[string]any)["fql"].([]any)[0] {
+		case "throttled":
+			if atomic.AddInt32(&throttles, 1) == 1 {
+				return http.StatusTooManyRequests, errorBody("limit_exceeded", "too many requests")
+			}
+		case "abort":
+			return http.StatusBadRequest, `{"error":{"code":"abort","message":"oops","abort":null},"summary":"","txn_ts":1680000000000000,"stats":{"compute_ops":1}}`
+		}
+		return http.StatusOK, `{"data":null,"summary":"","txn_ts":1680000000000000,"stats":{"compute_ops":1,"read_ops":2,"write_ops":3}}`
+	})
+
+	meter := newTestMeter()
+	client := srv.client(WithMeter(meter))
+	query := func(fql string) error {
+		q, _ := FQL(fql, nil)
+		_, err := client.Query(q)
+		return err
+	}
+
+	assert.Equal(t, "s", meter.units[MetricQueryDuration])
+
+	assert.NoError(t, query("ok"))
+	assert.Equal(t, []Attribute{{"db.system", "fauna"}}, meter.last(MetricQueryDuration).attrs)
+	assert.Equal(t, 1.0, meter.sum(MetricComputeOps))
+	assert.Equal(t, 2.0, meter.sum(MetricReadOps))
+	assert.Equal(t, 3.0, meter.sum(MetricWriteOps))
+
+	assert.Error(t, query("abort"))
+	assert.Equal(t, []Attribute{{"db.system", "fauna"}, {"error.type", "abort"}}, meter.last(MetricQueryDuration).attrs)
+	assert.Equal(t, 2.0, meter.sum(MetricComputeOps), "failed queries still consume ops")
+
+	assert.NoError(t, query("throttled"))
+	assert.Equal(t, 1.0, meter.sum(MetricThrottled))
+	assert.Equal(t, 1.0, meter.sum(MetricRetries))
+	assert.Len(t, meter.measurements[MetricQueryDuration], 3, "retries are part of the request's duration")
+
+	t.Run("prepared queries are named", func(t *testing.T) {
+		assert.NoError(t, client.Prepare("ok", "ok"))
+		_, err := client.Execute("ok", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []Attribute{{"db.system", "fauna"}, {"fauna.query.name", "ok"}}, meter.last(MetricQueryDuration).attrs)
+	})
+
+	t.Run("network errors", func(t *testing.T) {
+		meter := newTestMeter()
+		client := NewClient("secret", DefaultTimeouts(), URL("http://127.0.0.1:1"), WithMeter(meter))
+
+		q, _ := FQL(`null`, nil)
+		_, err := client.Query(q)
+		assert.Error(t, err)
+		assert.Equal(t, []Attribute{{"db.system", "fauna"}, {"error.type", "network"}}, meter.last(MetricQueryDuration).attrs)
+	})
+}
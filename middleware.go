@@ -0,0 +1,127 @@
+package fauna
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RequestScopeFn configuration options for [Client.Middleware]
+type RequestScopeFn func(*requestScope)
+
+type requestScope struct {
+	secret func(r *http.Request) (string, error)
+	tags   func(r *http.Request) map[string]string
+}
+
+// ScopeSecret authenticates each request's queries with the secret fn returns,
+// such as a tenant's key taken from the request's credentials. An empty secret
+// uses the [fauna.Client]'s secret, and an error fails the request with
+// 401 Unauthorized. See [fauna.BearerToken].
+func ScopeSecret(fn func(r *http.Request) (string, error)) RequestScopeFn {
+	return func(s *requestScope) { s.secret = fn }
+}
+
+// ScopeTags adds the tags fn returns, such as the request's route, to each
+// request's queries, alongside any set with [fauna.QueryTags].
+func ScopeTags(fn func(r *http.Request) map[string]string) RequestScopeFn {
+	return func(s *requestScope) { s.tags = fn }
+}
+
+// BearerToken returns the token of the request's `Authorization: Bearer`
+// header, for use with [fauna.ScopeSecret].
+func BearerToken(r *http.Request) (string, error) {
+	auth := r.Header.Get(headerAuthorization)
+	if token := strings.TrimPrefix(auth, "Bearer "); token != auth && token != "" {
+		return token, nil
+	}
+
+	return "", fmt.Errorf("missing bearer token")
+}
+
+type clientKey struct{}
+
+// ContextWithClient returns a copy of ctx carrying client, to be retrieved
+// with [fauna.ClientFromContext].
+func ContextWithClient(ctx context.Context, client *Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the [fauna.Client] carried by ctx, such as the
+// request-scoped client added by [Client.Middleware].
+func ClientFromContext(ctx context.Context) (*Client, bool) {
+	client, ok := ctx.Value(clientKey{}).(*Client)
+	return client, ok
+}
+
+// Middleware returns net/http middleware which derives a [fauna.Client] for
+// each request and adds it to the request's context, for handlers to retrieve
+// with [fauna.ClientFromContext]. The derived client shares the transport,
+// caches and limits of the [fauna.Client], and its queries:
+//
+//   - propagate the request's `traceparent` header, if any, unless the
+//     query's context carries another [fauna.SpanContext]
+//   - are authenticated and tagged as configured with [fauna.ScopeSecret] and
+//     [fauna.ScopeTags]
+//
+// Handlers should pass the request's context to each query with
+// [fauna.QueryContext], so queries are canceled with the request.
+//
+// The middleware can be used directly with routers such as chi, and with echo
+// by wrapping it with echo.WrapMiddleware.
+func (c *Client) Middleware(opts ...RequestScopeFn) func(http.Handler) http.Handler {
+	var scope requestScope
+	for _, optFn := range opts {
+		optFn(&scope)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			scoped := c.derive()
+			if sc, err := ParseTraceparent(r.Header.Get(HeaderTraceparent)); err == nil {
+				ctx = ContextWithSpanContext(ctx, sc)
+				scoped.spanContext = sc
+			}
+
+			if scope.secret != nil {
+				secret, err := scope.secret(r)
+				if err != nil {
+					http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+					return
+				}
+				if secret != "" {
+					scoped.scopedSecret = secret
+				}
+			}
+
+			if scope.tags != nil {
+				if tags := scope.tags(r); len(tags) > 0 {
+					scoped.headers[HeaderTags] = mergeTags(scoped.headers[HeaderTags], tags)
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(ContextWithClient(ctx, scoped)))
+		})
+	}
+}
+
+// derive copies the [fauna.Client], sharing its transport, transaction time,
+// caches and limits, with headers and error hooks that can be changed
+// independently.
+func (c *Client) derive() *Client {
+	derived := *c
+	// configuration reloaded into the client after it's derived doesn't
+	// change the derived client, or the other way round
+	derived.endpoint = newEndpoint(*c.endpoint.Load())
+	derived.headers = make(map[string]string, len(c.headers))
+	for k, v := range c.headers {
+		derived.headers[k] = v
+	}
+	// hooks added to the derived client mustn't be appended into the
+	// client's backing array
+	derived.errorHooks = c.errorHooks[:len(c.errorHooks):len(c.errorHooks)]
+
+	return &derived
+}
@@ -0,0 +1,86 @@
+package fauna
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, successBody(`null`)
+	})
+	client := srv.client(QueryTags(map[string]string{"service": "api"}))
+
+	handler := client.Middleware(
+		ScopeSecret(BearerToken),
+		ScopeTags(func(r *http.Request) map[string]string {
+			return map[string]string{"route": r.URL.Path}
+		}),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scoped, ok := ClientFromContext(r.Context())
+		if !assert.True(t, ok) {
+			return
+		}
+
+		q, _ := FQL(`null`, nil)
+		if _, err := scoped.Query(q, QueryContext(r.Context())); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/dogs", nil)
+	req.Header.Set("Authorization", "Bearer tenant-secret")
+	req.Header.Set("Traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	received := srv.received()
+	if assert.Len(t, received, 1) {
+		header := received[0].Header
+		assert.Equal(t, "Bearer tenant-secret", header.Get(headerAuthorization))
+		assert.Equal(t, "route=%2Fdogs,service=api", header.Get(HeaderTags))
+		assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", header.Get(HeaderTraceparent))
+	}
+
+	q, _ := FQL(`null`, nil)
+	_, err := client.Query(q)
+	assert.NoError(t, err)
+	received = srv.received()
+	header := received[len(received)-1].Header
+	assert.Equal(t, "Bearer secret", header.Get(headerAuthorization), "the client is unchanged")
+	assert.Equal(t, "service=api", header.Get(HeaderTags))
+
+	t.Run("unauthorized", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/dogs", nil))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+	t.Run("queries without the request's context", func(t *testing.T) {
+		handler := client.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scoped, _ := ClientFromContext(r.Context())
+
+			q, _ := FQL(`null`, nil)
+			_, _ = scoped.Query(q)
+
+			other := SpanContext{TraceID: [16]byte{1}, SpanID: [8]byte{2}, Sampled: true}
+			_, _ = scoped.Query(q, QueryContext(ContextWithSpanContext(context.Background(), other)))
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/dogs", nil)
+		req.Header.Set("Traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		received := srv.received()
+		if assert.GreaterOrEqual(t, len(received), 2) {
+			last := received[len(received)-2:]
+			assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", last[0].Header.Get(HeaderTraceparent))
+			assert.Equal(t, "00-01000000000000000000000000000000-0200000000000000-01", last[1].Header.Get(HeaderTraceparent))
+		}
+	})
+}
@@ -0,0 +1,379 @@
+// Package migrate applies ordered FQL migrations to a Fauna database,
+// recording each applied migration in a ledger collection so deploys can
+// evolve schema and data repeatably.
+//
+// Migrations are read from pairs of files named by version and description:
+//
+//	migrations/0001_create_users.up.fql
+//	migrations/0001_create_users.down.fql
+//	migrations/0002_backfill_emails.up.fql
+//
+// Each file is plain FQL with no `${}` arguments. A migration and its ledger
+// entry are written in the same transaction, so a failed migration is never
+// recorded as applied. Runs hold a lock in the ledger collection so that
+// concurrent deploys can't apply the same migrations twice.
+package migrate
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fauna/fauna-go"
+)
+
+const (
+	// LedgerDefault is the collection migrations are recorded in unless
+	// configured with [migrate.Ledger].
+	LedgerDefault = "Migrations"
+
+	// LockTTLDefault is how long a run's lock is held unless configured with
+	// [migrate.LockTTL].
+	LockTTLDefault = 15 * time.Minute
+
+	upExt   = ".up.fql"
+	downExt = ".down.fql"
+)
+
+// ErrLocked is returned when another run holds the migration lock.
+var ErrLocked = errors.New("migrations are locked by another run")
+
+// Migration is a single versioned change to a database.
+type Migration struct {
+	Version int64
+	Name    string
+
+	// Up is the FQL that applies the migration.
+	Up string
+
+	// Down is the FQL that reverts the migration, or empty if it can't be
+	// reverted.
+	Down string
+}
+
+// Status describes whether a [migrate.Migration] has been applied.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Load reads every migration beneath dir in fsys, ordered by version. A
+// version with a `.down.fql` file but no `.up.fql` file, a version used by
+// more than one migration, or a file that isn't valid FQL template text is an
+// error.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	byVersion := map[int64]*Migration{}
+
+	walkErr := fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		base := path.Base(p)
+		isUp, isDown := strings.HasSuffix(base, upExt), strings.HasSuffix(base, downExt)
+		if d.IsDir() || (!isUp && !isDown) {
+			return nil
+		}
+
+		stem := strings.TrimSuffix(strings.TrimSuffix(base, upExt), downExt)
+		rawVersion, name, _ := strings.Cut(stem, "_")
+		version, parseErr := strconv.ParseInt(rawVersion, 10, 64)
+		if parseErr != nil {
+			return fmt.Errorf("migration %s must start with a numeric version", p)
+		}
+
+		bin, readErr := fs.ReadFile(fsys, p)
+		if readErr != nil {
+			return readErr
+		}
+
+		text := string(bin)
+		if _, fqlErr := fauna.FQL(text, nil); fqlErr != nil {
+			return fmt.Errorf("failed to parse %s: %w", p, fqlErr)
+		}
+
+		m, found := byVersion[version]
+		if !found {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return fmt.Errorf("migration version %d is used by both %s and %s", version, m.Name, name)
+		}
+
+		if isUp {
+			m.Up = text
+		} else {
+			m.Down = text
+		}
+
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", walkErr)
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no %s file", m.Version, m.Name, upExt)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// RunnerConfigFn configuration options for the [migrate.Runner]
+type RunnerConfigFn func(r *Runner)
+
+// Ledger sets the collection the [migrate.Runner] records applied migrations
+// and its lock in.
+func Ledger(name string) RunnerConfigFn {
+	return func(r *Runner) { r.ledger = &fauna.Module{Name: name} }
+}
+
+// LockTTL sets how long the [migrate.Runner] lock is held before another run
+// may take it over, which bounds how long a crashed run blocks deploys.
+func LockTTL(ttl time.Duration) RunnerConfigFn {
+	return func(r *Runner) { r.lockTTL = ttl }
+}
+
+// Runner applies and reverts migrations.
+type Runner struct {
+	client     *fauna.Client
+	migrations []Migration
+	ledger     *fauna.Module
+	lockTTL    time.Duration
+}
+
+// New creates a [migrate.Runner] for migrations, typically returned by
+// [migrate.Load].
+func New(client *fauna.Client, migrations []Migration, configFns ...RunnerConfigFn) *Runner {
+	r := &Runner{
+		client:     client,
+		migrations: migrations,
+		ledger:     &fauna.Module{Name: LedgerDefault},
+		lockTTL:    LockTTLDefault,
+	}
+
+	for _, configFn := range configFns {
+		configFn(r)
+	}
+
+	return r
+}
+
+// Status reports whether each migration has been applied.
+func (r *Runner) Status() ([]Status, error) {
+	if err := r.ensureLedger(); err != nil {
+		return nil, err
+	}
+
+	applied, err := r.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(r.migrations))
+	for _, m := range r.migrations {
+		entry, isApplied := applied[m.Version]
+		statuses = append(statuses, Status{Migration: m, Applied: isApplied, AppliedAt: entry.AppliedAt})
+	}
+
+	return statuses, nil
+}
+
+// Up applies every pending migration in version order, returning those that
+// were applied. It stops at the first migration that fails.
+func (r *Runner) Up() ([]Migration, error) {
+	ran := make([]Migration, 0)
+
+	err := r.withLock(func(owner string) error {
+		applied, err := r.applied()
+		if err != nil {
+			return err
+		}
+
+		for _, m := range r.migrations {
+			if _, isApplied := applied[m.Version]; isApplied {
+				continue
+			}
+
+			if err := r.run(owner, m.Up, m,
+				"if (${ledger}.where(.version == ${version}).nonEmpty()) abort(\"migration already applied\")\n",
+				"\n${ledger}.create({ version: ${version}, name: ${name}, applied_at: Time.now() })\nnull",
+			); err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", m.Version, m.Name, err)
+			}
+
+			ran = append(ran, m)
+		}
+
+		return nil
+	})
+
+	return ran, err
+}
+
+// Down reverts the most recently applied steps migrations, newest first,
+// returning those that were reverted. It stops at the first migration that
+// fails or has no down migration.
+func (r *Runner) Down(steps int) ([]Migration, error) {
+	reverted := make([]Migration, 0, steps)
+
+	err := r.withLock(func(owner string) error {
+		applied, err := r.applied()
+		if err != nil {
+			return err
+		}
+
+		for i := len(r.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			m := r.migrations[i]
+			if _, isApplied := applied[m.Version]; !isApplied {
+				continue
+			}
+
+			if m.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down migration", m.Version, m.Name)
+			}
+
+			if err := r.run(owner, m.Down, m,
+				"",
+				"\n${ledger}.where(.version == ${version}).forEach(.delete())\nnull",
+			); err != nil {
+				return fmt.Errorf("failed to revert migration %d_%s: %w", m.Version, m.Name, err)
+			}
+
+			reverted = append(reverted, m)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+type ledgerEntry struct {
+	Version   int64     `fauna:"version"`
+	Name      string    `fauna:"name"`
+	AppliedAt time.Time `fauna:"applied_at"`
+}
+
+func (r *Runner) applied() (map[int64]ledgerEntry, error) {
+	q, err := fauna.FQL(
+		"${ledger}.where(.version != null).map(m => { version: m.version, name: m.name, applied_at: m.applied_at })",
+		map[string]any{"ledger": r.ledger},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := map[int64]ledgerEntry{}
+	for it := r.client.Paginate(q); it.HasNext(); {
+		page, pageErr := it.Next()
+		if pageErr != nil {
+			return nil, pageErr
+		}
+
+		var entries []ledgerEntry
+		if err := page.Unmarshal(&entries); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal ledger: %w", err)
+		}
+
+		for _, entry := range entries {
+			applied[entry.Version] = entry
+		}
+	}
+
+	return applied, nil
+}
+
+// run executes body in a single transaction that first checks the lock is
+// still held by owner.
+func (r *Runner) run(owner string, body string, m Migration, before string, after string) error {
+	q, err := fauna.FQL(
+		"if (${ledger}.where(.lock == true && .owner == ${owner}).isEmpty()) abort(\"migration lock lost\")\n"+
+			before+body+after,
+		map[string]any{"ledger": r.ledger, "owner": owner, "version": m.Version, "name": m.Name},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Query(q)
+	return err
+}
+
+func (r *Runner) ensureLedger() error {
+	q, err := fauna.FQL(
+		"if (Collection.byName(${name}) == null) Collection.create({ name: ${name} })\nnull",
+		map[string]any{"name": r.ledger.Name},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Query(q)
+	return err
+}
+
+func (r *Runner) withLock(fn func(owner string) error) error {
+	if err := r.ensureLedger(); err != nil {
+		return err
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return fmt.Errorf("failed to generate lock owner: %w", err)
+	}
+	owner := hex.EncodeToString(token)
+
+	acquire, err := fauna.FQL(
+		"let lock = ${ledger}.where(.lock == true).first()\n"+
+			"if (lock != null && lock!.expires_at > Time.now()) {\n"+
+			"  false\n"+
+			"} else {\n"+
+			"  if (lock != null) lock!.delete()\n"+
+			"  ${ledger}.create({ lock: true, owner: ${owner}, expires_at: Time.now().add(${ttl}, \"milliseconds\") })\n"+
+			"  true\n"+
+			"}",
+		map[string]any{"ledger": r.ledger, "owner": owner, "ttl": r.lockTTL.Milliseconds()},
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.client.Query(acquire)
+	if err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	if acquired, _ := res.Data.(bool); !acquired {
+		return ErrLocked
+	}
+
+	fnErr := fn(owner)
+
+	release, err := fauna.FQL(
+		"${ledger}.where(.lock == true && .owner == ${owner}).forEach(.delete())\nnull",
+		map[string]any{"ledger": r.ledger, "owner": owner},
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, releaseErr := r.client.Query(release); releaseErr != nil && fnErr == nil {
+		return fmt.Errorf("failed to release migration lock: %w", releaseErr)
+	}
+
+	return fnErr
+}
@@ -0,0 +1,191 @@
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/fauna/fauna-go"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMigrations = fstest.MapFS{
+	"migrations/0001_create_users.up.fql":    {Data: []byte(`Collection.create({ name: "Users" })`)},
+	"migrations/0001_create_users.down.fql":  {Data: []byte(`Collection.byName("Users")!.delete()`)},
+	"migrations/0002_add_admin.up.fql":       {Data: []byte(`Users.create({ name: "admin" })`)},
+	"migrations/0002_add_admin.down.fql":     {Data: []byte(`Users.where(.name == "admin").forEach(.delete())`)},
+	"migrations/0010_index_names.up.fql":     {Data: []byte(`Collection.byName("Users")!.update({ indexes: { byName: { terms: [{ field: ".name" }] } } })`)},
+	"migrations/README.md":                   {Data: []byte("ignored")},
+	"other/0001_not_a_migration.up.fql":      {Data: []byte("ignored")},
+	"migrations/nested/0003_nested.up.fql":   {Data: []byte(`Users.all().count()`)},
+	"migrations/nested/0003_nested.down.fql": {Data: []byte(`null`)},
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load(testMigrations, "migrations")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var names []string
+	for _, m := range migrations {
+		names = append(names, fmt.Sprintf("%d_%s", m.Version, m.Name))
+	}
+	assert.Equal(t, []string{"1_create_users", "2_add_admin", "3_nested", "10_index_names"}, names)
+	assert.Equal(t, `Collection.create({ name: "Users" })`, migrations[0].Up)
+	assert.Empty(t, migrations[3].Down)
+
+	t.Run("errors", func(t *testing.T) {
+		for name, fsys := range map[string]fstest.MapFS{
+			"numeric version": {"m/first.up.fql": {Data: []byte("null")}},
+			"no .up.fql file": {"m/0001_a.down.fql": {Data: []byte("null")}},
+			"used by both":    {"m/0001_a.up.fql": {Data: []byte("null")}, "m/0001_b.up.fql": {Data: []byte("null")}},
+			"failed to parse": {"m/0001_a.up.fql": {Data: []byte("${oops")}},
+		} {
+			_, err := Load(fsys, "m")
+			assert.ErrorContains(t, err, name)
+		}
+	})
+}
+
+// fakeLedger answers the runner's queries from an in-memory ledger.
+type fakeLedger struct {
+	mu      sync.Mutex
+	locked  bool
+	applied []int64
+	ran     []string
+}
+
+func (l *fakeLedger) serve(t *testing.T) *fauna.Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bin, _ := io.ReadAll(r.Body)
+
+		var body struct {
+			Query struct {
+				FQL []json.RawMessage `json:"fql"`
+			} `json:"query"`
+		}
+		_ = json.Unmarshal(bin, &body)
+
+		var text strings.Builder
+		var version int64
+		for _, frag := range body.Query.FQL {
+			var lit string
+			if json.Unmarshal(frag, &lit) == nil {
+				text.WriteString(lit)
+				continue
+			}
+
+			var value struct {
+				Value struct {
+					Int string `json:"@int"`
+				} `json:"value"`
+			}
+			if json.Unmarshal(frag, &value) == nil && value.Value.Int != "" {
+				_, _ = fmt.Sscan(value.Value.Int, &version)
+			}
+			text.WriteString("$")
+		}
+
+		l.mu.Lock()
+		data := l.answer(text.String(), version)
+		l.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json;charset=utf-8")
+		_, _ = io.WriteString(w, `{"data":`+data+`,"summary":"","txn_ts":1680000000000000,"stats":{}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	return fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(srv.URL))
+}
+
+func (l *fakeLedger) answer(fql string, version int64) string {
+	switch {
+	case strings.HasPrefix(fql, "if (Collection.byName"):
+		return "null"
+	case strings.HasPrefix(fql, "let lock"):
+		if l.locked {
+			return "false"
+		}
+		l.locked = true
+		return "true"
+	case strings.HasPrefix(fql, "$.where(.version != null)"):
+		entries := make([]string, 0, len(l.applied))
+		for _, v := range l.applied {
+			entries = append(entries, fmt.Sprintf(`{"version":{"@int":"%d"},"name":"m","applied_at":{"@time":"2023-04-01T00:00:00Z"}}`, v))
+		}
+		return `{"@set":{"data":[` + strings.Join(entries, ",") + `]}}`
+	case strings.HasPrefix(fql, "$.where(.lock == true && .owner == $).forEach"):
+		l.locked = false
+		return "null"
+	}
+
+	lines := strings.Split(fql, "\n")
+	if strings.Contains(fql, ".create({ version:") {
+		l.ran = append(l.ran, lines[2])
+		l.applied = append(l.applied, version)
+	} else {
+		l.ran = append(l.ran, lines[1])
+		for i, v := range l.applied {
+			if v == version {
+				l.applied = append(l.applied[:i], l.applied[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return "null"
+}
+
+func TestRunner(t *testing.T) {
+	migrations, err := Load(testMigrations, "migrations")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ledger := &fakeLedger{applied: []int64{1}}
+	runner := New(ledger.serve(t), migrations)
+
+	ran, err := runner.Up()
+	if assert.NoError(t, err) {
+		assert.Len(t, ran, 3)
+	}
+	assert.Equal(t, []int64{1, 2, 3, 10}, ledger.applied)
+	assert.Equal(t, []string{migrations[1].Up, migrations[2].Up, migrations[3].Up}, ledger.ran)
+	assert.False(t, ledger.locked, "lock should be released")
+
+	statuses, err := runner.Status()
+	if assert.NoError(t, err) {
+		for _, status := range statuses {
+			assert.True(t, status.Applied)
+		}
+	}
+
+	t.Run("down", func(t *testing.T) {
+		_, err := runner.Down(1)
+		assert.ErrorContains(t, err, "has no down migration")
+		assert.False(t, ledger.locked)
+
+		ledger.applied = []int64{1, 2, 3}
+		ledger.ran = nil
+		reverted, err := runner.Down(2)
+		if assert.NoError(t, err) {
+			assert.Len(t, reverted, 2)
+		}
+		assert.Equal(t, []int64{1}, ledger.applied)
+		assert.Equal(t, []string{migrations[2].Down, migrations[1].Down}, ledger.ran)
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		ledger.locked = true
+		_, err := runner.Up()
+		assert.True(t, errors.Is(err, ErrLocked))
+	})
+}
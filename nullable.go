@@ -0,0 +1,174 @@
+package fauna
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Nullable is a struct field which can be absent, null, or have a value, for
+// where a null field means something other than a missing one, such as in a
+// patch, where null removes a field and a missing field is left unchanged:
+//
+//	type DogPatch struct {
+//		Name  fauna.Nullable[string]     `fauna:"name"`
+//		Owner fauna.Nullable[*fauna.Ref] `fauna:"owner"`
+//	}
+//
+//	patch := DogPatch{Owner: fauna.Null[*fauna.Ref]()} // {"owner": null}
+//
+// A Nullable which isn't Set is never encoded, like a field tagged
+// `omitempty`. Decoding sets Set for fields which are present, and Null for
+// those which are null.
+//
+// Other fields follow simpler rules. Unless they're tagged `omitempty`, nil
+// pointers and interfaces are encoded as null, and nil maps and slices as
+// empty ones. Pointers to pointers are encoded as the value they point to. A
+// null decodes into the zero value of the field, and a missing field is left
+// as it is.
+type Nullable[T any] struct {
+	// Value is the field's value, if it's Set and not Null.
+	Value T
+
+	// Null is true if the field is null.
+	Null bool
+
+	// Set is true if the field is present, either null or with a value.
+	Set bool
+}
+
+// Some is a [fauna.Nullable] with the value v.
+func Some[T any](v T) Nullable[T] {
+	return Nullable[T]{Value: v, Set: true}
+}
+
+// Null is a [fauna.Nullable] which is null.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{Null: true, Set: true}
+}
+
+// Get returns the value, and whether it has one rather than being null or
+// absent.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.Value, n.Set && !n.Null
+}
+
+func (n Nullable[T]) nullable() (value any, set bool, null bool) {
+	return n.Value, n.Set, n.Null
+}
+
+func (n *Nullable[T]) decodeNullable(data any, c codec) error {
+	if data == explicitNull {
+		n.reset(true)
+		return nil
+	}
+
+	n.reset(false)
+	return c.decodeInto(data, &n.Value)
+}
+
+// reset sets n to present, and null or not, returning its value to decode
+// into.
+func (n *Nullable[T]) reset(null bool) reflect.Value {
+	*n = Nullable[T]{Set: true, Null: null}
+	return reflect.ValueOf(&n.Value).Elem()
+}
+
+type nullable interface {
+	nullable() (value any, set bool, null bool)
+}
+
+type nullableDecoder interface {
+	decodeNullable(data any, c codec) error
+	reset(null bool) reflect.Value
+}
+
+var nullableDecoderType = reflect.TypeOf((*nullableDecoder)(nil)).Elem()
+
+func isNullable(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(nullableDecoderType)
+}
+
+// explicitNull stands in for the nulls of [fauna.Nullable] values while
+// they're decoded, as the generic decoder skips nulls before its hooks.
+var explicitNull = &struct{ null bool }{}
+
+// markNulls is a decode hook which marks the nulls decoded into
+// [fauna.Nullable] struct fields, map values, and slice elements.
+func (c codec) markNulls(_ reflect.Type, t reflect.Type, data any) (any, error) {
+	switch t.Kind() {
+	case reflect.Struct:
+		fields, isMap := data.(map[string]any)
+		if !isMap || isNullable(t) {
+			return data, nil
+		}
+
+		info := c.structInfoFor(t)
+		return markMapNulls(fields, func(key string) bool {
+			index, found := info.fields[key]
+			if !found {
+				index, found = info.folded[strings.ToLower(key)]
+			}
+			return found && isNullable(t.FieldByIndex(index).Type)
+		}), nil
+
+	case reflect.Map:
+		fields, isMap := data.(map[string]any)
+		if !isMap || !isNullable(t.Elem()) {
+			return data, nil
+		}
+		return markMapNulls(fields, func(string) bool { return true }), nil
+
+	case reflect.Slice, reflect.Array:
+		elems, isSlice := data.([]any)
+		if !isSlice || !isNullable(t.Elem()) {
+			return data, nil
+		}
+
+		out := make([]any, len(elems))
+		for i, elem := range elems {
+			if elem == nil {
+				elem = explicitNull
+			}
+			out[i] = elem
+		}
+		return out, nil
+	}
+
+	return data, nil
+}
+
+func markMapNulls(fields map[string]any, marked func(key string) bool) map[string]any {
+	var out map[string]any
+	for key, value := range fields {
+		if value != nil || !marked(key) {
+			continue
+		}
+
+		if out == nil {
+			out = make(map[string]any, len(fields))
+			for k, v := range fields {
+				out[k] = v
+			}
+		}
+		out[key] = explicitNull
+	}
+
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// decodeNullable is a decode hook which decodes [fauna.Nullable] values.
+func (c codec) decodeNullable(_ reflect.Type, t reflect.Type, data any) (any, error) {
+	if !isNullable(t) {
+		return data, nil
+	}
+
+	n := reflect.New(t)
+	if err := n.Interface().(nullableDecoder).decodeNullable(data, c); err != nil {
+		return nil, err
+	}
+
+	return n.Elem().Interface(), nil
+}
@@ -0,0 +1,73 @@
+package fauna
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type nullableTestPatch struct {
+	Name    Nullable[string]            `fauna:"name"`
+	Age     Nullable[int]               `fauna:"age"`
+	Owner   Nullable[*Ref]              `fauna:"owner"`
+	Tags    map[string]Nullable[string] `fauna:"tags"`
+	Scores  []Nullable[int]             `fauna:"scores"`
+	Nick    *string                     `fauna:"nick"`
+	Deep    **int                       `fauna:"deep"`
+	Skipped *string                     `fauna:"skipped,omitempty"`
+}
+
+func TestNullable(t *testing.T) {
+	one := 1
+	onePtr := &one
+
+	bin, err := marshal(nullableTestPatch{
+		Name:   Some("Rex"),
+		Owner:  Null[*Ref](),
+		Tags:   map[string]Nullable[string]{"color": Some("brown"), "size": Null[string]()},
+		Scores: []Nullable[int]{Some(1), Null[int]()},
+		Deep:   &onePtr,
+	})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{
+			"name":"Rex","owner":null,
+			"tags":{"color":"brown","size":null},
+			"scores":[{"@int":"1"},null],
+			"nick":null,"deep":{"@int":"1"}
+		}`, string(bin), "unset values are omitted")
+	}
+
+	const body = `{
+		"name":"Rex","owner":null,
+		"tags":{"color":"brown","size":null},
+		"scores":[{"@int":"1"},null],
+		"nick":null,"deep":{"@int":"1"}
+	}`
+
+	for name, decode := range map[string]func([]byte, any) error{"generic": unmarshal, "direct": decodeDirect} {
+		nick := "old"
+		got := nullableTestPatch{Age: Some(3), Nick: &nick}
+		if !assert.NoError(t, decode([]byte(body), &got), name) {
+			continue
+		}
+
+		assert.Equal(t, Some("Rex"), got.Name, name)
+		assert.Equal(t, Some(3), got.Age, "missing fields are left as they are")
+		assert.Equal(t, Null[*Ref](), got.Owner, name)
+		assert.Equal(t, map[string]Nullable[string]{"color": Some("brown"), "size": Null[string]()}, got.Tags, name)
+		assert.Equal(t, []Nullable[int]{Some(1), Null[int]()}, got.Scores, name)
+		assert.Nil(t, got.Nick, "null decodes into the zero value")
+		if assert.NotNil(t, got.Deep, name) && assert.NotNil(t, *got.Deep, name) {
+			assert.Equal(t, 1, **got.Deep, name)
+		}
+
+		value, ok := got.Name.Get()
+		assert.True(t, ok, name)
+		assert.Equal(t, "Rex", value, name)
+		_, ok = got.Owner.Get()
+		assert.False(t, ok, name)
+	}
+
+	assert.Equal(t, map[string]any{"name": "Rex", "owner": nil, "tags": map[string]any{}, "scores": []any{}, "nick": nil, "deep": 1},
+		Redact(nullableTestPatch{Name: Some("Rex"), Owner: Null[*Ref](), Deep: &onePtr}))
+}
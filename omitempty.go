@@ -0,0 +1,26 @@
+package fauna
+
+import "reflect"
+
+// tagOptOmitEmpty leaves a struct field out of the encoded object when it
+// holds its zero value, as encoding/json does, so documents written from
+// partially filled structs don't overwrite stored fields with zeros:
+//
+//	type Product struct {
+//		Name  string `fauna:"name"`
+//		Price *int   `fauna:"price,omitempty"`
+//	}
+//
+// Fields without the option are always encoded.
+const tagOptOmitEmpty = "omitempty"
+
+// omitField reports whether the field v, tagged with the options opts, is
+// left out of the encoded object.
+func omitField(opts []string, v reflect.Value) bool {
+	for _, opt := range opts {
+		if opt == tagOptOmitEmpty {
+			return v.IsZero()
+		}
+	}
+	return false
+}
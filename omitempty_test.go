@@ -0,0 +1,39 @@
+package fauna
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodingOmitEmpty(t *testing.T) {
+	type obj struct {
+		Name    string         `fauna:"name"`
+		Alias   string         `fauna:"alias,omitempty"`
+		Count   *int           `fauna:"count,omitempty"`
+		Born    time.Time      `fauna:"born,date,omitempty"`
+		Tags    []string       `fauna:"tags,omitempty"`
+		Data    map[string]any `fauna:"data,omitempty"`
+		Enabled bool           `fauna:"enabled"`
+	}
+
+	t.Run("omits zero values", func(t *testing.T) {
+		bs := marshalAndCheck(t, obj{Name: "foo"})
+		assert.JSONEq(t, `{"name":"foo","enabled":false}`, string(bs))
+	})
+
+	t.Run("keeps set values and type hints", func(t *testing.T) {
+		zero := 0
+		born := time.Date(2023, 2, 24, 0, 0, 0, 0, time.UTC)
+		roundTripCheck(t, obj{Name: "foo", Alias: "bar", Count: &zero, Born: born, Tags: []string{"a"}, Data: map[string]any{"a": "b"}}, `{
+      "name":"foo",
+      "alias":"bar",
+      "count":{"@int":"0"},
+      "born":{"@date":"2023-02-24"},
+      "tags":["a"],
+      "data":{"a":"b"},
+      "enabled":false
+    }`)
+	})
+}
@@ -0,0 +1,75 @@
+package fauna
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const versionConflictKey = "fauna_version_conflict"
+
+// UpdateIfTs merges patch into the document with the given id only if the
+// document's ts still equals expectedTs, packaging the optimistic locking
+// pattern into a single compare-and-set query. When the document has changed
+// an [fauna.ErrVersionConflict] is returned and nothing is written.
+func (c *Client) UpdateIfTs(coll string, id string, expectedTs time.Time, patch any, opts ...QueryOptFn) (*Document, error) {
+	res, err := c.updateIfTs(&Module{Name: coll}, id, expectedTs, patch, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc, isDoc := res.Data.(*Document); isDoc {
+		return doc, nil
+	}
+
+	return nil, fmt.Errorf("unexpected result updating document %s: %v", id, res.Data)
+}
+
+// UpdateIfTs merges patch into the document with the given id only if the
+// document's ts still equals expectedTs. See [fauna.Client.UpdateIfTs].
+func (c *Collection[T]) UpdateIfTs(id string, expectedTs time.Time, patch any, opts ...QueryOptFn) (*T, error) {
+	res, err := c.client.updateIfTs(c.mod, id, expectedTs, patch, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc T
+	if err := res.Unmarshal(&doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s document: %w", c.mod.Name, err)
+	}
+
+	return &doc, nil
+}
+
+func (c *Client) updateIfTs(coll *Module, id string, expectedTs time.Time, patch any, opts []QueryOptFn) (*QuerySuccess, error) {
+	q, err := FQL(
+		"let doc = ${coll}.byId(${id})\n"+
+			"if (!doc.exists()) doc\n"+
+			"else if (doc.ts != ${ts}) abort({ "+versionConflictKey+": doc.ts })\n"+
+			"else doc.update(${patch})",
+		map[string]any{"coll": coll, "id": id, "ts": expectedTs, "patch": patch},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.Query(q, opts...)
+	if err != nil {
+		var abortErr *ErrAbort
+		if errors.As(err, &abortErr) {
+			if abort, isMap := abortErr.Abort.(map[string]any); isMap {
+				if current, isTime := abort[versionConflictKey].(*time.Time); isTime {
+					return nil, ErrVersionConflict{Ref: &Ref{ID: id, Coll: coll}, ExpectedTS: expectedTs, CurrentTS: *current}
+				}
+			}
+		}
+
+		return nil, err
+	}
+
+	if nullDoc, isNull := res.Data.(*NullDocument); isNull {
+		return nil, ErrDocumentNotFound{nullDoc}
+	}
+
+	return res, nil
+}
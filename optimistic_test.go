@@ -0,0 +1,66 @@
+package fauna
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateIfTs(t *testing.T) {
+	expected := time.Date(2023, 2, 28, 18, 10, 10, 10000, time.UTC)
+
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		var ts any
+		for _, f := range req.Body["query"].(map[string]any)["fql"].([]any) {
+			fragment, _ := f.(map[string]any)
+			if v, isValue := fragment["value"].(map[string]any); isValue {
+				if tv, isTime := v["@time"]; isTime {
+					ts = tv
+				}
+			}
+		}
+
+		if ts == "2023-02-28T18:10:10.00001Z" {
+			return http.StatusOK, successBody(`{"@doc":{"id":"1","coll":{"@mod":"Dinos"},"ts":{"@time":"2023-03-01T00:00:00Z"},"name":"Dino"}}`)
+		}
+
+		return http.StatusBadRequest, `{
+      "error":{"code":"abort","message":"Query aborted.","abort":{"@object":{"fauna_version_conflict":{"@time":"2023-03-01T00:00:00Z"}}}},
+      "summary":"","txn_ts":1680000000000000
+    }`
+	})
+	client := srv.client()
+
+	t.Run("updates when ts matches", func(t *testing.T) {
+		doc, err := client.UpdateIfTs("Dinos", "1", expected, map[string]any{"name": "Dino"})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "1", doc.ID)
+			assert.Equal(t, "Dino", doc.Data["name"])
+		}
+	})
+
+	t.Run("conflicts when ts is stale", func(t *testing.T) {
+		_, err := client.UpdateIfTs("Dinos", "1", expected.Add(-time.Hour), map[string]any{"name": "Dino"})
+
+		var conflict ErrVersionConflict
+		if assert.ErrorAs(t, err, &conflict) {
+			assert.Equal(t, "1", conflict.Ref.ID)
+			assert.Equal(t, expected.Add(-time.Hour), conflict.ExpectedTS)
+			assert.Equal(t, time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), conflict.CurrentTS)
+		}
+	})
+
+	t.Run("collection", func(t *testing.T) {
+		dinos := NewCollection[collectionTestDino](client, "Dinos")
+		dino, err := dinos.UpdateIfTs("1", expected, map[string]any{"name": "Dino"})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Dino", dino.Name)
+		}
+
+		_, err = dinos.UpdateIfTs("1", time.Time{}, map[string]any{"name": "Dino"})
+		var conflict ErrVersionConflict
+		assert.ErrorAs(t, err, &conflict)
+	})
+}
@@ -0,0 +1,136 @@
+package fauna
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PaginateSet continues paginating a set returned within a query's result,
+// such as a set field of a document decoded into a [fauna.Page], from its
+// After cursor:
+//
+//	var dog struct {
+//		Name    string      `fauna:"name"`
+//		Puppies *fauna.Page `fauna:"puppies"`
+//	}
+//
+//	puppies := client.PaginateSet(dog.Puppies)
+//
+// The page itself isn't returned again, so the iterator has no pages if
+// page has no After cursor. A set returned as only a cursor is a page with
+// no data, whose pages all come from the iterator.
+func (c *Client) PaginateSet(page *Page, opts ...QueryOptFn) *QueryIterator {
+	q := &QueryIterator{client: c, opts: opts}
+	if page != nil {
+		// the template is constant, so building the query can't fail
+		_ = q.nextPage(page.After)
+	}
+	return q
+}
+
+// query fetches the iterator's next page, fetching it again with the same
+// cursor while it's throttled, up to the client's PageRetries. Queries which
+// aren't retried, such as with [fauna.QueryNoRetries], aren't retried here
+// either.
+func (q *QueryIterator) query() (*QuerySuccess, error) {
+	for retry := 0; ; retry++ {
+		res, err := q.client.Query(q.fql, q.opts...)
+
+		var exhausted *ErrRetriesExhausted
+		if err == nil || retry >= q.client.pageRetries ||
+			!errors.As(err, &exhausted) || !errors.As(err, new(*ErrThrottling)) {
+			return res, err
+		}
+
+		last := exhausted.Attempts[len(exhausted.Attempts)-1]
+		delay := q.client.retryDelay(len(exhausted.Attempts)+retry, last.RetryAfter)
+
+		ctx := queryContext(q.opts)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// MapPages fetches the iterator's remaining pages, calling fn with the items
+// of each decoded into a []T as it's fetched, such as to write a page of an
+// export in one batch. It stops at the first error, from fetching or decoding
+// a page or returned by fn. The iterator can be resumed after an error
+// fetching a page, as its cursor isn't advanced.
+func MapPages[T any](it PageIterator, fn func(items []T) error) error {
+	for it.HasNext() {
+		page, err := it.Next()
+		if err != nil {
+			return err
+		}
+
+		var items []T
+		if err := page.Unmarshal(&items); err != nil {
+			return fmt.Errorf("failed to decode page: %w", err)
+		}
+
+		if err := fn(items); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MapItems fetches the iterator's remaining pages, calling fn with each item
+// decoded into T as its page is fetched, so a pipeline can transform or
+// write the items of a large set without collecting them:
+//
+//	err := fauna.MapItems(it, func(dog Dog) error {
+//		return enc.Encode(dog)
+//	})
+//
+// Each item is decoded as it's passed to fn, so only one decoded item is held
+// at a time. It stops at the first error, like [fauna.MapPages].
+func MapItems[T any](it PageIterator, fn func(item T) error) error {
+	for it.HasNext() {
+		page, err := it.Next()
+		if err != nil {
+			return err
+		}
+
+		for i, data := range page.Data {
+			var item T
+			if err := page.codec.decodeInto(data, &item); err != nil {
+				return fmt.Errorf("failed to decode item %d of page: %w", i, err)
+			}
+
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// PaginateReverse paginates set, such as a collection or index, in reverse
+// order, for feeds showing the latest first:
+//
+//	posts, _ := fauna.FQL(`Posts.byAuthor(${author})`, map[string]any{"author": author})
+//	it, err := client.PaginateReverse(posts)
+//
+// The set's own order is reversed, so to list the latest first it should be
+// ordered oldest first, such as an index whose values are `ts`. Its pages'
+// After cursors continue the reversed order, and can be resumed with
+// [Client.PaginateSet] like any other.
+func (c *Client) PaginateReverse(set *Query, opts ...QueryOptFn) (*QueryIterator, error) {
+	if set == nil {
+		return nil, fmt.Errorf("set must not be nil")
+	}
+
+	q, err := FQL(`(${set}).reverse()`, map[string]any{"set": set})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Paginate(q, opts...), nil
+}
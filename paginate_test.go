@@ -0,0 +1,251 @@
+package fauna
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginateSet(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		return http.StatusOK, successBody(`{"@set":{"data":["Max","Bella"]}}`)
+	})
+	client := srv.client()
+
+	var dog struct {
+		Name    string `fauna:"name"`
+		Puppies *Page  `fauna:"puppies"`
+		Friends *Page  `fauna:"friends"`
+	}
+	data := `{"@object":{"name":"Scout","puppies":{"@set":{"data":["Rex"],"after":"next"}},"friends":{"@set":"cursor"}}}`
+	if !assert.NoError(t, unmarshal([]byte(data), &dog)) {
+		return
+	}
+
+	t.Run("from a page", func(t *testing.T) {
+		it := client.PaginateSet(dog.Puppies)
+		if !assert.True(t, it.HasNext()) {
+			return
+		}
+
+		page, err := it.Next()
+		if assert.NoError(t, err) {
+			assert.Equal(t, []any{"Max", "Bella"}, page.Data)
+			assert.False(t, it.HasNext())
+		}
+
+		received := srv.received()
+		bs, _ := marshal(received[len(received)-1].Body["query"])
+		assert.Contains(t, string(bs), `Set.paginate(`)
+		assert.Contains(t, string(bs), `"next"`)
+	})
+
+	t.Run("from a cursor", func(t *testing.T) {
+		assert.Empty(t, dog.Friends.Data)
+
+		it := client.PaginateSet(dog.Friends)
+		if assert.True(t, it.HasNext()) {
+			_, err := it.Next()
+			assert.NoError(t, err)
+		}
+
+		received := srv.received()
+		bs, _ := marshal(received[len(received)-1].Body["query"])
+		assert.Contains(t, string(bs), `"cursor"`)
+	})
+
+	t.Run("without a cursor", func(t *testing.T) {
+		assert.False(t, client.PaginateSet(&Page{Data: []any{"a"}}).HasNext())
+		assert.False(t, client.PaginateSet(nil).HasNext())
+	})
+}
+
+func TestThrottledPages(t *testing.T) {
+	var throttle int32
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		atomic.AddInt32(&requests, 1)
+
+		w.Header().Set("Content-Type", "application/json;charset=utf-8")
+		if atomic.AddInt32(&throttle, -1) >= 0 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = io.WriteString(w, errorBody("limit_exceeded", "too many requests"))
+			return
+		}
+		_, _ = io.WriteString(w, successBody(`{"@set":{"data":["a"]}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	newClient := func(configFns ...ClientConfigFn) *Client {
+		return NewClient("secret", DefaultTimeouts(), append([]ClientConfigFn{URL(srv.URL), MaxAttempts(2), MaxBackoff(time.Millisecond)}, configFns...)...)
+	}
+	q, _ := FQL(`Dogs.all()`, nil)
+
+	t.Run("retries the page", func(t *testing.T) {
+		atomic.StoreInt32(&throttle, 5)
+		atomic.StoreInt32(&requests, 0)
+
+		page, err := newClient().Paginate(q).Next()
+		if assert.NoError(t, err) {
+			assert.Equal(t, []any{"a"}, page.Data)
+		}
+		assert.Equal(t, int32(6), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("gives up after page retries", func(t *testing.T) {
+		atomic.StoreInt32(&throttle, 100)
+		atomic.StoreInt32(&requests, 0)
+
+		it := newClient(PageRetries(1)).Paginate(q)
+		_, err := it.Next()
+		assert.ErrorAs(t, err, new(*ErrThrottling))
+		assert.Equal(t, int32(4), atomic.LoadInt32(&requests))
+		assert.True(t, it.HasNext(), "the page can be fetched again")
+
+		var exhausted *ErrRetriesExhausted
+		if assert.ErrorAs(t, err, &exhausted) {
+			assert.Zero(t, exhausted.Attempts[0].RetryAfter)
+		}
+	})
+
+	t.Run("not retried without retries", func(t *testing.T) {
+		atomic.StoreInt32(&throttle, 100)
+		atomic.StoreInt32(&requests, 0)
+
+		_, err := newClient().Paginate(q, QueryNoRetries()).Next()
+		assert.ErrorAs(t, err, new(*ErrThrottling))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		atomic.StoreInt32(&throttle, 100)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := newClient(MaxBackoff(time.Hour)).Paginate(q, QueryContext(ctx)).Next()
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	header := http.Header{}
+	assert.Zero(t, retryAfter(header))
+
+	header.Set("Retry-After", "3")
+	assert.Equal(t, 3*time.Second, retryAfter(header))
+
+	header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.InDelta(t, float64(time.Minute), float64(retryAfter(header)), float64(2*time.Second))
+
+	header.Set("Retry-After", "soon")
+	assert.Zero(t, retryAfter(header))
+
+	client := NewClient("secret", DefaultTimeouts(), MaxBackoff(5*time.Second))
+	assert.Equal(t, 2*time.Second, client.retryDelay(0, 2*time.Second))
+	assert.Equal(t, 5*time.Second, client.retryDelay(0, time.Minute))
+}
+
+func TestMapItems(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		if bs, _ := marshal(req.Body["query"]); strings.Contains(string(bs), "Set.paginate") {
+			return http.StatusOK, successBody(`{"@set":{"data":[{"name":"Rex","age":{"@int":"2"}}]}}`)
+		}
+		return http.StatusOK, successBody(`{"@set":{"data":[{"name":"Scout","age":{"@int":"4"}},{"name":"Max","age":{"@int":"1"}}],"after":"next"}}`)
+	})
+	client := srv.client()
+	q, _ := FQL(`Dogs.all()`, nil)
+
+	type dog struct {
+		Name string `fauna:"name"`
+		Age  int    `fauna:"age"`
+	}
+
+	t.Run("items", func(t *testing.T) {
+		var names []string
+		err := MapItems(client.Paginate(q), func(d dog) error {
+			names = append(names, fmt.Sprintf("%s:%d", d.Name, d.Age))
+			return nil
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"Scout:4", "Max:1", "Rex:2"}, names)
+		}
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		var sizes []int
+		err := MapPages(client.Paginate(q), func(dogs []dog) error {
+			sizes = append(sizes, len(dogs))
+			return nil
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []int{2, 1}, sizes)
+		}
+	})
+
+	t.Run("stops at an error", func(t *testing.T) {
+		stop := errors.New("stop")
+		calls := 0
+		it := client.Paginate(q)
+		err := MapItems(it, func(d dog) error {
+			calls++
+			return stop
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, calls)
+
+		err = MapItems(client.Paginate(q), func(n int) error { return nil })
+		assert.ErrorContains(t, err, "failed to decode item 0")
+
+		err = MapPages(client.Paginate(q), func(n []int) error { return nil })
+		assert.ErrorContains(t, err, "failed to decode page")
+	})
+}
+
+func TestPaginateReverse(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		if bs, _ := marshal(req.Body["query"]); strings.Contains(string(bs), "Set.paginate") {
+			return http.StatusOK, successBody(`{"@set":{"data":[1]}}`)
+		}
+		return http.StatusOK, successBody(`{"@set":{"data":[3,2],"after":"next"}}`)
+	})
+	client := srv.client()
+	posts, _ := FQL(`Posts.byAuthor(${author})`, map[string]any{"author": "anna"})
+
+	it, err := client.PaginateReverse(posts)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var ids []int
+	err = MapItems(it, func(id int) error {
+		ids = append(ids, id)
+		return nil
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []int{3, 2, 1}, ids)
+	}
+
+	received := srv.received()
+	if assert.Len(t, received, 2) {
+		bs, _ := marshal(received[0].Body["query"])
+		assert.Contains(t, string(bs), `Posts.byAuthor(`)
+		assert.Contains(t, string(bs), `).reverse()`)
+
+		bs, _ = marshal(received[1].Body["query"])
+		assert.Contains(t, string(bs), `"next"`)
+	}
+
+	_, err = client.PaginateReverse(nil)
+	assert.Error(t, err)
+}
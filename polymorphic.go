@@ -0,0 +1,128 @@
+package fauna
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// A Discriminator returns the name of the concrete type a polymorphic
+// document, with data fields and metadata meta, decodes into. See
+// [fauna.RegisterInterface].
+type Discriminator func(fields map[string]any, meta Metadata) string
+
+// ByField discriminates documents by the string value of their field name,
+// such as "kind".
+func ByField(name string) Discriminator {
+	return func(fields map[string]any, _ Metadata) string {
+		value, _ := fields[name].(string)
+		return value
+	}
+}
+
+// ByCollection discriminates documents by the name of their collection.
+func ByCollection() Discriminator {
+	return func(_ map[string]any, meta Metadata) string {
+		return meta.Coll
+	}
+}
+
+type polymorphic struct {
+	discriminate Discriminator
+	types        map[string]reflect.Type
+}
+
+var polymorphicTypes sync.Map
+
+// RegisterInterface decodes documents and objects into the interface type of
+// iface, a nil pointer to the interface such as (*Animal)(nil), as the
+// concrete type registered in types under the name discriminate returns for
+// them. The values of types are any value of each concrete type, such as
+// Dog{} or &Dog{}, which must implement the interface:
+//
+//	err := fauna.RegisterInterface((*Animal)(nil), fauna.ByField("kind"), map[string]any{
+//		"dog": Dog{},
+//		"cat": &Cat{},
+//	})
+//
+// Struct fields, slices, and maps of the interface type are then decoded into
+// the registered types. Decoding a value whose name isn't registered fails.
+// Registering an interface again replaces its types.
+func RegisterInterface(iface any, discriminate Discriminator, types map[string]any) error {
+	t := reflect.TypeOf(iface)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Interface {
+		return fmt.Errorf("expected a pointer to an interface, got %v", t)
+	}
+	t = t.Elem()
+
+	if discriminate == nil {
+		return fmt.Errorf("no discriminator for %v", t)
+	}
+
+	p := &polymorphic{discriminate: discriminate, types: make(map[string]reflect.Type, len(types))}
+	for name, value := range types {
+		concrete := reflect.TypeOf(value)
+		if concrete == nil || !concrete.Implements(t) {
+			return fmt.Errorf("type %v registered as %q doesn't implement %v", concrete, name, t)
+		}
+		p.types[name] = concrete
+	}
+
+	polymorphicTypes.Store(t, p)
+	return nil
+}
+
+// decodePolymorphic is a decode hook which decodes documents and objects
+// into the concrete type registered for the interface type t.
+func (c codec) decodePolymorphic(_ reflect.Type, t reflect.Type, data any) (any, error) {
+	if t.Kind() != reflect.Interface {
+		return data, nil
+	}
+
+	registered, found := polymorphicTypes.Load(t)
+	if !found {
+		return data, nil
+	}
+	p := registered.(*polymorphic)
+
+	var (
+		fields map[string]any
+		meta   Metadata
+	)
+	switch doc := data.(type) {
+	case *Document:
+		fields, meta = doc.Data, Metadata{ID: doc.ID, TS: doc.TS}
+		if doc.Coll != nil {
+			meta.Coll = doc.Coll.Name
+		}
+	case *NamedDocument:
+		fields, meta = doc.Data, Metadata{Name: doc.Name, TS: doc.TS}
+		if doc.Coll != nil {
+			meta.Coll = doc.Coll.Name
+		}
+	case map[string]any:
+		fields = doc
+		meta.ID, _ = doc["id"].(string)
+		meta.Name, _ = doc["name"].(string)
+		meta.TS, _ = doc["ts"].(*time.Time)
+		if coll, isMod := doc["coll"].(*Module); isMod && coll != nil {
+			meta.Coll = coll.Name
+		}
+	default:
+		return data, nil
+	}
+
+	name := p.discriminate(fields, meta)
+	concrete, found := p.types[name]
+	if !found {
+		return nil, fmt.Errorf("no type is registered as %q for %v", name, t)
+	}
+
+	result := reflect.New(concrete)
+	if err := c.decodeInto(data, result.Interface()); err != nil {
+		return nil, err
+	}
+
+	return result.Elem().Interface(), nil
+}
@@ -0,0 +1,79 @@
+package fauna
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type polymorphicTestAnimal interface {
+	Sound() string
+}
+
+type polymorphicTestDog struct {
+	Document
+	Name string `fauna:"name"`
+}
+
+func (d polymorphicTestDog) Sound() string { return d.Name + " barks" }
+
+type polymorphicTestCat struct {
+	Name  string `fauna:"name"`
+	Lives int    `fauna:"lives"`
+}
+
+func (c *polymorphicTestCat) Sound() string { return c.Name + " meows" }
+
+func TestRegisterInterface(t *testing.T) {
+	t.Cleanup(func() { polymorphicTypes.Delete(reflect.TypeOf((*polymorphicTestAnimal)(nil)).Elem()) })
+
+	assert.Error(t, RegisterInterface(polymorphicTestCat{}, ByField("kind"), nil), "not an interface")
+	assert.Error(t, RegisterInterface((*polymorphicTestAnimal)(nil), ByField("kind"), map[string]any{"cat": polymorphicTestCat{}}),
+		"the cat's methods are on its pointer")
+
+	type owner struct {
+		Pets    []polymorphicTestAnimal          `fauna:"pets"`
+		ByName  map[string]polymorphicTestAnimal `fauna:"by_name"`
+		Favored polymorphicTestAnimal            `fauna:"favored"`
+	}
+	const body = `{
+		"pets":[
+			{"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"},"name":"Rex"}},
+			{"kind":"cat","name":"Tom","lives":{"@int":"9"}}
+		],
+		"by_name":{"Rex":{"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"},"name":"Rex"}}},
+		"favored":{"kind":"cat","name":"Felix","lives":{"@int":"3"}}
+	}`
+
+	err := RegisterInterface((*polymorphicTestAnimal)(nil), func(fields map[string]any, meta Metadata) string {
+		if meta.Coll == "Dogs" {
+			return "dog"
+		}
+		return ByField("kind")(fields, meta)
+	}, map[string]any{"dog": polymorphicTestDog{}, "cat": &polymorphicTestCat{}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for name, decode := range map[string]func([]byte, any) error{"generic": unmarshal, "direct": decodeDirect} {
+		var got owner
+		if !assert.NoError(t, decode([]byte(body), &got), name) {
+			continue
+		}
+
+		if assert.Len(t, got.Pets, 2, name) {
+			dog, isDog := got.Pets[0].(polymorphicTestDog)
+			if assert.True(t, isDog, name) {
+				assert.Equal(t, "1", dog.ID, name)
+				assert.Equal(t, "Rex barks", dog.Sound(), name)
+			}
+			assert.Equal(t, &polymorphicTestCat{Name: "Tom", Lives: 9}, got.Pets[1], name)
+		}
+		assert.Equal(t, "Rex barks", got.ByName["Rex"].Sound(), name)
+		assert.Equal(t, &polymorphicTestCat{Name: "Felix", Lives: 3}, got.Favored, name)
+	}
+
+	var got owner
+	assert.ErrorContains(t, unmarshal([]byte(`{"favored":{"kind":"bird"}}`), &got), `no type is registered as "bird"`)
+}
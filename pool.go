@@ -0,0 +1,199 @@
+package fauna
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// PoolStats describes a [fauna.Client]'s pool of connections to Fauna, for
+// tuning its size and spotting saturation.
+type PoolStats struct {
+	// Open is the number of connections currently open.
+	Open int
+
+	// Idle is the number of open connections not serving a request.
+	Idle int
+
+	// Dials is the number of connections opened since the client was
+	// created.
+	Dials int64
+
+	// Requests is the number of requests which were given a connection.
+	Requests int64
+
+	// Reused is the number of those requests given an already open
+	// connection.
+	Reused int64
+
+	// Waits is the number of requests which waited for a new connection to
+	// be opened, as none could be reused.
+	Waits int64
+
+	// WaitTime is the total time requests spent waiting for a connection.
+	WaitTime time.Duration
+}
+
+// ReuseRatio returns the fraction of requests given an already open
+// connection, or 0 if no requests have been sent.
+func (s PoolStats) ReuseRatio() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Reused) / float64(s.Requests)
+}
+
+// PoolStats returns the current state of the client's connection pool. The
+// pool is only tracked for the client's own transport, so PoolStats returns
+// zero values if the client's [http.Client] was set with
+// [fauna.HTTPClient].
+func (c *Client) PoolStats() PoolStats {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+
+	return PoolStats{
+		Open:     c.pool.open,
+		Idle:     c.pool.open - c.pool.busy,
+		Dials:    c.pool.dials,
+		Requests: c.pool.requests,
+		Reused:   c.pool.reused,
+		Waits:    c.pool.waits,
+		WaitTime: c.pool.waitTime,
+	}
+}
+
+// connPool tracks the connections opened by a transport, and the requests
+// using them.
+type connPool struct {
+	mu       sync.Mutex
+	open     int
+	busy     int
+	dials    int64
+	requests int64
+	reused   int64
+	waits    int64
+	waitTime time.Duration
+}
+
+// dialer wraps dial so the connections it opens are tracked.
+func (p *connPool) dialer(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		p.mu.Lock()
+		p.open++
+		p.dials++
+		p.mu.Unlock()
+
+		return &poolConn{Conn: conn, pool: p}, nil
+	}
+}
+
+// acquire records a request given the connection in info after waiting for
+// wait, returning the connection if it's tracked.
+func (p *connPool) acquire(info httptrace.GotConnInfo, wait time.Duration) *poolConn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests++
+	p.waitTime += wait
+	if info.Reused {
+		p.reused++
+	} else {
+		p.waits++
+	}
+
+	conn := info.Conn
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	pc, ok := conn.(*poolConn)
+	if !ok {
+		return nil
+	}
+
+	// HTTP/2 connections serve many requests at once
+	if pc.inUse == 0 && !pc.closed {
+		p.busy++
+	}
+	pc.inUse++
+	return pc
+}
+
+// release records a request finished with conn.
+func (p *connPool) release(conn *poolConn) {
+	if conn == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	conn.inUse--
+	if conn.inUse == 0 && !conn.closed {
+		p.busy--
+	}
+}
+
+// poolConn is a connection tracked by a connPool.
+type poolConn struct {
+	net.Conn
+	pool *connPool
+
+	// guarded by pool.mu
+	inUse  int
+	closed bool
+}
+
+func (c *poolConn) Close() error {
+	c.pool.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		c.pool.open--
+		if c.inUse > 0 {
+			c.pool.busy--
+		}
+	}
+	c.pool.mu.Unlock()
+
+	return c.Conn.Close()
+}
+
+// poolTransport records which connection each request is given, until its
+// response body is closed.
+type poolTransport struct {
+	base http.RoundTripper
+	pool *connPool
+}
+
+func (t *poolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var (
+		start time.Time
+		conn  *poolConn
+	)
+
+	trace := &httptrace.ClientTrace{
+		GetConn: func(string) { start = time.Now() },
+		GotConn: func(info httptrace.GotConnInfo) {
+			// the transport may retry on another connection
+			t.pool.release(conn)
+			conn = t.pool.acquire(info, time.Since(start))
+		},
+	}
+
+	res, err := t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+	if err != nil {
+		t.pool.release(conn)
+		return nil, err
+	}
+
+	res.Body = &releaseBody{ReadCloser: res.Body, release: func() { t.pool.release(conn) }}
+	return res, nil
+}
@@ -0,0 +1,59 @@
+package fauna
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolStats(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, successBody(`null`)
+	})
+	client := srv.client()
+	q, _ := FQL(`null`, nil)
+
+	assert.Zero(t, client.PoolStats())
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Query(q)
+		assert.NoError(t, err)
+	}
+
+	stats := client.PoolStats()
+	assert.Equal(t, 1, stats.Open)
+	assert.Equal(t, 1, stats.Idle, "the connection is idle once the response is read")
+	assert.Equal(t, int64(1), stats.Dials)
+	assert.Equal(t, int64(3), stats.Requests)
+	assert.Equal(t, int64(2), stats.Reused)
+	assert.Equal(t, int64(1), stats.Waits)
+	assert.InDelta(t, 2.0/3, stats.ReuseRatio(), 0.001)
+
+	t.Run("counts busy connections", func(t *testing.T) {
+		r, err := client.http.Get(srv.URL)
+		if assert.NoError(t, err) {
+			assert.Zero(t, client.PoolStats().Idle)
+			_ = r.Body.Close()
+		}
+		assert.Equal(t, 1, client.PoolStats().Idle)
+	})
+
+	t.Run("counts closed connections", func(t *testing.T) {
+		srv.CloseClientConnections()
+		assert.Eventually(t, func() bool {
+			// the transport notices the closed connection when it's next used
+			_, err := client.Query(q)
+			return err == nil && client.PoolStats().Dials == 2
+		}, time.Second, 10*time.Millisecond)
+		assert.Equal(t, 1, client.PoolStats().Open)
+	})
+
+	t.Run("untracked http client", func(t *testing.T) {
+		client := srv.client(HTTPClient(&http.Client{}))
+		_, err := client.Query(q)
+		assert.NoError(t, err)
+		assert.Zero(t, client.PoolStats())
+	})
+}
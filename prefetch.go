@@ -0,0 +1,159 @@
+package fauna
+
+import (
+	"errors"
+	"sync"
+)
+
+type prefetchOptions struct {
+	pages int
+	items int
+}
+
+// PrefetchOptFn configuration options for [fauna.Prefetch]
+type PrefetchOptFn func(*prefetchOptions)
+
+// PrefetchPages sets the most pages a [fauna.PrefetchIterator] buffers ahead
+// of its consumer. The default is 1.
+func PrefetchPages(pages int) PrefetchOptFn {
+	return func(o *prefetchOptions) { o.pages = pages }
+}
+
+// PrefetchItems sets the most items a [fauna.PrefetchIterator] buffers ahead
+// of its consumer, across its buffered pages. A page is fetched only while
+// fewer items are buffered, so a page larger than the limit is still
+// buffered alone. The default is no limit other than PrefetchPages.
+func PrefetchItems(items int) PrefetchOptFn {
+	return func(o *prefetchOptions) { o.items = items }
+}
+
+// errNoMorePages is returned by the Next of page iterators after their last
+// page.
+var errNoMorePages = errors.New("no more pages")
+
+// A PrefetchIterator fetches the pages of a [fauna.QueryIterator] in the
+// background while the previous pages are processed, buffering a bounded
+// number of them, so a long export is limited by the slower of Fauna and its
+// consumer while its memory stays flat.
+type PrefetchIterator struct {
+	it   *QueryIterator
+	opts prefetchOptions
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	pages  []*Page
+	items  int
+	err    error
+	done   bool
+	closed bool
+}
+
+// Prefetch starts fetching the remaining pages of it in the background. It
+// mustn't be used by anything else until the [fauna.PrefetchIterator] is done
+// or closed. If fetching a page fails, prefetching stops and the error is
+// returned by Next after the pages buffered before it; it can then be resumed
+// from the failed page.
+func Prefetch(it *QueryIterator, opts ...PrefetchOptFn) *PrefetchIterator {
+	o := prefetchOptions{pages: 1}
+	for _, optFn := range opts {
+		optFn(&o)
+	}
+	if o.pages < 1 {
+		o.pages = 1
+	}
+
+	p := &PrefetchIterator{it: it, opts: o}
+	p.cond = sync.NewCond(&p.mu)
+	go p.fetch()
+
+	return p
+}
+
+// Next returns the next page, waiting for it to be fetched.
+func (p *PrefetchIterator) Next() (*Page, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for len(p.pages) == 0 && !p.done {
+		p.cond.Wait()
+	}
+
+	if len(p.pages) > 0 {
+		page := p.pages[0]
+		p.pages[0] = nil
+		p.pages = p.pages[1:]
+		p.items -= len(page.Data)
+		p.cond.Broadcast()
+		return page, nil
+	}
+
+	if p.err != nil {
+		err := p.err
+		p.err = nil
+		return nil, err
+	}
+
+	return nil, errNoMorePages
+}
+
+// HasNext returns whether there is another page or an error to return,
+// waiting for the next page to be fetched if none are buffered.
+func (p *PrefetchIterator) HasNext() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for len(p.pages) == 0 && !p.done {
+		p.cond.Wait()
+	}
+
+	return len(p.pages) > 0 || p.err != nil
+}
+
+// Close stops prefetching and drops the buffered pages. A page being fetched
+// when it's closed is dropped once it arrives.
+func (p *PrefetchIterator) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	p.pages, p.items = nil, 0
+	p.cond.Broadcast()
+}
+
+func (p *PrefetchIterator) fetch() {
+	for {
+		p.mu.Lock()
+		for !p.closed && p.full() {
+			p.cond.Wait()
+		}
+		if p.closed || !p.it.HasNext() {
+			p.finish(nil)
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+
+		page, err := p.it.Next()
+
+		p.mu.Lock()
+		if err != nil || p.closed {
+			p.finish(err)
+			p.mu.Unlock()
+			return
+		}
+		p.pages = append(p.pages, page)
+		p.items += len(page.Data)
+		p.cond.Broadcast()
+		p.mu.Unlock()
+	}
+}
+
+// full reports whether as many pages or items as allowed are buffered.
+func (p *PrefetchIterator) full() bool {
+	return len(p.pages) >= p.opts.pages || (p.opts.items > 0 && p.items >= p.opts.items)
+}
+
+func (p *PrefetchIterator) finish(err error) {
+	p.err, p.done = err, true
+	p.cond.Broadcast()
+}
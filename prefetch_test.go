@@ -0,0 +1,101 @@
+package fauna
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefetch(t *testing.T) {
+	const pages = 5
+
+	var failAt int32 = -1
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		bs, _ := marshal(req.Body["query"])
+
+		n := 0
+		if i := strings.Index(string(bs), `"page-`); i >= 0 {
+			n, _ = strconv.Atoi(string(bs[i+6 : i+7]))
+		}
+		if int32(n) == atomic.LoadInt32(&failAt) {
+			return http.StatusBadRequest, errorBody("invalid_query", "failed")
+		}
+
+		after := ""
+		if n+1 < pages {
+			after = `,"after":"page-` + strconv.Itoa(n+1) + `"`
+		}
+		return http.StatusOK, successBody(`{"@set":{"data":["a","b"]` + after + `}}`)
+	})
+	client := srv.client()
+	q, _ := FQL(`Dogs.all()`, nil)
+
+	// settled waits for prefetching to pause with want pages fetched
+	settled := func(t *testing.T, before, want int) {
+		assert.Eventually(t, func() bool { return len(srv.received())-before == want }, time.Second, time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		assert.Len(t, srv.received()[before:], want)
+	}
+
+	t.Run("reads every page", func(t *testing.T) {
+		p := Prefetch(client.Paginate(q), PrefetchPages(2))
+		defer p.Close()
+
+		count := 0
+		for p.HasNext() {
+			page, err := p.Next()
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Len(t, page.Data, 2)
+			count++
+		}
+		assert.Equal(t, pages, count)
+
+		_, err := p.Next()
+		assert.Error(t, err)
+	})
+
+	t.Run("bounded by pages", func(t *testing.T) {
+		before := len(srv.received())
+		p := Prefetch(client.Paginate(q))
+		defer p.Close()
+
+		settled(t, before, 1)
+		_, err := p.Next()
+		assert.NoError(t, err)
+		settled(t, before, 2)
+	})
+
+	t.Run("bounded by items", func(t *testing.T) {
+		before := len(srv.received())
+		p := Prefetch(client.Paginate(q), PrefetchPages(10), PrefetchItems(3))
+		defer p.Close()
+
+		settled(t, before, 2)
+	})
+
+	t.Run("stops at an error", func(t *testing.T) {
+		atomic.StoreInt32(&failAt, 2)
+		defer atomic.StoreInt32(&failAt, -1)
+
+		it := client.Paginate(q)
+		p := Prefetch(it, PrefetchPages(10))
+		defer p.Close()
+
+		for i := 0; i < 2; i++ {
+			_, err := p.Next()
+			assert.NoError(t, err)
+		}
+		assert.True(t, p.HasNext())
+		_, err := p.Next()
+		assert.Error(t, err)
+		assert.False(t, p.HasNext())
+		assert.True(t, it.HasNext(), "the iterator can be resumed")
+	})
+}
@@ -0,0 +1,76 @@
+package fauna
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type preparedQueries struct {
+	sync.RWMutex
+
+	templates map[string]string
+}
+
+// Prepare registers a named FQL template on the [fauna.Client] so it can be run
+// by name with [fauna.Client.Execute]. The template is parsed when registered,
+// and registering a name twice is an error.
+func (c *Client) Prepare(name string, template string) error {
+	if name == "" {
+		return fmt.Errorf("prepared query name must not be empty")
+	}
+
+	if _, parseErr := newTemplate(template).Parse(); parseErr != nil {
+		return fmt.Errorf("failed to prepare query %s: %w", name, parseErr)
+	}
+
+	c.prepared.Lock()
+	defer c.prepared.Unlock()
+
+	if _, exists := c.prepared.templates[name]; exists {
+		return fmt.Errorf("prepared query %s already registered", name)
+	}
+	c.prepared.templates[name] = template
+
+	return nil
+}
+
+// Execute runs the named prepared query with the given args, optionally
+// setting multiple [QueryOptFn]. Its metrics are labelled with its name, as
+// `fauna.query.name`, and the [fauna.QueryErrorInfo] of its errors has the
+// name.
+func (c *Client) Execute(name string, args map[string]any, opts ...QueryOptFn) (*QuerySuccess, error) {
+	c.prepared.RLock()
+	template, found := c.prepared.templates[name]
+	c.prepared.RUnlock()
+
+	if !found {
+		return nil, fmt.Errorf("prepared query %s not found", name)
+	}
+
+	fql, fqlErr := FQL(template, args)
+	if fqlErr != nil {
+		return nil, fmt.Errorf("failed to build prepared query %s: %w", name, fqlErr)
+	}
+
+	return c.Query(fql, append([]QueryOptFn{queryName(name)}, opts...)...)
+}
+
+// PreparedQueries returns the sorted names of the queries registered with
+// [fauna.Client.Prepare].
+func (c *Client) PreparedQueries() []string {
+	c.prepared.RLock()
+	defer c.prepared.RUnlock()
+
+	names := make([]string, 0, len(c.prepared.templates))
+	for name := range c.prepared.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func queryName(name string) QueryOptFn {
+	return func(req *fqlRequest) { req.Name = name }
+}
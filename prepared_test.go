@@ -0,0 +1,37 @@
+package fauna
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepare(t *testing.T) {
+	client := NewClient("secret", DefaultTimeouts(), URL(EndpointLocal))
+
+	t.Run("registers queries", func(t *testing.T) {
+		assert.NoError(t, client.Prepare("getUser", "Users.byId(${id})"))
+		assert.NoError(t, client.Prepare("allUsers", "Users.all()"))
+		assert.Equal(t, []string{"allUsers", "getUser"}, client.PreparedQueries())
+	})
+
+	t.Run("rejects duplicates", func(t *testing.T) {
+		assert.ErrorContains(t, client.Prepare("getUser", "Users.all()"), "already registered")
+	})
+
+	t.Run("rejects invalid templates", func(t *testing.T) {
+		assert.Error(t, client.Prepare("broken", "Users.byId($id)"))
+		assert.Error(t, client.Prepare("", "Users.all()"))
+		assert.NotContains(t, client.PreparedQueries(), "broken")
+	})
+
+	t.Run("execute unknown query", func(t *testing.T) {
+		_, err := client.Execute("missing", nil)
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("execute with missing arguments", func(t *testing.T) {
+		_, err := client.Execute("getUser", nil)
+		assert.ErrorContains(t, err, "getUser")
+	})
+}
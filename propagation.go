@@ -0,0 +1,162 @@
+package fauna
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// SpanContext identifies the span a query is made in, for propagating to
+// Fauna so its query logs can be correlated with the caller's traces.
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+}
+
+// IsValid reports whether the trace and span IDs are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// ParseTraceparent parses a W3C [traceparent] header.
+//
+// [traceparent]: https://www.w3.org/TR/trace-context/#traceparent-header
+func ParseTraceparent(traceparent string) (SpanContext, error) {
+	var sc SpanContext
+
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return sc, fmt.Errorf("invalid traceparent: %q", traceparent)
+	}
+
+	traceID, traceErr := hex.DecodeString(parts[1])
+	spanID, spanErr := hex.DecodeString(parts[2])
+	flags, flagsErr := hex.DecodeString(parts[3])
+	if traceErr != nil || spanErr != nil || flagsErr != nil || len(traceID) != 16 || len(spanID) != 8 || len(flags) != 1 {
+		return sc, fmt.Errorf("invalid traceparent: %q", traceparent)
+	}
+
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Sampled = flags[0]&1 == 1
+
+	if !sc.IsValid() {
+		return sc, fmt.Errorf("invalid traceparent: %q", traceparent)
+	}
+
+	return sc, nil
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns a copy of ctx carrying sc, which the
+// [fauna.Client]'s [fauna.Propagator] injects into queries made with it.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the [fauna.SpanContext] carried by ctx, if
+// any.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	if ctx == nil {
+		return SpanContext{}, false
+	}
+
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// Propagator writes a [fauna.SpanContext] to request headers, in the format
+// understood by a tracing backend.
+type Propagator interface {
+	Inject(sc SpanContext, header http.Header)
+}
+
+// WithPropagator sets the [fauna.Propagator] the [fauna.Client] injects the
+// span context of each query's context with, see
+// [fauna.ContextWithSpanContext]. The default is [fauna.W3CPropagator]. A
+// header set explicitly, such as with [fauna.Traceparent], takes precedence.
+func WithPropagator(p Propagator) ClientConfigFn {
+	return func(c *Client) { c.propagator = p }
+}
+
+// W3CPropagator injects the W3C Trace Context `traceparent` header.
+type W3CPropagator struct{}
+
+// Inject implements [fauna.Propagator]
+func (W3CPropagator) Inject(sc SpanContext, header http.Header) {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+
+	header.Set(HeaderTraceparent, fmt.Sprintf("00-%x-%x-%s", sc.TraceID, sc.SpanID, flags))
+}
+
+// B3Propagator injects Zipkin [B3] headers, either the single `b3` header or
+// the multiple `X-B3-*` headers.
+//
+// [B3]: https://github.com/openzipkin/b3-propagation
+type B3Propagator struct {
+	// SingleHeader injects the `b3` header rather than `X-B3-TraceId`,
+	// `X-B3-SpanId` and `X-B3-Sampled`.
+	SingleHeader bool
+}
+
+// Inject implements [fauna.Propagator]
+func (p B3Propagator) Inject(sc SpanContext, header http.Header) {
+	sampled := "0"
+	if sc.Sampled {
+		sampled = "1"
+	}
+
+	traceID, spanID := hex.EncodeToString(sc.TraceID[:]), hex.EncodeToString(sc.SpanID[:])
+	if p.SingleHeader {
+		header.Set("b3", traceID+"-"+spanID+"-"+sampled)
+		return
+	}
+
+	header.Set("X-B3-TraceId", traceID)
+	header.Set("X-B3-SpanId", spanID)
+	header.Set("X-B3-Sampled", sampled)
+}
+
+// DatadogPropagator injects Datadog's `x-datadog-*` headers. Datadog trace IDs
+// are 64 bits, so the high bits of 128-bit trace IDs are sent in the
+// `_dd.p.tid` tag.
+type DatadogPropagator struct{}
+
+// Inject implements [fauna.Propagator]
+func (DatadogPropagator) Inject(sc SpanContext, header http.Header) {
+	priority := "0"
+	if sc.Sampled {
+		priority = "1"
+	}
+
+	high, low := binary.BigEndian.Uint64(sc.TraceID[:8]), binary.BigEndian.Uint64(sc.TraceID[8:])
+	header.Set("x-datadog-trace-id", strconv.FormatUint(low, 10))
+	header.Set("x-datadog-parent-id", strconv.FormatUint(binary.BigEndian.Uint64(sc.SpanID[:]), 10))
+	header.Set("x-datadog-sampling-priority", priority)
+	if high != 0 {
+		header.Set("x-datadog-tags", fmt.Sprintf("_dd.p.tid=%016x", high))
+	}
+}
+
+// Propagators combines propagators, injecting the headers of each, for
+// requests traced by more than one backend.
+func Propagators(propagators ...Propagator) Propagator {
+	return multiPropagator(propagators)
+}
+
+type multiPropagator []Propagator
+
+func (m multiPropagator) Inject(sc SpanContext, header http.Header) {
+	for _, p := range m {
+		p.Inject(sc, header)
+	}
+}
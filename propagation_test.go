@@ -0,0 +1,149 @@
+package fauna
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	sc, err := ParseTraceparent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	if assert.NoError(t, err) {
+		assert.Equal(t, SpanContext{
+			TraceID: [16]byte{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+			SpanID:  [8]byte{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31},
+			Sampled: true,
+		}, sc)
+	}
+
+	for _, invalid := range []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd-b7ad6b7169203331-01",
+	} {
+		_, err := ParseTraceparent(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestPropagators(t *testing.T) {
+	sc, _ := ParseTraceparent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	inject := func(p Propagator) http.Header {
+		header := http.Header{}
+		p.Inject(sc, header)
+		return header
+	}
+
+	assert.Equal(t, http.Header{
+		"Traceparent": {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+	}, inject(W3CPropagator{}))
+
+	assert.Equal(t, http.Header{
+		"B3": {"0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-1"},
+	}, inject(B3Propagator{SingleHeader: true}))
+
+	assert.Equal(t, http.Header{
+		"X-B3-Traceid": {"0af7651916cd43dd8448eb211c80319c"},
+		"X-B3-Spanid":  {"b7ad6b7169203331"},
+		"X-B3-Sampled": {"1"},
+	}, inject(B3Propagator{}))
+
+	assert.Equal(t, http.Header{
+		"X-Datadog-Trace-Id":          {"9532127138774266268"},
+		"X-Datadog-Parent-Id":         {"13235353014750950193"},
+		"X-Datadog-Sampling-Priority": {"1"},
+		"X-Datadog-Tags":              {"_dd.p.tid=0af7651916cd43dd"},
+	}, inject(DatadogPropagator{}))
+
+	both := inject(Propagators(W3CPropagator{}, DatadogPropagator{}))
+	assert.NotEmpty(t, both.Get("traceparent"))
+	assert.NotEmpty(t, both.Get("x-datadog-trace-id"))
+}
+
+func TestWithPropagator(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, successBody(`null`)
+	})
+
+	sc, _ := ParseTraceparent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00")
+	ctx := ContextWithSpanContext(context.Background(), sc)
+	q, _ := FQL(`null`, nil)
+
+	t.Run("defaults to W3C", func(t *testing.T) {
+		client := srv.client()
+
+		_, err := client.Query(q, QueryContext(ctx))
+		assert.NoError(t, err)
+		received := srv.received()
+		assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00", received[len(received)-1].Header.Get(HeaderTraceparent))
+
+		_, err = client.Query(q)
+		assert.NoError(t, err)
+		received = srv.received()
+		assert.Empty(t, received[len(received)-1].Header.Get(HeaderTraceparent), "queries without a span context have no headers")
+	})
+
+	t.Run("B3", func(t *testing.T) {
+		client := srv.client(WithPropagator(B3Propagator{SingleHeader: true}))
+
+		_, err := client.Query(q, QueryContext(ctx))
+		assert.NoError(t, err)
+		received := srv.received()
+		header := received[len(received)-1].Header
+		assert.Equal(t, "0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-0", header.Get("b3"))
+		assert.Empty(t, header.Get(HeaderTraceparent))
+	})
+}
+
+func TestQuerySpanContext(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, successBody(`null`)
+	})
+	client := srv.client(WithPropagator(B3Propagator{}))
+	q, _ := FQL(`null`, nil)
+
+	sc, _ := ParseTraceparent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	other, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	_, err := client.Query(q, QueryContext(ContextWithSpanContext(context.Background(), other)), WithSpanContext(ContextWithSpanContext(context.Background(), sc)))
+	assert.NoError(t, err)
+
+	_, err = client.Query(q, WithSpanContext(context.Background()))
+	assert.NoError(t, err)
+
+	received := srv.received()
+	if assert.Len(t, received, 2) {
+		assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", received[0].Header.Get("X-B3-TraceId"), "the option wins over the query's context")
+		assert.Empty(t, received[1].Header.Get("X-B3-TraceId"))
+	}
+
+	t.Run("traceparent doesn't leak across queries", func(t *testing.T) {
+		client := srv.client()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if i%2 == 0 {
+					_, _ = client.Query(q, Traceparent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"))
+				} else {
+					_, _ = client.Query(q, Tags(map[string]string{"untraced": "true"}))
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		for _, req := range srv.received()[2:] {
+			traced := req.Header.Get(HeaderTraceparent) != ""
+			assert.NotEqual(t, traced, req.Header.Get(HeaderTags) != "")
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package fauna
+
+import (
+	"errors"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Provenance identifies the query a Fauna error came from, attached to the
+// error's [fauna.ErrFauna].
+type Provenance struct {
+	// Template is the query's FQL, with `${...}` in place of each argument so
+	// no values are included. See [Query.Template].
+	Template string
+
+	// CallSite is the `file:line` [fauna.FQL] was called from, or empty
+	// unless [fauna.CaptureCallSites] is enabled.
+	CallSite string
+}
+
+var captureCallSites atomic.Bool
+
+// CaptureCallSites records where each [fauna.Query] is created with
+// [fauna.FQL], to report in the [fauna.Provenance] of its errors. It's off by
+// default, as finding the caller costs a little on every call.
+func CaptureCallSites(enabled bool) {
+	captureCallSites.Store(enabled)
+}
+
+// driverDir is the directory of the driver's source, whose frames are skipped
+// when finding a call site.
+var driverDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// callSite returns the `file:line` of the first caller outside the driver, so
+// queries built by the driver's helpers are reported where the helper was
+// called.
+func callSite() string {
+	if !captureCallSites.Load() {
+		return ""
+	}
+
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !inDriver(frame.File) {
+			return frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+func inDriver(file string) bool {
+	return strings.HasPrefix(file, driverDir+"/") && !strings.HasSuffix(file, "_test.go")
+}
+
+// Template returns the query's FQL, including that of any queries composed
+// into it, with `${...}` in place of each argument, so it can be logged
+// without the arguments' values.
+func (q *Query) Template() string {
+	var b strings.Builder
+	q.writeTemplate(&b)
+	return b.String()
+}
+
+func (q *Query) writeTemplate(b *strings.Builder) {
+	for _, fragment := range q.fragments {
+		switch value := fragment.value.(type) {
+		case string:
+			if fragment.literal {
+				b.WriteString(value)
+				continue
+			}
+		case *Query:
+			value.writeTemplate(b)
+			continue
+		}
+		b.WriteString("${...}")
+	}
+}
+
+// queryProvenance returns the [fauna.Provenance] of the request's query, or
+// nil if it isn't a [fauna.Query].
+func queryProvenance(request *fqlRequest) *Provenance {
+	query, isQuery := request.Query.(*Query)
+	if !isQuery || query == nil {
+		return nil
+	}
+
+	return &Provenance{Template: query.Template(), CallSite: query.callSite}
+}
+
+// withProvenance attaches the request's query's [fauna.Provenance] to a Fauna
+// error.
+func withProvenance(request *fqlRequest, err error) error {
+	var fe interface{ base() *ErrFauna }
+	if errors.As(err, &fe) {
+		if base := fe.base(); base != nil && base.Provenance == nil {
+			base.Provenance = queryProvenance(request)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,44 @@
+package fauna
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvenance(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusBadRequest, errorBody("invalid_query", "bad query")
+	})
+	client := srv.client()
+
+	filter, _ := FQL(`.name == ${name}`, map[string]any{"name": "Scout"})
+	q, _ := FQL(`Dogs.where(${filter}).take(${n})`, map[string]any{"filter": filter, "n": 10})
+	assert.Equal(t, "Dogs.where(.name == ${...}).take(${...})", q.Template(), "values are redacted")
+
+	var fe *ErrQueryCheck
+	_, err := client.Query(q)
+	if assert.ErrorAs(t, err, &fe) && assert.NotNil(t, fe.Provenance) {
+		assert.Equal(t, q.Template(), fe.Provenance.Template)
+		assert.Empty(t, fe.Provenance.CallSite, "call sites are only captured when enabled")
+	}
+
+	CaptureCallSites(true)
+	defer CaptureCallSites(false)
+
+	q, _ = FQL(`Dogs.all()`, nil)
+	_, err = client.Query(q)
+	if assert.ErrorAs(t, err, &fe) && assert.NotNil(t, fe.Provenance) {
+		file, _, _ := strings.Cut(fe.Provenance.CallSite, ":")
+		assert.Equal(t, "provenance_test.go", filepath.Base(file))
+	}
+
+	_, err = client.GetKV("key", new(string))
+	if assert.ErrorAs(t, err, &fe) && assert.NotNil(t, fe.Provenance) {
+		file, _, _ := strings.Cut(fe.Provenance.CallSite, ":")
+		assert.Equal(t, "provenance_test.go", filepath.Base(file), "the driver's frames are skipped")
+	}
+}
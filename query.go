@@ -13,6 +13,10 @@ type queryFragment struct {
 // Query represents a query to be sent to Fauna.
 type Query struct {
 	fragments []*queryFragment
+
+	// callSite is where [fauna.FQL] was called, if [fauna.CaptureCallSites]
+	// is enabled
+	callSite string
 }
 
 // FQL creates a [fauna.Query] from an FQL string and set of arguments.
@@ -21,8 +25,8 @@ type Query struct {
 // in the query. FQL `${value} + 1` must have an argument called "value" in the
 // args map.
 //
-// The values of args can be any type, including [fauna.Query] to allow for
-// query composition.
+// The values of args can be any type, including [fauna.Query] and
+// [fauna.Fragment] to allow for query composition.
 func FQL(query string, args map[string]any) (*Query, error) {
 	template := newTemplate(query)
 	parts, err := template.Parse()
@@ -44,6 +48,16 @@ func FQL(query string, args map[string]any) (*Query, error) {
 			}
 
 			if arg, ok := args[part.Text]; ok {
+				if f, isFragment := arg.(*Fragment); isFragment {
+					q, fragmentErr := expandFragment(part.Text, f, args)
+					if fragmentErr != nil {
+						return nil, fragmentErr
+					}
+					arg = q
+				} else if validateErr := validateArgument(part.Text, arg); validateErr != nil {
+					return nil, validateErr
+				}
+
 				fragments = append(fragments, &queryFragment{false, arg})
 			} else {
 				return nil, fmt.Errorf("template variable %s not found in args", part.Text)
@@ -52,5 +66,5 @@ func FQL(query string, args map[string]any) (*Query, error) {
 		}
 	}
 
-	return &Query{fragments: fragments}, nil
+	return &Query{fragments: fragments, callSite: callSite()}, nil
 }
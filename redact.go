@@ -0,0 +1,225 @@
+package fauna
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const tagOptSensitive = "sensitive"
+
+// Redacted replaces the values of sensitive fields in the output of
+// [fauna.Redact] and [Query.Debug].
+const Redacted = "[REDACTED]"
+
+var sensitiveName atomic.Pointer[func(name string) bool]
+
+// RedactFields sets a callback reporting whether fields named name are
+// sensitive, such as "password" or "token", so they're redacted wherever they
+// appear, including in maps and documents. Struct fields tagged `sensitive`
+// or `encrypted` are always redacted. A nil callback removes it.
+func RedactFields(sensitive func(name string) bool) {
+	if sensitive == nil {
+		sensitiveName.Store(nil)
+		return
+	}
+	sensitiveName.Store(&sensitive)
+}
+
+// Redact returns a copy of v for logging or printing, with the value of each
+// sensitive field replaced with [fauna.Redacted]. Structs are copied into
+// maps by the names of their fields in Fauna, as they're encoded, and slices
+// and arrays into []any. Other values are returned as they are.
+func Redact(v any) any {
+	if v == nil {
+		return nil
+	}
+	return redactValue(reflect.ValueOf(v))
+}
+
+// Debug returns the query's FQL, like [Query.Template], with the JSON of each
+// argument in place of `${...}` and sensitive fields redacted, as with
+// [fauna.Redact].
+func (q *Query) Debug() string {
+	var b strings.Builder
+	q.writeDebug(&b)
+	return b.String()
+}
+
+func (q *Query) writeDebug(b *strings.Builder) {
+	for _, fragment := range q.fragments {
+		switch value := fragment.value.(type) {
+		case string:
+			if fragment.literal {
+				b.WriteString(value)
+				continue
+			}
+		case *Query:
+			value.writeDebug(b)
+			continue
+		}
+
+		bin, err := json.Marshal(Redact(fragment.value))
+		if err != nil {
+			b.WriteString("${...}")
+			continue
+		}
+		b.Write(bin)
+	}
+}
+
+func isSensitiveName(name string) bool {
+	sensitive := sensitiveName.Load()
+	return sensitive != nil && (*sensitive)(name)
+}
+
+func redactValue(v reflect.Value) any {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+
+	if n, isNullable := v.Interface().(nullable); isNullable {
+		value, set, null := n.nullable()
+		if !set || null {
+			return nil
+		}
+		return Redact(value)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type() == reflect.TypeOf(&Query{}) {
+			return v.Interface().(*Query).Debug()
+		}
+		return redactValue(v.Elem())
+
+	case reflect.Struct:
+		switch v.Type() {
+		case timeType, reflect.TypeOf(Module{}), reflect.TypeOf(Ref{}), reflect.TypeOf(NamedRef{}):
+			return v.Interface()
+		case reflect.TypeOf(Query{}):
+			query := v.Interface().(Query)
+			return query.Debug()
+		}
+		out := map[string]any{}
+		redactInto(v, out)
+		return out
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		out := make(map[string]any, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			key := it.Key().String()
+			if isSensitiveName(key) {
+				out[key] = Redacted
+				continue
+			}
+			out[key] = redactValue(it.Value())
+		}
+		return out
+
+	case reflect.Slice, reflect.Array:
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = redactValue(v.Index(i))
+		}
+		return out
+	}
+
+	return v.Interface()
+}
+
+// redactStruct copies the fields of the struct v encodeStruct encodes into
+// out, flattening embedded structs.
+func redactStruct(v reflect.Value, out map[string]any) {
+	for i := 0; i < v.NumField(); i++ {
+		structField := v.Type().Field(i)
+		field := v.Field(i)
+
+		tags := strings.Split(codec{}.tagOf(structField), ",")
+		if tags[0] == "-" {
+			continue
+		}
+
+		if structField.Anonymous && tags[0] == "" {
+			if field.Kind() == reflect.Pointer {
+				if field.IsNil() {
+					continue
+				}
+				field = field.Elem()
+			}
+			if field.Kind() == reflect.Struct && field.CanInterface() {
+				redactInto(field, out)
+				continue
+			}
+		}
+
+		if !structField.IsExported() {
+			continue
+		}
+
+		if omitField(tags[1:], field) {
+			continue
+		}
+
+		sensitive := false
+		for _, opt := range tags[1:] {
+			sensitive = sensitive || opt == tagOptSensitive || opt == tagOptEncrypted
+		}
+
+		if n, isNullable := field.Interface().(nullable); isNullable {
+			if _, set, _ := n.nullable(); !set {
+				continue
+			}
+		}
+
+		name := tags[0]
+		if name == "" {
+			name = structField.Name
+		}
+
+		if sensitive || isSensitiveName(name) {
+			out[name] = Redacted
+			continue
+		}
+		out[name] = redactValue(field)
+	}
+}
+
+// redactInto copies the fields of the struct v into out, including the
+// metadata and data of a [fauna.Document] or [fauna.NamedDocument].
+func redactInto(v reflect.Value, out map[string]any) {
+	var (
+		meta map[string]any
+		data map[string]any
+	)
+	switch doc := v.Interface().(type) {
+	case Document:
+		meta, data = map[string]any{"id": doc.ID, "coll": doc.Coll, "ts": doc.TS}, doc.Data
+	case NamedDocument:
+		meta, data = map[string]any{"name": doc.Name, "coll": doc.Coll, "ts": doc.TS}, doc.Data
+	default:
+		redactStruct(v, out)
+		return
+	}
+
+	for name, value := range meta {
+		if reflect.ValueOf(value).IsZero() {
+			continue
+		}
+		if ts, isTime := value.(*time.Time); isTime {
+			value = *ts
+		}
+		out[name] = value
+	}
+	for name, value := range redactValue(reflect.ValueOf(data)).(map[string]any) {
+		out[name] = value
+	}
+}
@@ -0,0 +1,84 @@
+package fauna
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type redactTestUser struct {
+	Document
+	Email    string            `fauna:"email"`
+	Password string            `fauna:"password,sensitive"`
+	SSN      string            `fauna:"ssn,encrypted"`
+	Note     string            `fauna:"note,omitempty,sensitive"`
+	Prefs    map[string]string `fauna:"prefs"`
+	Friends  []redactTestUser  `fauna:"friends"`
+	Skipped  string            `fauna:"-"`
+}
+
+func TestRedact(t *testing.T) {
+	ts := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	user := redactTestUser{
+		Document: Document{ID: "1", Coll: &Module{"Users"}, TS: &ts, Data: map[string]any{"token": "t0ps3cret"}},
+		Email:    "ada@example.com",
+		Password: "hunter2",
+		SSN:      "123-45-6789",
+		Prefs:    map[string]string{"theme": "dark", "api_key": "k3y"},
+		Friends:  []redactTestUser{{Email: "bob@example.com", Password: "pass"}},
+		Skipped:  "skipped",
+	}
+
+	assert.Equal(t, map[string]any{
+		"id":       "1",
+		"coll":     &Module{"Users"},
+		"ts":       ts,
+		"token":    "t0ps3cret",
+		"email":    "ada@example.com",
+		"password": Redacted,
+		"ssn":      Redacted,
+		"prefs":    map[string]any{"theme": "dark", "api_key": "k3y"},
+		"friends": []any{map[string]any{
+			"email":    "bob@example.com",
+			"password": Redacted,
+			"ssn":      Redacted,
+			"prefs":    map[string]any{},
+			"friends":  []any{},
+		}},
+	}, Redact(&user))
+
+	t.Run("by name", func(t *testing.T) {
+		RedactFields(func(name string) bool { return name == "token" || strings.HasSuffix(name, "_key") })
+		t.Cleanup(func() { RedactFields(nil) })
+
+		redacted := Redact(user).(map[string]any)
+		assert.Equal(t, Redacted, redacted["token"])
+		assert.Equal(t, map[string]any{"theme": "dark", "api_key": Redacted}, redacted["prefs"])
+
+		assert.Equal(t, []any{map[string]any{"token": Redacted}, "x"}, Redact([]any{map[string]string{"token": "t"}, "x"}))
+	})
+
+	t.Run("queries", func(t *testing.T) {
+		inner, _ := FQL(`Users.byEmail(${email})`, map[string]any{"email": "ada@example.com"})
+		q, err := FQL(`${inner}!.update(${user})`, map[string]any{"inner": inner, "user": redactTestUser{Email: "ada@example.com", Password: "hunter2"}})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t,
+			`Users.byEmail("ada@example.com")!.update({"email":"ada@example.com","friends":[],"password":"[REDACTED]","prefs":{},"ssn":"[REDACTED]"})`,
+			q.Debug())
+		assert.Equal(t, `Users.byEmail(${...})!.update(${...})`, q.Template())
+	})
+
+	t.Run("encoding ignores the tag", func(t *testing.T) {
+		bin, err := marshal(struct {
+			Password string `fauna:"password,sensitive"`
+		}{"hunter2"})
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(bin), `"password":"hunter2"`)
+		}
+	})
+}
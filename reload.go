@@ -0,0 +1,160 @@
+package fauna
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// LiveConfig is the configuration of a [fauna.Client] which can be changed
+// while it's in use, with [Client.Reconfigure] or [Client.WatchConfig]. Empty
+// fields are left unchanged.
+type LiveConfig struct {
+	// Endpoint is the URL of Fauna, as set with [fauna.URL].
+	Endpoint string `json:"endpoint"`
+
+	// Secret authenticates queries.
+	Secret string `json:"secret"`
+
+	// QueryTimeout is the query timeout, as set with [fauna.QueryTimeout].
+	// The client's HTTP timeout isn't changed, so a query timeout longer
+	// than the client was created with may be cut short.
+	QueryTimeout time.Duration `json:"-"`
+}
+
+// endpointConfig is where and how a client connects to Fauna. It's replaced
+// as a whole, so each request sees a consistent configuration.
+type endpointConfig struct {
+	url    string
+	secret string
+
+	// queryTimeout is the value of the `x-query-timeout-ms` header, or empty
+	// to not send one.
+	queryTimeout string
+}
+
+func newEndpoint(config endpointConfig) *atomic.Pointer[endpointConfig] {
+	endpoint := &atomic.Pointer[endpointConfig]{}
+	endpoint.Store(&config)
+	return endpoint
+}
+
+// updateEndpoint replaces the client's configuration with a copy changed by
+// fn.
+func (c *Client) updateEndpoint(fn func(e *endpointConfig)) {
+	for {
+		current := c.endpoint.Load()
+		updated := *current
+		fn(&updated)
+		if c.endpoint.CompareAndSwap(current, &updated) {
+			return
+		}
+	}
+}
+
+// endpointFor returns the configuration to send request with, using its
+// secret if it has one. request may be nil.
+func (c *Client) endpointFor(request *fqlRequest) endpointConfig {
+	ep := *c.endpoint.Load()
+	if c.scopedSecret != "" {
+		ep.secret = c.scopedSecret
+	}
+	if request != nil && request.Secret != "" {
+		ep.secret = request.Secret
+	}
+
+	return ep
+}
+
+// Reconfigure atomically applies the non-empty fields of config to the
+// [fauna.Client]. Requests in flight finish with the configuration they
+// started with, and later requests use the new one. Clients derived with
+// [Client.With] keep the configuration they were derived with.
+func (c *Client) Reconfigure(config LiveConfig) {
+	c.updateEndpoint(func(e *endpointConfig) {
+		if config.Endpoint != "" {
+			e.url = config.Endpoint
+		}
+		if config.Secret != "" {
+			e.secret = config.Secret
+		}
+		if config.QueryTimeout > 0 {
+			e.queryTimeout = fmt.Sprintf("%v", config.QueryTimeout.Milliseconds())
+		}
+	})
+}
+
+// ConfigSource loads the current [fauna.LiveConfig], for
+// [Client.WatchConfig], such as from a dynamic configuration system.
+type ConfigSource func(ctx context.Context) (LiveConfig, error)
+
+// ConfigFile is a [fauna.ConfigSource] which reads a JSON file at path, such
+// as one mounted from a secret store:
+//
+//	{"endpoint": "https://db.fauna.com", "secret": "...", "query_timeout": "5s"}
+func ConfigFile(path string) ConfigSource {
+	return func(context.Context) (LiveConfig, error) {
+		bin, err := os.ReadFile(path)
+		if err != nil {
+			return LiveConfig{}, fmt.Errorf("failed to read config: %w", err)
+		}
+
+		var file struct {
+			LiveConfig
+			QueryTimeout string `json:"query_timeout"`
+		}
+		if err := json.Unmarshal(bin, &file); err != nil {
+			return LiveConfig{}, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+
+		config := file.LiveConfig
+		if file.QueryTimeout != "" {
+			if config.QueryTimeout, err = time.ParseDuration(file.QueryTimeout); err != nil {
+				return LiveConfig{}, fmt.Errorf("invalid query_timeout in config %s: %w", path, err)
+			}
+		}
+
+		return config, nil
+	}
+}
+
+// WatchConfig loads the configuration from source, applying it with
+// [Client.Reconfigure], immediately and then every interval until ctx is
+// done. If the configuration can't be loaded, the client keeps its current
+// configuration and onError, if not nil, is called with the error.
+//
+// WatchConfig blocks, so is usually run in its own goroutine:
+//
+//	go client.WatchConfig(ctx, time.Minute, fauna.ConfigFile("/etc/fauna/config.json"), nil)
+func (c *Client) WatchConfig(ctx context.Context, interval time.Duration, source ConfigSource, onError func(error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", interval)
+	}
+
+	load := func() {
+		config, err := source(ctx)
+		if err != nil {
+			if onError != nil {
+				onError(err)
+			}
+			return
+		}
+		c.Reconfigure(config)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	load()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			load()
+		}
+	}
+}
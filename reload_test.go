@@ -0,0 +1,84 @@
+package fauna
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconfigure(t *testing.T) {
+	handler := func(testRequest) (int, string) { return http.StatusOK, successBody(`null`) }
+	first, second := newTestServer(t, handler), newTestServer(t, handler)
+	client := first.client()
+	derived := client.With()
+	q, _ := FQL(`null`, nil)
+
+	client.Reconfigure(LiveConfig{Endpoint: second.URL, Secret: "rotated", QueryTimeout: 2 * time.Second})
+	assert.Equal(t, second.URL, client.String())
+
+	_, err := client.Query(q)
+	assert.NoError(t, err)
+	if received := second.received(); assert.Len(t, received, 1) {
+		assert.Equal(t, "Bearer rotated", received[0].Header.Get(headerAuthorization))
+		assert.Equal(t, "2000", received[0].Header.Get(HeaderQueryTimeoutMs))
+	}
+
+	_, err = derived.Query(q)
+	assert.NoError(t, err)
+	if received := first.received(); assert.Len(t, received, 1, "derived clients keep their configuration") {
+		assert.Equal(t, "Bearer secret", received[0].Header.Get(headerAuthorization))
+	}
+
+	client.Reconfigure(LiveConfig{Secret: "again"})
+	_, err = client.Query(q)
+	assert.NoError(t, err)
+	if received := second.received(); assert.Len(t, received, 2, "empty fields are unchanged") {
+		assert.Equal(t, "Bearer again", received[1].Header.Get(headerAuthorization))
+		assert.Equal(t, "2000", received[1].Header.Get(HeaderQueryTimeoutMs))
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) { return http.StatusOK, successBody(`null`) })
+	client := NewClient("secret", DefaultTimeouts())
+
+	path := filepath.Join(t.TempDir(), "fauna.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"endpoint":"`+srv.URL+`","secret":"from-file","query_timeout":"3s"}`), 0o600))
+
+	errs := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- client.WatchConfig(ctx, 5*time.Millisecond, ConfigFile(path), func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		})
+	}()
+
+	assert.Eventually(t, func() bool { return client.String() == srv.URL }, time.Second, time.Millisecond)
+	q, _ := FQL(`null`, nil)
+	_, err := client.Query(q)
+	assert.NoError(t, err)
+	if received := srv.received(); assert.Len(t, received, 1) {
+		assert.Equal(t, "Bearer from-file", received[0].Header.Get(headerAuthorization))
+		assert.Equal(t, "3000", received[0].Header.Get(HeaderQueryTimeoutMs))
+	}
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{`), 0o600))
+	assert.Error(t, <-errs)
+	assert.Equal(t, srv.URL, client.String(), "the configuration is kept when it can't be loaded")
+
+	cancel()
+	assert.NoError(t, <-done)
+
+	failing := func(context.Context) (LiveConfig, error) { return LiveConfig{}, errors.New("unavailable") }
+	assert.ErrorContains(t, client.WatchConfig(context.Background(), 0, failing, nil), "interval")
+}
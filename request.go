@@ -3,19 +3,39 @@ package fauna
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 type fqlRequest struct {
-	Context   context.Context
-	Headers   map[string]string
-	Query     any            `fauna:"query"`
-	Arguments map[string]any `fauna:"arguments"`
+	Context         context.Context
+	Headers         map[string]string
+	Name            string
+	Secret          string
+	Into            any
+	NoCache         bool
+	NoCoalesce      bool
+	NoRetries       bool
+	PlainSummary    bool
+	MaxResponseSize int64
+	CacheTags       []string
+	SpanContext     SpanContext
+	Err             error
+	Attempts        int
+	History         []RetryAttempt
+	SentHeader      http.Header
+	Query           any            `fauna:"query"`
+	Arguments       map[string]any `fauna:"arguments"`
 }
 
 type queryResponse struct {
@@ -29,6 +49,27 @@ type queryResponse struct {
 	Summary       string          `json:"summary"`
 	TxnTime       int64           `json:"txn_ts"`
 	Tags          string          `json:"query_tags"`
+	RequestID     string          `json:"-"`
+}
+
+// stripFormatting removes terminal escape sequences from the response's
+// summary and error message.
+func (r *queryResponse) stripFormatting() {
+	r.Summary = StripFormatting(r.Summary)
+	if r.Error != nil {
+		r.Error.Message = StripFormatting(r.Error.Message)
+	}
+}
+
+// clone copies the response, so the copy's stats can be modified.
+func (r *queryResponse) clone() *queryResponse {
+	res := *r
+	if r.Stats != nil {
+		stats := *r.Stats
+		res.Stats = &stats
+	}
+
+	return &res
 }
 
 func (r *queryResponse) queryTags() map[string]string {
@@ -36,79 +77,417 @@ func (r *queryResponse) queryTags() map[string]string {
 
 	if r.Tags != "" {
 		for _, tag := range strings.Split(r.Tags, `,`) {
-			tokens := strings.Split(tag, `=`)
-			ret[tokens[0]] = tokens[1]
+			tokens := strings.SplitN(tag, `=`, 2)
+			if len(tokens) != 2 {
+				continue
+			}
+			k, kErr := url.QueryUnescape(tokens[0])
+			v, vErr := url.QueryUnescape(tokens[1])
+			if kErr != nil || vErr != nil {
+				k, v = tokens[0], tokens[1]
+			}
+			ret[k] = v
 		}
 	}
 
 	return ret
 }
 
+// maxPooledBufferSize is the largest buffer returned to bufferPool, so one
+// huge request or response doesn't pin its memory for the life of the process.
+const maxPooledBufferSize = 1 << 20
+
+// bufferPool holds the buffers requests are encoded into and responses are
+// read into, which are otherwise the largest per-query allocations.
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
 func (c *Client) do(request *fqlRequest) (*QuerySuccess, error) {
-	bytesOut, bytesErr := marshal(request)
-	if bytesErr != nil {
+	res, err := c.doQuery(request)
+	if err != nil {
+		return nil, c.hookError(request, err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) doQuery(request *fqlRequest) (*QuerySuccess, error) {
+	reqBuf := getBuffer()
+	defer putBuffer(reqBuf)
+
+	if bytesErr := c.codec.marshalTo(reqBuf, request); bytesErr != nil {
 		return nil, fmt.Errorf("marshal request failed: %w", bytesErr)
 	}
 
-	reqURL, urlErr := url.Parse(c.url)
+	ep := c.endpointFor(request)
+
+	useCache := c.cache != nil && !request.NoCache
+	coalesce := c.coalescing != nil && !request.NoCoalesce
+
+	key := ""
+	if useCache || coalesce {
+		key = requestKey(ep.secret, request.Headers, reqBuf.Bytes())
+	}
+
+	if useCache {
+		if cached, hit := c.cache.get(key); hit {
+			ret, err := c.result(request, cached, 0)
+			if err == nil {
+				ret.Cached = true
+			}
+			return ret, err
+		}
+	}
+
+	send := func() (*queryResponse, int, error) {
+		start := time.Now()
+		res, attempts, err := c.send(request, ep, reqBuf.Bytes())
+		c.recordQuery(request, time.Since(start), res, err)
+		return res, attempts, withProvenance(request, err)
+	}
+
+	var (
+		res      *queryResponse
+		attempts int
+		err      error
+	)
+	if coalesce {
+		var shared bool
+		res, attempts, err, shared = c.coalescing.do(request.Context, key, c.GetLastTxnTime(), send)
+		if shared && !sharable(request, res, err) {
+			res, attempts, err = send()
+		} else if err == nil {
+			// every caller in the flight has the same response
+			res = res.clone()
+		}
+	} else {
+		res, attempts, err = send()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if useCache && (res.Stats == nil || res.Stats.WriteOps == 0) {
+		c.cache.set(key, res, request.CacheTags)
+	}
+
+	return c.result(request, res, attempts)
+}
+
+func (c *Client) doRaw(request *fqlRequest, w io.Writer) (int64, error) {
+	n, err := c.doRawQuery(request, w)
+	if err != nil {
+		return n, c.hookError(request, err)
+	}
+
+	return n, nil
+}
+
+func (c *Client) doRawQuery(request *fqlRequest, w io.Writer) (int64, error) {
+	reqBuf := getBuffer()
+	defer putBuffer(reqBuf)
+
+	if bytesErr := c.codec.marshalTo(reqBuf, request); bytesErr != nil {
+		return 0, fmt.Errorf("marshal request failed: %w", bytesErr)
+	}
+
+	start := time.Now()
+	n, err := c.sendRaw(request, c.endpointFor(request), reqBuf.Bytes(), w)
+	c.recordQuery(request, time.Since(start), nil, err)
+
+	return n, err
+}
+
+// sendRaw runs the encoded request body, writing the response to w if it was
+// successful. Nothing is written if the response is an error or longer than
+// the request's MaxResponseSize.
+func (c *Client) sendRaw(request *fqlRequest, ep endpointConfig, body []byte, w io.Writer) (int64, error) {
+	_, r, err := c.post(request, ep, body)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		resBuf := getBuffer()
+		defer putBuffer(resBuf)
+
+		if readErr := readLimited(resBuf, r.Body, request.MaxResponseSize); readErr != nil {
+			return 0, readErr
+		}
+
+		var res queryResponse
+		if unmarshalErr := json.Unmarshal(resBuf.Bytes(), &res); unmarshalErr != nil {
+			return 0, fmt.Errorf("failed to umarmshal response: %w", unmarshalErr)
+		}
+		res.Header = r.Header
+		res.RequestID = request.SentHeader.Get(HeaderRequestID)
+		if request.PlainSummary {
+			res.stripFormatting()
+		}
+
+		if serviceErr := c.codec.getErrFauna(r.StatusCode, &res); serviceErr != nil {
+			return 0, withProvenance(request, c.retriesExhausted(request, serviceErr))
+		}
+
+		return 0, fmt.Errorf("unexpected response status %d", r.StatusCode)
+	}
+
+	limit := request.MaxResponseSize
+	if limit > 0 && r.ContentLength > limit {
+		return 0, ErrResponseTooLarge{Limit: limit}
+	}
+
+	// without a length, the response has to be read to know it fits
+	if limit > 0 && r.ContentLength < 0 {
+		resBuf := getBuffer()
+		defer putBuffer(resBuf)
+
+		if readErr := readLimited(resBuf, r.Body, limit); readErr != nil {
+			return 0, readErr
+		}
+
+		n, writeErr := resBuf.WriteTo(w)
+		if writeErr != nil {
+			return n, fmt.Errorf("failed to stream response body: %w", writeErr)
+		}
+		return n, nil
+	}
+
+	n, copyErr := io.Copy(w, r.Body)
+	if copyErr != nil {
+		return n, fmt.Errorf("failed to stream response body: %w", copyErr)
+	}
+
+	return n, nil
+}
+
+// send runs the encoded request body, returning the response if it was
+// successful.
+func (c *Client) send(request *fqlRequest, ep endpointConfig, body []byte) (*queryResponse, int, error) {
+	attempts, r, err := c.post(request, ep, body)
+	if err != nil {
+		return nil, attempts, err
+	}
+	defer r.Body.Close()
+
+	var res queryResponse
+
+	resBuf := getBuffer()
+	defer putBuffer(resBuf)
+
+	if readErr := readLimited(resBuf, r.Body, request.MaxResponseSize); readErr != nil {
+		return nil, attempts, readErr
+	}
+
+	// res.Data is copied out of resBuf, so the buffer can be reused once the
+	// response is decoded
+	if unmarshalErr := json.Unmarshal(resBuf.Bytes(), &res); unmarshalErr != nil {
+		return nil, attempts, fmt.Errorf("failed to umarmshal response: %w", unmarshalErr)
+	}
+
+	c.syncTxnTime(res.TxnTime)
+	res.Header = r.Header
+	res.RequestID = request.SentHeader.Get(HeaderRequestID)
+	if request.PlainSummary {
+		res.stripFormatting()
+	}
+
+	if serviceErr := c.codec.getErrFauna(r.StatusCode, &res); serviceErr != nil {
+		return nil, attempts, c.retriesExhausted(request, serviceErr)
+	}
+
+	return &res, attempts, nil
+}
+
+// post sends the encoded request body to the query endpoint, retrying if
+// appropriate. The caller must close the response body.
+func (c *Client) post(request *fqlRequest, ep endpointConfig, body []byte) (int, *http.Response, error) {
+	reqURL, urlErr := url.Parse(ep.url)
 	if urlErr != nil {
-		return nil, urlErr
+		return 0, nil, urlErr
 	}
 
 	if path, err := url.JoinPath(reqURL.Path, "query", "1"); err != nil {
-		return nil, err
+		return 0, nil, err
 	} else {
 		reqURL.Path = path
 	}
 
-	req, reqErr := http.NewRequestWithContext(request.Context, http.MethodPost, reqURL.String(), bytes.NewReader(bytesOut))
+	ctx, done, closedErr := c.inflight.start(request.Context)
+	if closedErr != nil {
+		return 0, nil, closedErr
+	}
+
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodPost, reqURL.String(), bytes.NewReader(body))
 	if reqErr != nil {
-		return nil, fmt.Errorf("failed to init request: %w", reqErr)
+		done()
+		return 0, nil, fmt.Errorf("failed to init request: %w", reqErr)
 	}
 
-	req.Header.Set(headerAuthorization, `Bearer `+c.secret)
+	req.Header.Set(headerAuthorization, `Bearer `+ep.secret)
 	if lastTxnTs := c.lastTxnTime.string(); lastTxnTs != "" {
 		req.Header.Set(HeaderLastTxnTs, lastTxnTs)
 	}
 
+	sc, ok := SpanContextFromContext(request.Context)
+	if request.SpanContext.IsValid() {
+		sc, ok = request.SpanContext, true
+	} else if !ok && c.spanContext.IsValid() {
+		// the span of the request a middleware client was derived for
+		sc, ok = c.spanContext, true
+	}
+	if ok && c.propagator != nil {
+		c.propagator.Inject(sc, req.Header)
+	}
+
 	for k, v := range request.Headers {
 		req.Header.Set(k, v)
 	}
 
-	attempts, r, doErr := c.doWithRetry(req, 0)
+	if deadline, ok := request.Context.Deadline(); ok {
+		capQueryTimeout(req.Header, time.Until(deadline))
+	}
+
+	releaseLimit, limitErr := c.limit(ctx)
+	if limitErr != nil {
+		done()
+		return 0, nil, limitErr
+	}
+	release := func() {
+		releaseLimit()
+		done()
+	}
+
+	start := time.Now()
+	history, r, doErr := c.doWithRetry(req, c.attemptLimit(request))
+	attempts := len(history)
+	request.Attempts, request.History, request.SentHeader = attempts, history, req.Header
 	if doErr != nil {
-		return nil, ErrNetwork(fmt.Errorf("network error: %w", doErr))
+		release()
+		return attempts, nil, c.retriesExhausted(request, newErrNetwork(req, attempts, start, doErr))
 	}
+	r.Body = &releaseBody{ReadCloser: r.Body, release: release}
 
-	var res queryResponse
+	return attempts, r, nil
+}
+
+// attemptLimit returns the most attempts to make at sending request.
+func (c *Client) attemptLimit(request *fqlRequest) int {
+	if request.NoRetries {
+		return 1
+	}
+	return c.maxAttempts
+}
 
-	bin, readErr := io.ReadAll(r.Body)
-	if readErr != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", readErr)
+// retriesExhausted wraps err in an [ErrRetriesExhausted] if request failed
+// after being retried up to the client's MaxAttempts.
+func (c *Client) retriesExhausted(request *fqlRequest, err error) error {
+	if limit := c.attemptLimit(request); limit < 2 || len(request.History) < limit {
+		return err
 	}
+	return &ErrRetriesExhausted{Attempts: request.History, Err: err}
+}
 
-	if unmarshalErr := json.Unmarshal(bin, &res); unmarshalErr != nil {
-		return nil, fmt.Errorf("failed to umarmshal response: %w", unmarshalErr)
+// capQueryTimeout lowers the request's query timeout to remaining, the time
+// left before the caller's context expires, so Fauna stops running a query
+// the caller has given up on. The HTTP request itself is canceled with the
+// context.
+func capQueryTimeout(header http.Header, remaining time.Duration) {
+	ms := remaining.Milliseconds()
+	if ms < 1 {
+		ms = 1
 	}
 
-	c.lastTxnTime.sync(res.TxnTime)
-	res.Header = r.Header
+	if current, err := strconv.ParseInt(header.Get(HeaderQueryTimeoutMs), 10, 64); err == nil && current <= ms {
+		return
+	}
+
+	header.Set(HeaderQueryTimeoutMs, strconv.FormatInt(ms, 10))
+}
+
+// readLimited reads body into buf, failing with [fauna.ErrResponseTooLarge]
+// if it's longer than limit bytes. A limit of zero or less reads all of body.
+func readLimited(buf *bytes.Buffer, body io.Reader, limit int64) error {
+	if limit > 0 {
+		body = io.LimitReader(body, limit+1)
+	}
+
+	if _, readErr := buf.ReadFrom(body); readErr != nil {
+		return fmt.Errorf("failed to read response body: %w", readErr)
+	}
 
-	if serviceErr := getErrFauna(r.StatusCode, &res); serviceErr != nil {
-		return nil, serviceErr
+	if limit > 0 && int64(buf.Len()) > limit {
+		return ErrResponseTooLarge{Limit: limit}
 	}
 
-	data, decodeErr := decode(res.Data)
-	if decodeErr != nil {
-		return nil, fmt.Errorf("failed to decode data: %w", decodeErr)
+	return nil
+}
+
+// result decodes a successful response.
+func (c *Client) result(request *fqlRequest, res *queryResponse, attempts int) (*QuerySuccess, error) {
+	var data any
+	if request.Into != nil {
+		if decodeErr := c.codec.decodeDirect(res.Data, request.Into); decodeErr != nil {
+			return nil, fmt.Errorf("failed to decode data: %w", decodeErr)
+		}
+	} else {
+		var decodeErr error
+		if data, decodeErr = c.codec.decode(res.Data); decodeErr != nil {
+			return nil, fmt.Errorf("failed to decode data: %w", decodeErr)
+		}
 	}
 
 	ret := &QuerySuccess{
-		QueryInfo:  newQueryInfo(&res),
+		QueryInfo:  newQueryInfo(res),
 		Data:       data,
 		StaticType: res.StaticType,
+		codec:      c.codec,
+	}
+	if ret.Stats == nil {
+		ret.Stats = &Stats{}
 	}
 	ret.Stats.Attempts = attempts
 
 	return ret, nil
 }
+
+// requestKey canonicalizes a request for the [fauna.QueryCache] and read
+// coalescing. The body is already canonical as the encoder sorts object keys,
+// so it's combined with the secret and headers.
+func requestKey(secret string, reqHeaders map[string]string, body []byte) string {
+	headers := make([]string, 0, len(reqHeaders))
+	for k, v := range reqHeaders {
+		switch k {
+		case HeaderTraceparent, HeaderTags:
+			continue
+		}
+		headers = append(headers, k+"\x00"+v)
+	}
+	sort.Strings(headers)
+
+	h := sha256.New()
+	h.Write([]byte(secret))
+	h.Write([]byte{0})
+	for _, header := range headers {
+		h.Write([]byte(header))
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
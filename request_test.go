@@ -0,0 +1,472 @@
+package fauna
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufferPool(t *testing.T) {
+	buf := getBuffer()
+	buf.WriteString("leftover")
+	putBuffer(buf)
+
+	assert.Zero(t, getBuffer().Len(), "pooled buffers should be reset")
+
+	huge := getBuffer()
+	huge.Grow(maxPooledBufferSize + 1)
+	huge.WriteString("leftover")
+	putBuffer(huge)
+	assert.Equal(t, "leftover", huge.String(), "oversized buffers are dropped rather than reset")
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	body := successBody(`"` + string(bytes.Repeat([]byte("a"), 100)) + `"`)
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, body
+	})
+	client := srv.client()
+	q, _ := FQL(`"a" * 100`, nil)
+
+	_, err := client.Query(q, MaxResponseSize(int64(len(body))))
+	assert.NoError(t, err)
+
+	_, err = client.Query(q, MaxResponseSize(int64(len(body)-1)))
+	var tooLarge ErrResponseTooLarge
+	if assert.ErrorAs(t, err, &tooLarge) {
+		assert.Equal(t, int64(len(body)-1), tooLarge.Limit)
+	}
+}
+
+func TestQueryRaw(t *testing.T) {
+	body := successBody(`{"@int":"1"}`)
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		if bytes.Contains(req.Raw, []byte("abort")) {
+			return http.StatusBadRequest, `{"error":{"code":"abort","message":"aborted","abort":"oops"},"summary":""}`
+		}
+		return http.StatusOK, body
+	})
+	client := srv.client()
+
+	t.Run("streams the response", func(t *testing.T) {
+		q, _ := FQL(`1`, nil)
+		var out bytes.Buffer
+		n, err := client.QueryRaw(q, &out)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(body)), n)
+		assert.Equal(t, body, out.String())
+	})
+
+	t.Run("returns query errors", func(t *testing.T) {
+		q, _ := FQL(`abort("oops")`, nil)
+		var out bytes.Buffer
+		_, err := client.QueryRaw(q, &out)
+		var abortErr *ErrAbort
+		assert.ErrorAs(t, err, &abortErr)
+		assert.Zero(t, out.Len())
+	})
+
+	t.Run("limits the response size", func(t *testing.T) {
+		q, _ := FQL(`1`, nil)
+
+		var out bytes.Buffer
+		_, err := client.QueryRaw(q, &out, MaxResponseSize(int64(len(body))))
+		assert.NoError(t, err)
+
+		out.Reset()
+		n, err := client.QueryRaw(q, &out, MaxResponseSize(10))
+		assert.ErrorAs(t, err, &ErrResponseTooLarge{})
+		assert.Zero(t, n)
+		assert.Zero(t, out.Len(), "nothing is written")
+	})
+
+	t.Run("limits responses without a length", func(t *testing.T) {
+		// bodies too large to buffer are sent without a Content-Length
+		long := successBody(`"` + strings.Repeat("a", 8192) + `"`)
+		srv := newTestServer(t, func(testRequest) (int, string) {
+			return http.StatusOK, long
+		})
+		client := srv.client()
+		q, _ := FQL(`1`, nil)
+
+		var out bytes.Buffer
+		n, err := client.QueryRaw(q, &out, MaxResponseSize(int64(len(long))))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(long)), n)
+		assert.Equal(t, long, out.String())
+
+		out.Reset()
+		n, err = client.QueryRaw(q, &out, MaxResponseSize(int64(len(long)-1)))
+		assert.ErrorAs(t, err, &ErrResponseTooLarge{})
+		assert.Zero(t, n)
+		assert.Zero(t, out.Len(), "nothing is written")
+	})
+
+	t.Run("records every outcome", func(t *testing.T) {
+		srv := newTestServer(t, func(testRequest) (int, string) {
+			return http.StatusBadRequest, `not json`
+		})
+		client := srv.client()
+		q, _ := FQL(`1`, nil)
+
+		_, err := client.QueryRaw(q, io.Discard)
+		assert.Error(t, err)
+		_, err = client.QueryRaw(q, io.Discard, MaxResponseSize(1))
+		assert.ErrorAs(t, err, &ErrResponseTooLarge{})
+
+		stats := client.StatsSnapshot()
+		assert.Equal(t, int64(2), stats.Queries)
+		assert.Equal(t, int64(2), stats.Failed)
+	})
+}
+
+func BenchmarkQuery(b *testing.B) {
+	srv := newTestServer(b, func(testRequest) (int, string) {
+		return http.StatusOK, successBody(`{"name":"Scout","tags":["good","dog"],"age":{"@int":"3"}}`)
+	})
+	client := srv.client()
+
+	q, _ := FQL(`Dogs.byId(${id})`, map[string]any{"id": "123"})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Query(q); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestQueryHeader(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, successBody(`null`)
+	})
+	client := srv.client()
+	q, _ := FQL(`null`, nil)
+
+	_, err := client.Query(q, Header("x-gateway-route", "dogs"), Tags(map[string]string{"a": "1"}))
+	assert.NoError(t, err)
+
+	_, err = client.Query(q)
+	assert.NoError(t, err)
+
+	received := srv.received()
+	if assert.Len(t, received, 2) {
+		assert.Equal(t, "dogs", received[0].Header.Get("X-Gateway-Route"))
+		assert.Equal(t, "a=1", received[0].Header.Get(HeaderTags))
+		assert.Empty(t, received[1].Header.Get("X-Gateway-Route"), "per-query headers don't leak into the client")
+		assert.Empty(t, received[1].Header.Get(HeaderTags))
+	}
+
+	for name, opt := range map[string]QueryOptFn{
+		"reserved":      Header("authorization", "Bearer other"),
+		"invalid name":  Header("x gateway", "dogs"),
+		"invalid value": Header("X-Gateway-Route", "dogs\r\nX-Injected: 1"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := client.Query(q, opt)
+			assert.Error(t, err)
+			assert.Len(t, srv.received(), 2, "the query isn't sent")
+		})
+	}
+}
+
+func TestContextDeadlineTimeout(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, successBody(`null`)
+	})
+	client := srv.client()
+	q, _ := FQL(`null`, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, err := client.Query(q, QueryContext(ctx))
+	assert.NoError(t, err)
+
+	_, err = client.Query(q, QueryContext(ctx), Timeout(100*time.Millisecond))
+	assert.NoError(t, err)
+
+	_, err = client.Query(q)
+	assert.NoError(t, err)
+
+	received := srv.received()
+	if assert.Len(t, received, 3) {
+		ms, err := strconv.Atoi(received[0].Header.Get(HeaderQueryTimeoutMs))
+		assert.NoError(t, err)
+		assert.True(t, ms > 0 && ms <= 2000, "the timeout is lowered to the deadline, got %d", ms)
+
+		assert.Equal(t, "100", received[1].Header.Get(HeaderQueryTimeoutMs), "shorter timeouts are kept")
+		assert.Equal(t, "5000", received[2].Header.Get(HeaderQueryTimeoutMs))
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		return http.StatusOK, `{"data":null,"summary":"","txn_ts":1,"stats":{},"query_tags":"` + req.Header.Get(HeaderTags) + `"}`
+	})
+	client := srv.client(
+		QueryTags(map[string]string{"service": "api", "version": "1"}),
+		QueryTags(map[string]string{"version": "2"}),
+	)
+	q, _ := FQL(`null`, nil)
+
+	res, err := client.Query(q, Tags(map[string]string{"request": "r/1"}), Tags(map[string]string{"service": "worker", "user": "u=1"}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{"request": "r/1", "service": "worker", "user": "u=1", "version": "2"}, res.QueryTags)
+	}
+
+	_, err = client.Query(q)
+	assert.NoError(t, err)
+
+	received := srv.received()
+	if assert.Len(t, received, 2) {
+		assert.Equal(t, "request=r%2F1,service=worker,user=u%3D1,version=2", received[0].Header.Get(HeaderTags))
+		assert.Equal(t, "service=api,version=2", received[1].Header.Get(HeaderTags), "query tags don't change the client's")
+	}
+}
+
+func TestResponseHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderTraceparent, "00-0af7651916cd43dd8448eb211c80319c-00f067aa0ba902b7-01")
+		w.Header().Set("X-Txn-Time", "1680000000000000")
+		if bytes.Contains([]byte(r.URL.RawQuery), []byte("fail")) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(errorBody("invalid_query", "bad query")))
+			return
+		}
+		_, _ = w.Write([]byte(successBody(`null`)))
+	}))
+	defer srv.Close()
+
+	client := NewClient("secret", DefaultTimeouts(), URL(srv.URL))
+	q, _ := FQL(`null`, nil)
+
+	res, err := client.Query(q)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-00f067aa0ba902b7-01", res.Traceparent())
+		assert.Equal(t, "1680000000000000", res.Header.Get("X-Txn-Time"))
+		assert.Equal(t, int64(1680000000000000), res.TxnTime)
+	}
+
+	_, err = NewClient("secret", DefaultTimeouts(), URL(srv.URL+"?fail")).Query(q)
+	var checkErr *ErrQueryCheck
+	if assert.ErrorAs(t, err, &checkErr) {
+		assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-00f067aa0ba902b7-01", checkErr.Traceparent())
+	}
+}
+
+func TestRetries(t *testing.T) {
+	var throttle int32
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		if atomic.AddInt32(&throttle, -1) >= 0 {
+			return http.StatusTooManyRequests, errorBody("limit_exceeded", "too many requests")
+		}
+		return http.StatusOK, successBody(`null`)
+	})
+	q, _ := FQL(`null`, nil)
+
+	t.Run("retries throttled queries", func(t *testing.T) {
+		atomic.StoreInt32(&throttle, 1)
+		before := len(srv.received())
+
+		res, err := srv.client(MaxBackoff(time.Millisecond)).Query(q)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 2, res.Stats.Attempts)
+		}
+
+		received := srv.received()[before:]
+		if assert.Len(t, received, 2) {
+			assert.Equal(t, received[0].Raw, received[1].Raw, "the body is resent")
+		}
+	})
+
+	t.Run("stops at max attempts", func(t *testing.T) {
+		atomic.StoreInt32(&throttle, 10)
+		before := len(srv.received())
+
+		_, err := srv.client(MaxAttempts(3), MaxBackoff(time.Millisecond)).Query(q)
+		assert.ErrorAs(t, err, new(*ErrThrottling))
+		assert.Len(t, srv.received()[before:], 3)
+
+		var exhausted *ErrRetriesExhausted
+		if assert.ErrorAs(t, err, &exhausted) && assert.Len(t, exhausted.Attempts, 3) {
+			for i, attempt := range exhausted.Attempts {
+				assert.Equal(t, http.StatusTooManyRequests, attempt.StatusCode)
+				assert.NoError(t, attempt.Err)
+				assert.False(t, attempt.Time.IsZero())
+				if i > 0 {
+					assert.False(t, attempt.Time.Before(exhausted.Attempts[i-1].Time))
+				}
+			}
+			assert.Zero(t, exhausted.Attempts[2].Delay)
+			assert.Contains(t, exhausted.Error(), "gave up after 3 attempts [429, 429, 429]")
+		}
+	})
+
+	for name, client := range map[string]func() (*QuerySuccess, error){
+		"client":    func() (*QuerySuccess, error) { return srv.client(NoRetries()).Query(q) },
+		"per query": func() (*QuerySuccess, error) { return srv.client().Query(q, QueryNoRetries()) },
+	} {
+		t.Run("no retries "+name, func(t *testing.T) {
+			atomic.StoreInt32(&throttle, 1)
+			before := len(srv.received())
+
+			_, err := client()
+			assert.ErrorAs(t, err, new(*ErrThrottling))
+			assert.False(t, errors.As(err, new(*ErrRetriesExhausted)), "a query which isn't retried isn't exhausted")
+			assert.Len(t, srv.received()[before:], 1)
+		})
+	}
+}
+
+func TestWith(t *testing.T) {
+	var throttle int32
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		if atomic.AddInt32(&throttle, -1) >= 0 {
+			return http.StatusTooManyRequests, errorBody("limit_exceeded", "too many requests")
+		}
+		return http.StatusOK, successBody(`null`)
+	})
+	client := srv.client(MaxBackoff(time.Millisecond), QueryTags(map[string]string{"service": "api"}))
+	derived := client.With(NoRetries(), DefaultTypecheck(false), QueryTags(map[string]string{"job": "report"}))
+	q, _ := FQL(`null`, nil)
+
+	_, err := derived.Query(q)
+	assert.NoError(t, err)
+	_, err = client.Query(q)
+	assert.NoError(t, err)
+
+	received := srv.received()
+	if assert.Len(t, received, 2) {
+		assert.Equal(t, "false", received[0].Header.Get(HeaderTypecheck))
+		assert.Equal(t, "job=report,service=api", received[0].Header.Get(HeaderTags))
+		assert.Empty(t, received[1].Header.Get(HeaderTypecheck), "the client is unchanged")
+		assert.Equal(t, "service=api", received[1].Header.Get(HeaderTags))
+		assert.Equal(t, received[0].RemoteAddr, received[1].RemoteAddr, "the connection is shared")
+	}
+
+	atomic.StoreInt32(&throttle, 1)
+	_, err = derived.Query(q)
+	assert.ErrorAs(t, err, new(*ErrThrottling), "the derived client doesn't retry")
+	atomic.StoreInt32(&throttle, 1)
+	_, err = client.Query(q)
+	assert.NoError(t, err, "the client still retries")
+
+	assert.Equal(t, client.GetLastTxnTime(), derived.GetLastTxnTime())
+	assert.Equal(t, int64(4), client.StatsSnapshot().Queries, "stats are shared")
+}
+
+func TestAppName(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		if req.URL.Path == "/schema/1/files" {
+			return http.StatusOK, `{"version":1,"files":[]}`
+		}
+		return http.StatusOK, successBody(`null`)
+	})
+	client := srv.client(AppName("billing service", "1.2.0"))
+
+	q, _ := FQL(`null`, nil)
+	_, err := client.Query(q)
+	assert.NoError(t, err)
+	_, err = client.PullSchema(context.Background())
+	assert.NoError(t, err)
+
+	for _, req := range srv.received() {
+		assert.Equal(t, "fauna-go/"+strings.TrimSpace(driverVersion)+" billing_service/1.2.0", req.Header.Get(headerUserAgent), req.URL.Path)
+		assert.True(t, strings.HasSuffix(req.Header.Get(headerDriverEnv), "; app=billing_service/1.2.0"), req.URL.Path)
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	var throttle int32 = 1
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		if atomic.AddInt32(&throttle, -1) >= 0 {
+			return http.StatusTooManyRequests, errorBody("limit_exceeded", "too many requests")
+		}
+		return http.StatusOK, successBody(`null`)
+	})
+	client := srv.client(MaxBackoff(time.Millisecond))
+	q, _ := FQL(`Dogs.create({ name: "Scout" })`, nil)
+
+	_, err := client.Query(q, IdempotencyKey("create-scout"))
+	assert.NoError(t, err)
+
+	generated := IdempotencyKey("")
+	_, err = client.Query(q, generated)
+	assert.NoError(t, err)
+	_, err = client.Query(q, generated)
+	assert.NoError(t, err)
+
+	received := srv.received()
+	if assert.Len(t, received, 4) {
+		assert.Equal(t, "create-scout", received[0].Header.Get(HeaderIdempotencyKey))
+		assert.Equal(t, "create-scout", received[1].Header.Get(HeaderIdempotencyKey), "retries reuse the key")
+
+		assert.Len(t, received[2].Header.Get(HeaderIdempotencyKey), 32)
+		assert.NotEqual(t, received[2].Header.Get(HeaderIdempotencyKey), received[3].Header.Get(HeaderIdempotencyKey))
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	var throttle int32 = 1
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		if atomic.AddInt32(&throttle, -1) >= 0 {
+			return http.StatusTooManyRequests, errorBody("limit_exceeded", "too many requests")
+		}
+		if req.Body["query"].(map[string]any)["fql"].([]any)[0] == "abort" {
+			return http.StatusBadRequest, errorBody("abort", "aborted")
+		}
+		return http.StatusOK, successBody(`null`)
+	})
+
+	var hooked string
+	client := srv.client(MaxBackoff(time.Millisecond), WithErrorHook(func(err error, info *QueryErrorInfo) error {
+		hooked = info.RequestID
+		return nil
+	}))
+
+	q, _ := FQL(`null`, nil)
+	res, err := client.Query(q)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	abort, _ := FQL(`abort`, nil)
+	_, err = client.Query(abort)
+	var abortErr *ErrAbort
+	assert.ErrorAs(t, err, &abortErr)
+
+	_, err = client.Query(q, Header(HeaderRequestID, "support-1234"))
+	assert.NoError(t, err)
+
+	received := srv.received()
+	if assert.Len(t, received, 4) {
+		first, retry := received[0].Header.Get(HeaderRequestID), received[1].Header.Get(HeaderRequestID)
+		assert.Len(t, first, 32)
+		assert.NotEqual(t, first, retry, "each attempt has its own ID")
+		assert.Equal(t, retry, res.RequestID, "the result has the ID of the attempt that got it")
+
+		assert.Equal(t, received[2].Header.Get(HeaderRequestID), abortErr.RequestID)
+		assert.Equal(t, abortErr.RequestID, hooked)
+
+		assert.Equal(t, "support-1234", received[3].Header.Get(HeaderRequestID), "a caller's ID is kept")
+	}
+
+	client = NewClient("secret", DefaultTimeouts(), URL("http://127.0.0.1:1"))
+	_, err = client.Query(q)
+	var netErr *ErrNetwork
+	if assert.ErrorAs(t, err, &netErr) {
+		assert.Len(t, netErr.RequestID, 32)
+	}
+}
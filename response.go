@@ -1,5 +1,12 @@
 package fauna
 
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
 // Stats provides access to stats generated by the query.
 type Stats struct {
 	// ComputeOps is the amount of Transactional Compute Ops consumed by the query.
@@ -24,10 +31,84 @@ type Stats struct {
 	// StorageBytesWrite is the amount of data written to storage, in bytes.
 	StorageBytesWrite int `json:"storage_bytes_write"`
 
+	// ProcessingTimeMs is the time spent processing the query, including
+	// time waiting for contention and rate limits, in milliseconds.
+	ProcessingTimeMs int `json:"processing_time_ms"`
+
+	// RateLimitsHit lists the rate limits the query hit, such as `read`,
+	// `write` or `compute`.
+	RateLimitsHit []string `json:"rate_limits_hit,omitempty"`
+
 	// Attempts is the number of times the client attempted to run the query.
-	Attempts int `json:"_"`
+	Attempts int `json:"-"`
+
+	// Extra holds the stats this version of the driver doesn't know about,
+	// as sent by Fauna.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// stats has the fields of [fauna.Stats] without its JSON methods.
+type stats Stats
+
+// UnmarshalJSON decodes the known stats into their fields, keeping the rest in
+// [fauna.Stats.Extra].
+func (s *Stats) UnmarshalJSON(data []byte) error {
+	var known stats
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+
+	for _, name := range statsFields {
+		delete(all, name)
+	}
+	if len(all) > 0 {
+		known.Extra = all
+	}
+
+	*s = Stats(known)
+	return nil
+}
+
+// MarshalJSON encodes the stats as Fauna sends them, including
+// [fauna.Stats.Extra].
+func (s Stats) MarshalJSON() ([]byte, error) {
+	bin, err := json.Marshal(stats(s))
+	if err != nil || len(s.Extra) == 0 {
+		return bin, err
+	}
+
+	all := make(map[string]json.RawMessage, len(s.Extra)+len(statsFields))
+	if err := json.Unmarshal(bin, &all); err != nil {
+		return nil, err
+	}
+	for name, val := range s.Extra {
+		if _, known := all[name]; !known {
+			all[name] = val
+		}
+	}
+
+	return json.Marshal(all)
 }
 
+// statsFields are the JSON names of the fields of [fauna.Stats].
+var statsFields = func() []string {
+	t := reflect.TypeOf(Stats{})
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}()
+
 // QueryInfo provides access to information about the query.
 type QueryInfo struct {
 	// TxnTime is the transaction commit time in micros since epoch. Used to
@@ -48,6 +129,30 @@ type QueryInfo struct {
 
 	// Stats provides access to stats generated by the query.
 	Stats *Stats
+
+	// Cached is true if the result was served from the [fauna.QueryCache]
+	// rather than Fauna.
+	Cached bool
+
+	// Header is the HTTP headers of the response, which must not be
+	// modified. Results served from the [fauna.QueryCache] have the headers
+	// of the response that was cached.
+	Header http.Header
+
+	// RequestID is the `x-request-id` header sent with the attempt that got
+	// the response, to correlate the query with Fauna's logs and support
+	// tickets.
+	RequestID string
+}
+
+// Traceparent is the `traceparent` response header, identifying the span
+// Fauna ran the query in, or empty if there wasn't one.
+func (i *QueryInfo) Traceparent() string {
+	if i == nil {
+		return ""
+	}
+
+	return i.Header.Get(HeaderTraceparent)
 }
 
 func newQueryInfo(res *queryResponse) *QueryInfo {
@@ -57,6 +162,8 @@ func newQueryInfo(res *queryResponse) *QueryInfo {
 		Summary:       res.Summary,
 		QueryTags:     res.queryTags(),
 		Stats:         res.Stats,
+		Header:        res.Header,
+		RequestID:     res.RequestID,
 	}
 }
 
@@ -71,10 +178,14 @@ type QuerySuccess struct {
 	// StaticType is the query's inferred static result type, if the query was
 	// typechecked.
 	StaticType string
+
+	// codec decodes Data in Unmarshal as the client which ran the query
+	// would.
+	codec codec
 }
 
 // Unmarshal will unmarshal the raw [fauna.QuerySuccess.Data] value into a
 // known type provided as `into`. `into` must be a pointer to a map or struct.
 func (r *QuerySuccess) Unmarshal(into any) error {
-	return decodeInto(r.Data, into)
+	return r.codec.decodeInto(r.Data, into)
 }
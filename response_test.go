@@ -0,0 +1,50 @@
+package fauna
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, `{"data":null,"summary":"","txn_ts":1,"stats":{"compute_ops":1,"read_ops":2,"write_ops":3,"query_time_ms":4,"contention_retries":5,"storage_bytes_read":6,"storage_bytes_write":7,"processing_time_ms":8,"rate_limits_hit":["read"],"cache_hits":{"@int":"9"}}}`
+	})
+
+	q, _ := FQL(`null`, nil)
+	res, err := srv.client().Query(q)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, &Stats{
+		ComputeOps:        1,
+		ReadOps:           2,
+		WriteOps:          3,
+		QueryTimeMs:       4,
+		ContentionRetries: 5,
+		StorageBytesRead:  6,
+		StorageBytesWrite: 7,
+		ProcessingTimeMs:  8,
+		RateLimitsHit:     []string{"read"},
+		Attempts:          1,
+		Extra:             map[string]json.RawMessage{"cache_hits": json.RawMessage(`{"@int":"9"}`)},
+	}, res.Stats)
+
+	bin, err := json.Marshal(res.Stats)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"compute_ops":1,"read_ops":2,"write_ops":3,"query_time_ms":4,"contention_retries":5,"storage_bytes_read":6,"storage_bytes_write":7,"processing_time_ms":8,"rate_limits_hit":["read"],"cache_hits":{"@int":"9"}}`, string(bin))
+
+	t.Run("missing stats", func(t *testing.T) {
+		srv := newTestServer(t, func(testRequest) (int, string) {
+			return http.StatusOK, `{"data":null,"summary":"","txn_ts":1}`
+		})
+
+		res, err := srv.client().Query(q)
+		if assert.NoError(t, err) {
+			assert.Equal(t, &Stats{Attempts: 1}, res.Stats)
+		}
+	})
+}
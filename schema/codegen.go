@@ -0,0 +1,163 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// documentFields are the metadata fields every document has, which generated
+// structs get by embedding [fauna.Document].
+var documentFields = map[string]bool{"id": true, "coll": true, "ts": true, "ttl": true}
+
+// commonInitialisms are written in upper case in generated Go names.
+var commonInitialisms = map[string]bool{
+	"api": true, "id": true, "ip": true, "json": true, "ttl": true, "uri": true, "url": true, "uuid": true,
+}
+
+// GenerateGo emits Go source declaring package pkg with a struct for each
+// collection, built from its declared fields and computed fields, and a
+// function returning a typed [fauna.Collection] for it. Collections without a
+// document type only get the embedded [fauna.Document] fields.
+func GenerateGo(pkg string, collections []Collection) ([]byte, error) {
+	sorted := append([]Collection{}, collections...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	names := make(map[string]bool, len(sorted))
+	for _, coll := range sorted {
+		names[coll.Name] = true
+	}
+
+	var body bytes.Buffer
+	usesTime := false
+
+	for _, coll := range sorted {
+		typeName := singular(goName(coll.Name))
+
+		fields := make(map[string]string, len(coll.Fields)+len(coll.ComputedFields))
+		for name, field := range coll.Fields {
+			fields[name] = field.Signature
+		}
+		for name, field := range coll.ComputedFields {
+			fields[name] = field.Signature
+		}
+
+		fieldNames := make([]string, 0, len(fields))
+		for name := range fields {
+			if !documentFields[name] {
+				fieldNames = append(fieldNames, name)
+			}
+		}
+		sort.Strings(fieldNames)
+
+		fmt.Fprintf(&body, "// %s is a document in the %s collection.\n", typeName, coll.Name)
+		fmt.Fprintf(&body, "type %s struct {\n\tfauna.Document\n", typeName)
+		for _, name := range fieldNames {
+			goType := signatureType(fields[name], names)
+			usesTime = usesTime || strings.Contains(goType, "time.Time")
+			fmt.Fprintf(&body, "\t%s %s `fauna:\"%s\"`\n", goName(name), goType, name)
+		}
+		fmt.Fprintf(&body, "}\n\n")
+
+		fmt.Fprintf(&body, "// %sCollection returns a typed [fauna.Collection] for the %s collection.\n", goName(coll.Name), coll.Name)
+		fmt.Fprintf(&body, "func %sCollection(client *fauna.Client) *fauna.Collection[%s] {\n", goName(coll.Name), typeName)
+		fmt.Fprintf(&body, "\treturn fauna.NewCollection[%s](client, %q)\n}\n\n", typeName, coll.Name)
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by fauna-gen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkg)
+	if usesTime {
+		fmt.Fprintf(&src, "\t\"time\"\n\n")
+	}
+	fmt.Fprintf(&src, "\t\"github.com/fauna/fauna-go\"\n)\n\n")
+	src.Write(body.Bytes())
+
+	out, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated source: %w", err)
+	}
+
+	return out, nil
+}
+
+// signatureType maps an FQL type signature to the Go type it decodes into.
+// References to collections decode into [fauna.Ref], and types without a Go
+// equivalent decode into any.
+func signatureType(signature string, collections map[string]bool) string {
+	signature = strings.TrimSpace(signature)
+
+	optional := false
+	if strings.HasSuffix(signature, "?") {
+		optional, signature = true, strings.TrimSuffix(signature, "?")
+	} else if parts := strings.Split(signature, "|"); len(parts) == 2 {
+		for i, part := range parts {
+			if strings.TrimSpace(part) == "Null" {
+				optional, signature = true, strings.TrimSpace(parts[1-i])
+			}
+		}
+	}
+
+	goType := "any"
+	switch {
+	case signature == "String":
+		goType = "string"
+	case signature == "Int":
+		goType = "int"
+	case signature == "Long":
+		goType = "int64"
+	case signature == "Double", signature == "Number":
+		goType = "float64"
+	case signature == "Boolean":
+		goType = "bool"
+	case signature == "Time", signature == "Date":
+		goType = "time.Time"
+	case strings.HasPrefix(signature, "Array<") && strings.HasSuffix(signature, ">"):
+		return "[]" + signatureType(signature[len("Array<"):len(signature)-1], collections)
+	case strings.HasPrefix(signature, "Ref<"), collections[signature]:
+		return "*fauna.Ref"
+	case strings.HasPrefix(signature, "{"):
+		return "map[string]any"
+	}
+
+	if optional && goType != "any" {
+		return "*" + goType
+	}
+
+	return goType
+}
+
+// goName converts a collection or field name such as `order_items` into an
+// exported Go name such as `OrderItems`.
+func goName(name string) string {
+	var out strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' }) {
+		if commonInitialisms[strings.ToLower(word)] {
+			out.WriteString(strings.ToUpper(word))
+			continue
+		}
+
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		out.WriteString(string(runes))
+	}
+
+	return out.String()
+}
+
+// singular naively singularizes an English plural, so collection `Dogs`
+// generates type `Dog`.
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "ss"), strings.HasSuffix(name, "us"):
+		return name
+	case strings.HasSuffix(name, "s") && len(name) > 1:
+		return strings.TrimSuffix(name, "s")
+	}
+
+	return name
+}
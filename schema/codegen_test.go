@@ -0,0 +1,84 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateGo(t *testing.T) {
+	src, err := GenerateGo("models", []Collection{
+		{
+			Name: "Orders",
+			Fields: map[string]Field{
+				"customer":   {Signature: "Customers"},
+				"line_items": {Signature: "Array<{ sku: String, qty: Int }>"},
+				"placed_at":  {Signature: "Time"},
+				"note":       {Signature: "String?"},
+				"total":      {Signature: "Double"},
+				"id":         {Signature: "ID"},
+			},
+			ComputedFields: map[string]ComputedField{
+				"item_count": {Body: "doc => doc.line_items.length", Signature: "Int"},
+			},
+		},
+		{Name: "Customers", Fields: map[string]Field{"api_key": {Signature: "String | Null"}}},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "// Code generated by fauna-gen. DO NOT EDIT.\n"+
+		"\n"+
+		"package models\n"+
+		"\n"+
+		"import (\n"+
+		"\t\"time\"\n"+
+		"\n"+
+		"\t\"github.com/fauna/fauna-go\"\n"+
+		")\n"+
+		"\n"+
+		"// Customer is a document in the Customers collection.\n"+
+		"type Customer struct {\n"+
+		"\tfauna.Document\n"+
+		"\tAPIKey *string `fauna:\"api_key\"`\n"+
+		"}\n"+
+		"\n"+
+		"// CustomersCollection returns a typed [fauna.Collection] for the Customers collection.\n"+
+		"func CustomersCollection(client *fauna.Client) *fauna.Collection[Customer] {\n"+
+		"\treturn fauna.NewCollection[Customer](client, \"Customers\")\n"+
+		"}\n"+
+		"\n"+
+		"// Order is a document in the Orders collection.\n"+
+		"type Order struct {\n"+
+		"\tfauna.Document\n"+
+		"\tCustomer  *fauna.Ref       `fauna:\"customer\"`\n"+
+		"\tItemCount int              `fauna:\"item_count\"`\n"+
+		"\tLineItems []map[string]any `fauna:\"line_items\"`\n"+
+		"\tNote      *string          `fauna:\"note\"`\n"+
+		"\tPlacedAt  time.Time        `fauna:\"placed_at\"`\n"+
+		"\tTotal     float64          `fauna:\"total\"`\n"+
+		"}\n"+
+		"\n"+
+		"// OrdersCollection returns a typed [fauna.Collection] for the Orders collection.\n"+
+		"func OrdersCollection(client *fauna.Client) *fauna.Collection[Order] {\n"+
+		"\treturn fauna.NewCollection[Order](client, \"Orders\")\n"+
+		"}\n", string(src))
+}
+
+func TestSignatureType(t *testing.T) {
+	for signature, want := range map[string]string{
+		"Long":           "int64",
+		"Boolean?":       "*bool",
+		"Any":            "any",
+		"Any?":           "any",
+		"Ref<Customers>": "*fauna.Ref",
+		"Array<String>":  "[]string",
+		"Array<Int?>":    "[]*int",
+		"Date":           "time.Time",
+		"{ *: Number }":  "map[string]any",
+		"String | Int":   "any",
+	} {
+		assert.Equal(t, want, signatureType(signature, nil), signature)
+	}
+}
@@ -0,0 +1,281 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fauna/fauna-go"
+)
+
+// Action is the kind of operation a [schema.Change] performs.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Definitions is the desired schema compared by [schema.Manager.Diff]. A nil
+// slice leaves that kind of resource unmanaged, while an empty, non-nil slice
+// means every existing resource of that kind should be deleted.
+type Definitions struct {
+	Collections []Collection
+	Functions   []Function
+	Roles       []Role
+}
+
+// FieldChange is a single top-level field of a definition that differs.
+type FieldChange struct {
+	Field   string
+	Current any
+	Desired any
+}
+
+// Change is a single operation needed to converge the live schema on the
+// desired [schema.Definitions].
+type Change struct {
+	Action   Action
+	Resource string
+	Name     string
+
+	// Fields lists the fields an update changes. It is empty for creates and
+	// deletes.
+	Fields []FieldChange
+
+	def any
+}
+
+// String describes the change, such as `update Collection Dogs: indexes`.
+func (c Change) String() string {
+	desc := fmt.Sprintf("%s %s %s", c.Action, c.Resource, c.Name)
+	if len(c.Fields) == 0 {
+		return desc
+	}
+
+	fields := make([]string, 0, len(c.Fields))
+	for _, field := range c.Fields {
+		fields = append(fields, field.Field)
+	}
+
+	return desc + ": " + strings.Join(fields, ", ")
+}
+
+// Query creates the FQL that performs the change.
+func (c Change) Query() (*fauna.Query, error) {
+	switch c.Action {
+	case ActionCreate:
+		return fauna.FQL(c.Resource+".create(${def})", map[string]any{"def": c.def})
+	case ActionUpdate:
+		return fauna.FQL(c.Resource+".byName(${name})!.update(${def})", map[string]any{"name": c.Name, "def": c.def})
+	case ActionDelete:
+		return fauna.FQL(c.Resource+".byName(${name})!.delete()\nnull", map[string]any{"name": c.Name})
+	}
+
+	return nil, fmt.Errorf("unknown schema action %q", c.Action)
+}
+
+// Plan is the ordered set of changes returned by [schema.Manager.Diff]. Creates
+// and updates are ordered collections, functions, then roles, so roles can
+// reference what they depend on, and deletes run in the reverse order.
+type Plan struct {
+	Changes []Change
+}
+
+// Empty reports whether the live schema already matches the definitions.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String lists the changes, one per line.
+func (p *Plan) String() string {
+	lines := make([]string, 0, len(p.Changes))
+	for _, change := range p.Changes {
+		lines = append(lines, change.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Diff compares desired against the live schema and returns the changes
+// needed to converge them, for review before calling [schema.Manager.Apply].
+//
+// Only fields set in a desired definition are compared, so fields Fauna
+// defaults, like a collection's `history_days`, don't produce changes unless
+// they are declared. Declared map fields such as `indexes` are authoritative:
+// entries missing from the desired definition are removed.
+func (m *Manager) Diff(desired Definitions) (*Plan, error) {
+	plan := &Plan{Changes: make([]Change, 0)}
+	var deletes [][]Change
+
+	if desired.Collections != nil {
+		live, err := m.ListCollections()
+		if err != nil {
+			return nil, err
+		}
+
+		changes, removed := diffResources(moduleCollection, desired.Collections, live, func(c Collection) string { return c.Name })
+		plan.Changes = append(plan.Changes, changes...)
+		deletes = append(deletes, removed)
+	}
+
+	if desired.Functions != nil {
+		live, err := m.ListFunctions()
+		if err != nil {
+			return nil, err
+		}
+
+		changes, removed := diffResources(moduleFunction, desired.Functions, live, func(f Function) string { return f.Name })
+		plan.Changes = append(plan.Changes, changes...)
+		deletes = append(deletes, removed)
+	}
+
+	if desired.Roles != nil {
+		live, err := m.ListRoles()
+		if err != nil {
+			return nil, err
+		}
+
+		changes, removed := diffResources(moduleRole, desired.Roles, live, func(r Role) string { return r.Name })
+		plan.Changes = append(plan.Changes, changes...)
+		deletes = append(deletes, removed)
+	}
+
+	for i := len(deletes) - 1; i >= 0; i-- {
+		plan.Changes = append(plan.Changes, deletes[i]...)
+	}
+
+	return plan, nil
+}
+
+// Apply runs each change in plan in order, stopping at the first that fails.
+func (m *Manager) Apply(plan *Plan) error {
+	for _, change := range plan.Changes {
+		q, err := change.Query()
+		if err != nil {
+			return err
+		}
+
+		if _, err := m.client.Query(q, m.opts...); err != nil {
+			return fmt.Errorf("failed to %s: %w", change, err)
+		}
+	}
+
+	return nil
+}
+
+func diffResources[T any](resource string, desired []T, live []T, name func(T) string) (changes []Change, deletes []Change) {
+	liveByName := make(map[string]T, len(live))
+	for _, def := range live {
+		liveByName[name(def)] = def
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for _, def := range desired {
+		defName := name(def)
+		wanted[defName] = true
+
+		current, exists := liveByName[defName]
+		if !exists {
+			changes = append(changes, Change{Action: ActionCreate, Resource: resource, Name: defName, def: def})
+			continue
+		}
+
+		fields, patch := diffFields(reflect.ValueOf(def), reflect.ValueOf(current))
+		if len(fields) > 0 {
+			changes = append(changes, Change{Action: ActionUpdate, Resource: resource, Name: defName, Fields: fields, def: patch})
+		}
+	}
+
+	for defName := range liveByName {
+		if !wanted[defName] {
+			deletes = append(deletes, Change{Action: ActionDelete, Resource: resource, Name: defName})
+		}
+	}
+	sort.Slice(deletes, func(i, j int) bool { return deletes[i].Name < deletes[j].Name })
+
+	return changes, deletes
+}
+
+// diffFields compares the set, top-level fields of desired against current,
+// returning what changed and the patch that updates current to match.
+func diffFields(desired reflect.Value, current reflect.Value) ([]FieldChange, map[string]any) {
+	var fields []FieldChange
+	patch := map[string]any{}
+
+	for i := 0; i < desired.NumField(); i++ {
+		field := strings.Split(desired.Type().Field(i).Tag.Get("fauna"), ",")[0]
+		want, have := desired.Field(i), current.Field(i)
+		if field == "name" || want.IsZero() || covers(want, have) {
+			continue
+		}
+
+		fields = append(fields, FieldChange{Field: field, Current: have.Interface(), Desired: want.Interface()})
+
+		if want.Kind() != reflect.Map {
+			patch[field] = want.Interface()
+			continue
+		}
+
+		entries := map[string]any{}
+		for _, key := range have.MapKeys() {
+			entries[key.String()] = nil
+		}
+		for _, key := range want.MapKeys() {
+			entries[key.String()] = want.MapIndex(key).Interface()
+		}
+		patch[field] = entries
+	}
+
+	return fields, patch
+}
+
+// covers reports whether current matches every set field of desired.
+func covers(desired reflect.Value, current reflect.Value) bool {
+	if desired.Kind() == reflect.Interface || current.Kind() == reflect.Interface {
+		return reflect.DeepEqual(desired.Interface(), current.Interface())
+	}
+
+	switch desired.Kind() {
+	case reflect.Pointer:
+		if desired.IsNil() || current.IsNil() {
+			return desired.IsNil() == current.IsNil()
+		}
+		return covers(desired.Elem(), current.Elem())
+
+	case reflect.Struct:
+		for i := 0; i < desired.NumField(); i++ {
+			if !desired.Field(i).IsZero() && !covers(desired.Field(i), current.Field(i)) {
+				return false
+			}
+		}
+		return true
+
+	case reflect.Slice:
+		if desired.Len() != current.Len() {
+			return false
+		}
+		for i := 0; i < desired.Len(); i++ {
+			if !covers(desired.Index(i), current.Index(i)) {
+				return false
+			}
+		}
+		return true
+
+	case reflect.Map:
+		if desired.Len() != current.Len() {
+			return false
+		}
+		for _, key := range desired.MapKeys() {
+			have := current.MapIndex(key)
+			if !have.IsValid() || !covers(desired.MapIndex(key), have) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return desired.Interface() == current.Interface()
+}
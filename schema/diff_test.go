@@ -0,0 +1,75 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	var applied []string
+	m := newManager(t, func(fql string, _ map[string]any) string {
+		switch fql {
+		case "Collection.all()":
+			return `{"@set":{"data":[` + dogsDef + `,{"@doc":{"name":"Cats","coll":{"@mod":"Collection"},"ts":{"@time":"2023-04-01T00:00:00Z"}}}]}}`
+		case "Role.all()":
+			return `{"@set":{"data":[{"@doc":{"name":"reader","coll":{"@mod":"Role"},"ts":{"@time":"2023-04-01T00:00:00Z"},"privileges":[{"resource":"Dogs","actions":{"read":true}}]}}]}}`
+		}
+
+		applied = append(applied, fql)
+		return "null"
+	})
+
+	plan, err := m.Diff(Definitions{
+		Collections: []Collection{
+			{
+				Name:    "Dogs",
+				Alias:   "Pups",
+				Indexes: map[string]Index{"byAge": {Terms: []IndexTerm{{Field: ".age"}}}},
+			},
+			{Name: "Birds"},
+		},
+		Roles: []Role{
+			{Name: "reader", Privileges: []Privilege{{Resource: "Dogs", Actions: map[string]any{"read": true}}}},
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, strings.Join([]string{
+		"update Collection Dogs: alias, indexes",
+		"create Collection Birds",
+		"delete Collection Cats",
+	}, "\n"), plan.String())
+
+	update := plan.Changes[0]
+	assert.Equal(t, map[string]any{
+		"alias": "Pups",
+		"indexes": map[string]any{
+			"byName": nil,
+			"byAge":  Index{Terms: []IndexTerm{{Field: ".age"}}},
+		},
+	}, update.def)
+
+	if assert.NoError(t, m.Apply(plan)) {
+		assert.Equal(t, []string{
+			"Collection.byName($)!.update($)",
+			"Collection.create($)",
+			"Collection.byName($)!.delete()\nnull",
+		}, applied)
+	}
+
+	t.Run("converged", func(t *testing.T) {
+		plan, err := m.Diff(Definitions{
+			Collections: []Collection{
+				{Name: "Dogs", Indexes: map[string]Index{"byName": {Terms: []IndexTerm{{Field: ".name"}}}}},
+				{Name: "Cats"},
+			},
+		})
+		if assert.NoError(t, err) {
+			assert.True(t, plan.Empty(), plan.String())
+		}
+	})
+}
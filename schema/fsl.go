@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/fauna/fauna-go"
+)
+
+const fslExt = ".fsl"
+
+// ReadFiles reads every `.fsl` file directly within dir.
+func ReadFiles(dir string) ([]fauna.SchemaFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema directory: %w", err)
+	}
+
+	files := make([]fauna.SchemaFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != fslExt {
+			continue
+		}
+
+		bin, readErr := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), readErr)
+		}
+
+		files = append(files, fauna.SchemaFile{Filename: entry.Name(), Content: string(bin)})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Filename < files[j].Filename })
+
+	return files, nil
+}
+
+// WriteFiles writes files into dir, creating it if needed. Existing files
+// with the same names are overwritten.
+func WriteFiles(dir string, files []fauna.SchemaFile) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create schema directory: %w", err)
+	}
+
+	for _, file := range files {
+		if file.Filename != filepath.Base(file.Filename) {
+			return fmt.Errorf("invalid schema filename %q", file.Filename)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, file.Filename), []byte(file.Content), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Filename, err)
+		}
+	}
+
+	return nil
+}
+
+// Pull writes the database's FSL files into dir and returns the schema
+// version they were read at.
+func (m *Manager) Pull(ctx context.Context, dir string) (int64, error) {
+	schema, err := m.client.PullSchema(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := WriteFiles(dir, schema.Files); err != nil {
+		return 0, err
+	}
+
+	return schema.Version, nil
+}
+
+// Push replaces the database's schema with the `.fsl` files in dir and
+// returns the new schema version. See [fauna.Client.PushSchema] for the
+// available options.
+func (m *Manager) Push(ctx context.Context, dir string, opts ...fauna.SchemaPushOptFn) (int64, error) {
+	files, err := ReadFiles(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	return m.client.PushSchema(ctx, files, opts...)
+}
+
+// DiffDir compares the `.fsl` files in dir against the database's schema,
+// returning the changes [schema.Manager.Push] would make.
+func (m *Manager) DiffDir(ctx context.Context, dir string) (*fauna.SchemaDiff, error) {
+	files, err := ReadFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.client.DiffSchema(ctx, files)
+}
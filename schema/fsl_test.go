@@ -0,0 +1,28 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fauna/fauna-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := []fauna.SchemaFile{
+		{Filename: "main.fsl", Content: "collection Dogs {}"},
+		{Filename: "a.fsl", Content: "role reader {}"},
+	}
+
+	assert.NoError(t, WriteFiles(dir, files))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644))
+
+	read, err := ReadFiles(dir)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []fauna.SchemaFile{files[1], files[0]}, read)
+	}
+
+	assert.ErrorContains(t, WriteFiles(dir, []fauna.SchemaFile{{Filename: "../main.fsl"}}), "invalid schema filename")
+}
@@ -0,0 +1,148 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Privilege actions a [schema.Role] can be granted.
+const (
+	PrivilegeCreate           = "create"
+	PrivilegeCreateWithID     = "create_with_id"
+	PrivilegeDelete           = "delete"
+	PrivilegeRead             = "read"
+	PrivilegeWrite            = "write"
+	PrivilegeHistoryRead      = "history_read"
+	PrivilegeHistoryWrite     = "history_write"
+	PrivilegeUnrestrictedRead = "unrestricted_read"
+	PrivilegeCall             = "call"
+)
+
+var privilegeActions = map[string]bool{
+	PrivilegeCreate:           true,
+	PrivilegeCreateWithID:     true,
+	PrivilegeDelete:           true,
+	PrivilegeRead:             true,
+	PrivilegeWrite:            true,
+	PrivilegeHistoryRead:      true,
+	PrivilegeHistoryWrite:     true,
+	PrivilegeUnrestrictedRead: true,
+	PrivilegeCall:             true,
+}
+
+// NewRole creates an empty [schema.Role] to add privileges and membership to.
+func NewRole(name string) *Role {
+	return &Role{Name: name}
+}
+
+// Grant allows the role to perform actions on resource unconditionally,
+// adding to any actions already granted.
+func (r *Role) Grant(resource string, actions ...string) *Role {
+	privilege := r.privilege(resource)
+	for _, action := range actions {
+		privilege.Actions[action] = true
+	}
+
+	return r
+}
+
+// GrantIf allows the role to perform action on resource when predicate, an
+// FQL predicate such as `doc => doc.owner == Query.identity()`, is true.
+func (r *Role) GrantIf(resource string, action string, predicate string) *Role {
+	r.privilege(resource).Actions[action] = predicate
+
+	return r
+}
+
+// AddMembership makes the documents of resource members of the role. An
+// optional predicate, such as `user => user.admin`, limits membership to the
+// documents it's true for.
+func (r *Role) AddMembership(resource string, predicate ...string) *Role {
+	r.Membership = append(r.Membership, Membership{Resource: resource, Predicate: strings.Join(predicate, "")})
+
+	return r
+}
+
+func (r *Role) privilege(resource string) *Privilege {
+	for i := range r.Privileges {
+		if r.Privileges[i].Resource == resource {
+			return &r.Privileges[i]
+		}
+	}
+
+	r.Privileges = append(r.Privileges, Privilege{Resource: resource, Actions: map[string]any{}})
+	return &r.Privileges[len(r.Privileges)-1]
+}
+
+// Validate checks the role has a name, that each privilege names a resource
+// and only known actions, and that each action is granted with either true or
+// a predicate.
+func (r Role) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("role name must not be empty")
+	}
+
+	for _, privilege := range r.Privileges {
+		if privilege.Resource == "" {
+			return fmt.Errorf("role %s has a privilege with no resource", r.Name)
+		}
+
+		if len(privilege.Actions) == 0 {
+			return fmt.Errorf("role %s grants no actions on %s", r.Name, privilege.Resource)
+		}
+
+		actions := make([]string, 0, len(privilege.Actions))
+		for action := range privilege.Actions {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+
+		for _, action := range actions {
+			if !privilegeActions[action] {
+				return fmt.Errorf("role %s grants unknown action %q on %s", r.Name, action, privilege.Resource)
+			}
+
+			switch grant := privilege.Actions[action].(type) {
+			case bool:
+				if !grant {
+					return fmt.Errorf("role %s grants %s on %s with false, omit the action instead", r.Name, action, privilege.Resource)
+				}
+			case string:
+				if grant == "" {
+					return fmt.Errorf("role %s grants %s on %s with an empty predicate", r.Name, action, privilege.Resource)
+				}
+			default:
+				return fmt.Errorf("role %s grants %s on %s with %T, must be true or a predicate", r.Name, action, privilege.Resource, grant)
+			}
+		}
+	}
+
+	for _, membership := range r.Membership {
+		if membership.Resource == "" {
+			return fmt.Errorf("role %s has a membership with no resource", r.Name)
+		}
+	}
+
+	return nil
+}
+
+// ResourceAccess audits which roles can access resource, returning the
+// actions each role is granted on it keyed by role name.
+func (m *Manager) ResourceAccess(resource string) (map[string]map[string]any, error) {
+	roles, err := m.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	access := map[string]map[string]any{}
+	for _, role := range roles {
+		for _, privilege := range role.Privileges {
+			if privilege.Resource == resource {
+				access[role.Name] = privilege.Actions
+			}
+		}
+	}
+
+	return access, nil
+}
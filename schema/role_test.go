@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleBuilder(t *testing.T) {
+	role := NewRole("author").
+		Grant("Posts", PrivilegeRead, PrivilegeCreate).
+		GrantIf("Posts", PrivilegeWrite, "(old, new) => old.author == Query.identity()").
+		Grant("publish", PrivilegeCall).
+		AddMembership("Users", "user => user.writer")
+
+	assert.Equal(t, &Role{
+		Name: "author",
+		Privileges: []Privilege{
+			{Resource: "Posts", Actions: map[string]any{
+				"read":   true,
+				"create": true,
+				"write":  "(old, new) => old.author == Query.identity()",
+			}},
+			{Resource: "publish", Actions: map[string]any{"call": true}},
+		},
+		Membership: []Membership{{Resource: "Users", Predicate: "user => user.writer"}},
+	}, role)
+	assert.NoError(t, role.Validate())
+}
+
+func TestRoleValidate(t *testing.T) {
+	for want, role := range map[string]*Role{
+		"name must not be empty":      NewRole("").Grant("Posts", PrivilegeRead),
+		"privilege with no resource":  NewRole("r").Grant("", PrivilegeRead),
+		"grants no actions on Posts":  NewRole("r").Grant("Posts"),
+		`unknown action "reed"`:       NewRole("r").Grant("Posts", "reed"),
+		"with false":                  {Name: "r", Privileges: []Privilege{{Resource: "Posts", Actions: map[string]any{"read": false}}}},
+		"with an empty predicate":     NewRole("r").GrantIf("Posts", PrivilegeRead, ""),
+		"with int":                    {Name: "r", Privileges: []Privilege{{Resource: "Posts", Actions: map[string]any{"read": 1}}}},
+		"membership with no resource": NewRole("r").AddMembership(""),
+	} {
+		assert.ErrorContains(t, role.Validate(), want)
+	}
+
+	t.Run("create rejects invalid roles", func(t *testing.T) {
+		m := newManager(t, func(string, map[string]any) string {
+			t.Fatal("invalid role should not be sent")
+			return ""
+		})
+
+		_, err := m.CreateRole(*NewRole("r").Grant("Posts", "reed"))
+		assert.Error(t, err)
+	})
+}
+
+func TestResourceAccess(t *testing.T) {
+	m := newManager(t, func(fql string, _ map[string]any) string {
+		assert.Equal(t, "Role.all()", fql)
+		return `{"@set":{"data":[` +
+			`{"@doc":{"name":"reader","coll":{"@mod":"Role"},"ts":{"@time":"2023-04-01T00:00:00Z"},"privileges":[{"resource":"Dogs","actions":{"read":true}}]}},` +
+			`{"@doc":{"name":"other","coll":{"@mod":"Role"},"ts":{"@time":"2023-04-01T00:00:00Z"},"privileges":[{"resource":"Cats","actions":{"read":true}}]}}` +
+			`]}}`
+	})
+
+	access, err := m.ResourceAccess("Dogs")
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]map[string]any{"reader": {"read": true}}, access)
+	}
+}
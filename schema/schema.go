@@ -0,0 +1,351 @@
+// Package schema manages Fauna schema, Collections, Indexes, Functions, Roles,
+// and child Databases, from Go using definitions expressed as structs.
+package schema
+
+import (
+	"fmt"
+
+	"github.com/fauna/fauna-go"
+)
+
+const (
+	moduleCollection = "Collection"
+	moduleFunction   = "Function"
+	moduleRole       = "Role"
+	moduleDatabase   = "Database"
+)
+
+// Collection is a collection definition.
+type Collection struct {
+	Name           string                   `fauna:"name,omitempty"`
+	Alias          string                   `fauna:"alias,omitempty"`
+	HistoryDays    *int                     `fauna:"history_days,omitempty"`
+	TTLDays        *int                     `fauna:"ttl_days,omitempty"`
+	Fields         map[string]Field         `fauna:"fields,omitempty"`
+	Wildcard       string                   `fauna:"wildcard,omitempty"`
+	Indexes        map[string]Index         `fauna:"indexes,omitempty"`
+	Constraints    []Constraint             `fauna:"constraints,omitempty"`
+	ComputedFields map[string]ComputedField `fauna:"computed_fields,omitempty"`
+	Data           map[string]any           `fauna:"data,omitempty"`
+}
+
+// Field is a field declared by a collection's document type.
+type Field struct {
+	// Signature is the field's FQL type, such as `String` or `Int?`.
+	Signature string `fauna:"signature"`
+	Default   string `fauna:"default,omitempty"`
+}
+
+// Index is an index definition on a [schema.Collection].
+type Index struct {
+	Terms     []IndexTerm  `fauna:"terms,omitempty"`
+	Values    []IndexValue `fauna:"values,omitempty"`
+	Queryable *bool        `fauna:"queryable,omitempty"`
+}
+
+// IndexTerm is a field an [schema.Index] is searched by.
+type IndexTerm struct {
+	Field string `fauna:"field"`
+	MVA   bool   `fauna:"mva,omitempty"`
+}
+
+// IndexValue is a field an [schema.Index] is sorted by.
+type IndexValue struct {
+	Field string `fauna:"field"`
+	Order string `fauna:"order,omitempty"`
+	MVA   bool   `fauna:"mva,omitempty"`
+}
+
+// Constraint is a unique or check constraint on a [schema.Collection].
+type Constraint struct {
+	Unique []string         `fauna:"unique,omitempty"`
+	Check  *CheckConstraint `fauna:"check,omitempty"`
+}
+
+// CheckConstraint is a named predicate documents must satisfy.
+type CheckConstraint struct {
+	Name string `fauna:"name"`
+	Body string `fauna:"body"`
+}
+
+// ComputedField is a field computed from a document when it is read.
+type ComputedField struct {
+	Body      string `fauna:"body"`
+	Signature string `fauna:"signature,omitempty"`
+}
+
+// Function is a user-defined function definition.
+type Function struct {
+	Name      string         `fauna:"name,omitempty"`
+	Alias     string         `fauna:"alias,omitempty"`
+	Body      string         `fauna:"body,omitempty"`
+	Role      string         `fauna:"role,omitempty"`
+	Signature string         `fauna:"signature,omitempty"`
+	Data      map[string]any `fauna:"data,omitempty"`
+}
+
+// Role is a user-defined role definition.
+type Role struct {
+	Name       string         `fauna:"name,omitempty"`
+	Privileges []Privilege    `fauna:"privileges,omitempty"`
+	Membership []Membership   `fauna:"membership,omitempty"`
+	Data       map[string]any `fauna:"data,omitempty"`
+}
+
+// Privilege grants a [schema.Role] actions on a resource. Action values are
+// either true or an FQL predicate.
+type Privilege struct {
+	Resource string         `fauna:"resource"`
+	Actions  map[string]any `fauna:"actions"`
+}
+
+// Membership makes documents in a collection members of a [schema.Role],
+// optionally only those matching an FQL predicate.
+type Membership struct {
+	Resource  string `fauna:"resource"`
+	Predicate string `fauna:"predicate,omitempty"`
+}
+
+// Database is a child database definition.
+type Database struct {
+	Name        string         `fauna:"name,omitempty"`
+	Protected   *bool          `fauna:"protected,omitempty"`
+	Typechecked *bool          `fauna:"typechecked,omitempty"`
+	Priority    *int           `fauna:"priority,omitempty"`
+	Data        map[string]any `fauna:"data,omitempty"`
+}
+
+// Manager creates, reads, updates, and deletes schema in the database a
+// [fauna.Client] is connected to. The client's secret must have permission to
+// manage schema.
+type Manager struct {
+	client *fauna.Client
+	opts   []fauna.QueryOptFn
+}
+
+// New creates a [schema.Manager], optionally setting multiple
+// [fauna.QueryOptFn] on every query it runs.
+func New(client *fauna.Client, opts ...fauna.QueryOptFn) *Manager {
+	return &Manager{client: client, opts: opts}
+}
+
+// CreateCollection creates a collection from def.
+func (m *Manager) CreateCollection(def Collection) (*Collection, error) {
+	return create[Collection](m, moduleCollection, def)
+}
+
+// GetCollection returns the definition of the named collection.
+func (m *Manager) GetCollection(name string) (*Collection, error) {
+	return get[Collection](m, moduleCollection, name)
+}
+
+// UpdateCollection merges def into the named collection's definition. Only
+// the fields set in def are changed, and setting def.Name renames it.
+func (m *Manager) UpdateCollection(name string, def Collection) (*Collection, error) {
+	return update[Collection](m, moduleCollection, name, def)
+}
+
+// DeleteCollection deletes the named collection and all of its documents.
+func (m *Manager) DeleteCollection(name string) error {
+	return remove(m, moduleCollection, name)
+}
+
+// ListCollections returns every collection definition.
+func (m *Manager) ListCollections() ([]Collection, error) {
+	return list[Collection](m, moduleCollection)
+}
+
+// SetIndex creates or replaces the named index on a collection.
+func (m *Manager) SetIndex(collection string, name string, def Index) (*Collection, error) {
+	return m.updateIndexes(collection, map[string]any{name: def})
+}
+
+// DeleteIndex removes the named index from a collection.
+func (m *Manager) DeleteIndex(collection string, name string) error {
+	_, err := m.updateIndexes(collection, map[string]any{name: nil})
+	return err
+}
+
+func (m *Manager) updateIndexes(collection string, indexes map[string]any) (*Collection, error) {
+	return update[Collection](m, moduleCollection, collection, map[string]any{"indexes": indexes})
+}
+
+// CreateFunction creates a function from def.
+func (m *Manager) CreateFunction(def Function) (*Function, error) {
+	return create[Function](m, moduleFunction, def)
+}
+
+// GetFunction returns the definition of the named function.
+func (m *Manager) GetFunction(name string) (*Function, error) {
+	return get[Function](m, moduleFunction, name)
+}
+
+// UpdateFunction merges def into the named function's definition.
+func (m *Manager) UpdateFunction(name string, def Function) (*Function, error) {
+	return update[Function](m, moduleFunction, name, def)
+}
+
+// DeleteFunction deletes the named function.
+func (m *Manager) DeleteFunction(name string) error {
+	return remove(m, moduleFunction, name)
+}
+
+// ListFunctions returns every function definition.
+func (m *Manager) ListFunctions() ([]Function, error) {
+	return list[Function](m, moduleFunction)
+}
+
+// CreateRole creates a role from def, after checking it with
+// [schema.Role.Validate].
+func (m *Manager) CreateRole(def Role) (*Role, error) {
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+
+	return create[Role](m, moduleRole, def)
+}
+
+// GetRole returns the definition of the named role.
+func (m *Manager) GetRole(name string) (*Role, error) {
+	return get[Role](m, moduleRole, name)
+}
+
+// UpdateRole merges def into the named role's definition.
+func (m *Manager) UpdateRole(name string, def Role) (*Role, error) {
+	return update[Role](m, moduleRole, name, def)
+}
+
+// DeleteRole deletes the named role.
+func (m *Manager) DeleteRole(name string) error {
+	return remove(m, moduleRole, name)
+}
+
+// ListRoles returns every role definition.
+func (m *Manager) ListRoles() ([]Role, error) {
+	return list[Role](m, moduleRole)
+}
+
+// CreateDatabase creates a child database from def.
+func (m *Manager) CreateDatabase(def Database) (*Database, error) {
+	return create[Database](m, moduleDatabase, def)
+}
+
+// GetDatabase returns the definition of the named child database.
+func (m *Manager) GetDatabase(name string) (*Database, error) {
+	return get[Database](m, moduleDatabase, name)
+}
+
+// UpdateDatabase merges def into the named child database's definition, such
+// as to change its priority or metadata.
+func (m *Manager) UpdateDatabase(name string, def Database) (*Database, error) {
+	return update[Database](m, moduleDatabase, name, def)
+}
+
+// RenameDatabase renames a child database. Secrets scoped to the database by
+// name must be recreated.
+func (m *Manager) RenameDatabase(name string, newName string) (*Database, error) {
+	return update[Database](m, moduleDatabase, name, Database{Name: newName})
+}
+
+// DeleteDatabase deletes the named child database and everything in it.
+func (m *Manager) DeleteDatabase(name string) error {
+	return remove(m, moduleDatabase, name)
+}
+
+// ListDatabases returns the definition of every child database.
+func (m *Manager) ListDatabases() ([]Database, error) {
+	return list[Database](m, moduleDatabase)
+}
+
+// CreateDatabaseKey creates a key for the named child database with the given
+// role, such as `admin` or `server`, and returns its secret. The secret can
+// only be read when the key is created.
+func (m *Manager) CreateDatabaseKey(name string, role string) (string, error) {
+	q, err := fauna.FQL(
+		"Key.create({ role: ${role}, database: ${name} }).secret",
+		map[string]any{"name": name, "role": role},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := m.client.Query(q, m.opts...)
+	if err != nil {
+		return "", err
+	}
+
+	secret, isString := res.Data.(string)
+	if !isString {
+		return "", fmt.Errorf("unexpected key secret %v", res.Data)
+	}
+
+	return secret, nil
+}
+
+func create[T any](m *Manager, module string, def any) (*T, error) {
+	return queryOne[T](m, module+".create(${def})", map[string]any{"def": def})
+}
+
+func get[T any](m *Manager, module string, name string) (*T, error) {
+	return queryOne[T](m, module+".byName(${name})", map[string]any{"name": name})
+}
+
+func update[T any](m *Manager, module string, name string, def any) (*T, error) {
+	return queryOne[T](m, module+".byName(${name})!.update(${def})", map[string]any{"name": name, "def": def})
+}
+
+func remove(m *Manager, module string, name string) error {
+	q, err := fauna.FQL(module+".byName(${name})!.delete()\nnull", map[string]any{"name": name})
+	if err != nil {
+		return err
+	}
+
+	_, err = m.client.Query(q, m.opts...)
+	return err
+}
+
+func list[T any](m *Manager, module string) ([]T, error) {
+	q, err := fauna.FQL(module+".all()", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defs := make([]T, 0)
+	for it := m.client.Paginate(q, m.opts...); it.HasNext(); {
+		page, pageErr := it.Next()
+		if pageErr != nil {
+			return nil, pageErr
+		}
+
+		var pageDefs []T
+		if err := page.Unmarshal(&pageDefs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s definitions: %w", module, err)
+		}
+		defs = append(defs, pageDefs...)
+	}
+
+	return defs, nil
+}
+
+func queryOne[T any](m *Manager, query string, args map[string]any) (*T, error) {
+	q, err := fauna.FQL(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := m.client.Query(q, m.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if nullDoc, isNull := res.Data.(*fauna.NullNamedDocument); isNull {
+		return nil, fmt.Errorf("%s %s not found: %s", nullDoc.Ref.Coll.Name, nullDoc.Ref.Name, nullDoc.Cause)
+	}
+
+	var def T
+	if err := res.Unmarshal(&def); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal definition: %w", err)
+	}
+
+	return &def, nil
+}
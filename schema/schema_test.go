@@ -0,0 +1,240 @@
+package schema
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fauna/fauna-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const dogsDef = `{"@doc":{"name":"Dogs","coll":{"@mod":"Collection"},"ts":{"@time":"2023-04-01T00:00:00Z"},"history_days":{"@int":"0"},"indexes":{"byName":{"terms":[{"field":".name","mva":false}],"queryable":true}}}}`
+
+func newManager(t *testing.T, handler func(fql string, body map[string]any) string) *Manager {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bin, _ := io.ReadAll(r.Body)
+
+		var body map[string]any
+		_ = json.Unmarshal(bin, &body)
+
+		var fql strings.Builder
+		query := body["query"].(map[string]any)
+		for _, frag := range query["fql"].([]any) {
+			if lit, isLit := frag.(string); isLit {
+				fql.WriteString(lit)
+			} else {
+				fql.WriteString("$")
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json;charset=utf-8")
+		_, _ = io.WriteString(w, `{"data":`+handler(fql.String(), body)+`,"summary":"","txn_ts":1680000000000000,"stats":{}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	return New(fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(srv.URL)))
+}
+
+func TestCollections(t *testing.T) {
+	t.Run("create", func(t *testing.T) {
+		var sent map[string]any
+		m := newManager(t, func(fql string, body map[string]any) string {
+			assert.Equal(t, "Collection.create($)", fql)
+			sent = body["query"].(map[string]any)["fql"].([]any)[1].(map[string]any)["value"].(map[string]any)
+			return dogsDef
+		})
+
+		days := 0
+		coll, err := m.CreateCollection(Collection{
+			Name:        "Dogs",
+			HistoryDays: &days,
+			Indexes: map[string]Index{
+				"byName": {Terms: []IndexTerm{{Field: ".name"}}},
+			},
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, map[string]any{
+			"name":         "Dogs",
+			"history_days": map[string]any{"@int": "0"},
+			"indexes": map[string]any{
+				"byName": map[string]any{"terms": []any{map[string]any{"field": ".name"}}},
+			},
+		}, sent)
+
+		assert.Equal(t, "Dogs", coll.Name)
+		if assert.NotNil(t, coll.HistoryDays) {
+			assert.Equal(t, 0, *coll.HistoryDays)
+		}
+		assert.Equal(t, ".name", coll.Indexes["byName"].Terms[0].Field)
+		if assert.NotNil(t, coll.Indexes["byName"].Queryable) {
+			assert.True(t, *coll.Indexes["byName"].Queryable)
+		}
+	})
+
+	t.Run("get missing", func(t *testing.T) {
+		m := newManager(t, func(fql string, _ map[string]any) string {
+			assert.Equal(t, "Collection.byName($)", fql)
+			return `{"@ref":{"name":"Cats","coll":{"@mod":"Collection"},"exists":false,"cause":"not found"}}`
+		})
+
+		_, err := m.GetCollection("Cats")
+		assert.ErrorContains(t, err, "Collection Cats not found")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		m := newManager(t, func(fql string, _ map[string]any) string {
+			assert.Equal(t, "Collection.byName($)!.update($)", fql)
+			return dogsDef
+		})
+
+		coll, err := m.UpdateCollection("Dogs", Collection{Alias: "Pups"})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Dogs", coll.Name)
+		}
+	})
+
+	t.Run("delete index", func(t *testing.T) {
+		var sent map[string]any
+		m := newManager(t, func(fql string, body map[string]any) string {
+			assert.Equal(t, "Collection.byName($)!.update($)", fql)
+			sent = body["query"].(map[string]any)["fql"].([]any)[3].(map[string]any)["value"].(map[string]any)
+			return dogsDef
+		})
+
+		assert.NoError(t, m.DeleteIndex("Dogs", "byName"))
+		assert.Equal(t, map[string]any{"indexes": map[string]any{"byName": nil}}, sent)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		m := newManager(t, func(fql string, _ map[string]any) string {
+			assert.Equal(t, "Collection.byName($)!.delete()\nnull", fql)
+			return `null`
+		})
+
+		assert.NoError(t, m.DeleteCollection("Dogs"))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		m := newManager(t, func(fql string, _ map[string]any) string {
+			assert.Equal(t, "Collection.all()", fql)
+			return `{"@set":{"data":[` + dogsDef + `]}}`
+		})
+
+		colls, err := m.ListCollections()
+		if assert.NoError(t, err) && assert.Len(t, colls, 1) {
+			assert.Equal(t, "Dogs", colls[0].Name)
+		}
+	})
+}
+
+func TestRolesAndFunctions(t *testing.T) {
+	t.Run("create role", func(t *testing.T) {
+		var sent map[string]any
+		m := newManager(t, func(fql string, body map[string]any) string {
+			assert.Equal(t, "Role.create($)", fql)
+			sent = body["query"].(map[string]any)["fql"].([]any)[1].(map[string]any)["value"].(map[string]any)
+			return `{"@doc":{"name":"reader","coll":{"@mod":"Role"},"ts":{"@time":"2023-04-01T00:00:00Z"},"privileges":[{"resource":"Dogs","actions":{"read":true}}]}}`
+		})
+
+		role, err := m.CreateRole(Role{
+			Name:       "reader",
+			Privileges: []Privilege{{Resource: "Dogs", Actions: map[string]any{"read": true}}},
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, map[string]any{
+			"name":       "reader",
+			"privileges": []any{map[string]any{"resource": "Dogs", "actions": map[string]any{"read": true}}},
+		}, sent)
+		assert.Equal(t, "Dogs", role.Privileges[0].Resource)
+	})
+
+	t.Run("get function", func(t *testing.T) {
+		m := newManager(t, func(fql string, _ map[string]any) string {
+			assert.Equal(t, "Function.byName($)", fql)
+			return `{"@doc":{"name":"double","coll":{"@mod":"Function"},"ts":{"@time":"2023-04-01T00:00:00Z"},"body":"x => x * 2"}}`
+		})
+
+		fn, err := m.GetFunction("double")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "x => x * 2", fn.Body)
+		}
+	})
+}
+
+func TestDatabases(t *testing.T) {
+	const tenantDef = `{"@doc":{"name":"tenant_2","coll":{"@mod":"Database"},"ts":{"@time":"2023-04-01T00:00:00Z"},"priority":{"@int":"5"},"data":{"plan":"pro"}}}`
+
+	t.Run("create", func(t *testing.T) {
+		var sent map[string]any
+		m := newManager(t, func(fql string, body map[string]any) string {
+			assert.Equal(t, "Database.create($)", fql)
+			sent = body["query"].(map[string]any)["fql"].([]any)[1].(map[string]any)["value"].(map[string]any)
+			return tenantDef
+		})
+
+		priority := 5
+		db, err := m.CreateDatabase(Database{Name: "tenant_2", Priority: &priority, Data: map[string]any{"plan": "pro"}})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, map[string]any{
+			"name":     "tenant_2",
+			"priority": map[string]any{"@int": "5"},
+			"data":     map[string]any{"plan": "pro"},
+		}, sent)
+		assert.Equal(t, 5, *db.Priority)
+		assert.Equal(t, "pro", db.Data["plan"])
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		var sent map[string]any
+		m := newManager(t, func(fql string, body map[string]any) string {
+			assert.Equal(t, "Database.byName($)!.update($)", fql)
+			sent = body["query"].(map[string]any)["fql"].([]any)[3].(map[string]any)["value"].(map[string]any)
+			return tenantDef
+		})
+
+		db, err := m.RenameDatabase("tenant_1", "tenant_2")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "tenant_2", db.Name)
+		}
+		assert.Equal(t, map[string]any{"name": "tenant_2"}, sent)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		m := newManager(t, func(fql string, _ map[string]any) string {
+			assert.Equal(t, "Database.all()", fql)
+			return `{"@set":{"data":[` + tenantDef + `]}}`
+		})
+
+		dbs, err := m.ListDatabases()
+		if assert.NoError(t, err) && assert.Len(t, dbs, 1) {
+			assert.Equal(t, "tenant_2", dbs[0].Name)
+		}
+	})
+
+	t.Run("key", func(t *testing.T) {
+		m := newManager(t, func(fql string, _ map[string]any) string {
+			assert.Equal(t, "Key.create({ role: $, database: $ }).secret", fql)
+			return `"fn-tenant-secret"`
+		})
+
+		secret, err := m.CreateDatabaseKey("tenant_2", "server")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "fn-tenant-secret", secret)
+		}
+	})
+}
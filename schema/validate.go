@@ -0,0 +1,248 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fauna/fauna-go"
+)
+
+// Mismatch is a difference between a Go struct and a collection definition
+// found by [schema.ValidateModel].
+type Mismatch struct {
+	// Field is the name of the document field.
+	Field string
+
+	// Problem describes the mismatch.
+	Problem string
+}
+
+// String describes the mismatch, such as `age: Int field decodes into string`.
+func (m Mismatch) String() string {
+	return m.Field + ": " + m.Problem
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	refType      = reflect.TypeOf(fauna.Ref{})
+	namedRefType = reflect.TypeOf(fauna.NamedRef{})
+	docType      = reflect.TypeOf(fauna.Document{})
+)
+
+// ValidateModel compares the `fauna` tagged fields of model, a struct or
+// pointer to one, against the named collection's declared and computed
+// fields. See [schema.ValidateStruct].
+func (m *Manager) ValidateModel(collection string, model any) ([]Mismatch, error) {
+	def, err := m.GetCollection(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	return ValidateStruct(*def, model)
+}
+
+// ValidateStruct compares the `fauna` tagged fields of model, a struct or
+// pointer to one, against def, reporting struct fields whose Go type can't hold
+// the declared type, struct fields the collection doesn't declare, and
+// required collection fields the struct is missing. Collections without a
+// document type accept any fields, so only their computed fields are
+// checked.
+//
+// It's intended for tests and CI, to catch drift between models and schema
+// before it surfaces as decode errors:
+//
+//	mismatches, err := schema.New(client).ValidateModel("Dogs", Dog{})
+//	assert.NoError(t, err)
+//	assert.Empty(t, mismatches)
+func ValidateStruct(def Collection, model any) ([]Mismatch, error) {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("model must be a struct, got %T", model)
+	}
+
+	signatures := make(map[string]string, len(def.Fields)+len(def.ComputedFields))
+	for name, field := range def.Fields {
+		signatures[name] = field.Signature
+	}
+	for name, field := range def.ComputedFields {
+		signatures[name] = field.Signature
+	}
+
+	names := make(map[string]bool)
+	var mismatches []Mismatch
+
+	for _, field := range structFields(t) {
+		name := field.name
+		names[name] = true
+
+		if documentFields[name] {
+			continue
+		}
+
+		signature, declared := signatures[name]
+		if !declared {
+			if len(def.Fields) > 0 && def.Wildcard == "" {
+				mismatches = append(mismatches, Mismatch{Field: name, Problem: fmt.Sprintf("%s declares no such field", def.Name)})
+			}
+			continue
+		}
+
+		if signature != "" && !holds(field.typ, signature) {
+			mismatches = append(mismatches, Mismatch{
+				Field:   name,
+				Problem: fmt.Sprintf("%s field decodes into %s", signature, field.typ),
+			})
+		}
+	}
+
+	for name, field := range def.Fields {
+		if !names[name] && !documentFields[name] && field.Default == "" && !nullable(field.Signature) {
+			mismatches = append(mismatches, Mismatch{Field: name, Problem: "required field has no struct field"})
+		}
+	}
+
+	sort.Slice(mismatches, func(i, j int) bool { return mismatches[i].Field < mismatches[j].Field })
+
+	return mismatches, nil
+}
+
+type modelField struct {
+	name string
+	typ  reflect.Type
+}
+
+// structFields lists the document fields t decodes, flattening embedded
+// structs the way the driver does.
+func structFields(t reflect.Type) []modelField {
+	var fields []modelField
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := strings.Split(field.Tag.Get("fauna"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, structFields(field.Type)...)
+			continue
+		}
+
+		if tag == "" {
+			tag = field.Name
+		}
+		fields = append(fields, modelField{name: tag, typ: field.Type})
+	}
+
+	return fields
+}
+
+func nullable(signature string) bool {
+	signature = strings.TrimSpace(signature)
+	if strings.HasSuffix(signature, "?") || signature == "Any" || signature == "Null" {
+		return true
+	}
+
+	for _, part := range strings.Split(signature, "|") {
+		if strings.TrimSpace(part) == "Null" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// holds reports whether a value of the FQL type signature decodes into t.
+func holds(t reflect.Type, signature string) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Interface {
+		return true
+	}
+
+	signature = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(signature), "?"))
+	if parts := strings.Split(signature, "|"); len(parts) > 1 {
+		for _, part := range parts {
+			if part = strings.TrimSpace(part); part != "Null" && !holds(t, part) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case signature == "String":
+		return t.Kind() == reflect.String
+	case signature == "Int", signature == "Long":
+		return isInt(t)
+	case signature == "Double":
+		return t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64
+	case signature == "Number":
+		return isInt(t) || t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64
+	case signature == "Boolean":
+		return t.Kind() == reflect.Bool
+	case signature == "Time", signature == "Date":
+		return t == timeType
+	case strings.HasPrefix(signature, "Array<") && strings.HasSuffix(signature, ">"):
+		return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) &&
+			holds(t.Elem(), signature[len("Array<"):len(signature)-1])
+	case strings.HasPrefix(signature, "{"):
+		return t.Kind() == reflect.Map || t.Kind() == reflect.Struct
+	case strings.HasPrefix(signature, "Ref<"), isIdentifier(signature):
+		return t == refType || t == namedRefType || t == docType || embedsDocument(t)
+	}
+
+	return true
+}
+
+func isInt(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+
+	return false
+}
+
+// isIdentifier reports whether signature names a user-defined type such as a
+// collection, rather than a built-in type.
+func isIdentifier(signature string) bool {
+	switch signature {
+	case "Any", "Null", "Bytes", "ID", "Set", "Object", "Array":
+		return false
+	}
+
+	for i, r := range signature {
+		if !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+
+	return signature != ""
+}
+
+func embedsDocument(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Anonymous && field.Type == docType {
+			return true
+		}
+	}
+
+	return false
+}
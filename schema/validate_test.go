@@ -0,0 +1,88 @@
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fauna/fauna-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type validateTestOrder struct {
+	fauna.Document
+	Customer  *fauna.Ref        `fauna:"customer"`
+	Total     float64           `fauna:"total"`
+	Quantity  string            `fauna:"quantity"`
+	Tags      []string          `fauna:"tags"`
+	PlacedAt  *time.Time        `fauna:"placed_at"`
+	Extra     map[string]any    `fauna:"extra"`
+	Note      string            `fauna:"-"`
+	LineItems []validateTestSKU `fauna:"line_items"`
+	ItemCount int               `fauna:"item_count"`
+	internal  string
+}
+
+type validateTestSKU struct {
+	SKU string `fauna:"sku"`
+}
+
+func TestValidateStruct(t *testing.T) {
+	def := Collection{
+		Name: "Orders",
+		Fields: map[string]Field{
+			"customer":   {Signature: "Customers"},
+			"total":      {Signature: "Number"},
+			"quantity":   {Signature: "Int"},
+			"tags":       {Signature: "Array<String>?"},
+			"placed_at":  {Signature: "Time"},
+			"line_items": {Signature: "Array<{ sku: String }>"},
+			"status":     {Signature: "String"},
+			"region":     {Signature: "String", Default: `"us"`},
+			"coupon":     {Signature: "String | Null"},
+		},
+		ComputedFields: map[string]ComputedField{
+			"item_count": {Body: "doc => doc.line_items.length", Signature: "Boolean"},
+		},
+	}
+
+	mismatches, err := ValidateStruct(def, &validateTestOrder{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []Mismatch{
+		{Field: "extra", Problem: "Orders declares no such field"},
+		{Field: "item_count", Problem: "Boolean field decodes into int"},
+		{Field: "quantity", Problem: "Int field decodes into string"},
+		{Field: "status", Problem: "required field has no struct field"},
+	}, mismatches)
+
+	t.Run("wildcard", func(t *testing.T) {
+		def.Wildcard = "Any"
+		mismatches, _ := ValidateStruct(def, validateTestOrder{})
+		assert.NotContains(t, mismatches, Mismatch{Field: "extra", Problem: "Orders declares no such field"})
+	})
+
+	t.Run("no document type", func(t *testing.T) {
+		mismatches, _ := ValidateStruct(Collection{Name: "Orders"}, validateTestOrder{})
+		assert.Empty(t, mismatches)
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		_, err := ValidateStruct(def, "order")
+		assert.ErrorContains(t, err, "model must be a struct")
+	})
+}
+
+func TestValidateModel(t *testing.T) {
+	m := newManager(t, func(fql string, _ map[string]any) string {
+		assert.Equal(t, "Collection.byName($)", fql)
+		return `{"@doc":{"name":"Orders","coll":{"@mod":"Collection"},"ts":{"@time":"2023-04-01T00:00:00Z"},"fields":{"total":{"signature":"String"}}}}`
+	})
+
+	mismatches, err := m.ValidateModel("Orders", validateTestOrder{})
+	if assert.NoError(t, err) {
+		assert.Contains(t, mismatches, Mismatch{Field: "total", Problem: "String field decodes into float64"})
+		assert.Equal(t, "total: String field decodes into float64", mismatches[len(mismatches)-1].String())
+	}
+}
@@ -1,6 +1,7 @@
 package fauna
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -15,7 +16,7 @@ const (
 	fieldTag = "fauna"
 
 	dateFormat = "2006-01-02"
-	timeFormat = "2006-01-02T15:04:05.999999Z"
+	timeFormat = "2006-01-02T15:04:05.999999999Z"
 
 	maxInt  = 2147483647
 	minInt  = -2147483648
@@ -36,13 +37,14 @@ const (
 	typeTagSet    typeTag = "@set"
 	typeTagMod    typeTag = "@mod"
 	typeTagObject typeTag = "@object"
+	typeTagStream typeTag = "@stream"
 )
 
 func keyConflicts(key string) bool {
 	switch typeTag(key) {
 	case typeTagInt, typeTagLong, typeTagDouble,
 		typeTagDate, typeTagTime,
-		typeTagDoc, typeTagMod, typeTagObject:
+		typeTagDoc, typeTagRef, typeTagSet, typeTagMod, typeTagObject, typeTagStream:
 		return true
 	default:
 		return false
@@ -90,34 +92,85 @@ type NamedRef struct {
 type Page struct {
 	Data  []any  `fauna:"data"`
 	After string `fauna:"after"`
+
+	// codec decodes Data in Unmarshal as the client which read the page
+	// would.
+	codec codec
 }
 
+// Unmarshal decodes the page's Data into into, a pointer to a slice, array,
+// or interface such as a *[]Dog or *any, decoding each item like
+// [fauna.QuerySuccess.Unmarshal], including tagged values such as documents,
+// times, and longs.
 func (p Page) Unmarshal(into any) error {
-	return decodeInto(p.Data, into)
+	v := reflect.ValueOf(into)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("expected a non-nil pointer to a slice, array, or interface, got %T", into)
+	}
+	if kind := v.Elem().Kind(); kind != reflect.Slice && kind != reflect.Array && kind != reflect.Interface {
+		return fmt.Errorf("expected a pointer to a slice, array, or interface, got %T", into)
+	}
+
+	return p.codec.decodeInto(p.Data, into)
+}
+
+// codec holds the settings a client encodes and decodes values with, such as
+// the keys of encrypted fields. Values which aren't read by a client, such as
+// abort data, are decoded with the zero codec.
+type codec struct {
+	keys KeyProvider
+
+	// lossless decodes Longs and Dates as [fauna.Long] and [fauna.Date].
+	lossless bool
+
+	times TimeOptions
+
+	// tags are the struct tags read from fields without a `fauna` tag, see
+	// [fauna.FallbackTags].
+	tags []string
 }
 
-func mapDecoder(into any) (*mapstructure.Decoder, error) {
+func (c codec) mapDecoder(into any) (*mapstructure.Decoder, error) {
 	return mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		TagName:              "fauna",
 		Result:               into,
 		IgnoreUntaggedFields: false,
 		ErrorUnused:          false,
 		ErrorUnset:           false,
-		DecodeHook:           unmarshalDoc,
+		ZeroFields:           true,
+		DecodeHook:           mapstructure.ComposeDecodeHookFunc(c.unmarshalDoc, c.decryptFields, c.markNulls, c.decodeNullable, convertDates, c.decodePolymorphic, c.renameFields),
 		Squash:               true,
 	})
 }
 
-func unmarshal(body []byte, into any) error {
-	decBody, err := decode(body)
+func (c codec) unmarshal(body []byte, into any) error {
+	decBody, err := c.decode(body)
 	if err != nil {
 		return err
 	}
-	return decodeInto(decBody, into)
+	return c.decodeInto(decBody, into)
+}
+
+// unmarshal, decode, decodeInto, and marshal use the zero codec, for values
+// which aren't read or sent by a client.
+func unmarshal(body []byte, into any) error {
+	return codec{}.unmarshal(body, into)
+}
+
+func decode(bodyBytes []byte) (any, error) {
+	return codec{}.decode(bodyBytes)
 }
 
 func decodeInto(body any, into any) error {
-	dec, err := mapDecoder(into)
+	return codec{}.decodeInto(body, into)
+}
+
+func marshal(v any) ([]byte, error) {
+	return codec{}.marshal(v)
+}
+
+func (c codec) decodeInto(body any, into any) error {
+	dec, err := c.mapDecoder(into)
 	if err != nil {
 		return err
 	}
@@ -130,11 +183,17 @@ var (
 	namedDocType = reflect.TypeOf(&NamedDocument{})
 )
 
-func unmarshalDoc(f reflect.Type, t reflect.Type, data any) (any, error) {
+func (c codec) unmarshalDoc(f reflect.Type, t reflect.Type, data any) (any, error) {
 	if f != docType && f != namedDocType {
 		return data, nil
 	}
 
+	// documents decoded into interfaces stay documents, so they encode as
+	// they were
+	if t.Kind() == reflect.Interface {
+		return data, nil
+	}
+
 	var docData map[string]any
 	if f == docType {
 		doc := data.(*Document)
@@ -153,7 +212,7 @@ func unmarshalDoc(f reflect.Type, t reflect.Type, data any) (any, error) {
 	}
 
 	result := reflect.New(t).Interface()
-	dec, err := mapDecoder(result)
+	dec, err := c.mapDecoder(result)
 	if err != nil {
 		return nil, err
 	}
@@ -165,36 +224,36 @@ func unmarshalDoc(f reflect.Type, t reflect.Type, data any) (any, error) {
 	return result, nil
 }
 
-func decode(bodyBytes []byte) (any, error) {
+func (c codec) decode(bodyBytes []byte) (any, error) {
 	var body any
 	if err := json.Unmarshal(bodyBytes, &body); err != nil {
 		return nil, err
 	}
 
-	return convert(false, body)
+	return c.convert(false, body)
 }
 
-func convert(escaped bool, body any) (any, error) {
+func (c codec) convert(escaped bool, body any) (any, error) {
 	switch b := body.(type) {
 	case map[string]any:
 		if escaped {
-			return convertMap(b)
+			return c.convertMap(b)
 		} else {
-			return unboxType(b)
+			return c.unboxType(b)
 		}
 
 	case []any:
-		return convertSlice(b)
+		return c.convertSlice(b)
 
 	default:
 		return body, nil
 	}
 }
 
-func convertMap(body map[string]any) (map[string]any, error) {
+func (c codec) convertMap(body map[string]any) (map[string]any, error) {
 	retBody := map[string]any{}
 	for k, vRaw := range body {
-		if v, err := convert(false, vRaw); err != nil {
+		if v, err := c.convert(false, vRaw); err != nil {
 			return nil, err
 		} else {
 			retBody[k] = v
@@ -203,9 +262,9 @@ func convertMap(body map[string]any) (map[string]any, error) {
 	return retBody, nil
 }
 
-func convertSlice(body []any) ([]any, error) {
+func (c codec) convertSlice(body []any) ([]any, error) {
 	for i, vRaw := range body {
-		if v, err := convert(false, vRaw); err != nil {
+		if v, err := c.convert(false, vRaw); err != nil {
 			return nil, err
 		} else {
 			body[i] = v
@@ -214,33 +273,45 @@ func convertSlice(body []any) ([]any, error) {
 	return body, nil
 }
 
-func unboxType(body map[string]any) (any, error) {
+func (c codec) unboxType(body map[string]any) (any, error) {
 	if len(body) == 1 {
 		for boxedK, v := range body {
 			switch typeTag(boxedK) {
-			case typeTagInt, typeTagLong:
+			case typeTagInt:
 				return unboxInt(v.(string))
+			case typeTagLong:
+				i, err := unboxInt(v.(string))
+				if err == nil && c.lossless {
+					return Long(i.(int64)), nil
+				}
+				return i, err
 			case typeTagDouble:
 				return unboxDouble(v.(string))
 			case typeTagDate:
-				return unboxDate(v.(string))
+				t, err := unboxDate(v.(string))
+				if err == nil && c.lossless {
+					return Date{*t}, nil
+				}
+				return t, err
 			case typeTagTime:
-				return unboxTime(v.(string))
+				return c.unboxTime(v.(string))
 			case typeTagMod:
 				return unboxMod(v.(string))
 			case typeTagRef:
-				return unboxRef(v.(map[string]any))
+				return c.unboxRef(v.(map[string]any))
 			case typeTagSet:
-				return unboxSet(v)
+				return c.unboxSet(v)
 			case typeTagDoc:
-				return unboxDoc(v.(map[string]any))
+				return c.unboxDoc(v.(map[string]any))
 			case typeTagObject:
-				return convertMap(v.(map[string]any))
+				return c.convertMap(v.(map[string]any))
+			case typeTagStream:
+				return EventSource(v.(string)), nil
 			}
 		}
 	}
 
-	return convertMap(body)
+	return c.convertMap(body)
 }
 
 func unboxMod(v string) (*Module, error) {
@@ -248,9 +319,9 @@ func unboxMod(v string) (*Module, error) {
 	return &m, nil
 }
 
-func getColl(v map[string]any) (*Module, error) {
+func (c codec) getColl(v map[string]any) (*Module, error) {
 	if coll, ok := v["coll"]; ok {
-		modI, err := convert(false, coll)
+		modI, err := c.convert(false, coll)
 		if err != nil {
 			return nil, err
 		}
@@ -290,8 +361,8 @@ func getExistsCause(v map[string]any) (exists bool, cause string) {
 	return true, ""
 }
 
-func unboxRef(v map[string]any) (any, error) {
-	mod, err := getColl(v)
+func (c codec) unboxRef(v map[string]any) (any, error) {
+	mod, err := c.getColl(v)
 	if err != nil {
 		return nil, err
 	}
@@ -327,15 +398,15 @@ func unboxRef(v map[string]any) (any, error) {
 	return nil, fmt.Errorf("invalid ref %v", v)
 }
 
-func unboxDoc(v map[string]any) (any, error) {
-	mod, err := getColl(v)
+func (c codec) unboxDoc(v map[string]any) (any, error) {
+	mod, err := c.getColl(v)
 	if err != nil {
 		return nil, err
 	}
 
 	var ts *time.Time
 	if tsRaw, ok := v["ts"]; ok {
-		if tsI, err := convert(false, tsRaw); err != nil {
+		if tsI, err := c.convert(false, tsRaw); err != nil {
 			return nil, err
 		} else {
 			if unboxedTS, ok := tsI.(*time.Time); ok {
@@ -355,7 +426,7 @@ func unboxDoc(v map[string]any) (any, error) {
 			delete(v, "name")
 		}
 
-		data, err := convertMap(v)
+		data, err := c.convertMap(v)
 		if err != nil {
 			return nil, err
 		}
@@ -372,21 +443,21 @@ func unboxDoc(v map[string]any) (any, error) {
 	return nil, fmt.Errorf("invalid doc %v", v)
 }
 
-func unboxSet(v any) (any, error) {
+func (c codec) unboxSet(v any) (any, error) {
 	if set, ok := v.(string); ok {
-		setC := Page{After: set}
+		setC := Page{After: set, codec: c}
 		return &setC, nil
 	}
 
 	set := v.(map[string]any)
 	if dataI, ok := set["data"]; ok {
 		if dataRaw, ok := dataI.([]any); ok {
-			data, err := convertSlice(dataRaw)
+			data, err := c.convertSlice(dataRaw)
 			if err != nil {
 				return nil, err
 			}
 
-			setC := Page{Data: data}
+			setC := Page{Data: data, codec: c}
 			if afterRaw, ok := set["after"]; ok {
 				if after, ok := afterRaw.(string); ok {
 					setC.After = after
@@ -400,8 +471,8 @@ func unboxSet(v any) (any, error) {
 	return nil, fmt.Errorf("invalid set %v", v)
 }
 
-func unboxTime(v string) (*time.Time, error) {
-	if t, err := time.Parse(timeFormat, v); err != nil {
+func (c codec) unboxTime(v string) (*time.Time, error) {
+	if t, err := c.parseTime(v); err != nil {
 		return nil, err
 	} else {
 		return &t, nil
@@ -432,52 +503,108 @@ func unboxDouble(v string) (any, error) {
 	}
 }
 
-func marshal(v any) ([]byte, error) {
-	if enc, err := encode(v, ""); err != nil {
+func (c codec) marshal(v any) ([]byte, error) {
+	if enc, err := c.encodeSealed(v); err != nil {
 		return nil, err
 	} else {
 		return json.Marshal(enc)
 	}
 }
 
-func encode(v any, hint string) (any, error) {
+// marshalTo encodes v into buf, avoiding the intermediate byte slice
+// allocated by marshal.
+func (c codec) marshalTo(buf *bytes.Buffer, v any) error {
+	enc, err := c.encodeSealed(v)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(buf).Encode(enc)
+}
+
+// encodeSealed encodes v, then encrypts its encrypted fields with the
+// codec's keys.
+func (c codec) encodeSealed(v any) (any, error) {
+	enc, err := c.encode(v, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return c.sealFields(enc)
+}
+
+func (c codec) encode(v any, hint string) (any, error) {
 	switch vt := v.(type) {
 	case *queryFragment:
-		return encodeQueryFragment(vt)
+		return c.encodeQueryFragment(vt)
+
+	case taggedJSON:
+		return json.RawMessage(vt), nil
 
 	case *Query:
-		return encodeQuery(vt)
+		return c.encodeQuery(vt)
+
+	case Query:
+		return c.encodeQuery(&vt)
 
 	case Module:
 		return encodeMod(vt)
 
 	case Ref,
 		NamedRef:
-		return encodeFaunaStruct(typeTagRef, vt)
+		return c.encodeFaunaStruct(typeTagRef, vt)
+
+	case Document:
+		return c.encodeDocument(vt, vt.Data)
+
+	case NamedDocument:
+		return c.encodeDocument(vt, vt.Data)
+
+	case Long:
+		return map[typeTag]any{typeTagLong: strconv.FormatInt(int64(vt), 10)}, nil
 
-	case Document,
-		NamedDocument:
-		return encodeFaunaStruct(typeTagDoc, vt)
+	case Date:
+		return c.encodeTime(vt.Time, "date")
+
+	case EventSource:
+		return map[typeTag]any{typeTagStream: string(vt)}, nil
+
+	case nullable:
+		value, set, null := vt.nullable()
+		if !set || null {
+			return nil, nil
+		}
+		return c.encode(value, hint)
+
+	case ArrayFunc:
+		if vt == nil {
+			return nil, nil
+		}
+		return streamedArray{fn: vt, codec: c}, nil
 
 	case NullDocument,
 		NullNamedDocument:
-		return encodeStruct(v)
+		return c.encodeStruct(v)
 
 	case Page:
-		return encodeFaunaStruct(typeTagSet, vt)
+		if vt.Data == nil && vt.After != "" {
+			// a set which hasn't been paginated is just its cursor
+			return map[typeTag]any{typeTagSet: vt.After}, nil
+		}
+		return c.encodeFaunaStruct(typeTagSet, vt)
 
 	case time.Time:
-		return encodeTime(vt, hint)
+		return c.encodeTime(vt, hint)
 
 	case fqlRequest:
-		query, err := encode(vt.Query, hint)
+		query, err := c.encode(vt.Query, hint)
 		if err != nil {
 			return nil, err
 		}
 
 		out := map[string]any{"query": query}
 		if len(vt.Arguments) > 0 {
-			if args, err := encodeMap(reflect.ValueOf(vt.Arguments)); err != nil {
+			if args, err := c.encodeMap(reflect.ValueOf(vt.Arguments)); err != nil {
 				return nil, err
 			} else {
 				out["arguments"] = args
@@ -508,16 +635,16 @@ func encode(v any, hint string) (any, error) {
 		if value.IsNil() {
 			return nil, nil
 		}
-		return encode(reflect.Indirect(value).Interface(), hint)
+		return c.encode(reflect.Indirect(value).Interface(), hint)
 
 	case reflect.Struct:
-		return encodeStruct(v)
+		return c.encodeStruct(v)
 
 	case reflect.Map:
-		return encodeMap(value)
+		return c.encodeMap(value)
 
 	case reflect.Slice:
-		return encodeSlice(value)
+		return c.encodeSlice(value)
 	}
 
 	return v, nil
@@ -531,12 +658,16 @@ func encodeInt(i int64) (any, error) {
 	return map[typeTag]any{tag: strconv.FormatInt(i, 10)}, nil
 }
 
-func encodeTime(t time.Time, hint string) (any, error) {
+func (c codec) encodeTime(t time.Time, hint string) (any, error) {
 	out := make(map[typeTag]any)
 	if hint == "date" {
 		out[typeTagDate] = t.Format(dateFormat)
 	} else {
-		out[typeTagTime] = t.UTC().Format(timeFormat)
+		t, err := c.checkPrecision(t)
+		if err != nil {
+			return nil, err
+		}
+		out[typeTagTime] = c.formatTime(t)
 	}
 	return out, nil
 }
@@ -545,15 +676,37 @@ func encodeMod(m Module) (any, error) {
 	return map[typeTag]string{typeTagMod: m.Name}, nil
 }
 
-func encodeFaunaStruct(tag typeTag, s any) (any, error) {
-	if doc, err := encodeStruct(s); err != nil {
+func (c codec) encodeFaunaStruct(tag typeTag, s any) (any, error) {
+	if doc, err := c.encodeStruct(s); err != nil {
 		return nil, err
 	} else {
 		return map[typeTag]any{tag: doc}, nil
 	}
 }
 
-func encodeMap(mv reflect.Value) (any, error) {
+// encodeDocument encodes the metadata of doc, a [fauna.Document] or
+// [fauna.NamedDocument], and its data as an @doc.
+func (c codec) encodeDocument(doc any, data map[string]any) (any, error) {
+	enc, err := c.encodeStruct(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	out := enc.(map[string]any)
+	for k, v := range data {
+		if _, isMetadata := out[k]; isMetadata {
+			continue
+		}
+
+		if out[k], err = c.encode(v, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return map[typeTag]any{typeTagDoc: out}, nil
+}
+
+func (c codec) encodeMap(mv reflect.Value) (any, error) {
 	hasConflictingKey := false
 	out := make(map[string]any)
 
@@ -563,7 +716,7 @@ func encodeMap(mv reflect.Value) (any, error) {
 			return mv.Interface(), nil
 		}
 
-		if enc, err := encode(mi.Value().Interface(), ""); err != nil {
+		if enc, err := c.encode(mi.Value().Interface(), ""); err != nil {
 			return nil, err
 		} else {
 
@@ -582,11 +735,11 @@ func encodeMap(mv reflect.Value) (any, error) {
 	}
 }
 
-func encodeSlice(sv reflect.Value) (any, error) {
+func (c codec) encodeSlice(sv reflect.Value) (any, error) {
 	sLen := sv.Len()
 	out := make([]any, sLen)
 	for i := 0; i < sLen; i++ {
-		if enc, err := encode(sv.Index(i).Interface(), ""); err != nil {
+		if enc, err := c.encode(sv.Index(i).Interface(), ""); err != nil {
 			return nil, err
 		} else {
 			out[i] = enc
@@ -596,7 +749,7 @@ func encodeSlice(sv reflect.Value) (any, error) {
 	return out, nil
 }
 
-func encodeStruct(s any) (any, error) {
+func (c codec) encodeStruct(s any) (any, error) {
 	hasConflictingKey := false
 	isDoc := false
 	out := make(map[string]any)
@@ -613,14 +766,14 @@ func encodeStruct(s any) (any, error) {
 			if doc.Ref != nil {
 				out["cause"] = doc.Cause
 
-				if ref, err := encode(doc.Ref, ""); err != nil {
+				if ref, err := c.encode(doc.Ref, ""); err != nil {
 					return nil, err
 				} else {
 					out["ref"] = ref
 				}
-
-				continue
 			}
+			// unset document metadata isn't encoded as a field
+			continue
 		}
 
 		if structField.Anonymous && (structField.Name == "NullNamedDocument") {
@@ -629,14 +782,26 @@ func encodeStruct(s any) (any, error) {
 			if doc.Ref != nil {
 				out["cause"] = doc.Cause
 
-				if ref, err := encode(doc.Ref, ""); err != nil {
+				if ref, err := c.encode(doc.Ref, ""); err != nil {
 					return nil, err
 				} else {
 					out["ref"] = ref
 				}
+			}
+			continue
+		}
 
-				continue
+		if structField.Anonymous && structField.Type == documentMetaType {
+			meta := elem.Field(i).Interface().(DocumentMeta)
+			// only the TTL of a document can be written
+			if meta.TTL != nil {
+				if ttl, err := c.encode(meta.TTL, "time"); err != nil {
+					return nil, err
+				} else {
+					out["ttl"] = ttl
+				}
 			}
+			continue
 		}
 
 		if structField.Anonymous && structField.Name == "Document" {
@@ -645,21 +810,21 @@ func encodeStruct(s any) (any, error) {
 			if doc.ID != "" && doc.Coll != nil && doc.TS != nil {
 				out["id"] = doc.ID
 
-				if coll, err := encode(doc.Coll, ""); err != nil {
+				if coll, err := c.encode(doc.Coll, ""); err != nil {
 					return nil, err
 				} else {
 					out["coll"] = coll
 				}
 
-				if ts, err := encode(doc.TS, "time"); err != nil {
+				if ts, err := c.encode(doc.TS, "time"); err != nil {
 					return nil, err
 				} else {
 					out["ts"] = ts
 				}
 
 				isDoc = true
-				continue
 			}
+			continue
 		}
 
 		if structField.Anonymous && structField.Name == "NamedDocument" {
@@ -668,36 +833,60 @@ func encodeStruct(s any) (any, error) {
 			if doc.Name != "" && doc.Coll != nil && doc.TS != nil {
 				out["name"] = doc.Name
 
-				if coll, err := encode(doc.Coll, ""); err != nil {
+				if coll, err := c.encode(doc.Coll, ""); err != nil {
 					return nil, err
 				} else {
 					out["coll"] = coll
 				}
 
-				if ts, err := encode(doc.TS, "time"); err != nil {
+				if ts, err := c.encode(doc.TS, "time"); err != nil {
 					return nil, err
 				} else {
 					out["ts"] = ts
 				}
 
 				isDoc = true
-				continue
 			}
+			continue
 		}
 
-		tag := structField.Tag.Get(fieldTag)
+		// unexported fields can't be read, so they aren't encoded
+		if !structField.IsExported() {
+			continue
+		}
+
+		tag := c.tagOf(structField)
 		tags := strings.Split(tag, ",")
 
 		if len(tags) > 0 && tags[0] == "-" {
 			continue
 		}
 
+		if omitField(tags[1:], elem.Field(i)) {
+			continue
+		}
+
 		typeHint := ""
-		if len(tags) > 1 {
-			typeHint = tags[1]
+		encrypted := false
+		for _, opt := range tags[1:] {
+			if opt == tagOptOmitEmpty {
+				continue
+			} else if opt == tagOptEncrypted {
+				encrypted = true
+			} else if opt == tagOptSensitive {
+				continue
+			} else if typeHint == "" {
+				typeHint = opt
+			}
 		}
 
-		if enc, err := encode(elem.Field(i).Interface(), typeHint); err != nil {
+		if n, isNullable := elem.Field(i).Interface().(nullable); isNullable {
+			if _, set, _ := n.nullable(); !set {
+				continue
+			}
+		}
+
+		if enc, err := c.encode(elem.Field(i).Interface(), typeHint); err != nil {
 			return nil, err
 		} else {
 			name := tags[0]
@@ -705,6 +894,10 @@ func encodeStruct(s any) (any, error) {
 				name = structField.Name
 			}
 
+			if encrypted && enc != nil {
+				enc = encryptedField{name: name, value: enc}
+			}
+
 			if keyConflicts(name) {
 				hasConflictingKey = true
 			}
@@ -723,12 +916,16 @@ func encodeStruct(s any) (any, error) {
 	return out, nil
 }
 
-func encodeQuery(q *Query) (any, error) {
+func (c codec) encodeQuery(q *Query) (any, error) {
 	const fqlLabel = "fql"
 
+	if q == nil {
+		return nil, fmt.Errorf("cannot interpolate a nil query")
+	}
+
 	rendered := make([]any, len(q.fragments))
 	for i, f := range q.fragments {
-		if r, err := encode(f, ""); err != nil {
+		if r, err := c.encode(f, ""); err != nil {
 			return nil, err
 		} else {
 			rendered[i] = r
@@ -738,21 +935,111 @@ func encodeQuery(q *Query) (any, error) {
 	return map[string]any{fqlLabel: rendered}, nil
 }
 
-func encodeQueryFragment(f *queryFragment) (any, error) {
+func (c codec) encodeQueryFragment(f *queryFragment) (any, error) {
 	if f.literal {
 		return f.value, nil
 	}
 
-	ret, err := encode(f.value, "")
+	return c.encodeInterpolation(f.value)
+}
+
+// encodeInterpolation renders a template argument. Arguments which are, or
+// contain, a [fauna.Query] are rendered as nested fql, object, or array
+// interpolations so the composed query is evaluated by Fauna rather than sent
+// as a plain value.
+func (c codec) encodeInterpolation(v any) (any, error) {
+	const (
+		arrLabel = "array"
+		objLabel = "object"
+		valLabel = "value"
+	)
+
+	switch vt := v.(type) {
+	case *Query:
+		return c.encodeQuery(vt)
+
+	case Query:
+		return c.encodeQuery(&vt)
+	}
+
+	value := reflect.ValueOf(v)
+	if containsQuery(value) {
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			value = value.Elem()
+		}
+
+		switch value.Kind() {
+		case reflect.Map:
+			out := make(map[string]any, value.Len())
+			mi := value.MapRange()
+			for mi.Next() {
+				if enc, err := c.encodeInterpolation(mi.Value().Interface()); err != nil {
+					return nil, err
+				} else {
+					out[mi.Key().String()] = enc
+				}
+			}
+			return map[string]any{objLabel: out}, nil
+
+		case reflect.Slice, reflect.Array:
+			out := make([]any, value.Len())
+			for i := 0; i < value.Len(); i++ {
+				if enc, err := c.encodeInterpolation(value.Index(i).Interface()); err != nil {
+					return nil, err
+				} else {
+					out[i] = enc
+				}
+			}
+			return map[string]any{arrLabel: out}, nil
+		}
+	}
+
+	ret, err := c.encode(v, "")
 	if err != nil {
 		return nil, err
 	}
 
-	if _, ok := f.value.(*Query); ok {
-		return ret, nil
+	return map[string]any{valLabel: ret}, nil
+}
 
-	} else {
-		const valLabel = "value"
-		return map[string]any{valLabel: ret}, nil
+var queryType = reflect.TypeOf(Query{})
+
+// containsQuery reports whether a map (with string keys) or slice value holds a
+// [fauna.Query] at any depth.
+func containsQuery(v reflect.Value) bool {
+	if !v.IsValid() {
+		return false
 	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return false
+		}
+		return containsQuery(v.Elem())
+
+	case reflect.Struct:
+		return v.Type() == queryType
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return false
+		}
+
+		mi := v.MapRange()
+		for mi.Next() {
+			if containsQuery(mi.Value()) {
+				return true
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if containsQuery(v.Index(i)) {
+				return true
+			}
+		}
+	}
+
+	return false
 }
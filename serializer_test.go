@@ -245,6 +245,46 @@ func TestDecodingToInterface(t *testing.T) {
 	}
 }
 
+func TestPageUnmarshal(t *testing.T) {
+	var page Page
+	err := unmarshal([]byte(`{"@set":{"data":[
+    {"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-03-17T00:00:00Z"},"name":"Scout","age":{"@long":"4"}}},
+    {"@doc":{"id":"2","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-03-18T00:00:00Z"},"name":"Rex","age":{"@int":"2"}}}
+  ],"after":"next"}}`), &page)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	type dog struct {
+		Document
+		Name string `fauna:"name"`
+		Age  int    `fauna:"age"`
+	}
+
+	var dogs []dog
+	if assert.NoError(t, page.Unmarshal(&dogs)) && assert.Len(t, dogs, 2) {
+		assert.Equal(t, "1", dogs[0].ID)
+		assert.Equal(t, "Scout", dogs[0].Name)
+		assert.Equal(t, 4, dogs[0].Age)
+		assert.Equal(t, time.Date(2023, 3, 18, 0, 0, 0, 0, time.UTC), *dogs[1].TS)
+	}
+
+	var items any
+	assert.NoError(t, page.Unmarshal(&items))
+
+	var names [2]struct {
+		Name string `fauna:"name"`
+	}
+	if assert.NoError(t, page.Unmarshal(&names)) {
+		assert.Equal(t, dogs[1].Name, names[1].Name)
+	}
+
+	var one dog
+	assert.ErrorContains(t, page.Unmarshal(&one), "expected a pointer to a slice")
+	assert.ErrorContains(t, page.Unmarshal(dogs), "expected a non-nil pointer")
+	assert.Error(t, page.Unmarshal((*[]dog)(nil)))
+}
+
 func TestEncodingFaunaStructs(t *testing.T) {
 	t.Run("encodes Module", func(t *testing.T) {
 		obj := Module{"Foo"}
@@ -262,7 +302,7 @@ func TestEncodingFaunaStructs(t *testing.T) {
 	})
 
 	t.Run("encodes Page", func(t *testing.T) {
-		obj := Page{[]any{"0", "1", "2"}, "foobarbaz"}
+		obj := Page{Data: []any{"0", "1", "2"}, After: "foobarbaz"}
 		roundTripCheck(t, obj, `{"@set":{"data":["0","1","2"],"after":"foobarbaz"}}`)
 	})
 
@@ -417,6 +457,56 @@ func TestEncodingObject(t *testing.T) {
 	})
 }
 
+// Embedded document metadata without the fields identifying a document, such
+// as in a struct built to create one, is left out rather than encoded as a
+// nested field.
+func TestEncodingUnsetDocumentMetadata(t *testing.T) {
+	ts := time.Date(2023, 02, 28, 18, 10, 10, 10000, time.UTC)
+
+	t.Run("Document", func(t *testing.T) {
+		type MyDoc struct {
+			Document
+			ExtraField string `fauna:"extra_field"`
+		}
+
+		bs := marshalAndCheck(t, MyDoc{ExtraField: "foobar"})
+		assert.JSONEq(t, `{"extra_field":"foobar"}`, string(bs))
+
+		bs = marshalAndCheck(t, MyDoc{Document: Document{ID: "1234", TS: &ts}, ExtraField: "foobar"})
+		assert.JSONEq(t, `{"extra_field":"foobar"}`, string(bs), "partly set metadata is left out too")
+	})
+
+	t.Run("NamedDocument", func(t *testing.T) {
+		type MyDoc struct {
+			NamedDocument
+			ExtraField string `fauna:"extra_field"`
+		}
+
+		bs := marshalAndCheck(t, MyDoc{ExtraField: "foobar"})
+		assert.JSONEq(t, `{"extra_field":"foobar"}`, string(bs))
+	})
+
+	t.Run("NullDocument", func(t *testing.T) {
+		type MyNullDoc struct {
+			NullDocument
+			ExtraField string `fauna:"extra_field"`
+		}
+
+		bs := marshalAndCheck(t, MyNullDoc{ExtraField: "foobar"})
+		assert.JSONEq(t, `{"extra_field":"foobar"}`, string(bs))
+	})
+
+	t.Run("NullNamedDocument", func(t *testing.T) {
+		type MyNullDoc struct {
+			NullNamedDocument
+			ExtraField string `fauna:"extra_field"`
+		}
+
+		bs := marshalAndCheck(t, MyNullDoc{ExtraField: "foobar"})
+		assert.JSONEq(t, `{"extra_field":"foobar"}`, string(bs))
+	})
+}
+
 func TestEncodingDocuments(t *testing.T) {
 	t.Run("Document", func(t *testing.T) {
 		type MyDoc struct {
@@ -601,4 +691,58 @@ func TestComposition(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("sub query by value", func(t *testing.T) {
+		predicate, predicateErr := FQL(".age > ${age}", map[string]any{"age": 2})
+		if !assert.NoError(t, predicateErr) {
+			return
+		}
+
+		q, err := FQL("Dinos.where(${predicate})", map[string]any{"predicate": *predicate})
+		if assert.NoError(t, err) {
+			bs := marshalAndCheck(t, q)
+			assert.JSONEq(t, `{"fql":[
+        "Dinos.where(",
+        {"fql":[".age > ",{"value":{"@int":"2"}}]},
+        ")"
+      ]}`, string(bs))
+		}
+	})
+
+	t.Run("sub queries nested in arguments", func(t *testing.T) {
+		name, nameErr := FQL("${name}.toUpperCase()", map[string]any{"name": "dino"})
+		if !assert.NoError(t, nameErr) {
+			return
+		}
+
+		q, err := FQL("Dinos.create(${doc})", map[string]any{"doc": map[string]any{
+			"name": name,
+			"tags": []any{"fossil", name},
+			"age":  0,
+		}})
+		if assert.NoError(t, err) {
+			bs := marshalAndCheck(t, q)
+			assert.JSONEq(t, `{"fql":[
+        "Dinos.create(",
+        {"object":{
+          "name":{"fql":[{"value":"dino"},".toUpperCase()"]},
+          "tags":{"array":[
+            {"value":"fossil"},
+            {"fql":[{"value":"dino"},".toUpperCase()"]}
+          ]},
+          "age":{"value":{"@int":"0"}}
+        }},
+        ")"
+      ]}`, string(bs))
+		}
+	})
+
+	t.Run("nil sub query", func(t *testing.T) {
+		var inner *Query
+		_, err := FQL("${inner}", map[string]any{"inner": inner})
+		assert.ErrorAs(t, err, new(*ErrInvalidArgument))
+
+		_, err = FQL("${outer}", map[string]any{"outer": map[string]any{"inner": inner}})
+		assert.ErrorAs(t, err, new(*ErrInvalidArgument))
+	})
 }
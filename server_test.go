@@ -0,0 +1,76 @@
+package fauna
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+// testRequest is a request received by a testServer.
+type testRequest struct {
+	Method     string
+	URL        *url.URL
+	RemoteAddr string
+	Header     http.Header
+	Raw        []byte
+	Body       map[string]any
+}
+
+// testServer is a fake Fauna endpoint which records the requests it receives
+// and replies using handler.
+type testServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []testRequest
+}
+
+func newTestServer(t testing.TB, handler func(req testRequest) (status int, body string)) *testServer {
+	srv := &testServer{}
+	srv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bin, _ := io.ReadAll(r.Body)
+
+		req := testRequest{Method: r.Method, URL: r.URL, RemoteAddr: r.RemoteAddr, Header: r.Header.Clone(), Raw: bin}
+		_ = json.Unmarshal(bin, &req.Body)
+
+		srv.mu.Lock()
+		srv.requests = append(srv.requests, req)
+		srv.mu.Unlock()
+
+		status, body := handler(req)
+		w.Header().Set("Content-Type", "application/json;charset=utf-8")
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// client creates a [fauna.Client] pointed at the test server.
+func (s *testServer) client(configFns ...ClientConfigFn) *Client {
+	return NewClient("secret", DefaultTimeouts(), append([]ClientConfigFn{URL(s.URL)}, configFns...)...)
+}
+
+// received returns the requests the server has received so far.
+func (s *testServer) received() []testRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]testRequest{}, s.requests...)
+}
+
+// successBody is a query response body with the given encoded data.
+func successBody(data string) string {
+	return fmt.Sprintf(`{"data":%s,"summary":"","txn_ts":1680000000000000,"stats":{"compute_ops":1}}`, data)
+}
+
+// errorBody is a query error response body.
+func errorBody(code, message string) string {
+	return fmt.Sprintf(`{"error":{"code":%q,"message":%q},"summary":"","txn_ts":1680000000000000}`, code, message)
+}
@@ -0,0 +1,138 @@
+package fauna
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClientClosed is returned by requests made after [Client.Shutdown].
+var ErrClientClosed = errors.New("fauna: client is shut down")
+
+// Shutdown stops the [fauna.Client] accepting new requests, which then fail
+// with [fauna.ErrClientClosed], and waits for requests in flight to finish,
+// like [http.Server.Shutdown]. If ctx is done first, the remaining requests
+// are canceled and ctx's error is returned. A request is in flight until its
+// response has been read. Streams aren't waited for, see [Stream.Drain].
+//
+// Shutdown applies to clients derived from the client too, as they share
+// its connections.
+func (c *Client) Shutdown(ctx context.Context) error {
+	idle := c.inflight.close()
+
+	select {
+	case <-idle:
+		if c.txnTimeStore != nil {
+			c.txnTimeStore.flush()
+		}
+		return nil
+	case <-ctx.Done():
+		c.inflight.cancelAll()
+		return ctx.Err()
+	}
+}
+
+// inflightRequests tracks a client's requests in flight, so they can be
+// waited for or canceled on shutdown.
+type inflightRequests struct {
+	mu      sync.Mutex
+	closed  bool
+	next    uint64
+	cancels map[uint64]context.CancelFunc
+	idle    chan struct{}
+	closing chan struct{}
+}
+
+// start admits a request made with ctx, returning the context to make it
+// with, which is canceled if the request is forced to stop, and a func that
+// must be called once the request is complete.
+func (f *inflightRequests) start(ctx context.Context) (context.Context, func(), error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return nil, nil, ErrClientClosed
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	id := f.next
+	f.next++
+	if f.cancels == nil {
+		f.cancels = map[uint64]context.CancelFunc{}
+	}
+	f.cancels[id] = cancel
+
+	var once sync.Once
+	return ctx, func() { once.Do(func() { f.finish(id) }) }, nil
+}
+
+func (f *inflightRequests) finish(id uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.cancels[id]()
+	delete(f.cancels, id)
+	if f.closed && len(f.cancels) == 0 {
+		f.signalIdle()
+	}
+}
+
+// close stops new requests being admitted, returning a channel closed once
+// none are in flight.
+func (f *inflightRequests) close() <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.idle == nil {
+		f.idle = make(chan struct{})
+	}
+	if f.closing == nil {
+		f.closing = make(chan struct{})
+	}
+	if !f.closed {
+		close(f.closing)
+	}
+	f.closed = true
+	if len(f.cancels) == 0 {
+		f.signalIdle()
+	}
+
+	return f.idle
+}
+
+// done returns a channel closed once new requests are no longer admitted.
+func (f *inflightRequests) done() <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closing == nil {
+		f.closing = make(chan struct{})
+		if f.closed {
+			close(f.closing)
+		}
+	}
+
+	return f.closing
+}
+
+// signalIdle closes idle, if it isn't already. f.mu must be held.
+func (f *inflightRequests) signalIdle() {
+	select {
+	case <-f.idle:
+	default:
+		close(f.idle)
+	}
+}
+
+func (f *inflightRequests) cancelAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, cancel := range f.cancels {
+		cancel()
+	}
+}
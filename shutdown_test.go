@@ -0,0 +1,74 @@
+package fauna
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdown(t *testing.T) {
+	arrived := make(chan struct{}, 1)
+	unblock := make(chan struct{})
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		arrived <- struct{}{}
+		<-unblock
+		return http.StatusOK, successBody(`null`)
+	})
+	q, _ := FQL(`null`, nil)
+
+	t.Run("waits for queries in flight", func(t *testing.T) {
+		client := srv.client()
+
+		queried := make(chan error)
+		go func() {
+			_, err := client.Query(q)
+			queried <- err
+		}()
+		<-arrived
+
+		shutdown := make(chan error)
+		go func() { shutdown <- client.Shutdown(context.Background()) }()
+
+		assert.Eventually(t, func() bool {
+			_, err := client.Query(q)
+			return errors.Is(err, ErrClientClosed)
+		}, time.Second, time.Millisecond, "new queries are refused")
+
+		select {
+		case <-shutdown:
+			t.Fatal("shut down with a query in flight")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		unblock <- struct{}{}
+		assert.NoError(t, <-queried)
+		assert.NoError(t, <-shutdown)
+	})
+
+	t.Run("cancels queries at the deadline", func(t *testing.T) {
+		client := srv.client()
+
+		queried := make(chan error)
+		go func() {
+			_, err := client.Query(q)
+			queried <- err
+		}()
+		<-arrived
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, client.Shutdown(ctx), context.DeadlineExceeded)
+		assert.ErrorIs(t, <-queried, context.Canceled)
+		unblock <- struct{}{}
+	})
+
+	t.Run("without queries", func(t *testing.T) {
+		client := srv.client()
+		assert.NoError(t, client.Shutdown(context.Background()))
+		assert.NoError(t, client.Shutdown(context.Background()), "shutting down again is a no-op")
+	})
+}
@@ -0,0 +1,447 @@
+// Package sqldriver provides a [database/sql] driver for Fauna, registered as
+// "fauna", for tools and code written against database/sql.
+//
+// Statements are FQL. Arguments are passed as query arguments rather than
+// interpolated, positional arguments as ${1}, ${2}, and so on, and named
+// arguments by name:
+//
+//	db, err := sql.Open("fauna", "secret=mysecret")
+//	rows, err := db.QueryContext(ctx, `Dogs.byName(${name})`, sql.Named("name", "Scout"))
+//
+// Results that are sets or arrays are returned a row per element, fetching
+// further pages of sets as rows are read. Any other result is a single row.
+// Documents and objects have a column per field, taken from the first row, and
+// other values have a single column, "value". Fields that aren't numbers,
+// booleans, strings, or times are returned as JSON, except references, whose
+// columns hold the referenced document's ID or name.
+//
+// Each statement is a transaction, so [sql.DB.Begin] isn't supported.
+package sqldriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fauna/fauna-go"
+)
+
+func init() {
+	sql.Register("fauna", &Driver{})
+}
+
+// ErrTransactionsUnsupported is returned by [sql.DB.Begin], as each FQL
+// statement is already a transaction.
+var ErrTransactionsUnsupported = errors.New("fauna: transactions are not supported, each statement is a transaction")
+
+// Driver is the "fauna" [database/sql/driver.Driver].
+type Driver struct{}
+
+// Open opens a connection using dsn. See [Driver.OpenConnector].
+func (d *Driver) Open(dsn string) (driver.Conn, error) {
+	connector, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return connector.Connect(context.Background())
+}
+
+// OpenConnector parses dsn, a space separated list of key=value settings:
+//
+//   - secret: the secret to authenticate with, defaulting to the
+//     FAUNA_SECRET environment variable
+//   - endpoint: the Fauna endpoint, defaulting to the FAUNA_ENDPOINT
+//     environment variable or [fauna.EndpointDefault]
+func (d *Driver) OpenConnector(dsn string) (driver.Connector, error) {
+	secret, _ := os.LookupEnv(fauna.EnvFaunaSecret)
+	endpoint, found := os.LookupEnv(fauna.EnvFaunaEndpoint)
+	if !found {
+		endpoint = fauna.EndpointDefault
+	}
+
+	for _, setting := range strings.Fields(dsn) {
+		key, value, found := strings.Cut(setting, "=")
+		if !found {
+			return nil, fmt.Errorf("fauna: invalid dsn setting %q", setting)
+		}
+
+		switch key {
+		case "secret":
+			secret = value
+		case "endpoint":
+			endpoint = value
+		default:
+			return nil, fmt.Errorf("fauna: unknown dsn setting %q", key)
+		}
+	}
+
+	if secret == "" {
+		return nil, fmt.Errorf("fauna: no secret in dsn or environment variable '%s'", fauna.EnvFaunaSecret)
+	}
+
+	return &connector{
+		driver: d,
+		client: fauna.NewClient(secret, fauna.DefaultTimeouts(), fauna.URL(endpoint)),
+	}, nil
+}
+
+// NewConnector returns a [database/sql/driver.Connector] running statements
+// with client, for use with [sql.OpenDB].
+func NewConnector(client *fauna.Client) driver.Connector {
+	return &connector{driver: &Driver{}, client: client}
+}
+
+type connector struct {
+	driver *Driver
+	client *fauna.Client
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{client: c.client}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// conn is a database/sql connection. The [fauna.Client] manages the actual
+// HTTP connections, so connections are cheap and share it.
+type conn struct {
+	client *fauna.Client
+}
+
+var (
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.ExecerContext      = (*conn)(nil)
+	_ driver.Pinger             = (*conn)(nil)
+	_ driver.NamedValueChecker  = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+)
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(_ context.Context, query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return nil, ErrTransactionsUnsupported
+}
+
+// CheckNamedValue accepts any argument, as the driver encodes arguments
+// itself, so maps, slices, structs and [fauna.Ref] can be passed.
+func (c *conn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	q, err := fauna.FQL(`null`, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.client.Query(q, fauna.QueryContext(ctx), fauna.NoCache(), fauna.NoCoalesce())
+	return err
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	res, err := c.run(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &rows{ctx: ctx, client: c.client}
+	switch data := res.Data.(type) {
+	case nil:
+	case *fauna.Page:
+		r.items, r.after = data.Data, data.After
+	case []any:
+		r.items = data
+	default:
+		r.items = []any{data}
+	}
+
+	r.columns = columns(r.items)
+
+	return r, nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	res, err := c.run(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return newResult(res), nil
+}
+
+func (c *conn) run(ctx context.Context, query string, args []driver.NamedValue) (*fauna.QuerySuccess, error) {
+	arguments := make(map[string]any, len(args))
+	for _, arg := range args {
+		if arg.Name != "" {
+			arguments[arg.Name] = arg.Value
+		} else {
+			arguments[strconv.Itoa(arg.Ordinal)] = arg.Value
+		}
+	}
+
+	q, err := fauna.FQL(query, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.client.Query(q, fauna.QueryContext(ctx))
+}
+
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+var (
+	_ driver.StmtQueryContext = (*stmt)(nil)
+	_ driver.StmtExecContext  = (*stmt)(nil)
+)
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+// NumInput is -1, as FQL arguments are checked when the statement runs.
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+
+	return named
+}
+
+// result reports the number of elements a statement returned as the rows
+// affected, or one for any other non-null result. Fauna has no insert IDs, so
+// use [sql.DB.QueryRowContext] to return created documents instead.
+type result int64
+
+func (r result) LastInsertId() (int64, error) {
+	return 0, errors.New("fauna: LastInsertId is not supported, return the document instead")
+}
+
+func (r result) RowsAffected() (int64, error) {
+	return int64(r), nil
+}
+
+func newResult(res *fauna.QuerySuccess) result {
+	switch data := res.Data.(type) {
+	case nil:
+		return 0
+	case *fauna.Page:
+		return result(len(data.Data))
+	case []any:
+		return result(len(data))
+	}
+
+	return 1
+}
+
+type rows struct {
+	ctx     context.Context
+	client  *fauna.Client
+	columns []string
+	items   []any
+	after   string
+}
+
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+func (r *rows) Close() error {
+	r.items, r.after = nil, ""
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	for len(r.items) == 0 {
+		if r.after == "" {
+			return io.EOF
+		}
+
+		if err := r.nextPage(); err != nil {
+			return err
+		}
+	}
+
+	item := r.items[0]
+	r.items = r.items[1:]
+
+	fields := rowFields(item)
+	for i, column := range r.columns {
+		value, err := columnValue(fields[column])
+		if err != nil {
+			return fmt.Errorf("fauna: column %s: %w", column, err)
+		}
+		dest[i] = value
+	}
+
+	return nil
+}
+
+func (r *rows) nextPage() error {
+	q, err := fauna.FQL(`Set.paginate(${after})`, map[string]any{"after": r.after})
+	if err != nil {
+		return err
+	}
+
+	res, err := r.client.Query(q, fauna.QueryContext(r.ctx))
+	if err != nil {
+		return err
+	}
+
+	r.items, r.after = nil, ""
+	switch page := res.Data.(type) {
+	case *fauna.Page:
+		r.items, r.after = page.Data, page.After
+	case map[string]any:
+		r.items, _ = page["data"].([]any)
+		r.after, _ = page["after"].(string)
+	}
+
+	return nil
+}
+
+// valueColumn is the column of results that aren't documents or objects.
+const valueColumn = "value"
+
+// columns lists the fields of the first item: document metadata first, then
+// its data in name order.
+func columns(items []any) []string {
+	if len(items) == 0 {
+		return []string{valueColumn}
+	}
+
+	var meta []string
+	switch items[0].(type) {
+	case *fauna.Document:
+		meta = []string{"id", "coll", "ts"}
+	case *fauna.NamedDocument:
+		meta = []string{"name", "coll", "ts"}
+	case map[string]any:
+	default:
+		return []string{valueColumn}
+	}
+
+	fields := rowFields(items[0])
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		if !contains(meta, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return append(meta, names...)
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func rowFields(item any) map[string]any {
+	switch v := item.(type) {
+	case *fauna.Document:
+		fields := map[string]any{"id": v.ID, "coll": v.Coll, "ts": v.TS}
+		for k, field := range v.Data {
+			fields[k] = field
+		}
+		return fields
+	case *fauna.NamedDocument:
+		fields := map[string]any{"name": v.Name, "coll": v.Coll, "ts": v.TS}
+		for k, field := range v.Data {
+			fields[k] = field
+		}
+		return fields
+	case map[string]any:
+		return v
+	}
+
+	return map[string]any{valueColumn: item}
+}
+
+// columnValue converts a decoded FQL value to a [database/sql/driver.Value].
+func columnValue(v any) (driver.Value, error) {
+	switch v := v.(type) {
+	case nil, int64, float64, bool, string, time.Time:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case *time.Time:
+		if v == nil {
+			return nil, nil
+		}
+		return *v, nil
+	case *fauna.Module:
+		if v == nil {
+			return nil, nil
+		}
+		return v.Name, nil
+	case *fauna.Ref:
+		return v.ID, nil
+	case *fauna.NamedRef:
+		return v.Name, nil
+	case *fauna.Document:
+		return v.ID, nil
+	case *fauna.NamedDocument:
+		return v.Name, nil
+	case *fauna.NullDocument, *fauna.NullNamedDocument:
+		return nil, nil
+	}
+
+	bin, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(bin), nil
+}
@@ -0,0 +1,194 @@
+package sqldriver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fauna/fauna-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	scoutDoc = `{"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"},"name":"Scout","age":{"@int":"3"},"owner":{"@ref":{"id":"7","coll":{"@mod":"People"}}},"tags":["good"]}}`
+	rexDoc   = `{"@doc":{"id":"2","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-02T00:00:00Z"},"name":"Rex"}}`
+)
+
+// newServer is a fake Fauna endpoint, which replies to each query with the
+// data handler returns for its FQL, with arguments written as $.
+func newServer(t *testing.T, handler func(fql string, args []any) string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bin, _ := io.ReadAll(r.Body)
+
+		var body map[string]any
+		_ = json.Unmarshal(bin, &body)
+
+		var fql strings.Builder
+		var args []any
+		query := body["query"].(map[string]any)
+		for _, frag := range query["fql"].([]any) {
+			if lit, isLit := frag.(string); isLit {
+				fql.WriteString(lit)
+			} else {
+				fql.WriteString("$")
+				args = append(args, frag.(map[string]any)["value"])
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json;charset=utf-8")
+		_, _ = io.WriteString(w, `{"data":`+handler(fql.String(), args)+`,"summary":"","txn_ts":1680000000000000,"stats":{}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestQuery(t *testing.T) {
+	srv := newServer(t, func(fql string, args []any) string {
+		switch fql {
+		case "Dogs.all()":
+			return `{"@set":{"data":[` + scoutDoc + `],"after":"next"}}`
+		case "Set.paginate($)":
+			return `{"@set":{"data":[` + rexDoc + `]}}`
+		case "Dogs.byName($).count()":
+			assert.Equal(t, []any{"Scout"}, args)
+			return `{"@int":"1"}`
+		case "[$, $]":
+			return `[{"@long":"1"},"two"]`
+		}
+
+		t.Errorf("unexpected query: %s", fql)
+		return "null"
+	})
+
+	db, err := sql.Open("fauna", "secret=secret endpoint="+srv.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	t.Run("documents", func(t *testing.T) {
+		rows, err := db.QueryContext(context.Background(), `Dogs.all()`)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer rows.Close()
+
+		columns, err := rows.Columns()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"id", "coll", "ts", "age", "name", "owner", "tags"}, columns)
+
+		type dog struct {
+			ID, Coll string
+			TS       time.Time
+			Age      sql.NullInt64
+			Name     string
+			Owner    sql.NullString
+			Tags     sql.NullString
+		}
+
+		var dogs []dog
+		for rows.Next() {
+			var d dog
+			assert.NoError(t, rows.Scan(&d.ID, &d.Coll, &d.TS, &d.Age, &d.Name, &d.Owner, &d.Tags))
+			dogs = append(dogs, d)
+		}
+		assert.NoError(t, rows.Err())
+
+		assert.Equal(t, []dog{
+			{
+				ID: "1", Coll: "Dogs", TS: time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC),
+				Age: sql.NullInt64{Int64: 3, Valid: true}, Name: "Scout",
+				Owner: sql.NullString{String: "7", Valid: true}, Tags: sql.NullString{String: `["good"]`, Valid: true},
+			},
+			{ID: "2", Coll: "Dogs", TS: time.Date(2023, 4, 2, 0, 0, 0, 0, time.UTC), Name: "Rex"},
+		}, dogs, "later pages are fetched as rows are read")
+	})
+
+	t.Run("named arguments", func(t *testing.T) {
+		var count int
+		err := db.QueryRow(`Dogs.byName(${name}).count()`, sql.Named("name", "Scout")).Scan(&count)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("positional arguments", func(t *testing.T) {
+		rows, err := db.Query(`[${1}, ${2}]`, 1, "two")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer rows.Close()
+
+		columns, _ := rows.Columns()
+		assert.Equal(t, []string{"value"}, columns)
+
+		var values []string
+		for rows.Next() {
+			var v string
+			assert.NoError(t, rows.Scan(&v))
+			values = append(values, v)
+		}
+		assert.Equal(t, []string{"1", "two"}, values)
+	})
+}
+
+func TestExec(t *testing.T) {
+	srv := newServer(t, func(fql string, args []any) string {
+		switch fql {
+		case "Dogs.create($)":
+			assert.Equal(t, []any{map[string]any{"name": "Scout"}}, args)
+			return scoutDoc
+		case "Dogs.all().toArray().map(.delete())":
+			return `[null, null]`
+		}
+		return "null"
+	})
+
+	db := sql.OpenDB(NewConnector(fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(srv.URL))))
+	defer db.Close()
+
+	res, err := db.Exec(`Dogs.create(${1})`, map[string]any{"name": "Scout"})
+	if assert.NoError(t, err) {
+		affected, _ := res.RowsAffected()
+		assert.Equal(t, int64(1), affected)
+
+		_, err = res.LastInsertId()
+		assert.Error(t, err)
+	}
+
+	res, err = db.Exec(`Dogs.all().toArray().map(.delete())`)
+	if assert.NoError(t, err) {
+		affected, _ := res.RowsAffected()
+		assert.Equal(t, int64(2), affected)
+	}
+
+	assert.NoError(t, db.Ping())
+
+	_, err = db.Begin()
+	assert.ErrorIs(t, err, ErrTransactionsUnsupported)
+}
+
+func TestOpenConnector(t *testing.T) {
+	t.Setenv(fauna.EnvFaunaSecret, "")
+
+	_, err := (&Driver{}).OpenConnector("")
+	assert.ErrorContains(t, err, "no secret")
+
+	_, err = (&Driver{}).OpenConnector("secret")
+	assert.ErrorContains(t, err, "invalid dsn setting")
+
+	_, err = (&Driver{}).OpenConnector("secret=x timeout=5s")
+	assert.ErrorContains(t, err, "unknown dsn setting")
+
+	t.Setenv(fauna.EnvFaunaSecret, "from-env")
+	_, err = (&Driver{}).OpenConnector("")
+	assert.NoError(t, err)
+}
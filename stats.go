@@ -0,0 +1,141 @@
+package fauna
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ClientStats are cumulative counts of a [fauna.Client]'s activity, since it
+// was created or since [Client.ResetStats]. They're counted whether or not
+// the client records metrics with [fauna.WithMeter].
+type ClientStats struct {
+	// Queries is the number of requests made to run queries, including
+	// failed ones but not those answered from the query cache.
+	Queries int64
+
+	// Failed is the number of those requests which failed.
+	Failed int64
+
+	// Retries is the number of requests retried by the client.
+	Retries int64
+
+	// Throttled is the number of requests Fauna throttled, whether or not
+	// they were retried.
+	Throttled int64
+
+	// ComputeOps is the number of Transactional Compute Ops consumed.
+	ComputeOps int64
+
+	// ReadOps is the number of Transactional Read Ops consumed.
+	ReadOps int64
+
+	// WriteOps is the number of Transactional Write Ops consumed.
+	WriteOps int64
+
+	// StreamEvents is the number of events received by streams.
+	StreamEvents int64
+
+	// StreamReconnects is the number of times streams reconnected after
+	// their connection dropped.
+	StreamReconnects int64
+
+	// Coalesced is the number of queries which waited for an identical query
+	// in flight to share its result, see [fauna.CoalesceReads]. Those whose
+	// shared query turned out to write are re-run, so are counted in Queries
+	// too.
+	Coalesced int64
+}
+
+// Sub returns the counts in s since earlier, an older snapshot.
+func (s ClientStats) Sub(earlier ClientStats) ClientStats {
+	return ClientStats{
+		Queries:          s.Queries - earlier.Queries,
+		Failed:           s.Failed - earlier.Failed,
+		Retries:          s.Retries - earlier.Retries,
+		Throttled:        s.Throttled - earlier.Throttled,
+		ComputeOps:       s.ComputeOps - earlier.ComputeOps,
+		ReadOps:          s.ReadOps - earlier.ReadOps,
+		WriteOps:         s.WriteOps - earlier.WriteOps,
+		StreamEvents:     s.StreamEvents - earlier.StreamEvents,
+		StreamReconnects: s.StreamReconnects - earlier.StreamReconnects,
+		Coalesced:        s.Coalesced - earlier.Coalesced,
+	}
+}
+
+// StatsSnapshot returns the client's counts so far. It's safe to call
+// concurrently with queries, though counts of queries in flight may be
+// partly included.
+func (c *Client) StatsSnapshot() ClientStats {
+	return c.stats.snapshot(false)
+}
+
+// ResetStats sets the client's counts to zero, returning the counts up to the
+// reset, so a periodic reporter can report the counts since its last report
+// without missing any recorded in between.
+func (c *Client) ResetStats() ClientStats {
+	return c.stats.snapshot(true)
+}
+
+type clientStats struct {
+	queries          atomic.Int64
+	failed           atomic.Int64
+	retries          atomic.Int64
+	throttled        atomic.Int64
+	computeOps       atomic.Int64
+	readOps          atomic.Int64
+	writeOps         atomic.Int64
+	streamEvents     atomic.Int64
+	streamReconnects atomic.Int64
+	coalesced        atomic.Int64
+}
+
+// snapshot loads each count, setting it to zero if reset is true.
+func (s *clientStats) snapshot(reset bool) ClientStats {
+	load := func(n *atomic.Int64) int64 {
+		if reset {
+			return n.Swap(0)
+		}
+		return n.Load()
+	}
+
+	return ClientStats{
+		Queries:          load(&s.queries),
+		Failed:           load(&s.failed),
+		Retries:          load(&s.retries),
+		Throttled:        load(&s.throttled),
+		ComputeOps:       load(&s.computeOps),
+		ReadOps:          load(&s.readOps),
+		WriteOps:         load(&s.writeOps),
+		StreamEvents:     load(&s.streamEvents),
+		StreamReconnects: load(&s.streamReconnects),
+		Coalesced:        load(&s.coalesced),
+	}
+}
+
+// query counts a request which either succeeded with res or failed with err.
+func (s *clientStats) query(res *queryResponse, err error) {
+	s.queries.Add(1)
+	if err != nil {
+		s.failed.Add(1)
+	}
+
+	var stats *Stats
+	if res != nil {
+		stats = res.Stats
+	} else if info := errorInfo(err); info != nil {
+		stats = info.Stats
+	}
+
+	if stats != nil {
+		s.computeOps.Add(int64(stats.ComputeOps))
+		s.readOps.Add(int64(stats.ReadOps))
+		s.writeOps.Add(int64(stats.WriteOps))
+	}
+}
+
+// recordQuery counts a request to Fauna which took elapsed, and either
+// succeeded with res or failed with err, in the client's stats and metrics.
+func (c *Client) recordQuery(request *fqlRequest, elapsed time.Duration, res *queryResponse, err error) {
+	c.stats.query(res, err)
+	c.metrics.query(request.Context, request.Name, elapsed, res, err)
+}
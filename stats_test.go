@@ -0,0 +1,61 @@
+package fauna
+
+import (
+	"bytes"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientStats(t *testing.T) {
+	var throttle int32
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		if atomic.AddInt32(&throttle, -1) >= 0 {
+			return http.StatusTooManyRequests, errorBody("limit_exceeded", "too many requests")
+		}
+		if bytes.Contains(req.Raw, []byte("abort")) {
+			return http.StatusBadRequest, `{"error":{"code":"abort","message":"aborted","abort":"oops"},"summary":"","stats":{"compute_ops":2}}`
+		}
+		return http.StatusOK, `{"data":null,"summary":"","txn_ts":1,"stats":{"compute_ops":1,"read_ops":2,"write_ops":3}}`
+	})
+	client := srv.client(MaxBackoff(time.Millisecond))
+
+	ok, _ := FQL(`null`, nil)
+	abort, _ := FQL(`abort`, nil)
+
+	atomic.StoreInt32(&throttle, 1)
+	_, err := client.Query(ok)
+	assert.NoError(t, err)
+	_, err = client.Query(abort)
+	assert.Error(t, err)
+
+	assert.Equal(t, ClientStats{
+		Queries:    2,
+		Failed:     1,
+		Retries:    1,
+		Throttled:  1,
+		ComputeOps: 3,
+		ReadOps:    2,
+		WriteOps:   3,
+	}, client.StatsSnapshot())
+
+	t.Run("resets", func(t *testing.T) {
+		before := client.ResetStats()
+		assert.Equal(t, int64(2), before.Queries, "returns the counts up to the reset")
+		assert.Zero(t, client.StatsSnapshot())
+
+		_, err := client.Query(ok)
+		assert.NoError(t, err)
+		assert.Equal(t, ClientStats{Queries: 1, ComputeOps: 1, ReadOps: 2, WriteOps: 3}, client.StatsSnapshot())
+	})
+
+	t.Run("subtracts", func(t *testing.T) {
+		earlier := client.StatsSnapshot()
+		_, err := client.Query(ok)
+		assert.NoError(t, err)
+		assert.Equal(t, ClientStats{Queries: 1, ComputeOps: 1, ReadOps: 2, WriteOps: 3}, client.StatsSnapshot().Sub(earlier))
+	})
+}
@@ -0,0 +1,601 @@
+package fauna
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type streamOptions struct {
+	cursor    string
+	startTime time.Time
+	dedup     int
+	name      string
+
+	checkpoint checkpointer
+}
+
+// StreamOptFn function to set options on [Client.Stream]
+type StreamOptFn func(o *streamOptions)
+
+// StreamCursor resumes the stream after the event at cursor, as given by
+// [fauna.Event] or [Stream.Cursor].
+func StreamCursor(cursor string) StreamOptFn {
+	return func(o *streamOptions) { o.cursor = cursor }
+}
+
+// StreamStartTime starts the stream with the events after t, which must be
+// within the database's history retention, so a consumer that was offline
+// catches up on the events it missed before receiving new ones. It's ignored
+// if [fauna.StreamCursor] is set.
+func StreamStartTime(t time.Time) StreamOptFn {
+	return func(o *streamOptions) { o.startTime = t }
+}
+
+// StreamDedup drops events for the same document at the same transaction time
+// as one of the last window events, such as those received again when the
+// stream resumes after reconnecting, for consumers which can't apply a change
+// twice. Events whose data isn't a document are compared by their data.
+func StreamDedup(window int) StreamOptFn {
+	return func(o *streamOptions) { o.dedup = window }
+}
+
+// StreamName names the stream in its [Stream.Health] and in the metrics
+// recorded with [fauna.WithMeter], to tell a client's streams apart.
+func StreamName(name string) StreamOptFn {
+	return func(o *streamOptions) { o.name = name }
+}
+
+// StreamCheckpoint saves the stream's cursor in store under name once an event
+// has been processed, which is when [Stream.Next] is called for the event after
+// it, or [Stream.Checkpoint] is called. A stream without [fauna.StreamCursor]
+// resumes from the stored cursor, so a consumer restarted after failing while
+// processing an event receives it again.
+func StreamCheckpoint(store CheckpointStore, name string) StreamOptFn {
+	return func(o *streamOptions) { o.checkpoint = checkpointer{store: store, name: name} }
+}
+
+// ErrStreamDisconnected is returned by [Stream.Next] when a stream's
+// connection dropped and it couldn't reconnect within the [fauna.Client]'s
+// MaxAttempts. It's retryable: the stream can be reopened later with
+// [fauna.StreamCursor].
+type ErrStreamDisconnected struct {
+	// Attempts is the number of attempts made to reconnect.
+	Attempts int
+
+	// Err is why the last attempt failed.
+	Err error
+}
+
+// Error describes the disconnection.
+func (e *ErrStreamDisconnected) Error() string {
+	return fmt.Sprintf("event stream disconnected after %d attempts to reconnect: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt to reconnect.
+func (e *ErrStreamDisconnected) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports true, as the stream can be reopened.
+func (e *ErrStreamDisconnected) Retryable() bool {
+	return true
+}
+
+// Stream receives the events of an [fauna.EventSource] as they happen. If the
+// connection drops the stream reconnects, resuming after the last event
+// received. A Stream's Next is not safe for concurrent use, but Close and
+// Drain can be called from any goroutine.
+type Stream struct {
+	client *Client
+	http   *http.Client
+	source EventSource
+
+	// ctx is canceled when the stream is closed, or parent is done
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// cursor and txnTime are where the stream resumes from
+	cursor  string
+	txnTime int64
+
+	name       string
+	seen       *eventWindow
+	checkpoint *checkpointer
+
+	mu     sync.Mutex
+	body   io.ReadCloser
+	dec    *json.Decoder
+	closed bool
+	health StreamHealth
+
+	// draining is closed by Drain, and drained once the stream has drained or
+	// closed
+	draining  chan struct{}
+	drained   chan struct{}
+	drainOnce sync.Once
+	drainErr  error
+}
+
+// StreamHealth describes how a [fauna.Stream] is keeping up, to alert on
+// consumers which are stuck or falling behind.
+type StreamHealth struct {
+	// Name is the stream's [fauna.StreamName].
+	Name string
+
+	// EventsReceived is the number of events received, including any dropped
+	// by [fauna.StreamDedup].
+	EventsReceived int64
+
+	// Reconnects is the number of times the stream reconnected after its
+	// connection dropped.
+	Reconnects int64
+
+	// LastTxnTime is the transaction time the stream has caught up to, as of
+	// the last message received.
+	LastTxnTime time.Time
+
+	// LastHeartbeat is when the last message was received. A stream receives
+	// status messages while there are no events, so a stale heartbeat means
+	// the connection is stuck.
+	LastHeartbeat time.Time
+}
+
+// Lag returns how far behind the database the stream was as of now, the time
+// since [fauna.StreamHealth.LastTxnTime], or zero before any message has been
+// received.
+func (h StreamHealth) Lag(now time.Time) time.Duration {
+	if h.LastTxnTime.IsZero() {
+		return 0
+	}
+
+	return now.Sub(h.LastTxnTime)
+}
+
+// Health returns the stream's [fauna.StreamHealth]. Unlike [Stream.Next], it
+// can be called from any goroutine.
+func (s *Stream) Health() StreamHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.health
+}
+
+// Stream opens a [fauna.Stream] of the events of source, starting from when
+// the stream is opened unless [fauna.StreamCursor] or
+// [fauna.StreamStartTime] is set. The stream lasts until ctx is done, when a
+// blocked [Stream.Next] returns the ctx's error, or the stream is closed with
+// [Stream.Close], which it must be.
+func (c *Client) Stream(ctx context.Context, source EventSource, opts ...StreamOptFn) (*Stream, error) {
+	var o streamOptions
+	for _, optFn := range opts {
+		optFn(&o)
+	}
+
+	// the client's timeout covers reading the whole response, which a stream
+	// never finishes
+	httpClient := *c.http
+	httpClient.Timeout = 0
+
+	s := &Stream{client: c, http: &httpClient, source: source, cursor: o.cursor, name: o.name}
+	s.health.Name = o.name
+	if o.checkpoint.store != nil {
+		s.checkpoint = &o.checkpoint
+		if o.cursor == "" {
+			var err error
+			if o.cursor, err = s.checkpoint.load(); err != nil {
+				return nil, err
+			}
+			s.cursor = o.cursor
+		} else {
+			s.checkpoint.saved = o.cursor
+		}
+	}
+	if o.dedup > 0 {
+		s.seen = newEventWindow(o.dedup)
+	}
+	if o.cursor == "" && !o.startTime.IsZero() {
+		s.txnTime = o.startTime.UnixMicro()
+	}
+	s.parent = ctx
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.draining, s.drained = make(chan struct{}), make(chan struct{})
+
+	if err := s.open(); err != nil {
+		s.cancel()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+type streamRequest struct {
+	Token   EventSource `json:"token"`
+	Cursor  string      `json:"cursor,omitempty"`
+	StartTS int64       `json:"start_ts,omitempty"`
+}
+
+// open connects to the stream, resuming from its cursor or transaction time.
+func (s *Stream) open() error {
+	streamReq := streamRequest{Token: s.source, Cursor: s.cursor}
+	if s.cursor == "" {
+		streamReq.StartTS = s.txnTime
+	}
+
+	body, err := json.Marshal(streamReq)
+	if err != nil {
+		return fmt.Errorf("marshal request failed: %w", err)
+	}
+
+	req, err := s.client.eventRequest(s.ctx, "stream", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(HeaderRequestID, newRequestID())
+
+	start := time.Now()
+	r, doErr := s.http.Do(req)
+	if doErr != nil {
+		return newErrNetwork(req, 1, start, doErr)
+	}
+
+	if r.StatusCode != http.StatusOK {
+		defer r.Body.Close()
+
+		bin, readErr := io.ReadAll(r.Body)
+		if readErr != nil {
+			return fmt.Errorf("failed to read response body: %w", readErr)
+		}
+
+		var res queryResponse
+		if err := json.Unmarshal(bin, &res); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		if serviceErr := s.client.codec.getErrFauna(r.StatusCode, &res); serviceErr != nil {
+			return serviceErr
+		}
+
+		return fmt.Errorf("unexpected status %d from event stream", r.StatusCode)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || s.isDraining() {
+		r.Body.Close()
+		return io.EOF
+	}
+	s.body, s.dec = r.Body, json.NewDecoder(r.Body)
+
+	return nil
+}
+
+// Next blocks until the next event is received. It returns [io.EOF] once the
+// stream is closed or drained, and the error of the stream's context once it's
+// done, which also closes the stream. The stream reconnects after transient
+// failures, including error events whose [ErrEvent.Retryable] is true, and
+// returns [fauna.ErrStreamDisconnected] if it can't. Other failures close the
+// stream, returning the error event's [fauna.ErrEvent] or the Fauna error
+// refusing to reopen the stream, such as [fauna.ErrAuthorization].
+func (s *Stream) Next() (*Event, error) {
+	if err := s.Checkpoint(); err != nil {
+		return nil, err
+	}
+
+	failures := 0
+
+	for {
+		s.mu.Lock()
+		dec, closed := s.dec, s.closed
+		s.mu.Unlock()
+		if closed {
+			return nil, io.EOF
+		}
+
+		var ev feedEvent
+		if err := dec.Decode(&ev); err != nil {
+			if s.isClosed() {
+				return nil, io.EOF
+			}
+			if ctxErr := s.parent.Err(); ctxErr != nil {
+				_ = s.Close()
+				return nil, ctxErr
+			}
+			if s.isDraining() {
+				return nil, s.finishDrain()
+			}
+
+			failures++
+			if reconnectErr := s.reconnect(failures, err); reconnectErr != nil {
+				return nil, reconnectErr
+			}
+			continue
+		}
+
+		// an error event right after reconnecting is another failure in a row
+		if ev.Type != "start" && ev.Type != "error" {
+			failures = 0
+		}
+
+		if ev.Cursor != "" {
+			s.cursor = ev.Cursor
+		}
+		if ev.TxnTime > s.txnTime {
+			s.txnTime = ev.TxnTime
+		}
+
+		s.received(ev.Type != "start" && ev.Type != "status")
+
+		switch ev.Type {
+		case "start", "status":
+			continue
+		case "error":
+			if ev.Error == nil {
+				ev.Error = &ErrEvent{Code: "unknown", Message: "the event stream failed"}
+			}
+			if ev.Error.Retryable() {
+				failures++
+				if reconnectErr := s.reconnect(failures, ev.Error); reconnectErr != nil {
+					return nil, reconnectErr
+				}
+				continue
+			}
+
+			_ = s.Close()
+			return nil, ev.Error
+		}
+
+		event, err := ev.event(s.client.codec)
+		if err != nil {
+			return nil, err
+		}
+		s.client.syncTxnTime(event.TxnTime)
+
+		if s.seen != nil && !s.seen.add(eventKey(&event)) {
+			continue
+		}
+
+		return &event, nil
+	}
+}
+
+// received records a message in the stream's health and metrics.
+func (s *Stream) received(event bool) {
+	now := time.Now()
+
+	s.mu.Lock()
+	if event {
+		s.health.EventsReceived++
+	}
+	if s.txnTime > 0 {
+		s.health.LastTxnTime = time.UnixMicro(s.txnTime)
+	}
+	s.health.LastHeartbeat = now
+	health := s.health
+	s.mu.Unlock()
+
+	if event {
+		s.client.stats.streamEvents.Add(1)
+	}
+	s.client.metrics.streamMessage(s.ctx, s.name, event, health.Lag(now))
+}
+
+// reconnect reopens a stream which dropped with cause, backing off between
+// attempts, and giving up and closing the stream once the [fauna.Client]'s
+// MaxAttempts attempts fail in a row.
+func (s *Stream) reconnect(failures int, cause error) error {
+	s.mu.Lock()
+	if s.body != nil {
+		s.body.Close()
+	}
+	s.mu.Unlock()
+
+	for {
+		if failures > s.client.maxAttempts {
+			_ = s.Close()
+			return &ErrStreamDisconnected{Attempts: failures - 1, Err: cause}
+		}
+
+		select {
+		case <-s.ctx.Done():
+			if ctxErr := s.parent.Err(); ctxErr != nil {
+				_ = s.Close()
+				return ctxErr
+			}
+			return io.EOF
+		case <-s.draining:
+			return s.finishDrain()
+		case <-time.After(s.client.backoff(failures - 1)):
+		}
+
+		err := s.open()
+		if err == nil {
+			s.mu.Lock()
+			s.health.Reconnects++
+			s.mu.Unlock()
+
+			s.client.stats.streamReconnects.Add(1)
+			s.client.metrics.streamReconnect(s.ctx, s.name)
+			return nil
+		}
+
+		// Fauna refusing the stream won't change by retrying, unless it's
+		// a transient problem with Fauna
+		var fe interface{ base() *ErrFauna }
+		if errors.As(err, &fe) && !streamRetryable(err) {
+			_ = s.Close()
+			return err
+		}
+		failures++
+		cause = err
+	}
+}
+
+// streamRetryable reports whether Fauna refused to open a stream because of a
+// transient problem.
+func streamRetryable(err error) bool {
+	var (
+		throttling *ErrThrottling
+		timeout    *ErrServiceTimeout
+		internal   *ErrServiceInternal
+	)
+
+	return errors.As(err, &throttling) ||
+		errors.As(err, &timeout) ||
+		errors.As(err, &internal)
+}
+
+func (s *Stream) isDraining() bool {
+	select {
+	case <-s.draining:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Stream) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closed
+}
+
+// Cursor returns the position the stream resumes from, to reopen it later
+// with [fauna.StreamCursor].
+func (s *Stream) Cursor() string {
+	return s.cursor
+}
+
+// Checkpoint saves the stream's cursor with its [fauna.StreamCheckpoint]
+// store, such as once the last event received has been processed before
+// closing the stream. It does nothing for a stream without a store.
+func (s *Stream) Checkpoint() error {
+	return s.checkpoint.save(s.cursor)
+}
+
+// Close closes the stream, causing a blocked [Stream.Next] to return
+// [io.EOF].
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.cancel()
+	s.drainOnce.Do(func() { close(s.drained) })
+
+	if s.body != nil {
+		return s.body.Close()
+	}
+
+	return nil
+}
+
+// Drain closes the stream gracefully, such as when a consumer is being
+// replaced during a deploy. The stream stops receiving events, and
+// [Stream.Next] returns the events already received before returning
+// [io.EOF], saving the stream's [fauna.StreamCheckpoint] once the last of them
+// has been processed. Drain blocks until then, or until ctx is done, when the
+// stream is closed without saving its checkpoint and the ctx's error is
+// returned. It returns the error saving the checkpoint, if any.
+func (s *Stream) Drain(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	if !s.isDraining() {
+		close(s.draining)
+	}
+	body := s.body
+	s.mu.Unlock()
+
+	// closing the connection leaves the events the decoder has buffered to be
+	// read
+	if body != nil {
+		_ = body.Close()
+	}
+
+	select {
+	case <-s.drained:
+		return s.drainErr
+	case <-ctx.Done():
+		_ = s.Close()
+		return ctx.Err()
+	}
+}
+
+// finishDrain saves the checkpoint of a drained stream and closes it,
+// returning the error for [Stream.Next].
+func (s *Stream) finishDrain() error {
+	err := s.Checkpoint()
+	s.mu.Lock()
+	s.drainErr = err
+	s.mu.Unlock()
+	_ = s.Close()
+
+	if err != nil {
+		return err
+	}
+
+	return io.EOF
+}
+
+// eventKey identifies the change an event describes, by its document and
+// transaction time.
+func eventKey(event *Event) string {
+	ts := strconv.FormatInt(event.TxnTime, 10)
+
+	switch doc := event.Data.(type) {
+	case *Document:
+		if doc.Coll != nil {
+			return ts + "\x00" + doc.Coll.Name + "\x00" + doc.ID
+		}
+	case *NamedDocument:
+		if doc.Coll != nil {
+			return ts + "\x00" + doc.Coll.Name + "\x00\x00" + doc.Name
+		}
+	}
+
+	return ts + "\x00\x00" + string(event.Raw)
+}
+
+// eventWindow is the set of the last keys added to it.
+type eventWindow struct {
+	keys  map[string]struct{}
+	order []string
+	next  int
+}
+
+func newEventWindow(size int) *eventWindow {
+	return &eventWindow{keys: make(map[string]struct{}, size), order: make([]string, 0, size)}
+}
+
+// add adds key, reporting false if it's already in the window.
+func (w *eventWindow) add(key string) bool {
+	if _, seen := w.keys[key]; seen {
+		return false
+	}
+
+	if len(w.order) < cap(w.order) {
+		w.order = append(w.order, key)
+	} else {
+		delete(w.keys, w.order[w.next])
+		w.order[w.next] = key
+		w.next = (w.next + 1) % len(w.order)
+	}
+	w.keys[key] = struct{}{}
+
+	return true
+}
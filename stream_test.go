@@ -0,0 +1,390 @@
+package fauna
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStreamServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []map[string]any
+}
+
+// newTestStreamServer is a fake event stream endpoint which writes the events
+// connection returns for each connection, in order, then holds the last
+// connection open.
+func newTestStreamServer(t *testing.T, connections ...[]string) *testStreamServer {
+	srv := &testStreamServer{}
+	srv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		srv.mu.Lock()
+		n := len(srv.requests)
+		srv.requests = append(srv.requests, body)
+		srv.mu.Unlock()
+
+		if n >= len(connections) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, errorBody("invalid_stream", "too many connections"))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, event := range connections[n] {
+			_, _ = io.WriteString(w, event+"\n")
+		}
+		w.(http.Flusher).Flush()
+
+		if n == len(connections)-1 {
+			<-r.Context().Done()
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func (s *testStreamServer) received() []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]map[string]any{}, s.requests...)
+}
+
+func TestStream(t *testing.T) {
+	srv := newTestStreamServer(t,
+		[]string{
+			`{"type":"start","txn_ts":10}`,
+			`{"type":"add","txn_ts":11,"cursor":"c1","data":{"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"},"name":"Scout"}}}`,
+		},
+		[]string{
+			`{"type":"start","txn_ts":12}`,
+			`{"type":"status","txn_ts":12,"cursor":"c1"}`,
+			`{"type":"update","txn_ts":13,"cursor":"c2","data":{"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"},"name":"Scout II"}}}`,
+		},
+	)
+	client := NewClient("secret", DefaultTimeouts(), URL(srv.URL), MaxBackoff(time.Millisecond))
+
+	stream, err := client.Stream(context.Background(), "token", StreamStartTime(time.UnixMicro(5)))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer stream.Close()
+
+	event, err := stream.Next()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "add", event.Type)
+		assert.Equal(t, "c1", event.Cursor)
+		assert.IsType(t, &Document{}, event.Data)
+	}
+
+	event, err = stream.Next()
+	if assert.NoError(t, err, "the stream reconnects after the connection drops") {
+		var dog struct {
+			Name string `fauna:"name"`
+		}
+		assert.Equal(t, "update", event.Type)
+		assert.NoError(t, event.Unmarshal(&dog))
+		assert.Equal(t, "Scout II", dog.Name)
+	}
+	assert.Equal(t, "c2", stream.Cursor())
+
+	received := srv.received()
+	if assert.Len(t, received, 2) {
+		assert.Equal(t, map[string]any{"token": "token", "start_ts": float64(5)}, received[0])
+		assert.Equal(t, map[string]any{"token": "token", "cursor": "c1"}, received[1], "the stream resumes after the last event")
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := stream.Next()
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, stream.Close())
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, io.EOF)
+	case <-time.After(time.Second):
+		t.Fatal("Close didn't unblock Next")
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	t.Run("error events", func(t *testing.T) {
+		srv := newTestStreamServer(t, []string{
+			`{"type":"start","txn_ts":10}`,
+			`{"type":"error","txn_ts":11,"error":{"code":"permission_denied","message":"no access"}}`,
+		})
+		client := NewClient("secret", DefaultTimeouts(), URL(srv.URL))
+
+		stream, err := client.Stream(context.Background(), "token")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		_, err = stream.Next()
+		var eventErr *ErrEvent
+		if assert.ErrorAs(t, err, &eventErr) {
+			assert.Equal(t, "permission_denied", eventErr.Code)
+			assert.False(t, eventErr.Retryable())
+		}
+
+		_, err = stream.Next()
+		assert.ErrorIs(t, err, io.EOF, "the stream is closed")
+	})
+
+	t.Run("retryable error events", func(t *testing.T) {
+		srv := newTestStreamServer(t,
+			[]string{
+				`{"type":"start","txn_ts":10}`,
+				`{"type":"add","txn_ts":11,"cursor":"c1","data":1}`,
+				`{"type":"error","txn_ts":12,"error":{"code":"internal_error","message":"restarting"}}`,
+			},
+			[]string{
+				`{"type":"start","txn_ts":12}`,
+				`{"type":"add","txn_ts":13,"cursor":"c2","data":2}`,
+			},
+		)
+		client := NewClient("secret", DefaultTimeouts(), URL(srv.URL), MaxBackoff(time.Millisecond))
+
+		stream, err := client.Stream(context.Background(), "token")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer stream.Close()
+
+		for _, cursor := range []string{"c1", "c2"} {
+			event, err := stream.Next()
+			if assert.NoError(t, err) {
+				assert.Equal(t, cursor, event.Cursor)
+			}
+		}
+		assert.Equal(t, "c1", srv.received()[1]["cursor"], "the stream resumes after the error")
+	})
+
+	t.Run("gives up reconnecting", func(t *testing.T) {
+		failing := []string{`{"type":"start","txn_ts":10}`, `{"type":"error","txn_ts":10,"error":{"code":"internal_error","message":"restarting"}}`}
+		srv := newTestStreamServer(t, failing, failing, failing)
+		client := NewClient("secret", DefaultTimeouts(), URL(srv.URL), MaxAttempts(2), MaxBackoff(time.Millisecond))
+
+		stream, err := client.Stream(context.Background(), "token")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		_, err = stream.Next()
+		var disconnected *ErrStreamDisconnected
+		if assert.ErrorAs(t, err, &disconnected) {
+			assert.Equal(t, 2, disconnected.Attempts)
+			assert.True(t, disconnected.Retryable())
+		}
+		var eventErr *ErrEvent
+		if assert.ErrorAs(t, err, &eventErr, "the last failure is unwrapped") {
+			assert.True(t, eventErr.Retryable())
+		}
+
+		_, err = stream.Next()
+		assert.ErrorIs(t, err, io.EOF, "the stream is closed")
+	})
+
+	t.Run("refused streams", func(t *testing.T) {
+		srv := newTestStreamServer(t)
+		client := NewClient("secret", DefaultTimeouts(), URL(srv.URL))
+
+		_, err := client.Stream(context.Background(), "token")
+		assert.ErrorAs(t, err, new(*ErrInvalidRequest))
+	})
+}
+
+func TestStreamDedup(t *testing.T) {
+	add := `{"type":"add","txn_ts":11,"cursor":"c1","data":{"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"}}}}`
+	update := `{"type":"update","txn_ts":13,"cursor":"c2","data":{"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"}}}}`
+	srv := newTestStreamServer(t,
+		[]string{`{"type":"start","txn_ts":10}`, add},
+		[]string{`{"type":"start","txn_ts":10}`, add, update},
+	)
+	client := NewClient("secret", DefaultTimeouts(), URL(srv.URL), MaxBackoff(time.Millisecond))
+
+	stream, err := client.Stream(context.Background(), "token", StreamDedup(10))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer stream.Close()
+
+	var types []string
+	for i := 0; i < 2; i++ {
+		event, err := stream.Next()
+		if !assert.NoError(t, err) {
+			return
+		}
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []string{"add", "update"}, types, "the replayed add is dropped")
+
+	t.Run("window", func(t *testing.T) {
+		w := newEventWindow(2)
+		assert.True(t, w.add("a"))
+		assert.True(t, w.add("b"))
+		assert.False(t, w.add("a"))
+		assert.True(t, w.add("c"))
+		assert.True(t, w.add("a"), "the oldest key is evicted")
+		assert.False(t, w.add("c"))
+	})
+}
+
+func TestStreamHealth(t *testing.T) {
+	srv := newTestStreamServer(t,
+		[]string{
+			`{"type":"start","txn_ts":1680000000000000}`,
+			`{"type":"add","txn_ts":1680000001000000,"cursor":"c1","data":{"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"}}}}`,
+		},
+		[]string{
+			`{"type":"status","txn_ts":1680000002000000,"cursor":"c1"}`,
+			`{"type":"add","txn_ts":1680000003000000,"cursor":"c2","data":{"@doc":{"id":"2","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"}}}}`,
+		},
+	)
+	meter := newTestMeter()
+	client := NewClient("secret", DefaultTimeouts(), URL(srv.URL), MaxBackoff(time.Millisecond), WithMeter(meter))
+
+	stream, err := client.Stream(context.Background(), "token", StreamName("dogs"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer stream.Close()
+
+	assert.Equal(t, StreamHealth{Name: "dogs"}, stream.Health())
+	assert.Zero(t, stream.Health().Lag(time.Now()))
+
+	before := time.Now()
+	for i := 0; i < 2; i++ {
+		_, err := stream.Next()
+		assert.NoError(t, err)
+	}
+
+	health := stream.Health()
+	assert.Equal(t, int64(2), health.EventsReceived)
+	assert.Equal(t, int64(1), health.Reconnects)
+	assert.Equal(t, time.UnixMicro(1680000003000000), health.LastTxnTime)
+	assert.False(t, health.LastHeartbeat.Before(before))
+	assert.Equal(t, 5*time.Second, health.Lag(time.UnixMicro(1680000008000000)))
+
+	attrs := []Attribute{{"db.system", "fauna"}, {"fauna.stream", "dogs"}}
+	assert.Equal(t, 2.0, meter.sum(MetricStreamEvents))
+	assert.Equal(t, 1.0, meter.sum(MetricStreamReconnects))
+	assert.Equal(t, attrs, meter.last(MetricStreamReconnects).attrs)
+	assert.Len(t, meter.measurements[MetricStreamLag], 4, "lag is measured on every message")
+	assert.Equal(t, attrs, meter.last(MetricStreamLag).attrs)
+	assert.Equal(t, "s", meter.units[MetricStreamLag])
+}
+
+func TestStreamDrain(t *testing.T) {
+	srv := newTestStreamServer(t, []string{
+		`{"type":"start","txn_ts":10}`,
+		`{"type":"add","txn_ts":11,"cursor":"c1","data":1}`,
+		`{"type":"add","txn_ts":12,"cursor":"c2","data":2}`,
+		`{"type":"add","txn_ts":13,"cursor":"c3","data":3}`,
+	})
+	client := NewClient("secret", DefaultTimeouts(), URL(srv.URL), MaxBackoff(time.Millisecond))
+	store := NewMemoryCheckpointStore()
+
+	stream, err := client.Stream(context.Background(), "token", StreamCheckpoint(store, "numbers"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer stream.Close()
+
+	_, err = stream.Next()
+	assert.NoError(t, err)
+
+	drained := make(chan error)
+	go func() { drained <- stream.Drain(context.Background()) }()
+
+	var cursors []string
+	for {
+		event, err := stream.Next()
+		if err != nil {
+			assert.ErrorIs(t, err, io.EOF)
+			break
+		}
+		cursors = append(cursors, event.Cursor)
+	}
+	assert.Equal(t, []string{"c2", "c3"}, cursors, "buffered events are delivered")
+
+	select {
+	case err := <-drained:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Drain didn't return")
+	}
+
+	cursor, _ := store.Load("numbers")
+	assert.Equal(t, "c3", cursor, "the checkpoint is flushed")
+	assert.Len(t, srv.received(), 1, "a draining stream doesn't reconnect")
+
+	t.Run("times out", func(t *testing.T) {
+		srv := newTestStreamServer(t, []string{`{"type":"add","txn_ts":11,"cursor":"c1","data":1}`})
+		client := NewClient("secret", DefaultTimeouts(), URL(srv.URL))
+		store := NewMemoryCheckpointStore()
+
+		stream, err := client.Stream(context.Background(), "token", StreamCheckpoint(store, "numbers"))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, stream.Drain(ctx), context.DeadlineExceeded)
+
+		_, err = stream.Next()
+		assert.ErrorIs(t, err, io.EOF, "the stream is closed")
+
+		cursor, _ := store.Load("numbers")
+		assert.Empty(t, cursor)
+	})
+}
+
+func TestStreamContext(t *testing.T) {
+	srv := newTestStreamServer(t, []string{`{"type":"start","txn_ts":10}`})
+	client := NewClient("secret", DefaultTimeouts(), URL(srv.URL))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Stream(ctx, "token")
+	if !assert.NoError(t, err) {
+		cancel()
+		return
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := stream.Next()
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("canceling the context didn't unblock Next")
+	}
+
+	_, err = stream.Next()
+	assert.ErrorIs(t, err, io.EOF, "the stream is closed")
+
+	// the test server's handler only returns once the connection is aborted,
+	// which its cleanup waits for
+}
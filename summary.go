@@ -0,0 +1,139 @@
+package fauna
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SummarySeverity is the kind of a [fauna.SummaryEntry].
+type SummarySeverity string
+
+const (
+	SeverityError           SummarySeverity = "error"
+	SeverityWarning         SummarySeverity = "warning"
+	SeverityPerformanceHint SummarySeverity = "performance_hint"
+	SeverityInfo            SummarySeverity = "info"
+)
+
+// SummaryEntry is an error, warning, performance hint or log message from a
+// query's summary.
+type SummaryEntry struct {
+	Severity SummarySeverity
+
+	// Code identifies the kind of performance hint, such as `full_set_read`,
+	// and is empty for other entries.
+	Code string
+
+	Message string
+
+	// Source is where the entry's span is, such as `*query*` for the query
+	// itself, and Line and Column its one-based position. Source is empty for
+	// entries without a position.
+	Source string
+	Line   int
+	Column int
+
+	// Snippet is the excerpt of the source the entry refers to, with the span
+	// underlined.
+	Snippet string
+
+	// Hints are suggestions for fixing an error.
+	Hints []string
+}
+
+var (
+	summaryHeader   = regexp.MustCompile(`^([a-z_]+)(?: at (.+?):(\d+))?: (.*)$`)
+	summaryPosition = regexp.MustCompile(`^at (.+?):(\d+):(\d+)$`)
+	summarySnippet  = regexp.MustCompile(`^\s*\d*\s*\|`)
+
+	// ansiEscape matches terminal escape sequences: CSI sequences such as
+	// colors, and OSC sequences such as hyperlinks
+	ansiEscape = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+)
+
+// StripFormatting removes terminal escape sequences, such as colors, from a
+// query's summary, leaving plain text. See [fauna.PlainSummary].
+func StripFormatting(summary string) string {
+	if !strings.Contains(summary, "\x1b") {
+		return summary
+	}
+
+	return ansiEscape.ReplaceAllString(summary, "")
+}
+
+// ParseSummary splits a query's summary into its entries, after removing any
+// formatting with [fauna.StripFormatting]. Lines which aren't part of an entry
+// are ignored.
+func ParseSummary(summary string) []SummaryEntry {
+	summary = StripFormatting(summary)
+
+	var (
+		entries []SummaryEntry
+		current *SummaryEntry
+		snippet []string
+	)
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Snippet = strings.Join(snippet, "\n")
+		entries = append(entries, *current)
+		current, snippet = nil, nil
+	}
+
+	for _, line := range strings.Split(summary, "\n") {
+		if current != nil {
+			if m := summaryPosition.FindStringSubmatch(line); m != nil && current.Source == "" {
+				current.Source = m[1]
+				current.Line, _ = strconv.Atoi(m[2])
+				current.Column, _ = strconv.Atoi(m[3])
+				continue
+			}
+
+			if summarySnippet.MatchString(line) {
+				snippet = append(snippet, line)
+				continue
+			}
+
+			if hint := strings.TrimPrefix(line, "hint: "); hint != line {
+				current.Hints = append(current.Hints, hint)
+				continue
+			}
+		}
+
+		if m := summaryHeader.FindStringSubmatch(line); m != nil {
+			flush()
+
+			current = &SummaryEntry{Severity: SummarySeverity(m[1]), Message: m[4]}
+			if m[2] != "" {
+				current.Source = m[2]
+				current.Line, _ = strconv.Atoi(m[3])
+			}
+			if current.Severity == SeverityPerformanceHint {
+				if code, msg, found := strings.Cut(current.Message, " - "); found {
+					current.Code, current.Message = code, msg
+				}
+			}
+			continue
+		}
+
+		if current != nil && strings.TrimSpace(line) != "" && current.Source == "" && len(snippet) == 0 {
+			current.Message += "\n" + line
+		}
+	}
+	flush()
+
+	return entries
+}
+
+// SummaryEntries parses the query's [fauna.QueryInfo.Summary] with
+// [fauna.ParseSummary].
+func (i *QueryInfo) SummaryEntries() []SummaryEntry {
+	if i == nil {
+		return nil
+	}
+
+	return ParseSummary(i.Summary)
+}
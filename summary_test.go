@@ -0,0 +1,93 @@
+package fauna
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSummary(t *testing.T) {
+	summary := `info at *query*:1: fetching dogs
+
+performance_hint: full_set_read - Using .all() causes the query to read all documents.
+at *query*:1:6
+  |
+1 | Dogs.all().where(.age > 3)
+  |      ^^^^^
+  |
+
+error: Type ` + "`String`" + ` does not have field ` + "`foo`" + `
+at *query*:2:5
+  |
+2 | "a".foo
+  |     ^^^
+  |
+hint: Did you mean ` + "`length`" + `?`
+
+	entries := ParseSummary(summary)
+	if !assert.Len(t, entries, 3) {
+		return
+	}
+
+	assert.Equal(t, SummaryEntry{
+		Severity: SeverityInfo,
+		Message:  "fetching dogs",
+		Source:   "*query*",
+		Line:     1,
+	}, entries[0])
+
+	assert.Equal(t, SummaryEntry{
+		Severity: SeverityPerformanceHint,
+		Code:     "full_set_read",
+		Message:  "Using .all() causes the query to read all documents.",
+		Source:   "*query*",
+		Line:     1,
+		Column:   6,
+		Snippet:  "  |\n1 | Dogs.all().where(.age > 3)\n  |      ^^^^^\n  |",
+	}, entries[1])
+
+	assert.Equal(t, SeverityError, entries[2].Severity)
+	assert.Equal(t, "Type `String` does not have field `foo`", entries[2].Message)
+	assert.Equal(t, 2, entries[2].Line)
+	assert.Equal(t, 5, entries[2].Column)
+	assert.Equal(t, []string{"Did you mean `length`?"}, entries[2].Hints)
+
+	assert.Empty(t, ParseSummary(""))
+
+	info := &QueryInfo{Summary: "warning: deprecated"}
+	assert.Equal(t, []SummaryEntry{{Severity: SeverityWarning, Message: "deprecated"}}, info.SummaryEntries())
+}
+
+func TestPlainSummary(t *testing.T) {
+	colored := "\x1b[1;31merror\x1b[0m: invalid query\n\x1b]8;;https://docs.fauna.com\x07docs\x1b]8;;\x07"
+	assert.Equal(t, "error: invalid query\ndocs", StripFormatting(colored))
+	assert.Equal(t, "plain", StripFormatting("plain"))
+	assert.Equal(t, []SummaryEntry{{Severity: SeverityError, Message: "invalid query\ndocs"}}, ParseSummary(colored))
+
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		if req.Body["query"].(map[string]any)["fql"].([]any)[0] == "fail" {
+			return http.StatusBadRequest, `{"error":{"code":"invalid_query","message":"\u001b[31mbad\u001b[0m"},"summary":"\u001b[31merror\u001b[0m: bad"}`
+		}
+		return http.StatusOK, `{"data":null,"summary":"\u001b[33minfo\u001b[0m: ok"}`
+	})
+	ok, _ := FQL(`ok`, nil)
+	fail, _ := FQL(`fail`, nil)
+
+	res, err := srv.client().Query(ok)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "\x1b[33minfo\x1b[0m: ok", res.Summary, "summaries are left alone by default")
+	}
+
+	res, err = srv.client().Query(ok, QueryPlainSummary())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "info: ok", res.Summary)
+	}
+
+	_, err = srv.client(PlainSummary()).Query(fail)
+	var queryErr *ErrQueryCheck
+	if assert.ErrorAs(t, err, &queryErr) {
+		assert.Equal(t, "bad\nerror: bad", queryErr.Message)
+		assert.Equal(t, "error: bad", queryErr.Summary)
+	}
+}
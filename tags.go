@@ -0,0 +1,82 @@
+package fauna
+
+import (
+	"reflect"
+	"strings"
+)
+
+// FallbackTags reads the names and options of struct fields without a
+// `fauna` tag from the first of the named tags they have, such as "json", so
+// models already tagged for other encodings work without tagging them twice:
+//
+//	client := fauna.NewClient(secret, fauna.DefaultTimeouts(), fauna.FallbackTags("json"))
+//
+//	type Dog struct {
+//		Name  string `json:"name"`
+//		Owner *fauna.Ref `json:"owner,omitempty"`
+//		Notes string `json:"-"`
+//	}
+//
+// Options the driver doesn't know, such as `json:",string"`, are ignored.
+// Calling it without names only reads `fauna` tags, which is the default.
+// Functions which aren't given a client, such as [fauna.Marshal] and
+// [fauna.Redact], only read `fauna` tags.
+func FallbackTags(names ...string) ClientConfigFn {
+	tags := append([]string{}, names...)
+	return func(c *Client) {
+		c.codec.tags = tags
+	}
+}
+
+// structTag returns the `fauna` tag of field, or the first of the
+// [fauna.FallbackTags] it has, reporting whether it's a fallback.
+func (c codec) structTag(field reflect.StructField) (tag string, fallback bool) {
+	if tag, found := field.Tag.Lookup(fieldTag); found {
+		return tag, false
+	}
+
+	for _, name := range c.tags {
+		if tag, found := field.Tag.Lookup(name); found {
+			return tag, true
+		}
+	}
+
+	return "", false
+}
+
+// tagOf returns the `fauna` tag of field, or its fallback tag.
+func (c codec) tagOf(field reflect.StructField) string {
+	tag, _ := c.structTag(field)
+	return tag
+}
+
+// renameFields is a decode hook which renames the fields of a document
+// decoded into the struct type t whose names come from fallback tags to the
+// names of their Go fields, and drops those the fallback tags ignore, as the
+// generic decoder only reads `fauna` tags.
+func (c codec) renameFields(_ reflect.Type, t reflect.Type, data any) (any, error) {
+	if t.Kind() != reflect.Struct {
+		return data, nil
+	}
+
+	fields, isMap := data.(map[string]any)
+	if !isMap {
+		return data, nil
+	}
+
+	info := c.structInfoFor(t)
+	if len(info.renamed) == 0 && len(info.ignored) == 0 {
+		return data, nil
+	}
+
+	out := make(map[string]any, len(fields))
+	for key, value := range fields {
+		if renamed, found := info.renamed[key]; found {
+			out[renamed] = value
+		} else if !info.ignored[strings.ToLower(key)] {
+			out[key] = value
+		}
+	}
+
+	return out, nil
+}
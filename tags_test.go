@@ -0,0 +1,93 @@
+package fauna
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tagsTestOwner struct {
+	FullName string `json:"full_name"`
+}
+
+type tagsTestDog struct {
+	Document
+	Owner    tagsTestOwner `json:"owner"`
+	Name     string        `json:"name"`
+	Born     time.Time     `json:"born" fauna:"birthday,date"`
+	Nickname string        `json:"nickname,omitempty"`
+	Secret   string        `json:"-"`
+	Vet      *Ref          `bson:"vet"`
+	Weight   float64       `json:"weight,string"`
+	Walked   *time.Time    `json:"walked"`
+}
+
+func TestFallbackTags(t *testing.T) {
+	c := codec{tags: []string{"json", "bson"}}
+
+	born := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	dog := tagsTestDog{
+		Owner:  tagsTestOwner{FullName: "Ada"},
+		Name:   "Scout",
+		Born:   born,
+		Secret: "hidden",
+		Vet:    &Ref{ID: "1", Coll: &Module{"Vets"}},
+		Weight: 12.5,
+	}
+
+	bin, err := c.marshal(dog)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{
+			"owner":{"full_name":"Ada"},
+			"name":"Scout","birthday":{"@date":"2020-01-02"},
+			"vet":{"@ref":{"id":"1","coll":{"@mod":"Vets"}}},
+			"weight":{"@double":"12.5"},"walked":null
+		}`, string(bin), "fauna tags take precedence and json's ignored fields are skipped")
+	}
+
+	const body = `{"@doc":{
+		"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"},
+		"owner":{"full_name":"Ada"},"name":"Scout","birthday":{"@date":"2020-01-02"},"nickname":"Scooter",
+		"Secret":"leaked","vet":{"@ref":{"id":"1","coll":{"@mod":"Vets"}}},"weight":{"@double":"12.5"}
+	}}`
+	for name, decode := range map[string]func([]byte, any) error{"generic": c.unmarshal, "direct": c.decodeDirect} {
+		var got tagsTestDog
+		if assert.NoError(t, decode([]byte(body), &got), name) {
+			assert.Equal(t, "1", got.ID, name)
+			assert.Equal(t, "Ada", got.Owner.FullName, name)
+			assert.Equal(t, "Scout", got.Name, name)
+			assert.Equal(t, "Scooter", got.Nickname, name)
+			assert.Equal(t, born, got.Born, name)
+			assert.Empty(t, got.Secret, name)
+			assert.Equal(t, &Ref{ID: "1", Coll: &Module{"Vets"}}, got.Vet, name)
+			assert.Equal(t, 12.5, got.Weight, name)
+		}
+	}
+
+	var got tagsTestDog
+	assert.NoError(t, unmarshal([]byte(`{"owner":{"full_name":"Ada"},"Owner":{"FullName":"Bob"}}`), &got))
+	assert.Equal(t, "Bob", got.Owner.FullName, "fallback tags can be turned off")
+}
+
+func TestClientFallbackTags(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		return http.StatusOK, successBody(`{"owner":{"full_name":"Ada"},"Owner":{"FullName":"Bob"}}`)
+	})
+	q, _ := FQL(`null`, nil)
+
+	tagged := srv.client(FallbackTags("json"))
+	untagged := srv.client()
+	for i := 0; i < 2; i++ {
+		var got tagsTestDog
+		if _, err := tagged.QueryInto(q, &got); assert.NoError(t, err) {
+			assert.Equal(t, "Ada", got.Owner.FullName)
+		}
+
+		got = tagsTestDog{}
+		if _, err := untagged.QueryInto(q, &got); assert.NoError(t, err) {
+			assert.Equal(t, "Bob", got.Owner.FullName, "clients don't share fallback tags")
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package fauna
+
+import (
+	"fmt"
+	"time"
+)
+
+// PrecisionPolicy is how times with sub-microsecond precision are encoded,
+// as Fauna's times have microsecond precision.
+type PrecisionPolicy int
+
+const (
+	// PrecisionIgnore sends times as they are, leaving Fauna to drop the
+	// extra precision.
+	PrecisionIgnore PrecisionPolicy = iota
+
+	// PrecisionWarn truncates times to the microsecond, calling
+	// [fauna.TimeOptions] OnPrecisionLoss with each time which is.
+	PrecisionWarn
+
+	// PrecisionError fails to encode times with sub-microsecond precision.
+	PrecisionError
+)
+
+// TimeOptions controls how a client decodes and encodes times, with
+// [fauna.WithTimeOptions].
+type TimeOptions struct {
+	// KeepOffset decodes times with the offset Fauna sent them with, rather
+	// than in UTC, and encodes times with their offset, so they round-trip.
+	KeepOffset bool
+
+	// SubMicrosecond is how times with sub-microsecond precision are
+	// encoded. The default is [fauna.PrecisionIgnore].
+	SubMicrosecond PrecisionPolicy
+
+	// OnPrecisionLoss is called with each time truncated by
+	// [fauna.PrecisionWarn], such as to log it. It may be called
+	// concurrently.
+	OnPrecisionLoss func(t time.Time)
+}
+
+// WithTimeOptions sets how the client decodes and encodes times.
+func WithTimeOptions(opts TimeOptions) ClientConfigFn {
+	return func(c *Client) {
+		c.codec.times = opts
+	}
+}
+
+// parseTime parses the value of an @time, in UTC unless KeepOffset is set.
+func (c codec) parseTime(v string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !c.times.KeepOffset {
+		t = t.UTC()
+	}
+	return t, nil
+}
+
+// formatTime formats t as the value of an @time, in UTC unless KeepOffset is
+// set.
+func (c codec) formatTime(t time.Time) string {
+	if c.times.KeepOffset {
+		return t.Format(time.RFC3339Nano)
+	}
+	return t.UTC().Format(timeFormat)
+}
+
+// checkPrecision applies the SubMicrosecond policy to t before it's encoded.
+func (c codec) checkPrecision(t time.Time) (time.Time, error) {
+	if t.Nanosecond()%int(time.Microsecond) == 0 {
+		return t, nil
+	}
+
+	switch c.times.SubMicrosecond {
+	case PrecisionWarn:
+		if c.times.OnPrecisionLoss != nil {
+			c.times.OnPrecisionLoss(t)
+		}
+		return t.Truncate(time.Microsecond), nil
+
+	case PrecisionError:
+		return time.Time{}, fmt.Errorf("time %s has sub-microsecond precision", t.Format(time.RFC3339Nano))
+	}
+
+	return t, nil
+}
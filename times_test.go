@@ -0,0 +1,97 @@
+package fauna
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeOptions(t *testing.T) {
+	const body = `{"@time":"2023-04-01T02:00:00.123456+02:00"}`
+	utc := time.Date(2023, 4, 1, 0, 0, 0, 123456000, time.UTC)
+
+	decoders := func(c codec) map[string]func([]byte, any) error {
+		return map[string]func([]byte, any) error{"generic": c.unmarshal, "direct": c.decodeDirect}
+	}
+	for name, decode := range decoders(codec{}) {
+		var got time.Time
+		if assert.NoError(t, decode([]byte(body), &got), name) {
+			assert.Equal(t, utc, got, name)
+		}
+	}
+
+	keep := codec{times: TimeOptions{KeepOffset: true}}
+	for name, decode := range decoders(keep) {
+		var got time.Time
+		if assert.NoError(t, decode([]byte(body), &got), name) {
+			assert.True(t, utc.Equal(got), name)
+			_, offset := got.Zone()
+			assert.Equal(t, 2*60*60, offset, name)
+		}
+	}
+
+	t.Run("offsets round-trip", func(t *testing.T) {
+		var got time.Time
+		if !assert.NoError(t, keep.unmarshal([]byte(body), &got)) {
+			return
+		}
+
+		bin, err := keep.marshal(got)
+		if assert.NoError(t, err) {
+			assert.Equal(t, body, string(bin))
+		}
+
+		bin, err = marshal(got)
+		if assert.NoError(t, err) {
+			assert.Equal(t, `{"@time":"2023-04-01T00:00:00.123456Z"}`, string(bin))
+		}
+	})
+
+	precise := time.Date(2023, 4, 1, 0, 0, 0, 123456789, time.UTC)
+
+	bin, err := marshal(precise)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"@time":"2023-04-01T00:00:00.123456789Z"}`, string(bin))
+	}
+
+	var warned []time.Time
+	warn := codec{times: TimeOptions{SubMicrosecond: PrecisionWarn, OnPrecisionLoss: func(t time.Time) { warned = append(warned, t) }}}
+	bin, err = warn.marshal([]time.Time{precise, utc})
+	if assert.NoError(t, err) {
+		assert.Equal(t, `[{"@time":"2023-04-01T00:00:00.123456Z"},{"@time":"2023-04-01T00:00:00.123456Z"}]`, string(bin))
+		assert.Equal(t, []time.Time{precise}, warned)
+	}
+
+	strict := codec{times: TimeOptions{SubMicrosecond: PrecisionError}}
+	_, err = strict.marshal(precise)
+	assert.ErrorContains(t, err, "sub-microsecond")
+	_, err = strict.marshal(utc)
+	assert.NoError(t, err)
+}
+
+func TestClientTimeOptions(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		return http.StatusOK, successBody(`{"@time":"2023-04-01T02:00:00+02:00"}`)
+	})
+
+	precise := time.Date(2023, 4, 1, 0, 0, 0, 123456789, time.UTC)
+	q, _ := FQL(`${t}`, map[string]any{"t": precise})
+
+	client := srv.client(WithTimeOptions(TimeOptions{KeepOffset: true, SubMicrosecond: PrecisionError}))
+	_, err := client.Query(q)
+	assert.ErrorContains(t, err, "sub-microsecond")
+
+	q, _ = FQL(`Time.now()`, nil)
+	res, err := client.Query(q)
+	if assert.NoError(t, err) {
+		_, offset := res.Data.(*time.Time).Zone()
+		assert.Equal(t, 2*60*60, offset)
+	}
+
+	res, err = srv.client().Query(q)
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.UTC, res.Data.(*time.Time).Location(), "other clients are unaffected")
+	}
+}
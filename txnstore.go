@@ -0,0 +1,251 @@
+package fauna
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// TxnTimeStore persists the last transaction time seen by a [fauna.Client],
+// so read-your-writes consistency survives restarts and can be shared between
+// processes. Times are Unix microseconds, as returned by
+// [Client.GetLastTxnTime].
+type TxnTimeStore interface {
+	// Load returns the stored time, or zero if there isn't one.
+	Load() (int64, error)
+
+	// Store records ts, if it's later than the stored time.
+	Store(ts int64) error
+}
+
+// WithTxnTimeStore loads the [fauna.Client]'s last transaction time from
+// store, and stores it each time it advances. Times are stored in the
+// background, so queries don't wait for store; if the time advances faster
+// than it's stored, only the latest time is. [Client.Shutdown] waits for the
+// latest time to be stored.
+//
+// As client options can't fail, a failed load starts the client without a
+// last transaction time, as if there were no store. Errors storing times are
+// passed to the func set with [fauna.TxnTimeStoreErrors].
+func WithTxnTimeStore(store TxnTimeStore) ClientConfigFn {
+	return func(c *Client) {
+		c.txnTimeStore = newTxnTimePersister(store)
+		if ts, err := store.Load(); err == nil {
+			c.lastTxnTime.sync(ts)
+		}
+	}
+}
+
+// TxnTimeStoreErrors calls onError with each error storing the
+// [fauna.Client]'s last transaction time in its [fauna.TxnTimeStore], such
+// as to log it. It's called from the goroutine storing the time.
+func TxnTimeStoreErrors(onError func(err error)) ClientConfigFn {
+	return func(c *Client) { c.txnTimeStoreErrors = onError }
+}
+
+// MemoryTxnTimeStore is a [fauna.TxnTimeStore] shared by the clients in a
+// process.
+type MemoryTxnTimeStore struct {
+	ts int64
+}
+
+// NewMemoryTxnTimeStore creates an empty [fauna.MemoryTxnTimeStore].
+func NewMemoryTxnTimeStore() *MemoryTxnTimeStore {
+	return &MemoryTxnTimeStore{}
+}
+
+// Load returns the stored time.
+func (s *MemoryTxnTimeStore) Load() (int64, error) {
+	return atomic.LoadInt64(&s.ts), nil
+}
+
+// Store records ts, if it's later than the stored time.
+func (s *MemoryTxnTimeStore) Store(ts int64) error {
+	for {
+		old := atomic.LoadInt64(&s.ts)
+		if old >= ts || atomic.CompareAndSwapInt64(&s.ts, old, ts) {
+			return nil
+		}
+	}
+}
+
+// FileTxnTimeStore is a [fauna.TxnTimeStore] backed by a file, which is
+// replaced atomically on each update. Processes storing times in the same
+// file take turns with a lock on a second file, the path with ".lock"
+// appended, so one can't overwrite a later time stored by another.
+type FileTxnTimeStore struct {
+	mu   sync.Mutex
+	path string
+	last int64
+}
+
+// NewFileTxnTimeStore creates a [fauna.FileTxnTimeStore] at path. The file
+// is created on the first store.
+func NewFileTxnTimeStore(path string) *FileTxnTimeStore {
+	return &FileTxnTimeStore{path: path}
+}
+
+// Load reads the stored time, returning zero if the file doesn't exist.
+func (s *FileTxnTimeStore) Load() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ts, err := s.read()
+	if err != nil {
+		return 0, err
+	}
+
+	if ts > s.last {
+		s.last = ts
+	}
+
+	return ts, nil
+}
+
+// Store writes ts, if it's later than the stored time.
+func (s *FileTxnTimeStore) Store(ts int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ts <= s.last {
+		return nil
+	}
+
+	unlock, err := lockFile(s.path + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to store txn time: %w", err)
+	}
+	defer unlock()
+
+	// another process may have stored a later time
+	if current, err := s.read(); err != nil {
+		return err
+	} else if current >= ts {
+		s.last = current
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to store txn time: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(strconv.FormatInt(ts, 10)); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to store txn time: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to store txn time: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to store txn time: %w", err)
+	}
+
+	s.last = ts
+
+	return nil
+}
+
+func (s *FileTxnTimeStore) read() (int64, error) {
+	bin, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to load txn time: %w", err)
+	}
+
+	ts, err := strconv.ParseInt(strings.TrimSpace(string(bin)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load txn time from %s: %w", s.path, err)
+	}
+
+	return ts, nil
+}
+
+// syncTxnTime advances the last transaction time to ts, storing it if it
+// changed.
+func (c *Client) syncTxnTime(ts int64) {
+	if c.lastTxnTime.sync(ts) && c.txnTimeStore != nil {
+		c.txnTimeStore.advance(ts, c.txnTimeStoreErrors)
+	}
+}
+
+// txnTimePersister stores a client's last transaction time in a
+// [fauna.TxnTimeStore] from a background goroutine, which runs while there's
+// a later time to store than it last stored.
+type txnTimePersister struct {
+	store TxnTimeStore
+
+	mu      sync.Mutex
+	idle    *sync.Cond
+	pending int64
+	stored  int64
+	running bool
+	onError func(error)
+}
+
+func newTxnTimePersister(store TxnTimeStore) *txnTimePersister {
+	p := &txnTimePersister{store: store}
+	p.idle = sync.NewCond(&p.mu)
+	return p
+}
+
+// advance stores ts in the background, reporting an error to onError.
+func (p *txnTimePersister) advance(ts int64, onError func(error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ts > p.pending {
+		p.pending = ts
+	}
+	p.onError = onError
+	if !p.running {
+		p.running = true
+		go p.run()
+	}
+}
+
+func (p *txnTimePersister) run() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for p.pending > p.stored {
+		ts, onError := p.pending, p.onError
+
+		p.mu.Unlock()
+		err := p.store.Store(ts)
+		p.mu.Lock()
+
+		// a failed time isn't retried, as a later one will be stored with the
+		// next query
+		p.stored = ts
+		if err != nil && onError != nil {
+			p.mu.Unlock()
+			onError(err)
+			p.mu.Lock()
+		}
+	}
+
+	p.running = false
+	p.idle.Broadcast()
+}
+
+// flush waits for the latest time to be stored.
+func (p *txnTimePersister) flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for p.running {
+		p.idle.Wait()
+	}
+}
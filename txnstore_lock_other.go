@@ -0,0 +1,35 @@
+//go:build !unix
+
+package fauna
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// staleLockAge is how old a lock file must be to be taken over, as the
+// process which created it has presumably exited without removing it.
+const staleLockAge = 10 * time.Second
+
+// lockFile takes an exclusive lock by creating the file at path, waiting
+// while another process has it, and returns a func which releases it.
+func lockFile(path string) (func(), error) {
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(path)
+			continue
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,122 @@
+package fauna
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxnTimeStore(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		return http.StatusOK, successBody(`null`)
+	})
+	q, _ := FQL(`null`, nil)
+
+	t.Run("memory", func(t *testing.T) {
+		store := NewMemoryTxnTimeStore()
+
+		client := srv.client(WithTxnTimeStore(store))
+		_, err := client.Query(q)
+		assert.NoError(t, err)
+		client.txnTimeStore.flush()
+
+		ts, _ := store.Load()
+		assert.Equal(t, int64(1680000000000000), ts)
+
+		assert.NoError(t, store.Store(1))
+		ts, _ = store.Load()
+		assert.Equal(t, int64(1680000000000000), ts, "stored times only advance")
+
+		restarted := srv.client(WithTxnTimeStore(store))
+		assert.Equal(t, int64(1680000000000000), restarted.GetLastTxnTime())
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "txn_ts")
+		store := NewFileTxnTimeStore(path)
+
+		ts, err := store.Load()
+		assert.NoError(t, err)
+		assert.Zero(t, ts)
+
+		client := srv.client(WithTxnTimeStore(store))
+		assert.Zero(t, client.GetLastTxnTime())
+
+		_, err = client.Query(q)
+		assert.NoError(t, err)
+		assert.NoError(t, client.Shutdown(context.Background()), "shutting down waits for the time to be stored")
+
+		bin, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, "1680000000000000", string(bin))
+
+		later := time.UnixMicro(1690000000000000)
+		client.SetLastTxnTime(later)
+		client.txnTimeStore.flush()
+
+		restarted := srv.client(WithTxnTimeStore(NewFileTxnTimeStore(path)))
+		assert.Equal(t, later.UnixMicro(), restarted.GetLastTxnTime())
+
+		_, err = restarted.Query(q)
+		assert.NoError(t, err)
+		received := srv.received()
+		assert.Equal(t, "1690000000000000", received[len(received)-1].Header.Get(HeaderLastTxnTs))
+	})
+
+	t.Run("file shared between processes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "txn_ts")
+		a, b := NewFileTxnTimeStore(path), NewFileTxnTimeStore(path)
+
+		assert.NoError(t, a.Store(20))
+		assert.NoError(t, b.Store(10))
+
+		ts, err := NewFileTxnTimeStore(path).Load()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(20), ts)
+	})
+
+	t.Run("file with concurrent writers", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "txn_ts")
+
+		var wg sync.WaitGroup
+		for i := 1; i <= 20; i++ {
+			wg.Add(1)
+			go func(ts int64) {
+				defer wg.Done()
+				assert.NoError(t, NewFileTxnTimeStore(path).Store(ts))
+			}(int64(i))
+		}
+		wg.Wait()
+
+		ts, err := NewFileTxnTimeStore(path).Load()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(20), ts, "no writer overwrites a later time")
+	})
+
+	t.Run("store errors", func(t *testing.T) {
+		errs := make(chan error, 1)
+		store := NewFileTxnTimeStore(filepath.Join(t.TempDir(), "missing", "txn_ts"))
+		client := srv.client(WithTxnTimeStore(store), TxnTimeStoreErrors(func(err error) { errs <- err }))
+
+		_, err := client.Query(q)
+		assert.NoError(t, err, "the query doesn't fail")
+		assert.ErrorContains(t, <-errs, "failed to store txn time")
+	})
+
+	t.Run("bad file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "txn_ts")
+		assert.NoError(t, os.WriteFile(path, []byte("garbage"), 0o600))
+
+		_, err := NewFileTxnTimeStore(path).Load()
+		assert.ErrorContains(t, err, "failed to load txn time")
+
+		client := srv.client(WithTxnTimeStore(NewFileTxnTimeStore(path)))
+		assert.Zero(t, client.GetLastTxnTime())
+	})
+}
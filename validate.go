@@ -0,0 +1,124 @@
+package fauna
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// maxArgumentDepth is the deepest a query argument can be nested, which also
+// stops self-referencing values from being followed forever.
+const maxArgumentDepth = 64
+
+// validateArgument checks the argument at path can be encoded, so a value
+// Fauna can't represent fails with an [fauna.ErrInvalidArgument] naming it,
+// rather than an encoding error once the query is sent.
+func validateArgument(path string, v any) error {
+	return validateValue(path, reflect.ValueOf(v), 0)
+}
+
+func validateValue(path string, value reflect.Value, depth int) error {
+	if !value.IsValid() {
+		return nil
+	}
+
+	if depth > maxArgumentDepth {
+		return &ErrInvalidArgument{Path: path, Reason: fmt.Sprintf("nested more than %d levels deep", maxArgumentDepth)}
+	}
+
+	if value.CanInterface() {
+		switch v := value.Interface().(type) {
+		case *Query:
+			if v == nil {
+				return &ErrInvalidArgument{Path: path, Reason: "a nil query can't be interpolated"}
+			}
+			return nil
+		case Query, *queryFragment, taggedJSON, Module, Ref, NamedRef,
+			Document, NamedDocument, NullDocument, NullNamedDocument, Page, time.Time, ArrayFunc:
+			return nil
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if f := value.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+			return &ErrInvalidArgument{Path: path, Reason: fmt.Sprintf("%v isn't a supported double", f)}
+		}
+
+	case reflect.Complex64, reflect.Complex128, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return &ErrInvalidArgument{Path: path, Reason: fmt.Sprintf("%s values can't be encoded", value.Type())}
+
+	case reflect.Interface:
+		return validateValue(path, value.Elem(), depth)
+
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+		return validateValue(path, value.Elem(), depth+1)
+
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return &ErrInvalidArgument{Path: path, Reason: fmt.Sprintf("map keys must be strings, not %s", value.Type().Key())}
+		}
+
+		mi := value.MapRange()
+		for mi.Next() {
+			if err := validateValue(path+"."+mi.Key().String(), mi.Value(), depth+1); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := validateValue(fmt.Sprintf("%s[%d]", path, i), value.Index(i), depth+1); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		return validateStruct(path, value, depth)
+	}
+
+	return nil
+}
+
+// validateStruct checks the fields encodeStruct encodes.
+func validateStruct(path string, value reflect.Value, depth int) error {
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+
+		if structField.Anonymous {
+			switch structField.Name {
+			case "Document", "NamedDocument", "NullDocument", "NullNamedDocument", "DocumentMeta":
+				continue
+			}
+		}
+
+		tags := strings.Split(codec{}.tagOf(structField), ",")
+		if tags[0] == "-" {
+			continue
+		}
+
+		if omitField(tags[1:], value.Field(i)) {
+			continue
+		}
+
+		name := tags[0]
+		if name == "" {
+			name = structField.Name
+		}
+
+		if !structField.IsExported() {
+			return &ErrInvalidArgument{Path: path + "." + name, Reason: "unexported fields can't be encoded"}
+		}
+
+		if err := validateValue(path+"."+name, value.Field(i), depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package fauna
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateArgument(t *testing.T) {
+	type toy struct {
+		Name    string   `fauna:"name"`
+		Squeak  func()   `fauna:"squeak,omitempty"`
+		Ignored chan int `fauna:"-"`
+	}
+	type dog struct {
+		Name string `fauna:"name"`
+		Toys []toy  `fauna:"toys"`
+	}
+	type secretive struct {
+		Name  string
+		owner string
+	}
+
+	deep := map[string]any{}
+	for i, m := 0, deep; i <= maxArgumentDepth; i++ {
+		next := map[string]any{}
+		m["next"] = next
+		m = next
+	}
+
+	valid := map[string]any{
+		"int":    42,
+		"double": 1.5,
+		"time":   time.Now(),
+		"doc":    &dog{Name: "Scout", Toys: []toy{{Name: "ball"}}},
+		"nil":    (*dog)(nil),
+		"array":  [2]int{1, 2},
+	}
+	for name, arg := range valid {
+		_, err := FQL(`${`+name+`}`, map[string]any{name: arg})
+		assert.NoError(t, err, name)
+	}
+
+	tests := []struct {
+		name   string
+		arg    any
+		path   string
+		reason string
+	}{
+		{"nan", math.NaN(), "arg", "NaN isn't a supported double"},
+		{"inf", []float64{1, math.Inf(-1)}, "arg[1]", "-Inf isn't a supported double"},
+		{"chan", make(chan int), "arg", "chan int values can't be encoded"},
+		{"func in struct", dog{Toys: []toy{{Squeak: func() {}}}}, "arg.toys[0].squeak", "func() values can't be encoded"},
+		{"complex", map[string]any{"z": complex(1, 2)}, "arg.z", "complex128 values can't be encoded"},
+		{"map keys", map[int]string{1: "one"}, "arg", "map keys must be strings, not int"},
+		{"unexported", secretive{Name: "Scout"}, "arg.owner", "unexported fields can't be encoded"},
+		{"nil query", map[string]any{"a": (*Query)(nil)}, "arg.a", "a nil query can't be interpolated"},
+		{"nil query in slice", []any{1, (*Query)(nil)}, "arg[1]", "a nil query can't be interpolated"},
+		{"deep", deep, "arg" + strings.Repeat(".next", maxArgumentDepth+1), "nested more than 64 levels deep"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FQL(`${arg}`, map[string]any{"arg": tt.arg})
+
+			var argErr *ErrInvalidArgument
+			if assert.ErrorAs(t, err, &argErr) {
+				assert.Equal(t, tt.path, argErr.Path)
+				assert.Equal(t, tt.reason, argErr.Reason)
+			}
+		})
+	}
+}
+
+func TestEncodeNilQuery(t *testing.T) {
+	for _, arg := range []any{
+		(*Query)(nil),
+		map[string]any{"a": (*Query)(nil)},
+		[]any{(*Query)(nil)},
+	} {
+		assert.NotPanics(t, func() {
+			_, err := codec{}.encodeInterpolation(arg)
+			assert.Error(t, err)
+		})
+	}
+}
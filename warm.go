@@ -0,0 +1,142 @@
+package fauna
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type warmupOptions struct {
+	query bool
+}
+
+// WarmupOptFn configures [Client.Warmup].
+type WarmupOptFn func(o *warmupOptions)
+
+// WarmupQuery opens the connection by running a no-op query instead, which
+// also checks the client's secret and warms Fauna's side of the first query.
+func WarmupQuery() WarmupOptFn {
+	return func(o *warmupOptions) { o.query = true }
+}
+
+// Warmup resolves the endpoint's DNS and opens a connection to Fauna,
+// completing the TCP and TLS handshakes, and leaves it in the client's pool,
+// so that the first query after a deploy or serverless cold start doesn't pay
+// for them. It doesn't run a query, so it's cheap enough to run in every cold
+// start. With [fauna.WarmupQuery] it's [Client.Warm] with one connection.
+func (c *Client) Warmup(ctx context.Context, opts ...WarmupOptFn) error {
+	var o warmupOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.query {
+		return c.Warm(ctx, 1)
+	}
+
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodHead, c.endpoint.Load().url, nil)
+	if reqErr != nil {
+		return fmt.Errorf("failed to init request: %w", reqErr)
+	}
+
+	// any response means the connection is open, whatever its status
+	r, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to warm up connection: %w", err)
+	}
+	_, _ = io.Copy(io.Discard, r.Body)
+	_ = r.Body.Close()
+
+	return nil
+}
+
+// Warm establishes up to n connections to Fauna ahead of time by running n
+// trivial queries concurrently, so that the first queries after a deploy or
+// cold start don't pay for DNS, TCP, and TLS setup. Each query also checks
+// the client's secret. HTTP/2 multiplexes concurrent queries over a single
+// connection, so against HTTP/2 endpoints fewer than n connections may be
+// opened.
+//
+// It returns the first error encountered, after every query has finished or
+// ctx is done.
+func (c *Client) Warm(ctx context.Context, n int) error {
+	if n < 1 {
+		return fmt.Errorf("n must be at least 1, got %d", n)
+	}
+
+	q, err := FQL(`null`, nil)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// cached or coalesced queries wouldn't touch a connection
+			if _, err := c.Query(q, QueryContext(ctx), NoCache(), NoCoalesce()); err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return fmt.Errorf("failed to warm connections: %w", firstErr)
+	}
+
+	return nil
+}
+
+// KeepAlive pings Fauna with [Client.Warm] and conns every interval, keeping
+// pooled connections from being closed by NATs, load balancers and gateways
+// with shorter idle timeouts than the client's, which would otherwise cause
+// occasional slow or reset first requests. The interval should be shorter
+// than the shortest idle timeout on the path to Fauna. Failed pings are
+// ignored, as the next ping tries again.
+//
+// The pings run in the background until [Client.Shutdown]. An interval of
+// zero or less disables them, and conns of less than one is one.
+func KeepAlive(interval time.Duration, conns int) ClientConfigFn {
+	return func(c *Client) {
+		if interval <= 0 {
+			c.keepAlive = nil
+			return
+		}
+		if conns < 1 {
+			conns = 1
+		}
+		c.keepAlive = &keepAlive{interval: interval, conns: conns}
+	}
+}
+
+type keepAlive struct {
+	interval time.Duration
+	conns    int
+}
+
+// run pings Fauna with c until c is shut down.
+func (k *keepAlive) run(c *Client) {
+	ticker := time.NewTicker(k.interval)
+	defer ticker.Stop()
+
+	done := c.inflight.done()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			_ = c.Warm(context.Background(), k.conns)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package fauna
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarm(t *testing.T) {
+	var (
+		warming int32 = 3
+		arrived sync.WaitGroup
+	)
+	arrived.Add(3)
+
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		// hold the warming requests until all have arrived, so each needs its
+		// own connection
+		if atomic.AddInt32(&warming, -1) >= 0 {
+			arrived.Done()
+			arrived.Wait()
+		}
+		return http.StatusOK, successBody(`null`)
+	})
+
+	conns := func() int {
+		addrs := map[string]bool{}
+		for _, req := range srv.received() {
+			addrs[req.RemoteAddr] = true
+		}
+		return len(addrs)
+	}
+
+	client := srv.client()
+	assert.NoError(t, client.Warm(context.Background(), 3))
+	assert.Len(t, srv.received(), 3)
+	assert.Equal(t, 3, conns())
+
+	t.Run("reuses warm connections", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			_, err := client.Query(&Query{})
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 3, conns())
+	})
+
+	t.Run("bypasses the cache", func(t *testing.T) {
+		client := srv.client(WithQueryCache(NewQueryCache(time.Minute, 10)))
+		before := len(srv.received())
+
+		assert.NoError(t, client.Warm(context.Background(), 1))
+		assert.NoError(t, client.Warm(context.Background(), 1))
+		assert.Len(t, srv.received(), before+2)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		assert.ErrorContains(t, client.Warm(context.Background(), 0), "at least 1")
+
+		srv := newTestServer(t, func(testRequest) (int, string) {
+			return http.StatusUnauthorized, errorBody("unauthorized", "Access token required")
+		})
+
+		err := srv.client().Warm(context.Background(), 2)
+		var authErr *ErrAuthentication
+		assert.ErrorAs(t, err, &authErr)
+	})
+}
+
+func TestKeepAlive(t *testing.T) {
+	srv := newTestServer(t, func(testRequest) (int, string) {
+		return http.StatusOK, successBody(`null`)
+	})
+
+	t.Run("pings until shutdown", func(t *testing.T) {
+		client := srv.client(KeepAlive(5*time.Millisecond, 2))
+		assert.Eventually(t, func() bool { return len(srv.received()) >= 4 }, time.Second, time.Millisecond)
+		assert.NoError(t, client.Shutdown(context.Background()))
+
+		stopped := len(srv.received())
+		time.Sleep(20 * time.Millisecond)
+		assert.Len(t, srv.received(), stopped, "pings stop once the client is shut down")
+	})
+
+	t.Run("disabled by a non-positive interval", func(t *testing.T) {
+		before := len(srv.received())
+		client := srv.client(KeepAlive(5*time.Millisecond, 1), KeepAlive(0, 1))
+		time.Sleep(20 * time.Millisecond)
+		assert.Len(t, srv.received(), before)
+		assert.NoError(t, client.Shutdown(context.Background()))
+	})
+}
+
+func TestWarmup(t *testing.T) {
+	srv := newTestServer(t, func(req testRequest) (int, string) {
+		if req.Method == http.MethodHead {
+			return http.StatusNotFound, ""
+		}
+		return http.StatusOK, successBody(`null`)
+	})
+	client := srv.client()
+
+	assert.NoError(t, client.Warmup(context.Background()))
+	received := srv.received()
+	if assert.Len(t, received, 1, "no query is run") {
+		assert.Equal(t, http.MethodHead, received[0].Method)
+	}
+
+	_, err := client.Query(&Query{})
+	assert.NoError(t, err)
+	received = srv.received()
+	if assert.Len(t, received, 2) {
+		assert.Equal(t, received[0].RemoteAddr, received[1].RemoteAddr, "queries reuse the warm connection")
+	}
+
+	t.Run("runs a query", func(t *testing.T) {
+		before := len(srv.received())
+		assert.NoError(t, client.Warmup(context.Background(), WarmupQuery()))
+
+		received := srv.received()[before:]
+		if assert.Len(t, received, 1) {
+			assert.Equal(t, http.MethodPost, received[0].Method)
+		}
+	})
+
+	t.Run("fails to connect", func(t *testing.T) {
+		client := NewClient("secret", DefaultTimeouts(), URL("http://127.0.0.1:1"))
+		assert.Error(t, client.Warmup(context.Background()))
+	})
+}
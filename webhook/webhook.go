@@ -0,0 +1,367 @@
+// Package webhook forwards the events of a Fauna event feed to an HTTP
+// endpoint, so changes to a set can be pushed to external systems.
+//
+// Each event is POSTed as JSON, with its data in Fauna's tagged format:
+//
+//	{"type":"add","txn_ts":1680000000000000,"cursor":"...","data":{"@doc":{...}}}
+//
+// Delivery is at least once. A failed delivery is retried with exponential
+// backoff, and the forwarder stops rather than skipping an event that can't be
+// delivered. The cursor of each delivered event is passed to the function set
+// with [webhook.OnCheckpoint], so a restarted forwarder can resume after it
+// with [webhook.Cursor]. An event may still be delivered twice if the
+// forwarder stops between delivering it and recording its cursor, so
+// receivers should use the [webhook.HeaderEventID] header to ignore repeats.
+//
+// Requests are signed with [webhook.Secret], and receivers check the
+// signature with [webhook.Verify].
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fauna/fauna-go"
+)
+
+const (
+	// HeaderEventID is the cursor of the delivered event, which is unique
+	// to it, for receivers to ignore repeated deliveries.
+	HeaderEventID = "X-Fauna-Event-Id"
+
+	// HeaderSignature is the request's signature when a [webhook.Secret] is
+	// set, as `t=<unix seconds>,v1=<hex HMAC-SHA256>`. The HMAC is of the
+	// timestamp, a `.`, and the request body.
+	HeaderSignature = "X-Fauna-Signature"
+
+	// MaxAttemptsDefault is the number of times an event is sent unless
+	// configured with [webhook.MaxAttempts].
+	MaxAttemptsDefault = 5
+
+	// MaxBackoffDefault is the longest wait between attempts unless
+	// configured with [webhook.MaxBackoff].
+	MaxBackoffDefault = 30 * time.Second
+
+	// PollIntervalDefault is how long the forwarder waits for new events
+	// once it's caught up, unless configured with [webhook.PollInterval].
+	PollIntervalDefault = time.Second
+)
+
+// ErrInvalidSignature is returned by [webhook.Verify] for requests which
+// weren't signed with the secret.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// ErrDelivery is returned when an event couldn't be delivered.
+type ErrDelivery struct {
+	// Cursor is the cursor of the undelivered event.
+	Cursor string
+
+	// Attempts is the number of times the event was sent.
+	Attempts int
+
+	// StatusCode is the status of the last response, or zero if the last
+	// attempt failed without a response.
+	StatusCode int
+
+	Err error
+}
+
+// Error provides the underlying error message.
+func (e *ErrDelivery) Error() string {
+	return fmt.Sprintf("failed to deliver event %s after %d attempts: %s", e.Cursor, e.Attempts, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ErrDelivery) Unwrap() error {
+	return e.Err
+}
+
+// ForwarderConfigFn configuration options for a [webhook.Forwarder]
+type ForwarderConfigFn func(f *Forwarder)
+
+// Secret signs each request with key. See [webhook.HeaderSignature].
+func Secret(key []byte) ForwarderConfigFn {
+	return func(f *Forwarder) { f.secret = key }
+}
+
+// Cursor resumes forwarding after the event at cursor, as passed to
+// [webhook.OnCheckpoint].
+func Cursor(cursor string) ForwarderConfigFn {
+	return func(f *Forwarder) { f.cursor = cursor }
+}
+
+// OnCheckpoint calls fn with the cursor of each event once it's delivered.
+// If fn fails the forwarder stops with its error.
+func OnCheckpoint(fn func(cursor string) error) ForwarderConfigFn {
+	return func(f *Forwarder) { f.checkpoint = fn }
+}
+
+// MaxAttempts sets the number of times an event is sent before the forwarder
+// gives up. Defaults to [webhook.MaxAttemptsDefault].
+func MaxAttempts(attempts int) ForwarderConfigFn {
+	return func(f *Forwarder) { f.maxAttempts = attempts }
+}
+
+// MaxBackoff sets the longest wait between attempts. Defaults to
+// [webhook.MaxBackoffDefault].
+func MaxBackoff(backoff time.Duration) ForwarderConfigFn {
+	return func(f *Forwarder) { f.maxBackoff = backoff }
+}
+
+// PollInterval sets how long the forwarder waits for new events once it's
+// caught up. Defaults to [webhook.PollIntervalDefault].
+func PollInterval(interval time.Duration) ForwarderConfigFn {
+	return func(f *Forwarder) { f.pollInterval = interval }
+}
+
+// HTTPClient sets the client events are sent with. Defaults to
+// [http.DefaultClient].
+func HTTPClient(client *http.Client) ForwarderConfigFn {
+	return func(f *Forwarder) { f.http = client }
+}
+
+// Header adds a header to each request, such as the receiver's credentials.
+func Header(key, value string) ForwarderConfigFn {
+	return func(f *Forwarder) { f.headers.Set(key, value) }
+}
+
+// Forwarder delivers the events of an [fauna.EventSource] to a webhook.
+type Forwarder struct {
+	client *fauna.Client
+	source fauna.EventSource
+	url    string
+
+	secret       []byte
+	checkpoint   func(cursor string) error
+	maxAttempts  int
+	maxBackoff   time.Duration
+	pollInterval time.Duration
+	http         *http.Client
+	headers      http.Header
+
+	mu     sync.Mutex
+	cursor string
+}
+
+// New creates a [webhook.Forwarder] of the events of source to url.
+func New(client *fauna.Client, source fauna.EventSource, url string, configFns ...ForwarderConfigFn) *Forwarder {
+	f := &Forwarder{
+		client:       client,
+		source:       source,
+		url:          url,
+		maxAttempts:  MaxAttemptsDefault,
+		maxBackoff:   MaxBackoffDefault,
+		pollInterval: PollIntervalDefault,
+		http:         http.DefaultClient,
+		headers:      http.Header{},
+	}
+
+	for _, configFn := range configFns {
+		configFn(f)
+	}
+
+	return f
+}
+
+// Cursor returns the cursor of the last delivered event.
+func (f *Forwarder) Cursor() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.cursor
+}
+
+// Run forwards events until ctx is done, returning its error, or until an
+// event can't be read or delivered. Run can be called again to resume from
+// the last delivered event.
+func (f *Forwarder) Run(ctx context.Context) error {
+	feed := f.client.Feed(f.source, fauna.FeedCursor(f.Cursor()))
+
+	for {
+		page, err := feed.Next(ctx)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return fmt.Errorf("failed to read events: %w", err)
+		}
+
+		for i := range page.Events {
+			event := &page.Events[i]
+			if event.Error != nil {
+				return fmt.Errorf("event feed failed: %w", event.Error)
+			}
+
+			if err := f.deliver(ctx, event); err != nil {
+				return err
+			}
+
+			f.mu.Lock()
+			f.cursor = event.Cursor
+			f.mu.Unlock()
+
+			if f.checkpoint != nil {
+				if err := f.checkpoint(event.Cursor); err != nil {
+					return fmt.Errorf("failed to checkpoint event %s: %w", event.Cursor, err)
+				}
+			}
+		}
+
+		if page.HasNext {
+			continue
+		}
+
+		if err := sleep(ctx, f.pollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+type payload struct {
+	Type    string          `json:"type"`
+	TxnTime int64           `json:"txn_ts"`
+	Cursor  string          `json:"cursor"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// deliver sends event, retrying until it's accepted or the attempts run out.
+func (f *Forwarder) deliver(ctx context.Context, event *fauna.Event) error {
+	body, err := json.Marshal(payload{Type: event.Type, TxnTime: event.TxnTime, Cursor: event.Cursor, Data: event.Raw})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event %s: %w", event.Cursor, err)
+	}
+
+	var (
+		attempts int
+		status   int
+	)
+	for {
+		attempts++
+
+		var retry bool
+		status, retry, err = f.send(ctx, event.Cursor, body)
+		if err == nil {
+			return nil
+		}
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		if !retry || attempts >= f.maxAttempts {
+			return &ErrDelivery{Cursor: event.Cursor, Attempts: attempts, StatusCode: status, Err: err}
+		}
+
+		if err := sleep(ctx, f.backoff(attempts)); err != nil {
+			return err
+		}
+	}
+}
+
+// send makes a single delivery attempt, reporting whether a failure is worth
+// retrying.
+func (f *Forwarder) send(ctx context.Context, cursor string, body []byte) (status int, retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to init request: %w", err)
+	}
+
+	for k, v := range f.headers {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, cursor)
+	if f.secret != nil {
+		req.Header.Set(HeaderSignature, Sign(f.secret, time.Now(), body))
+	}
+
+	r, err := f.http.Do(req)
+	if err != nil {
+		return 0, true, err
+	}
+	defer r.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(r.Body, 4096))
+
+	switch {
+	case r.StatusCode >= 200 && r.StatusCode < 300:
+		return r.StatusCode, false, nil
+	case r.StatusCode == http.StatusRequestTimeout, r.StatusCode == http.StatusTooManyRequests, r.StatusCode >= 500:
+		return r.StatusCode, true, fmt.Errorf("webhook responded with status %d", r.StatusCode)
+	default:
+		return r.StatusCode, false, fmt.Errorf("webhook responded with status %d", r.StatusCode)
+	}
+}
+
+func (f *Forwarder) backoff(attempt int) time.Duration {
+	sleep := 100 * time.Millisecond << (attempt - 1)
+	if sleep <= 0 || sleep > f.maxBackoff {
+		sleep = f.maxBackoff
+	}
+
+	return sleep
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Sign returns the [webhook.HeaderSignature] value of body sent at t.
+func Sign(secret []byte, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks that the request body was signed with secret no more than
+// tolerance ago, returning [webhook.ErrInvalidSignature] if not. A tolerance
+// of zero or less accepts signatures of any age.
+func Verify(secret []byte, header string, body []byte, tolerance time.Duration) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		if v := strings.TrimPrefix(part, "t="); v != part {
+			ts = v
+		} else if v := strings.TrimPrefix(part, "v1="); v != part {
+			sig = v
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return ErrInvalidSignature
+	}
+
+	signedAt := time.Unix(unix, 0)
+	if !hmac.Equal([]byte(Sign(secret, signedAt, body)), []byte("t="+ts+",v1="+sig)) {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 && time.Since(signedAt) > tolerance {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fauna/fauna-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFeedServer is a fake Fauna endpoint serving pages of events, keyed by
+// the cursor they're read from.
+func newFeedServer(t *testing.T, pages map[string]string) *fauna.Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Cursor string `json:"cursor"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		w.Header().Set("Content-Type", "application/json;charset=utf-8")
+		page, found := pages[req.Cursor]
+		if !found {
+			page = `{"events":[],"cursor":"` + req.Cursor + `","has_next":false,"stats":{}}`
+		}
+		_, _ = io.WriteString(w, page)
+	}))
+	t.Cleanup(srv.Close)
+
+	return fauna.NewClient("secret", fauna.DefaultTimeouts(), fauna.URL(srv.URL))
+}
+
+// receiver is a webhook endpoint replying with the statuses it's given, then
+// 200 OK.
+type receiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+	headers  []http.Header
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	rcv := &receiver{statuses: statuses}
+	rcv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bin, _ := io.ReadAll(r.Body)
+
+		rcv.mu.Lock()
+		defer rcv.mu.Unlock()
+		rcv.bodies = append(rcv.bodies, string(bin))
+		rcv.headers = append(rcv.headers, r.Header.Clone())
+
+		status := http.StatusOK
+		if len(rcv.statuses) > 0 {
+			status, rcv.statuses = rcv.statuses[0], rcv.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(rcv.Close)
+
+	return rcv
+}
+
+const twoEvents = `{"events":[
+	{"type":"add","txn_ts":1,"cursor":"c1","data":{"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"}}}},
+	{"type":"remove","txn_ts":2,"cursor":"c2","data":{"@doc":{"id":"2","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"}}}}
+],"cursor":"c2","has_next":false,"stats":{}}`
+
+func TestForwarder(t *testing.T) {
+	client := newFeedServer(t, map[string]string{"": twoEvents})
+	rcv := newReceiver(t, http.StatusServiceUnavailable)
+	secret := []byte("shh")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var checkpoints []string
+	fwd := New(client, "token", rcv.URL,
+		Secret(secret),
+		Header("Authorization", "Bearer hook"),
+		MaxBackoff(time.Millisecond),
+		PollInterval(time.Millisecond),
+		OnCheckpoint(func(cursor string) error {
+			checkpoints = append(checkpoints, cursor)
+			if cursor == "c2" {
+				cancel()
+			}
+			return nil
+		}),
+	)
+
+	err := fwd.Run(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"c1", "c2"}, checkpoints)
+	assert.Equal(t, "c2", fwd.Cursor())
+
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	if assert.Len(t, rcv.bodies, 3, "the first delivery is retried") {
+		assert.Equal(t, rcv.bodies[0], rcv.bodies[1])
+		assert.JSONEq(t, `{"type":"add","txn_ts":1,"cursor":"c1","data":{"@doc":{"id":"1","coll":{"@mod":"Dogs"},"ts":{"@time":"2023-04-01T00:00:00Z"}}}}`, rcv.bodies[1])
+
+		header := rcv.headers[2]
+		assert.Equal(t, "c2", header.Get(HeaderEventID))
+		assert.Equal(t, "Bearer hook", header.Get("Authorization"))
+		assert.NoError(t, Verify(secret, header.Get(HeaderSignature), []byte(rcv.bodies[2]), time.Minute))
+		assert.ErrorIs(t, Verify([]byte("wrong"), header.Get(HeaderSignature), []byte(rcv.bodies[2]), 0), ErrInvalidSignature)
+	}
+}
+
+func TestForwarderResumes(t *testing.T) {
+	client := newFeedServer(t, map[string]string{
+		"":   twoEvents,
+		"c1": `{"events":[{"type":"update","txn_ts":3,"cursor":"c3"}],"cursor":"c3","has_next":false,"stats":{}}`,
+	})
+	rcv := newReceiver(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fwd := New(client, "token", rcv.URL, Cursor("c1"), OnCheckpoint(func(string) error {
+		cancel()
+		return nil
+	}))
+	assert.ErrorIs(t, fwd.Run(ctx), context.Canceled)
+	assert.Equal(t, "c3", fwd.Cursor())
+
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	if assert.Len(t, rcv.bodies, 1) {
+		assert.JSONEq(t, `{"type":"update","txn_ts":3,"cursor":"c3"}`, rcv.bodies[0])
+	}
+}
+
+func TestForwarderDeliveryFailure(t *testing.T) {
+	client := newFeedServer(t, map[string]string{"": twoEvents})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		rcv := newReceiver(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+		fwd := New(client, "token", rcv.URL, MaxAttempts(3), MaxBackoff(time.Millisecond))
+
+		var deliveryErr *ErrDelivery
+		if assert.ErrorAs(t, fwd.Run(context.Background()), &deliveryErr) {
+			assert.Equal(t, "c1", deliveryErr.Cursor)
+			assert.Equal(t, 3, deliveryErr.Attempts)
+			assert.Equal(t, http.StatusInternalServerError, deliveryErr.StatusCode)
+		}
+		assert.Empty(t, fwd.Cursor(), "undelivered events aren't skipped")
+	})
+
+	t.Run("doesn't retry client errors", func(t *testing.T) {
+		rcv := newReceiver(t, http.StatusBadRequest)
+		fwd := New(client, "token", rcv.URL, MaxBackoff(time.Millisecond))
+
+		var deliveryErr *ErrDelivery
+		if assert.ErrorAs(t, fwd.Run(context.Background()), &deliveryErr) {
+			assert.Equal(t, 1, deliveryErr.Attempts)
+		}
+	})
+
+	t.Run("stops if the checkpoint fails", func(t *testing.T) {
+		rcv := newReceiver(t)
+		fail := errors.New("disk full")
+		fwd := New(client, "token", rcv.URL, OnCheckpoint(func(string) error { return fail }))
+
+		assert.ErrorIs(t, fwd.Run(context.Background()), fail)
+		assert.Equal(t, "c1", fwd.Cursor())
+	})
+}